@@ -18,10 +18,13 @@ import (
 	"github.com/supertokens/supertokens-golang/recipe/usermetadata"
 	"github.com/supertokens/supertokens-golang/supertokens"
 	"github.com/ysaakpr/rex/internal/api/handlers"
+	"github.com/ysaakpr/rex/internal/api/middleware"
 	"github.com/ysaakpr/rex/internal/api/router"
 	"github.com/ysaakpr/rex/internal/config"
 	"github.com/ysaakpr/rex/internal/database"
 	"github.com/ysaakpr/rex/internal/jobs"
+	"github.com/ysaakpr/rex/internal/pkg/dnsverify"
+	"github.com/ysaakpr/rex/internal/pkg/users"
 	"github.com/ysaakpr/rex/internal/repository"
 	"github.com/ysaakpr/rex/internal/services"
 	"go.uber.org/zap"
@@ -74,18 +77,89 @@ func main() {
 	rbacRepo := repository.NewRBACRepository(db)
 	platformAdminRepo := repository.NewPlatformAdminRepository(db)
 	systemUserRepo := repository.NewSystemUserRepository(db)
+	tenantDomainRepo := repository.NewTenantDomainRepository(db)
+	tenantEmailDomainRepo := repository.NewTenantEmailDomainRepository(db)
+	metadataSchemaRepo := repository.NewMetadataSchemaRepository(db)
+	provisioningRepo := repository.NewProvisioningRepository(db)
+	downstreamServiceRepo := repository.NewDownstreamServiceRepository(db)
+	planRepo := repository.NewPlanRepository(db)
+	featureFlagRepo := repository.NewFeatureFlagRepository(db)
+	tenantExportRepo := repository.NewTenantExportRepository(db)
+	tenantOwnershipRepo := repository.NewTenantOwnershipRepository(db)
+	tenantTemplateRepo := repository.NewTenantTemplateRepository(db)
+	memberBulkRepo := repository.NewMemberBulkRepository(db)
+	tenantGroupRepo := repository.NewTenantGroupRepository(db)
+	userProfileRepo := repository.NewUserProfileRepository(db)
+	accessRequestRepo := repository.NewTenantAccessRequestRepository(db)
+	accessReviewRepo := repository.NewAccessReviewRepository(db)
 
 	// Initialize services
-	rbacService := services.NewRBACService(rbacRepo)
-	tenantService := services.NewTenantService(tenantRepo, memberRepo, invitationRepo, rbacRepo, jobClient)
-	memberService := services.NewMemberService(memberRepo, tenantRepo, rbacRepo)
-	invitationService := services.NewInvitationService(invitationRepo, memberRepo, tenantRepo, rbacRepo, jobClient, cfg)
+	planService := services.NewPlanService(planRepo, tenantRepo)
+	rbacService := services.NewRBACService(rbacRepo, planService)
+	metadataSchemaService := services.NewMetadataSchemaService(metadataSchemaRepo, jobClient)
+	tenantService := services.NewTenantService(tenantRepo, memberRepo, invitationRepo, rbacRepo, tenantTemplateRepo, jobClient, metadataSchemaService)
+	tenantLifecycleService := services.NewTenantLifecycleService(
+		tenantRepo,
+		cfg.GetTenantRetention(),
+		services.NewSessionRevocationHook(memberRepo, platformAdminRepo),
+		services.NewTransitionJobHook(jobClient),
+	)
+	domainVerifier := dnsverify.NewVerifier(nil)
+	tenantDomainService := services.NewTenantDomainService(
+		tenantDomainRepo,
+		tenantRepo,
+		domainVerifier,
+		cfg.TenantRouting.BaseDomain,
+		cfg.SuperTokens.APIDomain,
+		cfg.SuperTokens.WebsiteDomain,
+	)
+	serviceRegistryService := services.NewServiceRegistryService(downstreamServiceRepo)
+	provisioningService := services.NewProvisioningService(provisioningRepo, tenantRepo, downstreamServiceRepo, jobClient, cfg.TenantInit.SigningSecret)
+	memberService := services.NewMemberService(memberRepo, tenantRepo, rbacRepo, platformAdminRepo, planService)
+	memberBulkService := services.NewMemberBulkService(memberBulkRepo, tenantRepo, memberRepo, rbacRepo, platformAdminRepo, jobClient)
+	tenantEmailDomainService := services.NewTenantEmailDomainService(tenantEmailDomainRepo, tenantRepo, memberRepo, rbacRepo, platformAdminRepo, planService, domainVerifier)
+	invitationService := services.NewInvitationService(invitationRepo, memberRepo, tenantRepo, rbacRepo, platformAdminRepo, planService, tenantEmailDomainService, jobClient, cfg)
 	platformAdminService := services.NewPlatformAdminService(platformAdminRepo)
+	featureFlagService := services.NewFeatureFlagService(featureFlagRepo, tenantRepo)
+	tenantExportService := services.NewTenantExportService(tenantExportRepo, tenantRepo, jobClient, cfg)
+	tenantOwnershipService := services.NewTenantOwnershipService(tenantOwnershipRepo, tenantRepo, memberRepo, rbacRepo, platformAdminRepo, jobClient)
+	tenantTemplateService := services.NewTenantTemplateService(tenantTemplateRepo, downstreamServiceRepo, metadataSchemaService)
+	tenantSecurityService := services.NewTenantSecurityService(tenantRepo, rbacRepo)
+	tenantGroupService := services.NewTenantGroupService(tenantGroupRepo, tenantRepo, memberRepo, rbacRepo, platformAdminRepo)
+	membershipService := services.NewMembershipService(memberRepo, tenantRepo, rbacRepo, userProfileRepo)
+	accessRequestService := services.NewTenantAccessRequestService(accessRequestRepo, tenantRepo, memberRepo, memberService, jobClient)
+	accessReviewService := services.NewAccessReviewService(accessReviewRepo, tenantRepo, memberRepo, rbacRepo, platformAdminRepo, tenantGroupRepo, memberService, jobClient, cfg.AccessReview.SigningSecret)
 	systemUserService := services.NewSystemUserService(systemUserRepo)
 
+	// Register services still configured through TENANT_INIT_SERVICES
+	if imported, err := serviceRegistryService.ImportLegacyServices(cfg.TenantInit.Services); err != nil {
+		logger.Warn("Failed to import TENANT_INIT_SERVICES into the service registry", zap.Error(err))
+	} else if imported > 0 {
+		logger.Warn("Imported TENANT_INIT_SERVICES into the service registry; manage services under /platform/services instead",
+			zap.Int("imported", imported),
+		)
+	}
+
 	// Initialize handlers
 	tenantHandler := handlers.NewTenantHandler(tenantService, db)
+	tenantLifecycleHandler := handlers.NewTenantLifecycleHandler(tenantLifecycleService)
+	tenantDomainHandler := handlers.NewTenantDomainHandler(tenantDomainService)
+	tenantEmailDomainHandler := handlers.NewTenantEmailDomainHandler(tenantEmailDomainService)
+	metadataSchemaHandler := handlers.NewMetadataSchemaHandler(metadataSchemaService)
+	provisioningHandler := handlers.NewProvisioningHandler(provisioningService)
+	serviceRegistryHandler := handlers.NewServiceRegistryHandler(serviceRegistryService)
+	planHandler := handlers.NewPlanHandler(planService)
+	featureFlagHandler := handlers.NewFeatureFlagHandler(featureFlagService)
+	tenantExportHandler := handlers.NewTenantExportHandler(tenantExportService)
+	tenantOwnershipHandler := handlers.NewTenantOwnershipHandler(tenantOwnershipService)
+	tenantTemplateHandler := handlers.NewTenantTemplateHandler(tenantTemplateService)
+	tenantSecurityHandler := handlers.NewTenantSecurityHandler(tenantSecurityService)
 	memberHandler := handlers.NewMemberHandler(memberService)
+	memberBulkHandler := handlers.NewMemberBulkHandler(memberBulkService)
+	tenantGroupHandler := handlers.NewTenantGroupHandler(tenantGroupService)
+	membershipHandler := handlers.NewMembershipHandler(membershipService)
+	accessRequestHandler := handlers.NewTenantAccessRequestHandler(accessRequestService)
+	accessReviewHandler := handlers.NewAccessReviewHandler(accessReviewService)
 	invitationHandler := handlers.NewInvitationHandler(invitationService, cfg)
 	rbacHandler := handlers.NewRBACHandler(rbacService)
 	platformAdminHandler := handlers.NewPlatformAdminHandler(platformAdminService)
@@ -95,18 +169,36 @@ func main() {
 
 	// Setup router
 	routerDeps := &router.RouterDeps{
-		TenantHandler:        tenantHandler,
-		MemberHandler:        memberHandler,
-		InvitationHandler:    invitationHandler,
-		RBACHandler:          rbacHandler,
-		PlatformAdminHandler: platformAdminHandler,
-		UserHandler:          userHandler,
-		SystemUserHandler:    systemUserHandler,
-		AuthConfigHandler:    authConfigHandler,
-		MemberRepo:           memberRepo,
-		RBACService:          rbacService,
-		Logger:               logger,
-		DB:                   db,
+		TenantHandler:              tenantHandler,
+		TenantLifecycleHandler:     tenantLifecycleHandler,
+		TenantDomainHandler:        tenantDomainHandler,
+		TenantEmailDomainHandler:   tenantEmailDomainHandler,
+		TenantAccessRequestHandler: accessRequestHandler,
+		AccessReviewHandler:        accessReviewHandler,
+		MetadataSchemaHandler:      metadataSchemaHandler,
+		ProvisioningHandler:        provisioningHandler,
+		ServiceRegistryHandler:     serviceRegistryHandler,
+		PlanHandler:                planHandler,
+		FeatureFlagHandler:         featureFlagHandler,
+		TenantExportHandler:        tenantExportHandler,
+		TenantOwnershipHandler:     tenantOwnershipHandler,
+		TenantTemplateHandler:      tenantTemplateHandler,
+		TenantSecurityHandler:      tenantSecurityHandler,
+		MemberHandler:              memberHandler,
+		MemberBulkHandler:          memberBulkHandler,
+		TenantGroupHandler:         tenantGroupHandler,
+		MembershipHandler:          membershipHandler,
+		InvitationHandler:          invitationHandler,
+		RBACHandler:                rbacHandler,
+		PlatformAdminHandler:       platformAdminHandler,
+		UserHandler:                userHandler,
+		SystemUserHandler:          systemUserHandler,
+		AuthConfigHandler:          authConfigHandler,
+		MemberRepo:                 memberRepo,
+		RBACService:                rbacService,
+		TenantDomainService:        tenantDomainService,
+		Logger:                     logger,
+		DB:                         db,
 	}
 
 	r := router.SetupRouter(routerDeps)
@@ -228,6 +320,20 @@ func initSuperTokens(cfg *config.Config) error {
 					tenantId string,
 					userContext supertokens.UserContext,
 				) (sessmodels.SessionContainer, error) {
+					if accessTokenPayload == nil {
+						accessTokenPayload = map[string]interface{}{}
+					}
+
+					// Record when, how and as whom the user signed in for
+					// tenant security settings; refreshes keep the claims
+					accessTokenPayload[middleware.SessionStartedAtClaim] = time.Now().Unix()
+					if method, err := users.LookupLoginMethod(userID); err == nil {
+						accessTokenPayload[middleware.LoginMethodClaim] = method
+					}
+					if email, err := users.LookupEmail(userID); err == nil {
+						accessTokenPayload[middleware.EmailClaim] = email
+					}
+
 					// Fetch user metadata from SuperTokens
 					metadata, err := usermetadata.GetUserMetadata(userID)
 					if err == nil && metadata != nil {
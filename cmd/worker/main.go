@@ -6,9 +6,17 @@ import (
 	"os/signal"
 	"syscall"
 
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/thirdparty"
+	"github.com/supertokens/supertokens-golang/recipe/thirdparty/tpmodels"
+	"github.com/supertokens/supertokens-golang/recipe/usermetadata"
+	"github.com/supertokens/supertokens-golang/supertokens"
 	"github.com/ysaakpr/rex/internal/config"
 	"github.com/ysaakpr/rex/internal/database"
 	"github.com/ysaakpr/rex/internal/jobs"
+	"github.com/ysaakpr/rex/internal/repository"
+	"github.com/ysaakpr/rex/internal/services"
 	"go.uber.org/zap"
 )
 
@@ -37,8 +45,19 @@ func main() {
 	}
 	logger.Info("Database connection established")
 
+	// Initialize SuperTokens so jobs can resolve user emails
+	if err := initSuperTokens(cfg); err != nil {
+		logger.Fatal("Failed to initialize SuperTokens", zap.Error(err))
+	}
+	logger.Info("SuperTokens initialized")
+
+	// Services whose checks jobs repeat when acting for a member
+	tenantRepo := repository.NewTenantRepository(db)
+	planService := services.NewPlanService(repository.NewPlanRepository(db), tenantRepo)
+	checkGrant := services.NewRoleGrantCheck(repository.NewRBACRepository(db), repository.NewPlatformAdminRepository(db))
+
 	// Initialize worker
-	worker, err := jobs.NewWorker(cfg, db, logger)
+	worker, err := jobs.NewWorker(cfg, db, logger, checkGrant, planService.GetEntitlements)
 	if err != nil {
 		logger.Fatal("Failed to initialize worker", zap.Error(err))
 	}
@@ -68,3 +87,51 @@ func initLogger(cfg *config.Config) (*zap.Logger, error) {
 	}
 	return zap.NewDevelopment()
 }
+
+// initSuperTokens connects the worker to the SuperTokens core. The worker never
+// serves auth routes; it only needs the recipes used to look up and manage users.
+func initSuperTokens(cfg *config.Config) error {
+	apiBasePath := cfg.SuperTokens.APIBasePath
+	websiteBasePath := "/auth"
+
+	recipeList := []supertokens.Recipe{
+		emailpassword.Init(nil),
+		usermetadata.Init(nil),
+		session.Init(nil),
+	}
+
+	if cfg.IsGoogleOAuthEnabled() {
+		recipeList = append(recipeList, thirdparty.Init(&tpmodels.TypeInput{
+			SignInAndUpFeature: tpmodels.TypeInputSignInAndUp{
+				Providers: []tpmodels.ProviderInput{
+					{
+						Config: tpmodels.ProviderConfig{
+							ThirdPartyId: "google",
+							Clients: []tpmodels.ProviderClientConfig{
+								{
+									ClientID:     cfg.SuperTokens.GoogleClientID,
+									ClientSecret: cfg.SuperTokens.GoogleClientSecret,
+								},
+							},
+						},
+					},
+				},
+			},
+		}))
+	}
+
+	return supertokens.Init(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: cfg.SuperTokens.ConnectionURI,
+			APIKey:        cfg.SuperTokens.APIKey,
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:         "UTM Backend Worker",
+			APIDomain:       cfg.SuperTokens.APIDomain,
+			WebsiteDomain:   cfg.SuperTokens.WebsiteDomain,
+			APIBasePath:     &apiBasePath,
+			WebsiteBasePath: &websiteBasePath,
+		},
+		RecipeList: recipeList,
+	})
+}
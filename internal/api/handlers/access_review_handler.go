@@ -0,0 +1,308 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/api/middleware"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/pkg/response"
+	"github.com/ysaakpr/rex/internal/pkg/signing"
+	"github.com/ysaakpr/rex/internal/services"
+)
+
+// AccessReviewHandler serves access review campaigns to tenant admins, under
+// /tenants/:id, and to platform admins, under /platform, where campaigns can
+// cover several tenants
+type AccessReviewHandler struct {
+	reviewService services.AccessReviewService
+}
+
+func NewAccessReviewHandler(reviewService services.AccessReviewService) *AccessReviewHandler {
+	return &AccessReviewHandler{
+		reviewService: reviewService,
+	}
+}
+
+// StartCampaign godoc
+// @Summary Start an access review campaign
+// @Description Snapshots each member's role in the covered tenants, and each role they inherit from a group, as an item for reviewers to certify or revoke, and emails the reviewers. Tenant campaigns cover their own tenant; platform campaigns cover tenant_ids, role_id or both.
+// @Tags access-reviews
+// @Accept json
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param input body models.StartAccessReviewInput true "Campaign scope and reviewers"
+// @Success 201 {object} response.Response{data=models.AccessReviewCampaignResponse}
+// @Router /tenants/{id}/access-reviews [post]
+// @Router /platform/access-reviews [post]
+func (h *AccessReviewHandler) StartCampaign(c *gin.Context) {
+	tenantID, ok := accessReviewScope(c)
+	if !ok {
+		return
+	}
+
+	actorID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var input models.StartAccessReviewInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	campaign, err := h.reviewService.StartCampaign(tenantID, &input, actorID)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	response.Created(c, "Access review started", campaign)
+}
+
+// ListCampaigns godoc
+// @Summary List access review campaigns
+// @Description Tenant admins see the campaigns their tenant started; platform admins see every campaign
+// @Tags access-reviews
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} response.Response{data=[]models.AccessReviewCampaign}
+// @Router /tenants/{id}/access-reviews [get]
+// @Router /platform/access-reviews [get]
+func (h *AccessReviewHandler) ListCampaigns(c *gin.Context) {
+	tenantID, ok := accessReviewScope(c)
+	if !ok {
+		return
+	}
+
+	campaigns, err := h.reviewService.ListCampaigns(tenantID)
+	if err != nil {
+		response.InternalServerError(c, err)
+		return
+	}
+
+	response.OK(c, campaigns)
+}
+
+// GetCampaign godoc
+// @Summary Get an access review campaign with its progress
+// @Tags access-reviews
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param campaign_id path string true "Campaign ID"
+// @Success 200 {object} response.Response{data=models.AccessReviewCampaignResponse}
+// @Router /tenants/{id}/access-reviews/{campaign_id} [get]
+// @Router /platform/access-reviews/{campaign_id} [get]
+func (h *AccessReviewHandler) GetCampaign(c *gin.Context) {
+	tenantID, campaignID, ok := accessReviewCampaignParams(c)
+	if !ok {
+		return
+	}
+
+	campaign, err := h.reviewService.GetCampaign(tenantID, campaignID)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.OK(c, campaign)
+}
+
+// ListItems godoc
+// @Summary List a campaign's review items
+// @Tags access-reviews
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param campaign_id path string true "Campaign ID"
+// @Param decision query string false "pending, certified or revoked"
+// @Success 200 {object} response.Response{data=[]models.AccessReviewItem}
+// @Router /tenants/{id}/access-reviews/{campaign_id}/items [get]
+// @Router /platform/access-reviews/{campaign_id}/items [get]
+func (h *AccessReviewHandler) ListItems(c *gin.Context) {
+	tenantID, campaignID, ok := accessReviewCampaignParams(c)
+	if !ok {
+		return
+	}
+
+	var params models.AccessReviewItemParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	items, err := h.reviewService.ListItems(tenantID, campaignID, &params)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.OK(c, items)
+}
+
+// CloseCampaign godoc
+// @Summary Close an access review campaign
+// @Description Stops further decisions, removes the members whose own role was revoked and takes members out of the groups whose role was revoked. Each revoked item records whether the revocation was applied, skipped or failed.
+// @Tags access-reviews
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param campaign_id path string true "Campaign ID"
+// @Success 200 {object} response.Response{data=models.AccessReviewCampaignResponse}
+// @Router /tenants/{id}/access-reviews/{campaign_id}/close [post]
+// @Router /platform/access-reviews/{campaign_id}/close [post]
+func (h *AccessReviewHandler) CloseCampaign(c *gin.Context) {
+	tenantID, campaignID, ok := accessReviewCampaignParams(c)
+	if !ok {
+		return
+	}
+
+	actorID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	campaign, err := h.reviewService.CloseCampaign(tenantID, campaignID, actorID)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Access review closed", campaign)
+}
+
+// ExportReport godoc
+// @Summary Export a signed access review report
+// @Description Downloads the campaign, its summary and every item as JSON. X-Rex-Signature is an HMAC-SHA256 of "<X-Rex-Timestamp>.<body>" with the access review signing secret.
+// @Tags access-reviews
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param campaign_id path string true "Campaign ID"
+// @Success 200 {object} models.AccessReviewReport
+// @Router /tenants/{id}/access-reviews/{campaign_id}/report [get]
+// @Router /platform/access-reviews/{campaign_id}/report [get]
+func (h *AccessReviewHandler) ExportReport(c *gin.Context) {
+	tenantID, campaignID, ok := accessReviewCampaignParams(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.reviewService.ExportReport(tenantID, campaignID)
+	if err != nil {
+		if errors.Is(err, services.ErrReportSigningDisabled) {
+			response.ErrorMessage(c, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	c.Header(signing.SignatureHeader, report.Signature)
+	c.Header(signing.TimestampHeader, strconv.FormatInt(report.SignedAt.Unix(), 10))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="access-review-%s.json"`, campaignID))
+	c.Data(http.StatusOK, "application/json", report.Body)
+}
+
+// ListMyItems godoc
+// @Summary List my access review tasks
+// @Description Lists the undecided items of open campaigns the current user reviews
+// @Tags access-reviews
+// @Produce json
+// @Success 200 {object} response.Response{data=[]models.AccessReviewItem}
+// @Router /users/me/access-reviews [get]
+func (h *AccessReviewHandler) ListMyItems(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	items, err := h.reviewService.ListMyItems(userID)
+	if err != nil {
+		response.InternalServerError(c, err)
+		return
+	}
+
+	response.OK(c, items)
+}
+
+// DecideItem godoc
+// @Summary Certify or revoke an access review item
+// @Description Records the decision; it can be changed until the campaign closes. Reviewers cannot decide on their own access.
+// @Tags access-reviews
+// @Accept json
+// @Produce json
+// @Param item_id path string true "Item ID"
+// @Param input body models.DecideAccessReviewInput true "Decision"
+// @Success 200 {object} response.Response{data=models.AccessReviewItem}
+// @Router /users/me/access-reviews/{item_id} [put]
+func (h *AccessReviewHandler) DecideItem(c *gin.Context) {
+	itemID, err := uuid.Parse(c.Param("item_id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	actorID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var input models.DecideAccessReviewInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	item, err := h.reviewService.DecideItem(itemID, &input, actorID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrAccessReviewForbidden):
+			response.Forbidden(c, err.Error())
+		case errors.Is(err, services.ErrAccessReviewClosed):
+			response.ErrorMessage(c, http.StatusConflict, err.Error())
+		default:
+			response.BadRequest(c, err)
+		}
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Decision recorded", item)
+}
+
+// accessReviewScope returns the tenant of tenant-scoped routes, or nil on
+// platform routes, writing the error response when the ID is invalid
+func accessReviewScope(c *gin.Context) (*uuid.UUID, bool) {
+	param := c.Param("id")
+	if param == "" {
+		return nil, true
+	}
+
+	tenantID, err := uuid.Parse(param)
+	if err != nil {
+		response.BadRequest(c, err)
+		return nil, false
+	}
+	return &tenantID, true
+}
+
+func accessReviewCampaignParams(c *gin.Context) (*uuid.UUID, uuid.UUID, bool) {
+	tenantID, ok := accessReviewScope(c)
+	if !ok {
+		return nil, uuid.Nil, false
+	}
+
+	campaignID, err := uuid.Parse(c.Param("campaign_id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return nil, uuid.Nil, false
+	}
+
+	return tenantID, campaignID, true
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/pkg/response"
+)
+
+// badRequestOrForbidden reports plan quota errors as 403 with the limit that
+// was hit, role grants beyond the actor's own permissions as 403 with the
+// missing permissions, security policy errors as 403 with the setting that
+// blocked the action, and any other error as a bad request
+func badRequestOrForbidden(c *gin.Context, err error) {
+	if lastManagerConflict(c, err) {
+		return
+	}
+
+	var quotaErr *models.QuotaExceededError
+	if errors.As(err, &quotaErr) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   quotaErr.Error(),
+			"details": gin.H{
+				"resource": quotaErr.Resource,
+				"limit":    quotaErr.Limit,
+			},
+		})
+		return
+	}
+
+	var grantErr *models.RoleGrantError
+	if errors.As(err, &grantErr) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   grantErr.Error(),
+			"details": gin.H{
+				"role_id":             grantErr.RoleID,
+				"missing_permissions": grantErr.Missing,
+			},
+		})
+		return
+	}
+
+	var policyErr *models.SecurityPolicyError
+	if errors.As(err, &policyErr) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   policyErr.Error(),
+			"details": gin.H{
+				"setting": policyErr.Setting,
+			},
+		})
+		return
+	}
+
+	response.BadRequest(c, err)
+}
+
+// lastManagerConflict writes a 409 with the last_tenant_manager code when err
+// is a LastManagerError, reporting whether it did
+func lastManagerConflict(c *gin.Context, err error) bool {
+	var managerErr *models.LastManagerError
+	if !errors.As(err, &managerErr) {
+		return false
+	}
+
+	c.JSON(http.StatusConflict, gin.H{
+		"success": false,
+		"error":   managerErr.Error(),
+		"code":    models.LastManagerErrorCode,
+		"details": gin.H{
+			"tenant_ids": managerErr.TenantIDs,
+		},
+	})
+	return true
+}
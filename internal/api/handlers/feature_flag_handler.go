@@ -0,0 +1,303 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/api/middleware"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/pkg/response"
+	"github.com/ysaakpr/rex/internal/services"
+)
+
+type FeatureFlagHandler struct {
+	flagService services.FeatureFlagService
+}
+
+func NewFeatureFlagHandler(flagService services.FeatureFlagService) *FeatureFlagHandler {
+	return &FeatureFlagHandler{
+		flagService: flagService,
+	}
+}
+
+// CreateFlag godoc
+// @Summary Create a feature flag (Platform Admin)
+// @Description Boolean flags get on/off variations and serve off by default
+// @Tags feature-flags
+// @Accept json
+// @Produce json
+// @Param input body models.CreateFeatureFlagInput true "Flag definition"
+// @Success 201 {object} response.Response{data=models.FeatureFlag}
+// @Router /platform/feature-flags [post]
+func (h *FeatureFlagHandler) CreateFlag(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var input models.CreateFeatureFlagInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	flag, err := h.flagService.CreateFlag(&input, userID)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	response.Created(c, "Feature flag created successfully", flag)
+}
+
+// ListFlags godoc
+// @Summary List feature flags (Platform Admin)
+// @Tags feature-flags
+// @Produce json
+// @Success 200 {object} response.Response{data=[]models.FeatureFlag}
+// @Router /platform/feature-flags [get]
+func (h *FeatureFlagHandler) ListFlags(c *gin.Context) {
+	flags, err := h.flagService.ListFlags()
+	if err != nil {
+		response.InternalServerError(c, err)
+		return
+	}
+
+	response.OK(c, flags)
+}
+
+// GetFlag godoc
+// @Summary Get a feature flag (Platform Admin)
+// @Tags feature-flags
+// @Produce json
+// @Param id path string true "Flag ID"
+// @Success 200 {object} response.Response{data=models.FeatureFlag}
+// @Router /platform/feature-flags/{id} [get]
+func (h *FeatureFlagHandler) GetFlag(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	flag, err := h.flagService.GetFlag(id)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.OK(c, flag)
+}
+
+// UpdateFlag godoc
+// @Summary Replace a feature flag's variations and rules (Platform Admin)
+// @Tags feature-flags
+// @Accept json
+// @Produce json
+// @Param id path string true "Flag ID"
+// @Param input body models.UpdateFeatureFlagInput true "Flag definition"
+// @Success 200 {object} response.Response{data=models.FeatureFlag}
+// @Router /platform/feature-flags/{id} [put]
+func (h *FeatureFlagHandler) UpdateFlag(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var input models.UpdateFeatureFlagInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	flag, err := h.flagService.UpdateFlag(id, &input, userID)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	response.OK(c, flag)
+}
+
+// DeleteFlag godoc
+// @Summary Delete a feature flag (Platform Admin)
+// @Description The flag's audit log is kept
+// @Tags feature-flags
+// @Param id path string true "Flag ID"
+// @Success 204
+// @Router /platform/feature-flags/{id} [delete]
+func (h *FeatureFlagHandler) DeleteFlag(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	if err := h.flagService.DeleteFlag(id, userID); err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// KillFlag godoc
+// @Summary Kill a feature flag (Platform Admin)
+// @Description Serves the off variation to every tenant and user until the flag is restored
+// @Tags feature-flags
+// @Accept json
+// @Produce json
+// @Param id path string true "Flag ID"
+// @Param input body models.FeatureFlagKillInput false "Optional reason"
+// @Success 200 {object} response.Response{data=models.FeatureFlag}
+// @Router /platform/feature-flags/{id}/kill [post]
+func (h *FeatureFlagHandler) KillFlag(c *gin.Context) {
+	h.setKilled(c, true)
+}
+
+// RestoreFlag godoc
+// @Summary Restore a killed feature flag (Platform Admin)
+// @Tags feature-flags
+// @Accept json
+// @Produce json
+// @Param id path string true "Flag ID"
+// @Param input body models.FeatureFlagKillInput false "Optional reason"
+// @Success 200 {object} response.Response{data=models.FeatureFlag}
+// @Router /platform/feature-flags/{id}/restore [post]
+func (h *FeatureFlagHandler) RestoreFlag(c *gin.Context) {
+	h.setKilled(c, false)
+}
+
+func (h *FeatureFlagHandler) setKilled(c *gin.Context, killed bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var input models.FeatureFlagKillInput
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			response.BadRequest(c, err)
+			return
+		}
+	}
+
+	var flag *models.FeatureFlag
+	if killed {
+		flag, err = h.flagService.KillFlag(id, input.Reason, userID)
+	} else {
+		flag, err = h.flagService.RestoreFlag(id, input.Reason, userID)
+	}
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	response.OK(c, flag)
+}
+
+// ListAuditLogs godoc
+// @Summary List changes to a feature flag (Platform Admin)
+// @Tags feature-flags
+// @Produce json
+// @Param id path string true "Flag ID"
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} response.Response{data=models.PaginatedResponse}
+// @Router /platform/feature-flags/{id}/audit [get]
+func (h *FeatureFlagHandler) ListAuditLogs(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	var pagination models.PaginationParams
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	entries, total, err := h.flagService.ListAuditLogs(id, &pagination)
+	if err != nil {
+		response.InternalServerError(c, err)
+		return
+	}
+
+	response.OK(c, paginate(entries, &pagination, total))
+}
+
+// EvaluateFlags godoc
+// @Summary Evaluate feature flags for the current user
+// @Description Returns every flag's value for the calling user in the tenant, keyed by flag key
+// @Tags feature-flags
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} response.Response{data=map[string]models.FlagEvaluation}
+// @Router /tenants/{id}/feature-flags [get]
+func (h *FeatureFlagHandler) EvaluateFlags(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	evaluations, err := h.flagService.Evaluate(tenantID, userID)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.OK(c, evaluations)
+}
+
+// EvaluateFlagsForUser godoc
+// @Summary Evaluate feature flags for any user in a tenant (Platform Admin)
+// @Description Omit user_id to evaluate for the tenant alone
+// @Tags feature-flags
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param user_id query string false "User ID"
+// @Success 200 {object} response.Response{data=map[string]models.FlagEvaluation}
+// @Router /platform/tenants/{id}/feature-flags [get]
+func (h *FeatureFlagHandler) EvaluateFlagsForUser(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	evaluations, err := h.flagService.Evaluate(tenantID, c.Query("user_id"))
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.OK(c, evaluations)
+}
@@ -67,7 +67,7 @@ func (h *InvitationHandler) CreateInvitation(c *gin.Context) {
 
 	invitation, err := h.invitationService.CreateInvitation(tenantID, &input, userID)
 	if err != nil {
-		response.BadRequest(c, err)
+		badRequestOrForbidden(c, err)
 		return
 	}
 
@@ -177,7 +177,7 @@ func (h *InvitationHandler) AcceptInvitation(c *gin.Context) {
 
 	member, err := h.invitationService.AcceptInvitation(token, userID, userEmail)
 	if err != nil {
-		response.BadRequest(c, err)
+		badRequestOrForbidden(c, err)
 		return
 	}
 
@@ -186,6 +186,7 @@ func (h *InvitationHandler) AcceptInvitation(c *gin.Context) {
 
 // CheckPendingInvitations godoc
 // @Summary Check and auto-accept pending invitations for current user
+// @Description Also joins tenants that verified the user's email domain, either as an active member or pending admin approval
 // @Tags invitations
 // @Produce json
 // @Success 200 {object} response.Response{data=[]models.MemberResponse}
@@ -211,14 +212,19 @@ func (h *InvitationHandler) CheckPendingInvitations(c *gin.Context) {
 	}
 
 	// Convert to response format
+	acceptedCount := 0
 	memberResponses := make([]*models.MemberResponse, len(members))
 	for i, member := range members {
+		if member.Status == models.MemberStatusActive {
+			acceptedCount++
+		}
 		memberResponses[i] = member.ToResponse()
 	}
 
 	response.OK(c, gin.H{
-		"accepted_count": len(members),
-		"memberships":    memberResponses,
+		"accepted_count":         acceptedCount,
+		"pending_approval_count": len(members) - acceptedCount,
+		"memberships":            memberResponses,
 	})
 }
 
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/api/middleware"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/pkg/response"
+	"github.com/ysaakpr/rex/internal/services"
+)
+
+// maxMemberBulkCSVSize is the largest CSV upload accepted, comfortably above
+// MaxMemberBulkRows rows of email and role
+const maxMemberBulkCSVSize = 2 << 20
+
+type MemberBulkHandler struct {
+	bulkService services.MemberBulkService
+}
+
+func NewMemberBulkHandler(bulkService services.MemberBulkService) *MemberBulkHandler {
+	return &MemberBulkHandler{
+		bulkService: bulkService,
+	}
+}
+
+// CreateOperation godoc
+// @Summary Add, update or remove members in bulk
+// @Description Queues a bulk operation. Send JSON with operation, dry_run and rows, or a multipart form with a CSV file (columns user_id or email, and role) plus operation and dry_run fields. Poll the returned operation for per-row results.
+// @Tags members
+// @Accept json,mpfd
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param input body models.MemberBulkOperationInput false "Bulk operation (JSON)"
+// @Param file formData file false "CSV of members (multipart)"
+// @Success 202 {object} response.Response{data=models.MemberBulkOperation}
+// @Router /tenants/{id}/members/bulk [post]
+func (h *MemberBulkHandler) CreateOperation(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	var input models.MemberBulkOperationInput
+	if c.ContentType() == "multipart/form-data" {
+		err = bindMemberBulkCSV(c, &input)
+	} else {
+		err = c.ShouldBindJSON(&input)
+	}
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	operation, err := h.bulkService.CreateOperation(tenantID, &input, userID)
+	if err != nil {
+		badRequestOrForbidden(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusAccepted, "Bulk operation queued", operation)
+}
+
+// bindMemberBulkCSV reads the operation and dry_run form fields and the rows
+// of the uploaded CSV file
+func bindMemberBulkCSV(c *gin.Context, input *models.MemberBulkOperationInput) error {
+	if err := c.ShouldBind(input); err != nil {
+		return err
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		return fmt.Errorf("a CSV file is required: %w", err)
+	}
+	if header.Size > maxMemberBulkCSVSize {
+		return fmt.Errorf("the CSV file must be at most %d MB", maxMemberBulkCSVSize>>20)
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open CSV file: %w", err)
+	}
+	defer file.Close()
+
+	input.Rows, err = models.ParseMemberBulkCSV(file)
+	return err
+}
+
+// ListOperations godoc
+// @Summary List bulk member operations
+// @Description Results are omitted; get a single operation for them
+// @Tags members
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} response.Response{data=models.PaginatedResponse}
+// @Router /tenants/{id}/members/bulk [get]
+func (h *MemberBulkHandler) ListOperations(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	var pagination models.PaginationParams
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	operations, total, err := h.bulkService.ListOperations(tenantID, &pagination)
+	if err != nil {
+		response.InternalServerError(c, err)
+		return
+	}
+
+	response.OK(c, paginate(operations, &pagination, total))
+}
+
+// GetOperation godoc
+// @Summary Get bulk member operation status and results
+// @Tags members
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param operation_id path string true "Operation ID"
+// @Success 200 {object} response.Response{data=models.MemberBulkOperation}
+// @Router /tenants/{id}/members/bulk/{operation_id} [get]
+func (h *MemberBulkHandler) GetOperation(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	operationID, err := uuid.Parse(c.Param("operation_id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	operation, err := h.bulkService.GetOperation(tenantID, operationID)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.OK(c, operation)
+}
@@ -50,7 +50,7 @@ func (h *MemberHandler) AddMember(c *gin.Context) {
 
 	member, err := h.memberService.AddMember(tenantID, &input, userID)
 	if err != nil {
-		response.BadRequest(c, err)
+		badRequestOrForbidden(c, err)
 		return
 	}
 
@@ -109,6 +109,37 @@ func (h *MemberHandler) ListMembers(c *gin.Context) {
 	response.OK(c, result)
 }
 
+// GetActivitySummary godoc
+// @Summary Member activity summary
+// @Description Counts active members seen in the last 7, 30 and 90 days and lists members with no activity for dormant_days (default 90)
+// @Tags members
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param dormant_days query int false "Days without activity that make a member dormant"
+// @Success 200 {object} response.Response{data=models.MemberActivitySummary}
+// @Router /tenants/{tenant_id}/members/activity [get]
+func (h *MemberHandler) GetActivitySummary(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	var params models.MemberActivityParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	summary, err := h.memberService.GetActivitySummary(tenantID, &params)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.OK(c, summary)
+}
+
 // GetMember godoc
 // @Summary Get member details
 // @Tags members
@@ -163,15 +194,21 @@ func (h *MemberHandler) UpdateMember(c *gin.Context) {
 		return
 	}
 
+	actorID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
 	var input models.UpdateMemberInput
 	if err := c.ShouldBindJSON(&input); err != nil {
 		response.BadRequest(c, err)
 		return
 	}
 
-	updatedMember, err := h.memberService.UpdateMember(member.ID, &input)
+	updatedMember, err := h.memberService.UpdateMember(member.ID, &input, actorID)
 	if err != nil {
-		response.BadRequest(c, err)
+		badRequestOrForbidden(c, err)
 		return
 	}
 
@@ -203,13 +240,104 @@ func (h *MemberHandler) RemoveMember(c *gin.Context) {
 	}
 
 	if err := h.memberService.RemoveMember(member.ID); err != nil {
-		response.BadRequest(c, err)
+		badRequestOrForbidden(c, err)
 		return
 	}
 
 	response.NoContent(c)
 }
 
+// SuspendMember godoc
+// @Summary Suspend a member
+// @Description Suspends an active member with a reason. An optional reactivate_at schedules automatic reactivation. The user's sessions are revoked when they have no other active tenant access.
+// @Tags members
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param user_id path string true "User ID"
+// @Param input body models.SuspendMemberInput true "Suspension"
+// @Success 200 {object} response.Response{data=models.MemberResponse}
+// @Router /tenants/{tenant_id}/members/{user_id}/suspend [post]
+func (h *MemberHandler) SuspendMember(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	member, err := h.memberService.GetMember(tenantID, c.Param("user_id"))
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	var input models.SuspendMemberInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	actorID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	suspended, err := h.memberService.SuspendMember(member.ID, &input, actorID)
+	if err != nil {
+		badRequestOrForbidden(c, err)
+		return
+	}
+
+	response.OK(c, suspended.ToResponse())
+}
+
+// ReactivateMember godoc
+// @Summary Reactivate a suspended member
+// @Tags members
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param user_id path string true "User ID"
+// @Param input body models.ReactivateMemberInput false "Reactivation"
+// @Success 200 {object} response.Response{data=models.MemberResponse}
+// @Router /tenants/{tenant_id}/members/{user_id}/reactivate [post]
+func (h *MemberHandler) ReactivateMember(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	member, err := h.memberService.GetMember(tenantID, c.Param("user_id"))
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	var input models.ReactivateMemberInput
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			response.BadRequest(c, err)
+			return
+		}
+	}
+
+	actorID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	reactivated, err := h.memberService.ReactivateMember(member.ID, &input, actorID)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	response.OK(c, reactivated.ToResponse())
+}
+
 // AssignRoles godoc
 // @Summary Assign roles to member
 // @Tags members
@@ -237,6 +365,12 @@ func (h *MemberHandler) AssignRoles(c *gin.Context) {
 		return
 	}
 
+	actorID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
 	var input struct {
 		RoleIDs []uuid.UUID `json:"role_ids" binding:"required"`
 	}
@@ -245,8 +379,8 @@ func (h *MemberHandler) AssignRoles(c *gin.Context) {
 		return
 	}
 
-	if err := h.memberService.AssignRolesToMember(member.ID, input.RoleIDs); err != nil {
-		response.BadRequest(c, err)
+	if err := h.memberService.AssignRolesToMember(member.ID, input.RoleIDs, actorID); err != nil {
+		badRequestOrForbidden(c, err)
 		return
 	}
 
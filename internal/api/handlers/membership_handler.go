@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/api/middleware"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/pkg/response"
+	"github.com/ysaakpr/rex/internal/services"
+)
+
+type MembershipHandler struct {
+	membershipService services.MembershipService
+}
+
+func NewMembershipHandler(membershipService services.MembershipService) *MembershipHandler {
+	return &MembershipHandler{
+		membershipService: membershipService,
+	}
+}
+
+// ListMyMemberships godoc
+// @Summary List my memberships
+// @Description Lists the current user's active memberships with their role, effective permissions (including those from groups) and default tenant, for building a tenant switcher
+// @Tags memberships
+// @Produce json
+// @Success 200 {object} response.Response{data=models.MyMemberships}
+// @Router /users/me/memberships [get]
+func (h *MembershipHandler) ListMyMemberships(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	memberships, err := h.membershipService.ListMyMemberships(userID)
+	if err != nil {
+		response.InternalServerError(c, err)
+		return
+	}
+
+	response.OK(c, memberships)
+}
+
+// LeaveTenant godoc
+// @Summary Leave a tenant
+// @Description Removes the current user's membership. The owner must transfer ownership first, and the tenant's last manager cannot leave.
+// @Tags memberships
+// @Param tenant_id path string true "Tenant ID"
+// @Success 204
+// @Failure 409 {object} response.Response
+// @Router /users/me/memberships/{tenant_id} [delete]
+func (h *MembershipHandler) LeaveTenant(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("tenant_id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	if err := h.membershipService.LeaveTenant(userID, tenantID); err != nil {
+		badRequestOrForbidden(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// SetDefaultTenant godoc
+// @Summary Set my default tenant
+// @Description Stores the tenant the frontend opens first in the current user's profile; a null tenant_id clears it
+// @Tags memberships
+// @Accept json
+// @Produce json
+// @Param input body models.SetDefaultTenantInput true "Default tenant"
+// @Success 200 {object} response.Response{data=models.UserProfile}
+// @Router /users/me/memberships/default [put]
+func (h *MembershipHandler) SetDefaultTenant(c *gin.Context) {
+	var input models.SetDefaultTenantInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	profile, err := h.membershipService.SetDefaultTenant(userID, input.TenantID)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	response.OK(c, profile)
+}
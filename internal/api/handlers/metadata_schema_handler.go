@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/api/middleware"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/pkg/response"
+	"github.com/ysaakpr/rex/internal/services"
+)
+
+type MetadataSchemaHandler struct {
+	schemaService services.MetadataSchemaService
+}
+
+func NewMetadataSchemaHandler(schemaService services.MetadataSchemaService) *MetadataSchemaHandler {
+	return &MetadataSchemaHandler{
+		schemaService: schemaService,
+	}
+}
+
+// CreateSchema godoc
+// @Summary Register a new tenant metadata schema version (platform admins only)
+// @Tags metadata-schemas
+// @Accept json
+// @Produce json
+// @Param input body models.CreateMetadataSchemaInput true "JSON Schema, optionally for one tenant type"
+// @Success 201 {object} response.Response{data=models.TenantMetadataSchema}
+// @Router /platform/metadata-schemas [post]
+func (h *MetadataSchemaHandler) CreateSchema(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var input models.CreateMetadataSchemaInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	schema, err := h.schemaService.CreateSchema(&input, userID)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	response.Created(c, "Metadata schema created successfully", schema)
+}
+
+// ListSchemas godoc
+// @Summary List tenant metadata schema versions (platform admins only)
+// @Tags metadata-schemas
+// @Produce json
+// @Param tenant_type query string false "Only versions for this tenant type"
+// @Success 200 {object} response.Response{data=[]models.TenantMetadataSchema}
+// @Router /platform/metadata-schemas [get]
+func (h *MetadataSchemaHandler) ListSchemas(c *gin.Context) {
+	var tenantType *string
+	if value, ok := c.GetQuery("tenant_type"); ok && value != "" {
+		tenantType = &value
+	}
+
+	schemas, err := h.schemaService.ListSchemas(tenantType)
+	if err != nil {
+		response.InternalServerError(c, err)
+		return
+	}
+
+	response.OK(c, schemas)
+}
+
+// GetSchema godoc
+// @Summary Get a tenant metadata schema version (platform admins only)
+// @Tags metadata-schemas
+// @Produce json
+// @Param id path string true "Schema ID"
+// @Success 200 {object} response.Response{data=models.TenantMetadataSchema}
+// @Router /platform/metadata-schemas/{id} [get]
+func (h *MetadataSchemaHandler) GetSchema(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	schema, err := h.schemaService.GetSchema(id)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.OK(c, schema)
+}
+
+// ActivateSchema godoc
+// @Summary Make a schema version the active one for its tenant type (platform admins only)
+// @Tags metadata-schemas
+// @Produce json
+// @Param id path string true "Schema ID"
+// @Success 200 {object} response.Response{data=models.TenantMetadataSchema}
+// @Router /platform/metadata-schemas/{id}/activate [post]
+func (h *MetadataSchemaHandler) ActivateSchema(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	schema, err := h.schemaService.ActivateSchema(id, userID)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	response.Success(c, 200, "Metadata schema activated successfully", schema)
+}
+
+// StartRevalidation godoc
+// @Summary Re-validate all tenants' metadata in the background (platform admins only)
+// @Tags metadata-schemas
+// @Produce json
+// @Success 202 {object} response.Response{data=models.MetadataValidationRun}
+// @Router /platform/metadata-schemas/revalidations [post]
+func (h *MetadataSchemaHandler) StartRevalidation(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	run, err := h.schemaService.StartRevalidation(userID)
+	if err != nil {
+		response.InternalServerError(c, err)
+		return
+	}
+
+	response.Success(c, 202, "Metadata revalidation started", run)
+}
+
+// ListRevalidationRuns godoc
+// @Summary List metadata revalidation runs (platform admins only)
+// @Tags metadata-schemas
+// @Produce json
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} response.Response{data=models.PaginatedResponse}
+// @Router /platform/metadata-schemas/revalidations [get]
+func (h *MetadataSchemaHandler) ListRevalidationRuns(c *gin.Context) {
+	var pagination models.PaginationParams
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	runs, total, err := h.schemaService.ListRevalidationRuns(&pagination)
+	if err != nil {
+		response.InternalServerError(c, err)
+		return
+	}
+
+	response.OK(c, paginate(runs, &pagination, total))
+}
+
+// GetRevalidationRun godoc
+// @Summary Get a revalidation run with its non-conforming tenants (platform admins only)
+// @Tags metadata-schemas
+// @Produce json
+// @Param run_id path string true "Run ID"
+// @Success 200 {object} response.Response{data=models.MetadataValidationRun}
+// @Router /platform/metadata-schemas/revalidations/{run_id} [get]
+func (h *MetadataSchemaHandler) GetRevalidationRun(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("run_id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	run, err := h.schemaService.GetRevalidationRun(id)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.OK(c, run)
+}
@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/api/middleware"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/pkg/response"
+	"github.com/ysaakpr/rex/internal/services"
+)
+
+type PlanHandler struct {
+	planService services.PlanService
+}
+
+func NewPlanHandler(planService services.PlanService) *PlanHandler {
+	return &PlanHandler{
+		planService: planService,
+	}
+}
+
+// CreatePlan godoc
+// @Summary Create a plan (Platform Admin)
+// @Description Omitted limits are unlimited
+// @Tags platform-admin
+// @Accept json
+// @Produce json
+// @Param input body models.CreatePlanInput true "Plan details"
+// @Success 201 {object} response.Response{data=models.Plan}
+// @Router /platform/plans [post]
+func (h *PlanHandler) CreatePlan(c *gin.Context) {
+	var input models.CreatePlanInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	plan, err := h.planService.CreatePlan(&input)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	response.Created(c, "Plan created successfully", plan)
+}
+
+// ListPlans godoc
+// @Summary List plans (Platform Admin)
+// @Tags platform-admin
+// @Produce json
+// @Success 200 {object} response.Response{data=[]models.Plan}
+// @Router /platform/plans [get]
+func (h *PlanHandler) ListPlans(c *gin.Context) {
+	plans, err := h.planService.ListPlans()
+	if err != nil {
+		response.InternalServerError(c, err)
+		return
+	}
+
+	response.OK(c, plans)
+}
+
+// GetPlan godoc
+// @Summary Get a plan (Platform Admin)
+// @Tags platform-admin
+// @Produce json
+// @Param id path string true "Plan ID"
+// @Success 200 {object} response.Response{data=models.Plan}
+// @Router /platform/plans/{id} [get]
+func (h *PlanHandler) GetPlan(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	plan, err := h.planService.GetPlan(id)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.OK(c, plan)
+}
+
+// UpdatePlan godoc
+// @Summary Update a plan (Platform Admin)
+// @Description Replaces the plan's limits and features; omitted limits become unlimited
+// @Tags platform-admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Plan ID"
+// @Param input body models.UpdatePlanInput true "Plan limits and features"
+// @Success 200 {object} response.Response{data=models.Plan}
+// @Router /platform/plans/{id} [put]
+func (h *PlanHandler) UpdatePlan(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	var input models.UpdatePlanInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	plan, err := h.planService.UpdatePlan(id, &input)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	response.OK(c, plan)
+}
+
+// DeletePlan godoc
+// @Summary Delete a plan (Platform Admin)
+// @Description Plans that are the default or assigned to tenants cannot be deleted
+// @Tags platform-admin
+// @Param id path string true "Plan ID"
+// @Success 204
+// @Router /platform/plans/{id} [delete]
+func (h *PlanHandler) DeletePlan(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	if err := h.planService.DeletePlan(id); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// AssignPlan godoc
+// @Summary Assign a plan to a tenant (Platform Admin)
+// @Tags platform-admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param input body models.AssignPlanInput true "Plan"
+// @Success 200 {object} response.Response{data=models.TenantEntitlements}
+// @Router /platform/tenants/{id}/plan [put]
+func (h *PlanHandler) AssignPlan(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	var input models.AssignPlanInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	entitlements, err := h.planService.AssignPlan(tenantID, input.PlanID)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	response.OK(c, entitlements)
+}
+
+// GetEntitlements godoc
+// @Summary Get tenant entitlements
+// @Description Returns the tenant's plan, effective limits after overrides, current usage and enabled features
+// @Tags tenants
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} response.Response{data=models.TenantEntitlements}
+// @Router /tenants/{id}/entitlements [get]
+func (h *PlanHandler) GetEntitlements(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	entitlements, err := h.planService.GetEntitlements(tenantID)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.OK(c, entitlements)
+}
+
+// SetOverride godoc
+// @Summary Override a tenant's entitlements (Platform Admin)
+// @Description Replaces the tenant's override; omitted limits inherit the plan
+// @Tags platform-admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param input body models.SetPlanOverrideInput true "Override"
+// @Success 200 {object} response.Response{data=models.TenantEntitlements}
+// @Router /platform/tenants/{id}/entitlements [put]
+func (h *PlanHandler) SetOverride(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var input models.SetPlanOverrideInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	entitlements, err := h.planService.SetOverride(tenantID, &input, userID)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	response.OK(c, entitlements)
+}
+
+// ClearOverride godoc
+// @Summary Remove a tenant's entitlement override (Platform Admin)
+// @Tags platform-admin
+// @Param id path string true "Tenant ID"
+// @Success 204
+// @Router /platform/tenants/{id}/entitlements [delete]
+func (h *PlanHandler) ClearOverride(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	if err := h.planService.ClearOverride(tenantID); err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.NoContent(c)
+}
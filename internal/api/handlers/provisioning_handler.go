@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/pkg/response"
+	"github.com/ysaakpr/rex/internal/pkg/signing"
+	"github.com/ysaakpr/rex/internal/services"
+)
+
+type ProvisioningHandler struct {
+	provisioningService services.ProvisioningService
+}
+
+func NewProvisioningHandler(provisioningService services.ProvisioningService) *ProvisioningHandler {
+	return &ProvisioningHandler{
+		provisioningService: provisioningService,
+	}
+}
+
+// GetTenantStatus godoc
+// @Summary Get tenant provisioning status
+// @Description Returns the tenant status and the provisioning progress in each downstream service
+// @Tags tenants
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} response.Response{data=models.TenantProvisioningStatus}
+// @Router /tenants/{id}/status [get]
+func (h *ProvisioningHandler) GetTenantStatus(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	status, err := h.provisioningService.GetStatus(id)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.OK(c, status)
+}
+
+// RetryProvisioning godoc
+// @Summary Retry failed tenant provisioning steps (Platform Admin)
+// @Tags platform-admin
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Success 202 {object} response.Response{data=models.TenantProvisioningStatus}
+// @Router /platform/tenants/{id}/provisioning/retry [post]
+func (h *ProvisioningHandler) RetryProvisioning(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	status, err := h.provisioningService.RetryProvisioning(id)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusAccepted, "Provisioning retry queued", status)
+}
+
+// GetDeprovisioningStatus godoc
+// @Summary Get teardown progress of a deleted tenant (Platform Admin)
+// @Description Tenants past retention stay in the deleting status until every downstream service confirms teardown
+// @Tags platform-admin
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} response.Response{data=models.TenantProvisioningStatus}
+// @Router /platform/tenants/{id}/deprovisioning [get]
+func (h *ProvisioningHandler) GetDeprovisioningStatus(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	status, err := h.provisioningService.GetDeprovisioningStatus(id)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.OK(c, status)
+}
+
+// RetryDeprovisioning godoc
+// @Summary Retry failed tenant teardown steps (Platform Admin)
+// @Tags platform-admin
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Success 202 {object} response.Response{data=models.TenantProvisioningStatus}
+// @Router /platform/tenants/{id}/deprovisioning/retry [post]
+func (h *ProvisioningHandler) RetryDeprovisioning(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	status, err := h.provisioningService.RetryDeprovisioning(id)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusAccepted, "Deprovisioning retry queued", status)
+}
+
+// HandleCallback godoc
+// @Summary Report the outcome of an accepted provisioning request
+// @Description Called by downstream services that answered a provisioning or deprovisioning request with 202. The body must be signed with the service's secret, or the platform provisioning secret (X-Rex-Signature over "<X-Rex-Timestamp>.<body>").
+// @Tags provisioning
+// @Accept json
+// @Produce json
+// @Param X-Rex-Timestamp header string true "Unix timestamp used in the signature"
+// @Param X-Rex-Signature header string true "sha256=<hex HMAC>"
+// @Param input body models.ProvisioningCallbackInput true "Step outcome"
+// @Success 200 {object} response.Response{data=models.TenantProvisioningStep}
+// @Router /provisioning/callback [post]
+func (h *ProvisioningHandler) HandleCallback(c *gin.Context) {
+	body, err := c.GetRawData()
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	var input models.ProvisioningCallbackInput
+	if err := binding.JSON.BindBody(body, &input); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	step, err := h.provisioningService.HandleCallback(
+		&input,
+		body,
+		c.GetHeader(signing.TimestampHeader),
+		c.GetHeader(signing.SignatureHeader),
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidCallbackSignature):
+			response.Unauthorized(c, err.Error())
+		case errors.Is(err, services.ErrCallbacksDisabled):
+			response.ErrorMessage(c, http.StatusServiceUnavailable, err.Error())
+		default:
+			response.BadRequest(c, err)
+		}
+		return
+	}
+
+	response.Success(c, http.StatusOK, "Callback recorded", step)
+}
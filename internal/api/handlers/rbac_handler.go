@@ -33,7 +33,7 @@ func (h *RBACHandler) CreateRole(c *gin.Context) {
 
 	role, err := h.rbacService.CreateRole(&input)
 	if err != nil {
-		response.BadRequest(c, err)
+		badRequestOrForbidden(c, err)
 		return
 	}
 
@@ -111,7 +111,7 @@ func (h *RBACHandler) DeleteRole(c *gin.Context) {
 	}
 
 	if err := h.rbacService.DeleteRole(id); err != nil {
-		response.BadRequest(c, err)
+		badRequestOrForbidden(c, err)
 		return
 	}
 
@@ -209,7 +209,7 @@ func (h *RBACHandler) DeletePolicy(c *gin.Context) {
 	}
 
 	if err := h.rbacService.DeletePolicy(id); err != nil {
-		response.BadRequest(c, err)
+		badRequestOrForbidden(c, err)
 		return
 	}
 
@@ -284,7 +284,7 @@ func (h *RBACHandler) DeletePermission(c *gin.Context) {
 	}
 
 	if err := h.rbacService.DeletePermission(id); err != nil {
-		response.BadRequest(c, err)
+		badRequestOrForbidden(c, err)
 		return
 	}
 
@@ -330,7 +330,7 @@ func (h *RBACHandler) RevokePermissionFromPolicy(c *gin.Context) {
 	}
 
 	if err := h.rbacService.RevokePermissionFromPolicy(policyID, permissionID); err != nil {
-		response.BadRequest(c, err)
+		badRequestOrForbidden(c, err)
 		return
 	}
 
@@ -399,6 +399,45 @@ func (h *RBACHandler) GetUserPermissions(c *gin.Context) {
 	response.OK(c, permissionResponses)
 }
 
+// ExplainPermission godoc
+// @Summary Explain a user's permission
+// @Description Lists every role and policy that gives the user the permission in the tenant, and whether the role is the member's own or granted to one of their groups
+// @Tags rbac
+// @Produce json
+// @Param tenant_id query string true "Tenant ID"
+// @Param user_id query string true "User ID"
+// @Param service query string true "Service"
+// @Param entity query string true "Entity"
+// @Param action query string true "Action"
+// @Success 200 {object} response.Response{data=models.PermissionExplanation}
+// @Router /permissions/explain [get]
+func (h *RBACHandler) ExplainPermission(c *gin.Context) {
+	tenantIDStr := c.Query("tenant_id")
+	userID := c.Query("user_id")
+	service := c.Query("service")
+	entity := c.Query("entity")
+	action := c.Query("action")
+
+	if tenantIDStr == "" || userID == "" || service == "" || entity == "" || action == "" {
+		response.BadRequest(c, errors.New("missing required query parameters"))
+		return
+	}
+
+	tenantID, err := uuid.Parse(tenantIDStr)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	explanation, err := h.rbacService.ExplainUserPermission(tenantID, userID, service, entity, action)
+	if err != nil {
+		response.InternalServerError(c, err)
+		return
+	}
+
+	response.OK(c, explanation)
+}
+
 // ============================================================================
 // Role-Policy assignments (was Relation-Role)
 // ============================================================================
@@ -438,7 +477,7 @@ func (h *RBACHandler) RevokePolicyFromRole(c *gin.Context) {
 	}
 
 	if err := h.rbacService.RevokePolicyFromRole(roleID, policyID); err != nil {
-		response.BadRequest(c, err)
+		badRequestOrForbidden(c, err)
 		return
 	}
 
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/api/middleware"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/pkg/response"
+	"github.com/ysaakpr/rex/internal/services"
+)
+
+type ServiceRegistryHandler struct {
+	registryService services.ServiceRegistryService
+}
+
+func NewServiceRegistryHandler(registryService services.ServiceRegistryService) *ServiceRegistryHandler {
+	return &ServiceRegistryHandler{
+		registryService: registryService,
+	}
+}
+
+// CreateService godoc
+// @Summary Register a downstream service (Platform Admin)
+// @Description Registers a service that tenants are provisioned in
+// @Tags platform-admin
+// @Accept json
+// @Produce json
+// @Param input body models.CreateDownstreamServiceInput true "Service details"
+// @Success 201 {object} response.Response{data=models.DownstreamServiceResponse}
+// @Router /platform/services [post]
+func (h *ServiceRegistryHandler) CreateService(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var input models.CreateDownstreamServiceInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	service, err := h.registryService.CreateService(&input, userID)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	response.Created(c, "Service registered successfully", service.ToResponse())
+}
+
+// ListServices godoc
+// @Summary List downstream services (Platform Admin)
+// @Tags platform-admin
+// @Produce json
+// @Success 200 {object} response.Response{data=[]models.DownstreamServiceResponse}
+// @Router /platform/services [get]
+func (h *ServiceRegistryHandler) ListServices(c *gin.Context) {
+	servicesList, err := h.registryService.ListServices()
+	if err != nil {
+		response.InternalServerError(c, err)
+		return
+	}
+
+	responses := make([]*models.DownstreamServiceResponse, len(servicesList))
+	for i, service := range servicesList {
+		responses[i] = service.ToResponse()
+	}
+
+	response.OK(c, responses)
+}
+
+// GetService godoc
+// @Summary Get a downstream service (Platform Admin)
+// @Tags platform-admin
+// @Produce json
+// @Param id path string true "Service ID"
+// @Success 200 {object} response.Response{data=models.DownstreamServiceResponse}
+// @Router /platform/services/{id} [get]
+func (h *ServiceRegistryHandler) GetService(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	service, err := h.registryService.GetService(id)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.OK(c, service.ToResponse())
+}
+
+// UpdateService godoc
+// @Summary Update a downstream service (Platform Admin)
+// @Tags platform-admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Service ID"
+// @Param input body models.UpdateDownstreamServiceInput true "Fields to change"
+// @Success 200 {object} response.Response{data=models.DownstreamServiceResponse}
+// @Router /platform/services/{id} [patch]
+func (h *ServiceRegistryHandler) UpdateService(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	var input models.UpdateDownstreamServiceInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	service, err := h.registryService.UpdateService(id, &input)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	response.OK(c, service.ToResponse())
+}
+
+// DeleteService godoc
+// @Summary Remove a downstream service (Platform Admin)
+// @Tags platform-admin
+// @Param id path string true "Service ID"
+// @Success 204
+// @Router /platform/services/{id} [delete]
+func (h *ServiceRegistryHandler) DeleteService(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	if err := h.registryService.DeleteService(id); err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.NoContent(c)
+}
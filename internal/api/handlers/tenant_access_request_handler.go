@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/api/middleware"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/pkg/response"
+	"github.com/ysaakpr/rex/internal/services"
+)
+
+type TenantAccessRequestHandler struct {
+	requestService services.TenantAccessRequestService
+}
+
+func NewTenantAccessRequestHandler(requestService services.TenantAccessRequestService) *TenantAccessRequestHandler {
+	return &TenantAccessRequestHandler{
+		requestService: requestService,
+	}
+}
+
+// RequestAccess godoc
+// @Summary Ask to join a tenant
+// @Description Records a request to join the tenant with the slug and emails its admins
+// @Tags access-requests
+// @Accept json
+// @Produce json
+// @Param slug path string true "Tenant slug"
+// @Param input body models.CreateAccessRequestInput false "Message to the tenant admins"
+// @Success 201 {object} response.Response{data=models.TenantAccessRequest}
+// @Router /tenants/by-slug/{slug}/access-requests [post]
+func (h *TenantAccessRequestHandler) RequestAccess(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var input models.CreateAccessRequestInput
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			response.BadRequest(c, err)
+			return
+		}
+	}
+
+	request, err := h.requestService.RequestAccess(c.Param("slug"), userID, &input)
+	if err != nil {
+		badRequestOrForbidden(c, err)
+		return
+	}
+
+	response.Created(c, "Access request sent to the tenant admins", request)
+}
+
+// ListRequests godoc
+// @Summary List the tenant's access requests
+// @Tags access-requests
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param status query string false "pending, approved, denied or cancelled"
+// @Success 200 {object} response.Response{data=[]models.TenantAccessRequest}
+// @Router /tenants/{id}/access-requests [get]
+func (h *TenantAccessRequestHandler) ListRequests(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	var params models.AccessRequestListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	requests, err := h.requestService.ListRequests(tenantID, &params)
+	if err != nil {
+		response.InternalServerError(c, err)
+		return
+	}
+
+	response.OK(c, requests)
+}
+
+// GetRequest godoc
+// @Summary Get an access request
+// @Tags access-requests
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param request_id path string true "Access request ID"
+// @Success 200 {object} response.Response{data=models.TenantAccessRequest}
+// @Router /tenants/{id}/access-requests/{request_id} [get]
+func (h *TenantAccessRequestHandler) GetRequest(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	requestID, err := uuid.Parse(c.Param("request_id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	request, err := h.requestService.GetRequest(tenantID, requestID)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.OK(c, request)
+}
+
+// ApproveRequest godoc
+// @Summary Approve an access request
+// @Description Adds the requester as a member with the chosen role, or the tenant's default role
+// @Tags access-requests
+// @Accept json
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param request_id path string true "Access request ID"
+// @Param input body models.ApproveAccessRequestInput false "Role to grant"
+// @Success 200 {object} response.Response{data=models.TenantAccessRequest}
+// @Router /tenants/{id}/access-requests/{request_id}/approve [post]
+func (h *TenantAccessRequestHandler) ApproveRequest(c *gin.Context) {
+	tenantID, requestID, actorID, ok := accessRequestParams(c)
+	if !ok {
+		return
+	}
+
+	var input models.ApproveAccessRequestInput
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			response.BadRequest(c, err)
+			return
+		}
+	}
+
+	request, err := h.requestService.ApproveRequest(tenantID, requestID, &input, actorID)
+	if err != nil {
+		badRequestOrForbidden(c, err)
+		return
+	}
+
+	response.Success(c, 200, "Access request approved", request)
+}
+
+// DenyRequest godoc
+// @Summary Deny an access request
+// @Tags access-requests
+// @Accept json
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param request_id path string true "Access request ID"
+// @Param input body models.DenyAccessRequestInput false "Reason shown to the requester"
+// @Success 200 {object} response.Response{data=models.TenantAccessRequest}
+// @Router /tenants/{id}/access-requests/{request_id}/deny [post]
+func (h *TenantAccessRequestHandler) DenyRequest(c *gin.Context) {
+	tenantID, requestID, actorID, ok := accessRequestParams(c)
+	if !ok {
+		return
+	}
+
+	var input models.DenyAccessRequestInput
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			response.BadRequest(c, err)
+			return
+		}
+	}
+
+	request, err := h.requestService.DenyRequest(tenantID, requestID, &input, actorID)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	response.Success(c, 200, "Access request denied", request)
+}
+
+// ListMyRequests godoc
+// @Summary List my access requests
+// @Description Lists the current user's requests to join tenants and how each was resolved
+// @Tags access-requests
+// @Produce json
+// @Success 200 {object} response.Response{data=[]models.MyAccessRequest}
+// @Router /users/me/access-requests [get]
+func (h *TenantAccessRequestHandler) ListMyRequests(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	requests, err := h.requestService.ListMyRequests(userID)
+	if err != nil {
+		response.InternalServerError(c, err)
+		return
+	}
+
+	myRequests := make([]*models.MyAccessRequest, len(requests))
+	for i, request := range requests {
+		myRequests[i] = request.ToMyAccessRequest()
+	}
+
+	response.OK(c, myRequests)
+}
+
+// CancelMyRequest godoc
+// @Summary Withdraw one of my pending access requests
+// @Tags access-requests
+// @Produce json
+// @Param request_id path string true "Access request ID"
+// @Success 200 {object} response.Response{data=models.TenantAccessRequest}
+// @Router /users/me/access-requests/{request_id} [delete]
+func (h *TenantAccessRequestHandler) CancelMyRequest(c *gin.Context) {
+	requestID, err := uuid.Parse(c.Param("request_id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	request, err := h.requestService.CancelMyRequest(requestID, userID)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	response.Success(c, 200, "Access request cancelled", request)
+}
+
+// accessRequestParams parses the tenant and request IDs and the acting user,
+// writing the error response when one is missing
+func accessRequestParams(c *gin.Context) (uuid.UUID, uuid.UUID, string, bool) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return uuid.Nil, uuid.Nil, "", false
+	}
+
+	requestID, err := uuid.Parse(c.Param("request_id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return uuid.Nil, uuid.Nil, "", false
+	}
+
+	actorID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return uuid.Nil, uuid.Nil, "", false
+	}
+
+	return tenantID, requestID, actorID, true
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/api/middleware"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/pkg/response"
+	"github.com/ysaakpr/rex/internal/services"
+)
+
+type TenantDomainHandler struct {
+	domainService services.TenantDomainService
+}
+
+func NewTenantDomainHandler(domainService services.TenantDomainService) *TenantDomainHandler {
+	return &TenantDomainHandler{
+		domainService: domainService,
+	}
+}
+
+// AddDomain godoc
+// @Summary Register a custom domain for the tenant
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param input body models.AddTenantDomainInput true "Domain"
+// @Success 201 {object} response.Response{data=models.TenantDomainResponse}
+// @Router /tenants/{id}/domains [post]
+func (h *TenantDomainHandler) AddDomain(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var input models.AddTenantDomainInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	domain, err := h.domainService.AddDomain(tenantID, input.Domain, userID)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	response.Created(c, "Domain added, publish the verification record and verify it", domain.ToResponse(h.domainService.VerificationRecord(domain)))
+}
+
+// ListDomains godoc
+// @Summary List the tenant's custom domains
+// @Tags tenants
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} response.Response{data=[]models.TenantDomainResponse}
+// @Router /tenants/{id}/domains [get]
+func (h *TenantDomainHandler) ListDomains(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	domains, err := h.domainService.ListDomains(tenantID)
+	if err != nil {
+		response.InternalServerError(c, err)
+		return
+	}
+
+	domainResponses := make([]*models.TenantDomainResponse, len(domains))
+	for i, domain := range domains {
+		domainResponses[i] = domain.ToResponse(h.domainService.VerificationRecord(domain))
+	}
+
+	response.OK(c, domainResponses)
+}
+
+// VerifyDomain godoc
+// @Summary Verify domain ownership through its DNS TXT record
+// @Tags tenants
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param domain_id path string true "Domain ID"
+// @Success 200 {object} response.Response{data=models.TenantDomainResponse}
+// @Router /tenants/{id}/domains/{domain_id}/verify [post]
+func (h *TenantDomainHandler) VerifyDomain(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	domainID, err := uuid.Parse(c.Param("domain_id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	domain, err := h.domainService.VerifyDomain(tenantID, domainID)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	response.Success(c, 200, "Domain verified successfully", domain.ToResponse(nil))
+}
+
+// RemoveDomain godoc
+// @Summary Remove a custom domain from the tenant
+// @Tags tenants
+// @Param id path string true "Tenant ID"
+// @Param domain_id path string true "Domain ID"
+// @Success 200 {object} response.Response
+// @Router /tenants/{id}/domains/{domain_id} [delete]
+func (h *TenantDomainHandler) RemoveDomain(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	domainID, err := uuid.Parse(c.Param("domain_id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	if err := h.domainService.RemoveDomain(tenantID, domainID); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	response.OK(c, gin.H{"message": "Domain removed successfully"})
+}
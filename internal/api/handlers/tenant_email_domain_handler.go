@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/api/middleware"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/pkg/response"
+	"github.com/ysaakpr/rex/internal/services"
+)
+
+type TenantEmailDomainHandler struct {
+	emailDomainService services.TenantEmailDomainService
+}
+
+func NewTenantEmailDomainHandler(emailDomainService services.TenantEmailDomainService) *TenantEmailDomainHandler {
+	return &TenantEmailDomainHandler{
+		emailDomainService: emailDomainService,
+	}
+}
+
+// AddEmailDomain godoc
+// @Summary Register an email domain whose users can join the tenant
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param input body models.AddTenantEmailDomainInput true "Email domain"
+// @Success 201 {object} response.Response{data=models.TenantEmailDomainResponse}
+// @Router /tenants/{id}/email-domains [post]
+func (h *TenantEmailDomainHandler) AddEmailDomain(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var input models.AddTenantEmailDomainInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	domain, err := h.emailDomainService.AddDomain(tenantID, &input, userID)
+	if err != nil {
+		badRequestOrForbidden(c, err)
+		return
+	}
+
+	response.Created(c, "Email domain added, publish the verification record and verify it", domain.ToResponse(h.emailDomainService.VerificationRecord(domain)))
+}
+
+// ListEmailDomains godoc
+// @Summary List the tenant's email domains
+// @Tags tenants
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} response.Response{data=[]models.TenantEmailDomainResponse}
+// @Router /tenants/{id}/email-domains [get]
+func (h *TenantEmailDomainHandler) ListEmailDomains(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	domains, err := h.emailDomainService.ListDomains(tenantID)
+	if err != nil {
+		response.InternalServerError(c, err)
+		return
+	}
+
+	domainResponses := make([]*models.TenantEmailDomainResponse, len(domains))
+	for i, domain := range domains {
+		domainResponses[i] = domain.ToResponse(h.emailDomainService.VerificationRecord(domain))
+	}
+
+	response.OK(c, domainResponses)
+}
+
+// UpdateEmailDomain godoc
+// @Summary Change the role or join mode of an email domain
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param domain_id path string true "Email domain ID"
+// @Param input body models.UpdateTenantEmailDomainInput true "Email domain settings"
+// @Success 200 {object} response.Response{data=models.TenantEmailDomainResponse}
+// @Router /tenants/{id}/email-domains/{domain_id} [patch]
+func (h *TenantEmailDomainHandler) UpdateEmailDomain(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	domainID, err := uuid.Parse(c.Param("domain_id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var input models.UpdateTenantEmailDomainInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	domain, err := h.emailDomainService.UpdateDomain(tenantID, domainID, &input, userID)
+	if err != nil {
+		badRequestOrForbidden(c, err)
+		return
+	}
+
+	response.OK(c, domain.ToResponse(h.emailDomainService.VerificationRecord(domain)))
+}
+
+// VerifyEmailDomain godoc
+// @Summary Verify email domain ownership through its DNS TXT record
+// @Tags tenants
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param domain_id path string true "Email domain ID"
+// @Success 200 {object} response.Response{data=models.TenantEmailDomainResponse}
+// @Router /tenants/{id}/email-domains/{domain_id}/verify [post]
+func (h *TenantEmailDomainHandler) VerifyEmailDomain(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	domainID, err := uuid.Parse(c.Param("domain_id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	domain, err := h.emailDomainService.VerifyDomain(tenantID, domainID)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	response.Success(c, 200, "Email domain verified successfully", domain.ToResponse(nil))
+}
+
+// RemoveEmailDomain godoc
+// @Summary Remove an email domain from the tenant
+// @Tags tenants
+// @Param id path string true "Tenant ID"
+// @Param domain_id path string true "Email domain ID"
+// @Success 200 {object} response.Response
+// @Router /tenants/{id}/email-domains/{domain_id} [delete]
+func (h *TenantEmailDomainHandler) RemoveEmailDomain(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	domainID, err := uuid.Parse(c.Param("domain_id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	if err := h.emailDomainService.RemoveDomain(tenantID, domainID); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	response.OK(c, gin.H{"message": "Email domain removed successfully"})
+}
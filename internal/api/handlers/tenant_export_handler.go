@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/api/middleware"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/pkg/response"
+	"github.com/ysaakpr/rex/internal/services"
+)
+
+type TenantExportHandler struct {
+	exportService services.TenantExportService
+}
+
+func NewTenantExportHandler(exportService services.TenantExportService) *TenantExportHandler {
+	return &TenantExportHandler{
+		exportService: exportService,
+	}
+}
+
+// RequestExport godoc
+// @Summary Export tenant data
+// @Description Queues an archive of the tenant record, members, invitations, roles and policies
+// @Tags tenants
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Success 202 {object} response.Response{data=models.TenantExport}
+// @Router /tenants/{id}/exports [post]
+func (h *TenantExportHandler) RequestExport(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	export, err := h.exportService.RequestExport(tenantID, userID)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	response.Success(c, http.StatusAccepted, "Export queued", export)
+}
+
+// ListExports godoc
+// @Summary List tenant exports
+// @Tags tenants
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} response.Response{data=models.PaginatedResponse}
+// @Router /tenants/{id}/exports [get]
+func (h *TenantExportHandler) ListExports(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	var pagination models.PaginationParams
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	exports, total, err := h.exportService.ListExports(tenantID, &pagination)
+	if err != nil {
+		response.InternalServerError(c, err)
+		return
+	}
+
+	response.OK(c, paginate(exports, &pagination, total))
+}
+
+// GetExport godoc
+// @Summary Get tenant export progress
+// @Tags tenants
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param export_id path string true "Export ID"
+// @Success 200 {object} response.Response{data=models.TenantExport}
+// @Router /tenants/{id}/exports/{export_id} [get]
+func (h *TenantExportHandler) GetExport(c *gin.Context) {
+	tenantID, exportID, ok := parseExportParams(c)
+	if !ok {
+		return
+	}
+
+	export, err := h.exportService.GetExport(tenantID, exportID)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.OK(c, export)
+}
+
+// CreateDownloadLink godoc
+// @Summary Create a download link for a tenant export
+// @Description The link needs no authentication and expires after TENANT_EXPORT_LINK_TTL_MINUTES; creating a new link revokes the previous one
+// @Tags tenants
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param export_id path string true "Export ID"
+// @Success 201 {object} response.Response{data=models.TenantExportLink}
+// @Router /tenants/{id}/exports/{export_id}/download-link [post]
+func (h *TenantExportHandler) CreateDownloadLink(c *gin.Context) {
+	tenantID, exportID, ok := parseExportParams(c)
+	if !ok {
+		return
+	}
+
+	link, err := h.exportService.CreateDownloadLink(tenantID, exportID)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	response.Created(c, "Download link created", link)
+}
+
+// Download godoc
+// @Summary Download a tenant export
+// @Description Public endpoint authenticated by the link token
+// @Tags tenants
+// @Produce application/zip
+// @Param token path string true "Download token"
+// @Success 200 {file} file
+// @Router /exports/download/{token} [get]
+func (h *TenantExportHandler) Download(c *gin.Context) {
+	export, err := h.exportService.ResolveDownload(c.Param("token"))
+	if err != nil {
+		if errors.Is(err, services.ErrExportLinkInvalid) {
+			response.NotFound(c, err.Error())
+			return
+		}
+		response.InternalServerError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.FileAttachment(export.FilePath, fmt.Sprintf("tenant-%s-export-%s.zip", export.TenantID, export.CreatedAt.Format("20060102-150405")))
+}
+
+func parseExportParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	exportID, err := uuid.Parse(c.Param("export_id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return tenantID, exportID, true
+}
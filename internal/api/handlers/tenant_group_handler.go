@@ -0,0 +1,352 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/api/middleware"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/pkg/response"
+	"github.com/ysaakpr/rex/internal/services"
+)
+
+type TenantGroupHandler struct {
+	groupService services.TenantGroupService
+}
+
+func NewTenantGroupHandler(groupService services.TenantGroupService) *TenantGroupHandler {
+	return &TenantGroupHandler{
+		groupService: groupService,
+	}
+}
+
+// CreateGroup godoc
+// @Summary Create a group
+// @Description Creates a team within the tenant. Members of the group inherit the roles granted to it.
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param input body models.CreateTenantGroupInput true "Group"
+// @Success 201 {object} response.Response{data=models.TenantGroup}
+// @Router /tenants/{id}/groups [post]
+func (h *TenantGroupHandler) CreateGroup(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	var input models.CreateTenantGroupInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	group, err := h.groupService.CreateGroup(tenantID, &input, userID)
+	if err != nil {
+		badRequestOrForbidden(c, err)
+		return
+	}
+
+	response.Created(c, "Group created successfully", group)
+}
+
+// ListGroups godoc
+// @Summary List groups
+// @Tags groups
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} response.Response{data=models.PaginatedResponse}
+// @Router /tenants/{id}/groups [get]
+func (h *TenantGroupHandler) ListGroups(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	var pagination models.PaginationParams
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	groups, total, err := h.groupService.ListGroups(tenantID, &pagination)
+	if err != nil {
+		response.InternalServerError(c, err)
+		return
+	}
+
+	response.OK(c, paginate(groups, &pagination, total))
+}
+
+// GetGroup godoc
+// @Summary Get a group
+// @Tags groups
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param group_id path string true "Group ID"
+// @Success 200 {object} response.Response{data=models.TenantGroup}
+// @Router /tenants/{id}/groups/{group_id} [get]
+func (h *TenantGroupHandler) GetGroup(c *gin.Context) {
+	tenantID, groupID, ok := parseGroupParams(c)
+	if !ok {
+		return
+	}
+
+	group, err := h.groupService.GetGroup(tenantID, groupID)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.OK(c, group)
+}
+
+// UpdateGroup godoc
+// @Summary Update a group
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param group_id path string true "Group ID"
+// @Param input body models.UpdateTenantGroupInput true "Group changes"
+// @Success 200 {object} response.Response{data=models.TenantGroup}
+// @Router /tenants/{id}/groups/{group_id} [patch]
+func (h *TenantGroupHandler) UpdateGroup(c *gin.Context) {
+	tenantID, groupID, ok := parseGroupParams(c)
+	if !ok {
+		return
+	}
+
+	var input models.UpdateTenantGroupInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	group, err := h.groupService.UpdateGroup(tenantID, groupID, &input)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	response.OK(c, group)
+}
+
+// DeleteGroup godoc
+// @Summary Delete a group
+// @Description Members of the group lose the roles it granted; their own roles are unchanged
+// @Tags groups
+// @Param id path string true "Tenant ID"
+// @Param group_id path string true "Group ID"
+// @Success 204
+// @Router /tenants/{id}/groups/{group_id} [delete]
+func (h *TenantGroupHandler) DeleteGroup(c *gin.Context) {
+	tenantID, groupID, ok := parseGroupParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.groupService.DeleteGroup(tenantID, groupID); err != nil {
+		if lastManagerConflict(c, err) {
+			return
+		}
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// ListMembers godoc
+// @Summary List group members
+// @Tags groups
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param group_id path string true "Group ID"
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} response.Response{data=models.PaginatedResponse}
+// @Router /tenants/{id}/groups/{group_id}/members [get]
+func (h *TenantGroupHandler) ListMembers(c *gin.Context) {
+	tenantID, groupID, ok := parseGroupParams(c)
+	if !ok {
+		return
+	}
+
+	var pagination models.PaginationParams
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	members, total, err := h.groupService.ListMembers(tenantID, groupID, &pagination)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	memberResponses := make([]*models.MemberResponse, len(members))
+	for i, member := range members {
+		memberResponses[i] = member.ToResponse()
+	}
+
+	response.OK(c, paginate(memberResponses, &pagination, total))
+}
+
+// AddMembers godoc
+// @Summary Add members to a group
+// @Description Users must already be members of the tenant; users already in the group are skipped
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param group_id path string true "Group ID"
+// @Param input body models.AddGroupMembersInput true "User IDs"
+// @Success 200 {object} response.Response{data=models.TenantGroup}
+// @Router /tenants/{id}/groups/{group_id}/members [post]
+func (h *TenantGroupHandler) AddMembers(c *gin.Context) {
+	tenantID, groupID, ok := parseGroupParams(c)
+	if !ok {
+		return
+	}
+
+	var input models.AddGroupMembersInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	group, err := h.groupService.AddMembers(tenantID, groupID, &input, userID)
+	if err != nil {
+		badRequestOrForbidden(c, err)
+		return
+	}
+
+	response.OK(c, group)
+}
+
+// RemoveMember godoc
+// @Summary Remove a member from a group
+// @Tags groups
+// @Param id path string true "Tenant ID"
+// @Param group_id path string true "Group ID"
+// @Param user_id path string true "User ID"
+// @Success 204
+// @Router /tenants/{id}/groups/{group_id}/members/{user_id} [delete]
+func (h *TenantGroupHandler) RemoveMember(c *gin.Context) {
+	tenantID, groupID, ok := parseGroupParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.groupService.RemoveMember(tenantID, groupID, c.Param("user_id")); err != nil {
+		if lastManagerConflict(c, err) {
+			return
+		}
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// AssignRoles godoc
+// @Summary Grant roles to a group
+// @Description Members of the group inherit the roles in addition to their own
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param group_id path string true "Group ID"
+// @Param input body models.AssignGroupRolesInput true "Role IDs"
+// @Success 200 {object} response.Response{data=models.TenantGroup}
+// @Router /tenants/{id}/groups/{group_id}/roles [post]
+func (h *TenantGroupHandler) AssignRoles(c *gin.Context) {
+	tenantID, groupID, ok := parseGroupParams(c)
+	if !ok {
+		return
+	}
+
+	var input models.AssignGroupRolesInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	group, err := h.groupService.AssignRoles(tenantID, groupID, input.RoleIDs, userID)
+	if err != nil {
+		badRequestOrForbidden(c, err)
+		return
+	}
+
+	response.OK(c, group)
+}
+
+// RevokeRole godoc
+// @Summary Revoke a role from a group
+// @Tags groups
+// @Param id path string true "Tenant ID"
+// @Param group_id path string true "Group ID"
+// @Param role_id path string true "Role ID"
+// @Success 204
+// @Router /tenants/{id}/groups/{group_id}/roles/{role_id} [delete]
+func (h *TenantGroupHandler) RevokeRole(c *gin.Context) {
+	tenantID, groupID, ok := parseGroupParams(c)
+	if !ok {
+		return
+	}
+
+	roleID, err := uuid.Parse(c.Param("role_id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	if err := h.groupService.RevokeRole(tenantID, groupID, roleID); err != nil {
+		if lastManagerConflict(c, err) {
+			return
+		}
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.NoContent(c)
+}
+
+func parseGroupParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	groupID, err := uuid.Parse(c.Param("group_id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return tenantID, groupID, true
+}
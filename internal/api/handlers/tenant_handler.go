@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"errors"
+
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/ysaakpr/rex/internal/api/middleware"
@@ -45,7 +47,7 @@ func (h *TenantHandler) CreateTenant(c *gin.Context) {
 
 	tenant, err := h.tenantService.CreateTenant(&input, userID)
 	if err != nil {
-		response.BadRequest(c, err)
+		respondTenantError(c, err)
 		return
 	}
 
@@ -83,7 +85,7 @@ func (h *TenantHandler) CreateManagedTenant(c *gin.Context) {
 
 	tenant, err := h.tenantService.CreateManagedTenant(&input.CreateTenantInput, input.AdminEmail, userID)
 	if err != nil {
-		response.BadRequest(c, err)
+		respondTenantError(c, err)
 		return
 	}
 
@@ -129,6 +131,36 @@ func (h *TenantHandler) GetTenant(c *gin.Context) {
 	response.OK(c, tenantResp)
 }
 
+// GetResolvedTenant godoc
+// @Summary Get the tenant resolved from the slug, X-Tenant-Slug header or Host
+// @Tags tenants
+// @Produce json
+// @Param slug path string false "Tenant slug (by-slug route only)"
+// @Param X-Tenant-Slug header string false "Tenant slug"
+// @Success 200 {object} response.Response{data=models.TenantResponse}
+// @Router /tenants/by-slug/{slug} [get]
+// @Router /tenants/current [get]
+func (h *TenantHandler) GetResolvedTenant(c *gin.Context) {
+	tenant, err := middleware.GetResolvedTenant(c)
+	if err != nil {
+		response.NotFound(c, "Tenant not found")
+		return
+	}
+
+	tenantResp := tenant.ToResponse()
+
+	// Count active members for this tenant
+	var memberCount int64
+	h.db.Table("tenant_members").
+		Where("tenant_id = ?", tenant.ID).
+		Where("status = ?", "active").
+		Count(&memberCount)
+
+	tenantResp.MemberCount = int(memberCount)
+
+	response.OK(c, tenantResp)
+}
+
 // GetTenantForPlatformAdmin godoc
 // @Summary Get tenant by ID (platform admins only, no membership required)
 // @Tags tenants
@@ -170,52 +202,28 @@ func (h *TenantHandler) GetTenantForPlatformAdmin(c *gin.Context) {
 // @Produce json
 // @Param page query int false "Page number"
 // @Param page_size query int false "Page size"
+// @Param status query []string false "Statuses to include" collectionFormat(multi)
+// @Param created_after query string false "Created at or after (RFC 3339)"
+// @Param created_before query string false "Created before (RFC 3339)"
+// @Param created_by query string false "Creator user ID"
+// @Param q query string false "Name or slug substring"
+// @Param metadata query []string false "Metadata key:value pairs" collectionFormat(multi)
+// @Param sort query string false "name, created_at or member_count; prefix with - for descending (default -created_at)"
 // @Success 200 {object} response.Response{data=models.PaginatedResponse}
 // @Router /platform/tenants [get]
 func (h *TenantHandler) ListAllTenants(c *gin.Context) {
-	var pagination models.PaginationParams
-	if err := c.ShouldBindQuery(&pagination); err != nil {
-		response.BadRequest(c, err)
+	params, ok := bindTenantListParams(c)
+	if !ok {
 		return
 	}
 
-	tenants, total, err := h.tenantService.GetAllTenants(&pagination)
+	tenants, total, err := h.tenantService.GetAllTenants(params)
 	if err != nil {
 		response.InternalServerError(c, err)
 		return
 	}
 
-	// Convert to response format and add member counts
-	tenantResponses := make([]*models.TenantResponse, len(tenants))
-	for i, tenant := range tenants {
-		tenantResp := tenant.ToResponse()
-
-		// Count active members for this tenant
-		var memberCount int64
-		h.db.Table("tenant_members").
-			Where("tenant_id = ?", tenant.ID).
-			Where("status = ?", "active").
-			Count(&memberCount)
-
-		tenantResp.MemberCount = int(memberCount)
-		tenantResponses[i] = tenantResp
-	}
-
-	pagination.Normalize()
-	totalPages := int(total) / pagination.PageSize
-	if int(total)%pagination.PageSize > 0 {
-		totalPages++
-	}
-
-	result := models.PaginatedResponse{
-		Data:       tenantResponses,
-		Page:       pagination.Page,
-		PageSize:   pagination.PageSize,
-		TotalCount: total,
-		TotalPages: totalPages,
-	}
-
-	response.OK(c, result)
+	response.OK(c, paginate(h.withMemberCounts(tenants), &params.PaginationParams, total))
 }
 
 // ListTenants godoc
@@ -224,6 +232,13 @@ func (h *TenantHandler) ListAllTenants(c *gin.Context) {
 // @Produce json
 // @Param page query int false "Page number"
 // @Param page_size query int false "Page size"
+// @Param status query []string false "Statuses to include" collectionFormat(multi)
+// @Param created_after query string false "Created at or after (RFC 3339)"
+// @Param created_before query string false "Created before (RFC 3339)"
+// @Param created_by query string false "Creator user ID"
+// @Param q query string false "Name or slug substring"
+// @Param metadata query []string false "Metadata key:value pairs" collectionFormat(multi)
+// @Param sort query string false "name, created_at or member_count; prefix with - for descending (default -created_at)"
 // @Success 200 {object} response.Response{data=models.PaginatedResponse}
 // @Router /tenants [get]
 func (h *TenantHandler) ListTenants(c *gin.Context) {
@@ -233,19 +248,36 @@ func (h *TenantHandler) ListTenants(c *gin.Context) {
 		return
 	}
 
-	var pagination models.PaginationParams
-	if err := c.ShouldBindQuery(&pagination); err != nil {
-		response.BadRequest(c, err)
+	params, ok := bindTenantListParams(c)
+	if !ok {
 		return
 	}
 
-	tenants, total, err := h.tenantService.GetUserTenants(userID, &pagination)
+	tenants, total, err := h.tenantService.GetUserTenants(userID, params)
 	if err != nil {
 		response.InternalServerError(c, err)
 		return
 	}
 
-	// Convert to response format and add member counts
+	response.OK(c, paginate(h.withMemberCounts(tenants), &params.PaginationParams, total))
+}
+
+func bindTenantListParams(c *gin.Context) (*models.TenantListParams, bool) {
+	var params models.TenantListParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		response.BadRequest(c, err)
+		return nil, false
+	}
+	if err := params.Validate(); err != nil {
+		response.BadRequest(c, err)
+		return nil, false
+	}
+	return &params, true
+}
+
+// withMemberCounts converts tenants to their response format with the number
+// of active members of each
+func (h *TenantHandler) withMemberCounts(tenants []*models.Tenant) []*models.TenantResponse {
 	tenantResponses := make([]*models.TenantResponse, len(tenants))
 	for i, tenant := range tenants {
 		tenantResp := tenant.ToResponse()
@@ -260,22 +292,7 @@ func (h *TenantHandler) ListTenants(c *gin.Context) {
 		tenantResp.MemberCount = int(memberCount)
 		tenantResponses[i] = tenantResp
 	}
-
-	pagination.Normalize()
-	totalPages := int(total) / pagination.PageSize
-	if int(total)%pagination.PageSize > 0 {
-		totalPages++
-	}
-
-	result := models.PaginatedResponse{
-		Data:       tenantResponses,
-		Page:       pagination.Page,
-		PageSize:   pagination.PageSize,
-		TotalCount: total,
-		TotalPages: totalPages,
-	}
-
-	response.OK(c, result)
+	return tenantResponses
 }
 
 // UpdateTenant godoc
@@ -303,7 +320,7 @@ func (h *TenantHandler) UpdateTenant(c *gin.Context) {
 
 	tenant, err := h.tenantService.UpdateTenant(id, &input)
 	if err != nil {
-		response.BadRequest(c, err)
+		respondTenantError(c, err)
 		return
 	}
 
@@ -321,50 +338,13 @@ func (h *TenantHandler) UpdateTenant(c *gin.Context) {
 	response.OK(c, tenantResp)
 }
 
-// DeleteTenant godoc
-// @Summary Delete tenant
-// @Tags tenants
-// @Param id path string true "Tenant ID"
-// @Success 204
-// @Router /tenants/{id} [delete]
-func (h *TenantHandler) DeleteTenant(c *gin.Context) {
-	idStr := c.Param("id")
-	id, err := uuid.Parse(idStr)
-	if err != nil {
-		response.BadRequest(c, err)
+// respondTenantError reports metadata schema violations field by field and any
+// other error as a bad request
+func respondTenantError(c *gin.Context, err error) {
+	var validationErr *services.MetadataValidationError
+	if errors.As(err, &validationErr) {
+		response.ValidationError(c, validationErr.Errors)
 		return
 	}
-
-	if err := h.tenantService.DeleteTenant(id); err != nil {
-		response.BadRequest(c, err)
-		return
-	}
-
-	response.NoContent(c)
-}
-
-// GetTenantStatus godoc
-// @Summary Get tenant initialization status
-// @Tags tenants
-// @Produce json
-// @Param id path string true "Tenant ID"
-// @Success 200 {object} response.Response{data=object}
-// @Router /tenants/{id}/status [get]
-func (h *TenantHandler) GetTenantStatus(c *gin.Context) {
-	idStr := c.Param("id")
-	id, err := uuid.Parse(idStr)
-	if err != nil {
-		response.BadRequest(c, err)
-		return
-	}
-
-	status, err := h.tenantService.GetTenantStatus(id)
-	if err != nil {
-		response.NotFound(c, err.Error())
-		return
-	}
-
-	response.OK(c, gin.H{
-		"status": status,
-	})
+	response.BadRequest(c, err)
 }
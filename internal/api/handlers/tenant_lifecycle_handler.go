@@ -0,0 +1,314 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/api/middleware"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/pkg/response"
+	"github.com/ysaakpr/rex/internal/services"
+)
+
+type TenantLifecycleHandler struct {
+	lifecycleService services.TenantLifecycleService
+}
+
+func NewTenantLifecycleHandler(lifecycleService services.TenantLifecycleService) *TenantLifecycleHandler {
+	return &TenantLifecycleHandler{
+		lifecycleService: lifecycleService,
+	}
+}
+
+// SuspendTenant godoc
+// @Summary Suspend a tenant (platform admins only)
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param input body models.SuspendTenantInput true "Suspension reason"
+// @Success 200 {object} response.Response{data=models.TenantResponse}
+// @Router /platform/tenants/{id}/suspend [post]
+func (h *TenantLifecycleHandler) SuspendTenant(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var input models.SuspendTenantInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	tenant, err := h.lifecycleService.SuspendTenant(id, input.Reason, userID)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	response.Success(c, 200, "Tenant suspended successfully", tenant.ToResponse())
+}
+
+// ReactivateTenant godoc
+// @Summary Reactivate a suspended or archived tenant (platform admins only)
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param input body models.TenantTransitionInput false "Optional reason"
+// @Success 200 {object} response.Response{data=models.TenantResponse}
+// @Router /platform/tenants/{id}/reactivate [post]
+func (h *TenantLifecycleHandler) ReactivateTenant(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	input, err := bindOptionalTransitionInput(c)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	tenant, err := h.lifecycleService.ReactivateTenant(id, input.Reason, userID)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	response.Success(c, 200, "Tenant reactivated successfully", tenant.ToResponse())
+}
+
+// ArchiveTenant godoc
+// @Summary Archive a tenant (platform admins only)
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param input body models.TenantTransitionInput false "Optional reason"
+// @Success 200 {object} response.Response{data=models.TenantResponse}
+// @Router /platform/tenants/{id}/archive [post]
+func (h *TenantLifecycleHandler) ArchiveTenant(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	input, err := bindOptionalTransitionInput(c)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	tenant, err := h.lifecycleService.ArchiveTenant(id, input.Reason, userID)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	response.Success(c, 200, "Tenant archived successfully", tenant.ToResponse())
+}
+
+// GetTransitionHistory godoc
+// @Summary Get the tenant's lifecycle transition history
+// @Tags tenants
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} response.Response{data=[]models.TenantStatusTransitionResponse}
+// @Router /tenants/{id}/transitions [get]
+func (h *TenantLifecycleHandler) GetTransitionHistory(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	transitions, err := h.lifecycleService.GetTransitionHistory(id)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	transitionResponses := make([]*models.TenantStatusTransitionResponse, len(transitions))
+	for i, transition := range transitions {
+		transitionResponses[i] = transition.ToResponse()
+	}
+
+	response.OK(c, transitionResponses)
+}
+
+// DeleteTenant godoc
+// @Summary Delete tenant (restorable until the retention window passes)
+// @Tags tenants
+// @Accept json
+// @Param id path string true "Tenant ID"
+// @Param input body models.TenantTransitionInput false "Optional reason"
+// @Success 204
+// @Router /tenants/{id} [delete]
+func (h *TenantLifecycleHandler) DeleteTenant(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	input, err := bindOptionalTransitionInput(c)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	if err := h.lifecycleService.DeleteTenant(id, input.Reason, userID); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
+// RestoreTenant godoc
+// @Summary Restore a deleted tenant within the retention window (platform admins only)
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param input body models.TenantTransitionInput false "Optional reason"
+// @Success 200 {object} response.Response{data=models.TenantResponse}
+// @Router /platform/tenants/{id}/restore [post]
+func (h *TenantLifecycleHandler) RestoreTenant(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	input, err := bindOptionalTransitionInput(c)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	tenant, err := h.lifecycleService.RestoreTenant(id, input.Reason, userID)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	response.Success(c, 200, "Tenant restored successfully", tenant.ToResponse())
+}
+
+// ListDeletedTenants godoc
+// @Summary List deleted tenants that can still be restored (platform admins only)
+// @Tags tenants
+// @Produce json
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} response.Response{data=models.PaginatedResponse}
+// @Router /platform/tenants/deleted [get]
+func (h *TenantLifecycleHandler) ListDeletedTenants(c *gin.Context) {
+	var pagination models.PaginationParams
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	tenants, total, err := h.lifecycleService.ListDeletedTenants(&pagination)
+	if err != nil {
+		response.InternalServerError(c, err)
+		return
+	}
+
+	retention := h.lifecycleService.Retention()
+	tenantResponses := make([]*models.DeletedTenantResponse, len(tenants))
+	for i, tenant := range tenants {
+		tenantResponses[i] = tenant.ToDeletedResponse(retention)
+	}
+
+	response.OK(c, paginate(tenantResponses, &pagination, total))
+}
+
+// ListPurgeReports godoc
+// @Summary List reports of permanently purged tenants (platform admins only)
+// @Tags tenants
+// @Produce json
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} response.Response{data=models.PaginatedResponse}
+// @Router /platform/tenants/purge-reports [get]
+func (h *TenantLifecycleHandler) ListPurgeReports(c *gin.Context) {
+	var pagination models.PaginationParams
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	reports, total, err := h.lifecycleService.ListPurgeReports(&pagination)
+	if err != nil {
+		response.InternalServerError(c, err)
+		return
+	}
+
+	response.OK(c, paginate(reports, &pagination, total))
+}
+
+// paginate wraps a page of results in the standard paginated response
+func paginate(data interface{}, pagination *models.PaginationParams, total int64) models.PaginatedResponse {
+	pagination.Normalize()
+	totalPages := int(total) / pagination.PageSize
+	if int(total)%pagination.PageSize > 0 {
+		totalPages++
+	}
+
+	return models.PaginatedResponse{
+		Data:       data,
+		Page:       pagination.Page,
+		PageSize:   pagination.PageSize,
+		TotalCount: total,
+		TotalPages: totalPages,
+	}
+}
+
+// bindOptionalTransitionInput binds the request body if one was sent
+func bindOptionalTransitionInput(c *gin.Context) (*models.TenantTransitionInput, error) {
+	var input models.TenantTransitionInput
+	if c.Request.ContentLength == 0 {
+		return &input, nil
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		return nil, err
+	}
+	return &input, nil
+}
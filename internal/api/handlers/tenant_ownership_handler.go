@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/api/middleware"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/pkg/response"
+	"github.com/ysaakpr/rex/internal/services"
+)
+
+type TenantOwnershipHandler struct {
+	ownershipService services.TenantOwnershipService
+}
+
+func NewTenantOwnershipHandler(ownershipService services.TenantOwnershipService) *TenantOwnershipHandler {
+	return &TenantOwnershipHandler{
+		ownershipService: ownershipService,
+	}
+}
+
+// InitiateTransfer godoc
+// @Summary Request a tenant ownership transfer
+// @Description The current owner or a platform admin nominates an active member as the new owner; the nominee must accept
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param input body models.InitiateOwnershipTransferInput true "New owner"
+// @Success 201 {object} response.Response{data=models.TenantOwnershipTransfer}
+// @Router /tenants/{id}/ownership-transfer [post]
+func (h *TenantOwnershipHandler) InitiateTransfer(c *gin.Context) {
+	tenantID, userID, ok := parseOwnershipParams(c)
+	if !ok {
+		return
+	}
+
+	var input models.InitiateOwnershipTransferInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	transfer, err := h.ownershipService.InitiateTransfer(tenantID, &input, userID)
+	if err != nil {
+		respondOwnershipError(c, err)
+		return
+	}
+
+	response.Created(c, "Ownership transfer requested", transfer)
+}
+
+// GetPendingTransfer godoc
+// @Summary Get the pending ownership transfer
+// @Tags tenants
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} response.Response{data=models.TenantOwnershipTransfer}
+// @Router /tenants/{id}/ownership-transfer [get]
+func (h *TenantOwnershipHandler) GetPendingTransfer(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	transfer, err := h.ownershipService.GetPendingTransfer(tenantID)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.OK(c, transfer)
+}
+
+// ListTransfers godoc
+// @Summary List a tenant's ownership transfers
+// @Description Every transfer with who requested and resolved it, newest first
+// @Tags tenants
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} response.Response{data=[]models.TenantOwnershipTransfer}
+// @Router /tenants/{id}/ownership-transfers [get]
+func (h *TenantOwnershipHandler) ListTransfers(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	transfers, err := h.ownershipService.ListTransfers(tenantID)
+	if err != nil {
+		response.InternalServerError(c, err)
+		return
+	}
+
+	response.OK(c, transfers)
+}
+
+// AcceptTransfer godoc
+// @Summary Accept an ownership transfer
+// @Description Only the nominee can accept. They become the owner and get the Admin role.
+// @Tags tenants
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} response.Response{data=models.TenantOwnershipTransfer}
+// @Router /tenants/{id}/ownership-transfer/accept [post]
+func (h *TenantOwnershipHandler) AcceptTransfer(c *gin.Context) {
+	tenantID, userID, ok := parseOwnershipParams(c)
+	if !ok {
+		return
+	}
+
+	transfer, err := h.ownershipService.AcceptTransfer(tenantID, userID)
+	if err != nil {
+		respondOwnershipError(c, err)
+		return
+	}
+
+	response.OK(c, transfer)
+}
+
+// DeclineTransfer godoc
+// @Summary Decline an ownership transfer
+// @Tags tenants
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} response.Response{data=models.TenantOwnershipTransfer}
+// @Router /tenants/{id}/ownership-transfer/decline [post]
+func (h *TenantOwnershipHandler) DeclineTransfer(c *gin.Context) {
+	tenantID, userID, ok := parseOwnershipParams(c)
+	if !ok {
+		return
+	}
+
+	transfer, err := h.ownershipService.DeclineTransfer(tenantID, userID)
+	if err != nil {
+		respondOwnershipError(c, err)
+		return
+	}
+
+	response.OK(c, transfer)
+}
+
+// CancelTransfer godoc
+// @Summary Cancel a pending ownership transfer
+// @Description The owner, the requester or a platform admin can cancel
+// @Tags tenants
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} response.Response{data=models.TenantOwnershipTransfer}
+// @Router /tenants/{id}/ownership-transfer [delete]
+func (h *TenantOwnershipHandler) CancelTransfer(c *gin.Context) {
+	tenantID, userID, ok := parseOwnershipParams(c)
+	if !ok {
+		return
+	}
+
+	transfer, err := h.ownershipService.CancelTransfer(tenantID, userID)
+	if err != nil {
+		respondOwnershipError(c, err)
+		return
+	}
+
+	response.OK(c, transfer)
+}
+
+func parseOwnershipParams(c *gin.Context) (uuid.UUID, string, bool) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return uuid.Nil, "", false
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return uuid.Nil, "", false
+	}
+
+	return tenantID, userID, true
+}
+
+func respondOwnershipError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrOwnershipTransferForbidden) {
+		response.Forbidden(c, err.Error())
+		return
+	}
+	response.BadRequest(c, err)
+}
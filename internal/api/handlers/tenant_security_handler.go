@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/pkg/response"
+	"github.com/ysaakpr/rex/internal/services"
+)
+
+type TenantSecurityHandler struct {
+	securityService services.TenantSecurityService
+}
+
+func NewTenantSecurityHandler(securityService services.TenantSecurityService) *TenantSecurityHandler {
+	return &TenantSecurityHandler{
+		securityService: securityService,
+	}
+}
+
+// GetSettings godoc
+// @Summary Get tenant security settings
+// @Tags tenants
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} response.Response{data=models.TenantSecuritySettings}
+// @Router /tenants/{id}/security-settings [get]
+func (h *TenantSecurityHandler) GetSettings(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	settings, err := h.securityService.GetSettings(tenantID)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.OK(c, settings)
+}
+
+// UpdateSettings godoc
+// @Summary Update tenant security settings
+// @Description Replaces the tenant's allowed email domains, maximum session lifetime, allowed login methods and invitation policy. Empty values impose no restriction.
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param input body models.UpdateTenantSecuritySettingsInput true "Security settings"
+// @Success 200 {object} response.Response{data=models.TenantSecuritySettings}
+// @Router /tenants/{id}/security-settings [put]
+func (h *TenantSecurityHandler) UpdateSettings(c *gin.Context) {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	var input models.UpdateTenantSecuritySettingsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	settings, err := h.securityService.UpdateSettings(tenantID, &input)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	response.OK(c, settings)
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/api/middleware"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/pkg/response"
+	"github.com/ysaakpr/rex/internal/services"
+)
+
+type TenantTemplateHandler struct {
+	templateService services.TenantTemplateService
+}
+
+func NewTenantTemplateHandler(templateService services.TenantTemplateService) *TenantTemplateHandler {
+	return &TenantTemplateHandler{
+		templateService: templateService,
+	}
+}
+
+// CreateTemplate godoc
+// @Summary Create a tenant template (Platform Admin)
+// @Description Defines the roles, policies, default metadata, default member role and services of tenants created from it
+// @Tags platform-admin
+// @Accept json
+// @Produce json
+// @Param input body models.CreateTenantTemplateInput true "Template definition"
+// @Success 201 {object} response.Response{data=models.TenantTemplate}
+// @Router /platform/tenant-templates [post]
+func (h *TenantTemplateHandler) CreateTemplate(c *gin.Context) {
+	var input models.CreateTenantTemplateInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	template, err := h.templateService.CreateTemplate(&input, userID)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	response.Created(c, "Template created successfully", template)
+}
+
+// ListTemplates godoc
+// @Summary List tenant templates (Platform Admin)
+// @Tags platform-admin
+// @Produce json
+// @Success 200 {object} response.Response{data=[]models.TenantTemplate}
+// @Router /platform/tenant-templates [get]
+func (h *TenantTemplateHandler) ListTemplates(c *gin.Context) {
+	templates, err := h.templateService.ListTemplates()
+	if err != nil {
+		response.InternalServerError(c, err)
+		return
+	}
+
+	response.OK(c, templates)
+}
+
+// GetTemplate godoc
+// @Summary Get a tenant template (Platform Admin)
+// @Tags platform-admin
+// @Produce json
+// @Param id path string true "Template ID"
+// @Success 200 {object} response.Response{data=models.TenantTemplate}
+// @Router /platform/tenant-templates/{id} [get]
+func (h *TenantTemplateHandler) GetTemplate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	template, err := h.templateService.GetTemplate(id)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.OK(c, template)
+}
+
+// UpdateTemplate godoc
+// @Summary Update a tenant template (Platform Admin)
+// @Description Replaces the template's definition; tenants already created from it are not changed
+// @Tags platform-admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Template ID"
+// @Param input body models.UpdateTenantTemplateInput true "Template definition"
+// @Success 200 {object} response.Response{data=models.TenantTemplate}
+// @Router /platform/tenant-templates/{id} [put]
+func (h *TenantTemplateHandler) UpdateTemplate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	var input models.UpdateTenantTemplateInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	template, err := h.templateService.UpdateTemplate(id, &input)
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	response.OK(c, template)
+}
+
+// DeleteTemplate godoc
+// @Summary Delete a tenant template (Platform Admin)
+// @Description Tenants created from the template keep their roles, policies and services
+// @Tags platform-admin
+// @Param id path string true "Template ID"
+// @Success 204
+// @Router /platform/tenant-templates/{id} [delete]
+func (h *TenantTemplateHandler) DeleteTemplate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, err)
+		return
+	}
+
+	if err := h.templateService.DeleteTemplate(id); err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.NoContent(c)
+}
@@ -5,11 +5,11 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/supertokens/supertokens-golang/recipe/emailpassword"
 	"github.com/supertokens/supertokens-golang/recipe/session"
 	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
 	"github.com/supertokens/supertokens-golang/supertokens"
 	"github.com/ysaakpr/rex/internal/pkg/response"
+	"github.com/ysaakpr/rex/internal/pkg/users"
 )
 
 // AuthMiddleware verifies SuperTokens session
@@ -77,24 +77,15 @@ func GetUserID(c *gin.Context) (string, error) {
 	return userIDStr, nil
 }
 
-// GetUserEmail fetches the user's email from SuperTokens
+// GetUserEmail fetches the user's email from SuperTokens. Both email/password
+// and third-party (Google) users are supported.
 func GetUserEmail(c *gin.Context) (string, error) {
 	userID, err := GetUserID(c)
 	if err != nil {
 		return "", err
 	}
 
-	// Get user info from SuperTokens
-	userInfo, err := emailpassword.GetUserByID(userID)
-	if err != nil {
-		return "", fmt.Errorf("failed to get user info: %w", err)
-	}
-
-	if userInfo == nil {
-		return "", fmt.Errorf("user not found")
-	}
-
-	return userInfo.Email, nil
+	return users.LookupEmail(userID)
 }
 
 // GetSession returns the SuperTokens session from context
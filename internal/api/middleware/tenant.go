@@ -2,15 +2,34 @@ package middleware
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/ysaakpr/rex/internal/models"
 	"github.com/ysaakpr/rex/internal/pkg/response"
+	"github.com/ysaakpr/rex/internal/pkg/users"
 	"github.com/ysaakpr/rex/internal/repository"
+	"github.com/ysaakpr/rex/internal/services"
 	"gorm.io/gorm"
 )
 
+// Access token claims recorded when a session is created, used to enforce
+// tenant security settings. All of them survive session refreshes.
+const (
+	// SessionStartedAtClaim holds the Unix time the user signed in
+	SessionStartedAtClaim = "session_started_at"
+	// LoginMethodClaim holds how the user signed in, such as "password" or "google"
+	LoginMethodClaim = "login_method"
+	// EmailClaim holds the email address the user signed in with
+	EmailClaim = "email"
+)
+
+// memberActivityInterval throttles activity tracking: a member's last_active_at
+// is written at most once per interval
+const memberActivityInterval = 15 * time.Minute
+
 // TenantAccessMiddleware validates that the user has access to the tenant
 // Platform admins can access any tenant without membership
 func TenantAccessMiddleware(memberRepo repository.MemberRepository, db *gorm.DB) gin.HandlerFunc {
@@ -42,39 +61,279 @@ func TenantAccessMiddleware(memberRepo repository.MemberRepository, db *gorm.DB)
 			return
 		}
 
-		// Check if user is a platform admin - they can access any tenant
-		var admin models.PlatformAdmin
-		err = db.Where("user_id = ?", userID).First(&admin).Error
-		if err == nil {
-			// User is a platform admin - grant access without membership check
-			c.Set("tenantID", tenantID)
-			c.Set("isPlatformAdmin", true)
-			c.Set("platformAdmin", &admin)
+		if authorizeTenantAccess(c, memberRepo, db, userID, tenantID) {
+			c.Next()
+		}
+	}
+}
+
+// TenantResolverMiddleware resolves the tenant a request is addressed to without
+// requiring its ID. It checks, in order, a :slug route parameter, the
+// X-Tenant-Slug header and the Host header (a subdomain of the tenant base domain
+// or a verified custom domain). When a tenant is found it is stored in the
+// context; requests that don't identify a tenant pass through unchanged.
+func TenantResolverMiddleware(domainService services.TenantDomainService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		slug := c.Param("slug")
+		if slug == "" {
+			slug = c.GetHeader("X-Tenant-Slug")
+		}
+
+		if slug != "" {
+			// An explicitly requested tenant must exist
+			tenant, err := domainService.ResolveSlug(slug)
+			if err != nil {
+				response.NotFound(c, "Tenant not found")
+				c.Abort()
+				return
+			}
+			c.Set("resolvedTenant", tenant)
 			c.Next()
 			return
 		}
 
-		// Not a platform admin, check tenant membership
-		member, err := memberRepo.GetByTenantAndUser(tenantID, userID)
-		if err != nil || member == nil {
-			response.Forbidden(c, "Access denied: You are not a member of this tenant")
+		tenant, err := domainService.ResolveHost(c.Request.Host)
+		if err != nil {
+			response.InternalServerError(c, err)
+			c.Abort()
+			return
+		}
+		if tenant != nil {
+			c.Set("resolvedTenant", tenant)
+		}
+
+		c.Next()
+	}
+}
+
+// ResolvedTenantAccessMiddleware requires a tenant resolved by
+// TenantResolverMiddleware and applies the same access rules as
+// TenantAccessMiddleware to it
+func ResolvedTenantAccessMiddleware(memberRepo repository.MemberRepository, db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := GetUserID(c)
+		if err != nil {
+			response.Unauthorized(c, "User not authenticated")
 			c.Abort()
 			return
 		}
 
-		// Check if member is active
-		if member.Status != "active" {
-			response.Forbidden(c, "Access denied: Your membership is not active")
+		tenant, err := GetResolvedTenant(c)
+		if err != nil {
+			response.NotFound(c, "No tenant could be resolved for this request")
 			c.Abort()
 			return
 		}
 
-		// Store tenant ID and member in context for later use
+		if authorizeTenantAccess(c, memberRepo, db, userID, tenant.ID) {
+			c.Next()
+		}
+	}
+}
+
+// authorizeTenantAccess checks that the user may access the tenant and stores the
+// tenant context for later handlers. It writes the error response and aborts the
+// request when access is denied.
+func authorizeTenantAccess(c *gin.Context, memberRepo repository.MemberRepository, db *gorm.DB, userID string, tenantID uuid.UUID) bool {
+	// Check if user is a platform admin - they can access any tenant
+	var admin models.PlatformAdmin
+	err := db.Where("user_id = ?", userID).First(&admin).Error
+	if err == nil {
+		// User is a platform admin - grant access without membership check
 		c.Set("tenantID", tenantID)
-		c.Set("member", member)
+		c.Set("isPlatformAdmin", true)
+		c.Set("platformAdmin", &admin)
+		return true
+	}
 
-		c.Next()
+	// Not a platform admin, check tenant membership
+	member, err := memberRepo.GetByTenantAndUser(tenantID, userID)
+	if err != nil || member == nil {
+		response.Forbidden(c, "Access denied: You are not a member of this tenant")
+		c.Abort()
+		return false
+	}
+
+	// Check if member is active
+	if member.Status == models.MemberStatusSuspended {
+		response.Forbidden(c, "Access denied: Your membership is suspended")
+		c.Abort()
+		return false
+	}
+	if member.Status != "active" {
+		response.Forbidden(c, "Access denied: Your membership is not active")
+		c.Abort()
+		return false
+	}
+
+	// Tenants that are not active are only reachable by platform admins
+	var tenant models.Tenant
+	if err := db.Select("id", "status", "security_settings").Where("id = ?", tenantID).First(&tenant).Error; err != nil {
+		response.NotFound(c, "Tenant not found")
+		c.Abort()
+		return false
+	}
+	if denial := tenantStatusDenial(tenant.Status, c.Request.Method, c.FullPath()); denial != "" {
+		response.Forbidden(c, "Access denied: "+denial)
+		c.Abort()
+		return false
 	}
+
+	if !enforceSecuritySettings(c, userID, &tenant.SecuritySettings) {
+		return false
+	}
+
+	recordMemberActivity(memberRepo, member)
+
+	// Store tenant ID and member in context for later use
+	c.Set("tenantID", tenantID)
+	c.Set("member", member)
+	return true
+}
+
+// pendingTenantRoutes are the routes, relative to /tenants/:id, that members
+// may use while their tenant is still being provisioned, so they can follow
+// its progress
+var pendingTenantRoutes = map[string]bool{
+	"GET ":        true,
+	"GET /status": true,
+}
+
+// tenantStatusDenial returns why members may not use the route of a tenant in
+// status, or "" when they may. Active tenants allow every route and pending
+// tenants only pendingTenantRoutes; every other status denies all of them.
+func tenantStatusDenial(status models.TenantStatus, method, fullPath string) string {
+	switch status {
+	case models.TenantStatusActive:
+		return ""
+	case models.TenantStatusPending:
+		route := fullPath
+		if i := strings.Index(route, "/tenants/:id"); i >= 0 {
+			route = route[i+len("/tenants/:id"):]
+		}
+		if pendingTenantRoutes[method+" "+route] {
+			return ""
+		}
+		return "This tenant is still being set up"
+	case models.TenantStatusSuspended:
+		return "This tenant is suspended"
+	case models.TenantStatusArchived:
+		return "This tenant is archived"
+	case models.TenantStatusDeleted, models.TenantStatusDeleting:
+		return "This tenant has been deleted"
+	}
+	return "This tenant is not active"
+}
+
+// recordMemberActivity updates the member's last_active_at in the background
+// when it is older than memberActivityInterval, so most requests write nothing
+// and none wait on the write
+func recordMemberActivity(memberRepo repository.MemberRepository, member *models.TenantMember) {
+	now := time.Now()
+	if member.LastActiveAt != nil && now.Sub(*member.LastActiveAt) < memberActivityInterval {
+		return
+	}
+
+	go func(memberID uuid.UUID) {
+		if err := memberRepo.TouchLastActive(memberID, now, memberActivityInterval); err != nil {
+			fmt.Printf("failed to record activity for member %s: %v\n", memberID, err)
+		}
+	}(member.ID)
+}
+
+// enforceSecuritySettings checks the member's session against the tenant's
+// maximum session lifetime and allowed login methods, and the member's email
+// against its allowed domains. It writes the error response and aborts the
+// request when a setting blocks access.
+func enforceSecuritySettings(c *gin.Context, userID string, settings *models.TenantSecuritySettings) bool {
+	deny := func(message string) bool {
+		response.Forbidden(c, "Access denied: "+message)
+		c.Abort()
+		return false
+	}
+
+	if settings.MaxSessionLifetimeMinutes > 0 || len(settings.AllowedLoginMethods) > 0 || len(settings.AllowedEmailDomains) > 0 {
+		sessionContainer, err := GetSession(c)
+		if err != nil {
+			response.Unauthorized(c, "Session not found")
+			c.Abort()
+			return false
+		}
+		payload := sessionContainer.GetAccessTokenPayload()
+
+		if settings.MaxSessionLifetimeMinutes > 0 {
+			startedAt, err := sessionStartedAt(payload, sessionContainer.GetTimeCreated)
+			if err != nil {
+				response.InternalServerError(c, err)
+				c.Abort()
+				return false
+			}
+			if settings.SessionExpired(startedAt, time.Now()) {
+				return deny(fmt.Sprintf("This tenant requires signing in again every %d minutes", settings.MaxSessionLifetimeMinutes))
+			}
+		}
+
+		if len(settings.AllowedLoginMethods) > 0 {
+			method, _ := payload[LoginMethodClaim].(string)
+			if method == "" {
+				// Sessions created before the claim was recorded
+				if method, err = users.LookupLoginMethod(userID); err != nil {
+					response.InternalServerError(c, err)
+					c.Abort()
+					return false
+				}
+			}
+			if !settings.AllowsLoginMethod(method) {
+				return deny(fmt.Sprintf("This tenant requires signing in with %s", strings.Join(settings.AllowedLoginMethods, " or ")))
+			}
+		}
+
+		if len(settings.AllowedEmailDomains) > 0 {
+			email, _ := payload[EmailClaim].(string)
+			if email == "" {
+				// Sessions created before the claim was recorded
+				if email, err = users.LookupEmail(userID); err != nil {
+					response.InternalServerError(c, err)
+					c.Abort()
+					return false
+				}
+			}
+			if !settings.AllowsEmail(email) {
+				return deny("Your email domain is not allowed in this tenant")
+			}
+		}
+	}
+
+	return true
+}
+
+// sessionStartedAt returns when the session's user signed in, falling back to
+// asking SuperTokens for sessions created before the claim was recorded
+func sessionStartedAt(payload map[string]interface{}, timeCreated func() (uint64, error)) (time.Time, error) {
+	if startedAt, ok := payload[SessionStartedAtClaim].(float64); ok {
+		return time.Unix(int64(startedAt), 0), nil
+	}
+
+	createdMillis, err := timeCreated()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get session creation time: %w", err)
+	}
+	return time.UnixMilli(int64(createdMillis)), nil
+}
+
+// GetResolvedTenant returns the tenant resolved by TenantResolverMiddleware
+func GetResolvedTenant(c *gin.Context) (*models.Tenant, error) {
+	tenant, exists := c.Get("resolvedTenant")
+	if !exists {
+		return nil, fmt.Errorf("resolved tenant not found in context")
+	}
+
+	resolved, ok := tenant.(*models.Tenant)
+	if !ok {
+		return nil, fmt.Errorf("resolved tenant has an unexpected type")
+	}
+
+	return resolved, nil
 }
 
 // GetTenantID extracts the tenant ID from the Gin context
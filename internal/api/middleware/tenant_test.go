@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/ysaakpr/rex/internal/models"
+)
+
+func TestTenantStatusDenial(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  models.TenantStatus
+		method  string
+		path    string
+		allowed bool
+	}{
+		{"active tenant info", models.TenantStatusActive, http.MethodGet, "/api/v1/tenants/:id", true},
+		{"active member change", models.TenantStatusActive, http.MethodPost, "/api/v1/tenants/:id/members", true},
+		{"active resolved tenant", models.TenantStatusActive, http.MethodGet, "/api/v1/tenants/current", true},
+
+		{"pending tenant info", models.TenantStatusPending, http.MethodGet, "/api/v1/tenants/:id", true},
+		{"pending provisioning status", models.TenantStatusPending, http.MethodGet, "/api/v1/tenants/:id/status", true},
+		{"pending tenant update", models.TenantStatusPending, http.MethodPatch, "/api/v1/tenants/:id", false},
+		{"pending members", models.TenantStatusPending, http.MethodGet, "/api/v1/tenants/:id/members", false},
+		{"pending resolved tenant", models.TenantStatusPending, http.MethodGet, "/api/v1/tenants/current", false},
+
+		{"suspended tenant info", models.TenantStatusSuspended, http.MethodGet, "/api/v1/tenants/:id", false},
+		{"suspended status", models.TenantStatusSuspended, http.MethodGet, "/api/v1/tenants/:id/status", false},
+		{"archived tenant info", models.TenantStatusArchived, http.MethodGet, "/api/v1/tenants/:id", false},
+		{"archived exports", models.TenantStatusArchived, http.MethodPost, "/api/v1/tenants/:id/exports", false},
+		{"deleted tenant info", models.TenantStatusDeleted, http.MethodGet, "/api/v1/tenants/:id", false},
+		{"deleting tenant info", models.TenantStatusDeleting, http.MethodGet, "/api/v1/tenants/:id", false},
+		{"deleting status", models.TenantStatusDeleting, http.MethodGet, "/api/v1/tenants/:id/status", false},
+		{"unknown status", models.TenantStatus("frozen"), http.MethodGet, "/api/v1/tenants/:id", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			denial := tenantStatusDenial(tt.status, tt.method, tt.path)
+			if tt.allowed && denial != "" {
+				t.Errorf("%s %s on a %s tenant denied: %s", tt.method, tt.path, tt.status, denial)
+			}
+			if !tt.allowed && denial == "" {
+				t.Errorf("%s %s on a %s tenant allowed, want it denied", tt.method, tt.path, tt.status)
+			}
+		})
+	}
+}
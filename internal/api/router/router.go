@@ -11,18 +11,36 @@ import (
 )
 
 type RouterDeps struct {
-	TenantHandler        *handlers.TenantHandler
-	MemberHandler        *handlers.MemberHandler
-	InvitationHandler    *handlers.InvitationHandler
-	RBACHandler          *handlers.RBACHandler
-	PlatformAdminHandler *handlers.PlatformAdminHandler
-	UserHandler          *handlers.UserHandler
-	SystemUserHandler    *handlers.SystemUserHandler
-	AuthConfigHandler    *handlers.AuthConfigHandler
-	MemberRepo           repository.MemberRepository
-	RBACService          services.RBACService
-	Logger               *zap.Logger
-	DB                   *gorm.DB
+	TenantHandler              *handlers.TenantHandler
+	TenantLifecycleHandler     *handlers.TenantLifecycleHandler
+	TenantDomainHandler        *handlers.TenantDomainHandler
+	TenantEmailDomainHandler   *handlers.TenantEmailDomainHandler
+	TenantAccessRequestHandler *handlers.TenantAccessRequestHandler
+	AccessReviewHandler        *handlers.AccessReviewHandler
+	MetadataSchemaHandler      *handlers.MetadataSchemaHandler
+	ProvisioningHandler        *handlers.ProvisioningHandler
+	ServiceRegistryHandler     *handlers.ServiceRegistryHandler
+	PlanHandler                *handlers.PlanHandler
+	FeatureFlagHandler         *handlers.FeatureFlagHandler
+	TenantExportHandler        *handlers.TenantExportHandler
+	TenantOwnershipHandler     *handlers.TenantOwnershipHandler
+	TenantTemplateHandler      *handlers.TenantTemplateHandler
+	TenantSecurityHandler      *handlers.TenantSecurityHandler
+	MemberHandler              *handlers.MemberHandler
+	MemberBulkHandler          *handlers.MemberBulkHandler
+	TenantGroupHandler         *handlers.TenantGroupHandler
+	MembershipHandler          *handlers.MembershipHandler
+	InvitationHandler          *handlers.InvitationHandler
+	RBACHandler                *handlers.RBACHandler
+	PlatformAdminHandler       *handlers.PlatformAdminHandler
+	UserHandler                *handlers.UserHandler
+	SystemUserHandler          *handlers.SystemUserHandler
+	AuthConfigHandler          *handlers.AuthConfigHandler
+	MemberRepo                 repository.MemberRepository
+	RBACService                services.RBACService
+	TenantDomainService        services.TenantDomainService
+	Logger                     *zap.Logger
+	DB                         *gorm.DB
 }
 
 func SetupRouter(deps *RouterDeps) *gin.Engine {
@@ -50,6 +68,12 @@ func SetupRouter(deps *RouterDeps) *gin.Engine {
 		// Auth configuration endpoint - returns which OAuth providers are enabled
 		v1.GET("/auth/config", deps.AuthConfigHandler.GetAuthConfig)
 
+		// Provisioning callbacks from downstream services (authenticated by HMAC signature)
+		v1.POST("/provisioning/callback", deps.ProvisioningHandler.HandleCallback)
+
+		// Tenant export downloads (authenticated by the time-limited link token)
+		v1.GET("/exports/download/:token", deps.TenantExportHandler.Download)
+
 		// Protected routes (require authentication)
 		auth := v1.Group("")
 		auth.Use(middleware.AuthMiddleware())
@@ -61,6 +85,16 @@ func SetupRouter(deps *RouterDeps) *gin.Engine {
 				tenants.POST("/managed", deps.TenantHandler.CreateManagedTenant)
 				tenants.GET("", deps.TenantHandler.ListTenants)
 
+				// Tenant lookup without the tenant ID (slug, X-Tenant-Slug header or Host);
+				// only these routes resolve the tenant, so other routes never look it up
+				resolveTenant := middleware.TenantResolverMiddleware(deps.TenantDomainService)
+				resolvedTenantAccess := middleware.ResolvedTenantAccessMiddleware(deps.MemberRepo, deps.DB)
+				tenants.GET("/by-slug/:slug", resolveTenant, resolvedTenantAccess, deps.TenantHandler.GetResolvedTenant)
+				tenants.GET("/current", resolveTenant, resolvedTenantAccess, deps.TenantHandler.GetResolvedTenant)
+
+				// Ask to join a tenant by its slug (no membership required)
+				tenants.POST("/by-slug/:slug/access-requests", deps.TenantAccessRequestHandler.RequestAccess)
+
 				// Tenant-scoped routes (require tenant membership or platform admin) - using :id consistently
 				tenantScoped := tenants.Group("/:id")
 				tenantScoped.Use(middleware.TenantAccessMiddleware(deps.MemberRepo, deps.DB))
@@ -68,8 +102,60 @@ func SetupRouter(deps *RouterDeps) *gin.Engine {
 					// Tenant info routes
 					tenantScoped.GET("", deps.TenantHandler.GetTenant)
 					tenantScoped.PATCH("", deps.TenantHandler.UpdateTenant)
-					tenantScoped.DELETE("", deps.TenantHandler.DeleteTenant)
-					tenantScoped.GET("/status", deps.TenantHandler.GetTenantStatus)
+					tenantScoped.DELETE("", deps.TenantLifecycleHandler.DeleteTenant)
+					tenantScoped.GET("/status", deps.ProvisioningHandler.GetTenantStatus)
+					tenantScoped.GET("/transitions", deps.TenantLifecycleHandler.GetTransitionHistory)
+					tenantScoped.GET("/entitlements", deps.PlanHandler.GetEntitlements)
+					tenantScoped.GET("/feature-flags", deps.FeatureFlagHandler.EvaluateFlags)
+
+					// Ownership transfer (owner or platform admin nominates, nominee accepts)
+					tenantScoped.POST("/ownership-transfer", deps.TenantOwnershipHandler.InitiateTransfer)
+					tenantScoped.GET("/ownership-transfer", deps.TenantOwnershipHandler.GetPendingTransfer)
+					tenantScoped.DELETE("/ownership-transfer", deps.TenantOwnershipHandler.CancelTransfer)
+					tenantScoped.POST("/ownership-transfer/accept", deps.TenantOwnershipHandler.AcceptTransfer)
+					tenantScoped.POST("/ownership-transfer/decline", deps.TenantOwnershipHandler.DeclineTransfer)
+					tenantScoped.GET("/ownership-transfers", deps.TenantOwnershipHandler.ListTransfers)
+
+					// Security settings (tenant admins)
+					tenantScoped.GET("/security-settings",
+						middleware.RequirePermission(deps.RBACService, "tenant-api", "security-settings", "read"),
+						deps.TenantSecurityHandler.GetSettings)
+					tenantScoped.PUT("/security-settings",
+						middleware.RequirePermission(deps.RBACService, "tenant-api", "security-settings", "update"),
+						deps.TenantSecurityHandler.UpdateSettings)
+
+					// Data exports (tenant admins)
+					canExport := middleware.RequirePermission(deps.RBACService, "tenant-api", "export", "create")
+					canReadExports := middleware.RequirePermission(deps.RBACService, "tenant-api", "export", "read")
+					tenantScoped.POST("/exports", canExport, deps.TenantExportHandler.RequestExport)
+					tenantScoped.GET("/exports", canReadExports, deps.TenantExportHandler.ListExports)
+					tenantScoped.GET("/exports/:export_id", canReadExports, deps.TenantExportHandler.GetExport)
+					tenantScoped.POST("/exports/:export_id/download-link", canReadExports, deps.TenantExportHandler.CreateDownloadLink)
+
+					// Custom domains the tenant is served on (tenant admins)
+					canReadSecurity := middleware.RequirePermission(deps.RBACService, "tenant-api", "security-settings", "read")
+					canUpdateSecurity := middleware.RequirePermission(deps.RBACService, "tenant-api", "security-settings", "update")
+					tenantScoped.POST("/domains", canUpdateSecurity, deps.TenantDomainHandler.AddDomain)
+					tenantScoped.GET("/domains", canReadSecurity, deps.TenantDomainHandler.ListDomains)
+					tenantScoped.POST("/domains/:domain_id/verify", canUpdateSecurity, deps.TenantDomainHandler.VerifyDomain)
+					tenantScoped.DELETE("/domains/:domain_id", canUpdateSecurity, deps.TenantDomainHandler.RemoveDomain)
+
+					// Email domains whose users join the tenant on sign-in (tenant admins)
+					tenantScoped.POST("/email-domains", canUpdateSecurity, deps.TenantEmailDomainHandler.AddEmailDomain)
+					tenantScoped.GET("/email-domains", canReadSecurity, deps.TenantEmailDomainHandler.ListEmailDomains)
+					tenantScoped.PATCH("/email-domains/:domain_id", canUpdateSecurity, deps.TenantEmailDomainHandler.UpdateEmailDomain)
+					tenantScoped.POST("/email-domains/:domain_id/verify", canUpdateSecurity, deps.TenantEmailDomainHandler.VerifyEmailDomain)
+					tenantScoped.DELETE("/email-domains/:domain_id", canUpdateSecurity, deps.TenantEmailDomainHandler.RemoveEmailDomain)
+
+					// Bulk member operations (tenant admins)
+					canBulkEditMembers := middleware.RequirePermission(deps.RBACService, "tenant-api", "member", "bulk")
+					canReadMembers := middleware.RequirePermission(deps.RBACService, "tenant-api", "member", "read")
+					tenantScoped.POST("/members/bulk", canBulkEditMembers, deps.MemberBulkHandler.CreateOperation)
+					tenantScoped.GET("/members/bulk", canReadMembers, deps.MemberBulkHandler.ListOperations)
+					tenantScoped.GET("/members/bulk/:operation_id", canReadMembers, deps.MemberBulkHandler.GetOperation)
+
+					// Member activity
+					tenantScoped.GET("/members/activity", canReadMembers, deps.MemberHandler.GetActivitySummary)
 
 					// Member routes
 					tenantScoped.POST("/members", deps.MemberHandler.AddMember)
@@ -80,6 +166,44 @@ func SetupRouter(deps *RouterDeps) *gin.Engine {
 					tenantScoped.POST("/members/:user_id/roles", deps.MemberHandler.AssignRoles)
 					tenantScoped.DELETE("/members/:user_id/roles/:role_id", deps.MemberHandler.RemoveRole)
 
+					// Member suspension
+					canUpdateMembers := middleware.RequirePermission(deps.RBACService, "tenant-api", "member", "update")
+					tenantScoped.POST("/members/:user_id/suspend", canUpdateMembers, deps.MemberHandler.SuspendMember)
+					tenantScoped.POST("/members/:user_id/reactivate", canUpdateMembers, deps.MemberHandler.ReactivateMember)
+
+					// Access requests from users asking to join (approval adds a member)
+					canAddMembers := middleware.RequirePermission(deps.RBACService, "tenant-api", "member", "create")
+					tenantScoped.GET("/access-requests", canReadMembers, deps.TenantAccessRequestHandler.ListRequests)
+					tenantScoped.GET("/access-requests/:request_id", canReadMembers, deps.TenantAccessRequestHandler.GetRequest)
+					tenantScoped.POST("/access-requests/:request_id/approve", canAddMembers, deps.TenantAccessRequestHandler.ApproveRequest)
+					tenantScoped.POST("/access-requests/:request_id/deny", canAddMembers, deps.TenantAccessRequestHandler.DenyRequest)
+
+					// Access review campaigns (reviewers decide items under /users/me/access-reviews)
+					canStartReviews := middleware.RequirePermission(deps.RBACService, "tenant-api", "access-review", "create")
+					canReadReviews := middleware.RequirePermission(deps.RBACService, "tenant-api", "access-review", "read")
+					tenantScoped.POST("/access-reviews", canStartReviews, deps.AccessReviewHandler.StartCampaign)
+					tenantScoped.GET("/access-reviews", canReadReviews, deps.AccessReviewHandler.ListCampaigns)
+					tenantScoped.GET("/access-reviews/:campaign_id", canReadReviews, deps.AccessReviewHandler.GetCampaign)
+					tenantScoped.GET("/access-reviews/:campaign_id/items", canReadReviews, deps.AccessReviewHandler.ListItems)
+					tenantScoped.POST("/access-reviews/:campaign_id/close", canStartReviews, deps.AccessReviewHandler.CloseCampaign)
+					tenantScoped.GET("/access-reviews/:campaign_id/report", canReadReviews, deps.AccessReviewHandler.ExportReport)
+
+					// Groups (members inherit the roles granted to their groups)
+					canCreateGroups := middleware.RequirePermission(deps.RBACService, "tenant-api", "group", "create")
+					canReadGroups := middleware.RequirePermission(deps.RBACService, "tenant-api", "group", "read")
+					canUpdateGroups := middleware.RequirePermission(deps.RBACService, "tenant-api", "group", "update")
+					canDeleteGroups := middleware.RequirePermission(deps.RBACService, "tenant-api", "group", "delete")
+					tenantScoped.POST("/groups", canCreateGroups, deps.TenantGroupHandler.CreateGroup)
+					tenantScoped.GET("/groups", canReadGroups, deps.TenantGroupHandler.ListGroups)
+					tenantScoped.GET("/groups/:group_id", canReadGroups, deps.TenantGroupHandler.GetGroup)
+					tenantScoped.PATCH("/groups/:group_id", canUpdateGroups, deps.TenantGroupHandler.UpdateGroup)
+					tenantScoped.DELETE("/groups/:group_id", canDeleteGroups, deps.TenantGroupHandler.DeleteGroup)
+					tenantScoped.GET("/groups/:group_id/members", canReadGroups, deps.TenantGroupHandler.ListMembers)
+					tenantScoped.POST("/groups/:group_id/members", canUpdateGroups, deps.TenantGroupHandler.AddMembers)
+					tenantScoped.DELETE("/groups/:group_id/members/:user_id", canUpdateGroups, deps.TenantGroupHandler.RemoveMember)
+					tenantScoped.POST("/groups/:group_id/roles", canUpdateGroups, deps.TenantGroupHandler.AssignRoles)
+					tenantScoped.DELETE("/groups/:group_id/roles/:role_id", canUpdateGroups, deps.TenantGroupHandler.RevokeRole)
+
 					// Invitation routes
 					tenantScoped.POST("/invitations", deps.InvitationHandler.CreateInvitation)
 					tenantScoped.GET("/invitations", deps.InvitationHandler.ListInvitations)
@@ -98,6 +222,20 @@ func SetupRouter(deps *RouterDeps) *gin.Engine {
 			users := auth.Group("/users")
 			{
 				users.GET("/me", deps.UserHandler.GetCurrentUser)
+
+				// The current user's own memberships
+				users.GET("/me/memberships", deps.MembershipHandler.ListMyMemberships)
+				users.PUT("/me/memberships/default", deps.MembershipHandler.SetDefaultTenant)
+				users.DELETE("/me/memberships/:tenant_id", deps.MembershipHandler.LeaveTenant)
+
+				// The current user's requests to join tenants
+				users.GET("/me/access-requests", deps.TenantAccessRequestHandler.ListMyRequests)
+				users.DELETE("/me/access-requests/:request_id", deps.TenantAccessRequestHandler.CancelMyRequest)
+
+				// Access review items waiting for the current user's decision
+				users.GET("/me/access-reviews", deps.AccessReviewHandler.ListMyItems)
+				users.PUT("/me/access-reviews/:item_id", deps.AccessReviewHandler.DecideItem)
+
 				users.GET("", deps.UserHandler.ListUsers)
 				users.GET("/search", deps.UserHandler.SearchUsers)
 				users.GET("/:user_id", deps.UserHandler.GetUserDetails)
@@ -120,8 +258,93 @@ func SetupRouter(deps *RouterDeps) *gin.Engine {
 
 				// Tenants management (all tenants)
 				platform.GET("/tenants", deps.TenantHandler.ListAllTenants)
+				platform.GET("/tenants/deleted", deps.TenantLifecycleHandler.ListDeletedTenants)
+				platform.GET("/tenants/purge-reports", deps.TenantLifecycleHandler.ListPurgeReports)
 				platform.GET("/tenants/:id", deps.TenantHandler.GetTenantForPlatformAdmin)
 
+				// Tenant lifecycle (validated status transitions)
+				platform.POST("/tenants/:id/suspend", deps.TenantLifecycleHandler.SuspendTenant)
+				platform.POST("/tenants/:id/reactivate", deps.TenantLifecycleHandler.ReactivateTenant)
+				platform.POST("/tenants/:id/archive", deps.TenantLifecycleHandler.ArchiveTenant)
+				platform.POST("/tenants/:id/restore", deps.TenantLifecycleHandler.RestoreTenant)
+				platform.GET("/tenants/:id/transitions", deps.TenantLifecycleHandler.GetTransitionHistory)
+
+				// Tenant provisioning in downstream services
+				platform.POST("/tenants/:id/provisioning/retry", deps.ProvisioningHandler.RetryProvisioning)
+				platform.GET("/tenants/:id/deprovisioning", deps.ProvisioningHandler.GetDeprovisioningStatus)
+				platform.POST("/tenants/:id/deprovisioning/retry", deps.ProvisioningHandler.RetryDeprovisioning)
+
+				// Tenant plans and entitlement overrides
+				platform.PUT("/tenants/:id/plan", deps.PlanHandler.AssignPlan)
+				platform.GET("/tenants/:id/entitlements", deps.PlanHandler.GetEntitlements)
+				platform.PUT("/tenants/:id/entitlements", deps.PlanHandler.SetOverride)
+				platform.DELETE("/tenants/:id/entitlements", deps.PlanHandler.ClearOverride)
+				plans := platform.Group("/plans")
+				{
+					plans.POST("", deps.PlanHandler.CreatePlan)
+					plans.GET("", deps.PlanHandler.ListPlans)
+					plans.GET("/:id", deps.PlanHandler.GetPlan)
+					plans.PUT("/:id", deps.PlanHandler.UpdatePlan)
+					plans.DELETE("/:id", deps.PlanHandler.DeletePlan)
+				}
+
+				// Access review campaigns covering any set of tenants
+				accessReviews := platform.Group("/access-reviews")
+				{
+					accessReviews.POST("", deps.AccessReviewHandler.StartCampaign)
+					accessReviews.GET("", deps.AccessReviewHandler.ListCampaigns)
+					accessReviews.GET("/:campaign_id", deps.AccessReviewHandler.GetCampaign)
+					accessReviews.GET("/:campaign_id/items", deps.AccessReviewHandler.ListItems)
+					accessReviews.POST("/:campaign_id/close", deps.AccessReviewHandler.CloseCampaign)
+					accessReviews.GET("/:campaign_id/report", deps.AccessReviewHandler.ExportReport)
+				}
+
+				// Tenant templates
+				templates := platform.Group("/tenant-templates")
+				{
+					templates.POST("", deps.TenantTemplateHandler.CreateTemplate)
+					templates.GET("", deps.TenantTemplateHandler.ListTemplates)
+					templates.GET("/:id", deps.TenantTemplateHandler.GetTemplate)
+					templates.PUT("/:id", deps.TenantTemplateHandler.UpdateTemplate)
+					templates.DELETE("/:id", deps.TenantTemplateHandler.DeleteTemplate)
+				}
+
+				// Feature flags
+				platform.GET("/tenants/:id/feature-flags", deps.FeatureFlagHandler.EvaluateFlagsForUser)
+				featureFlags := platform.Group("/feature-flags")
+				{
+					featureFlags.POST("", deps.FeatureFlagHandler.CreateFlag)
+					featureFlags.GET("", deps.FeatureFlagHandler.ListFlags)
+					featureFlags.GET("/:id", deps.FeatureFlagHandler.GetFlag)
+					featureFlags.PUT("/:id", deps.FeatureFlagHandler.UpdateFlag)
+					featureFlags.DELETE("/:id", deps.FeatureFlagHandler.DeleteFlag)
+					featureFlags.POST("/:id/kill", deps.FeatureFlagHandler.KillFlag)
+					featureFlags.POST("/:id/restore", deps.FeatureFlagHandler.RestoreFlag)
+					featureFlags.GET("/:id/audit", deps.FeatureFlagHandler.ListAuditLogs)
+				}
+
+				// Downstream service registry (tenant provisioning targets)
+				registry := platform.Group("/services")
+				{
+					registry.POST("", deps.ServiceRegistryHandler.CreateService)
+					registry.GET("", deps.ServiceRegistryHandler.ListServices)
+					registry.GET("/:id", deps.ServiceRegistryHandler.GetService)
+					registry.PATCH("/:id", deps.ServiceRegistryHandler.UpdateService)
+					registry.DELETE("/:id", deps.ServiceRegistryHandler.DeleteService)
+				}
+
+				// Tenant metadata schemas
+				metadataSchemas := platform.Group("/metadata-schemas")
+				{
+					metadataSchemas.POST("", deps.MetadataSchemaHandler.CreateSchema)
+					metadataSchemas.GET("", deps.MetadataSchemaHandler.ListSchemas)
+					metadataSchemas.POST("/revalidations", deps.MetadataSchemaHandler.StartRevalidation)
+					metadataSchemas.GET("/revalidations", deps.MetadataSchemaHandler.ListRevalidationRuns)
+					metadataSchemas.GET("/revalidations/:run_id", deps.MetadataSchemaHandler.GetRevalidationRun)
+					metadataSchemas.GET("/:id", deps.MetadataSchemaHandler.GetSchema)
+					metadataSchemas.POST("/:id/activate", deps.MetadataSchemaHandler.ActivateSchema)
+				}
+
 				// System users (M2M authentication)
 				systemUsers := platform.Group("/system-users")
 				{
@@ -198,6 +421,7 @@ func SetupRouter(deps *RouterDeps) *gin.Engine {
 				permissions.GET("", deps.RBACHandler.ListPermissions)
 				permissions.GET("/:id", deps.RBACHandler.GetPermission)
 				permissions.GET("/user", deps.RBACHandler.GetUserPermissions)
+				permissions.GET("/explain", deps.RBACHandler.ExplainPermission)
 			}
 
 			// Authorization check endpoint
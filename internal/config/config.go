@@ -12,15 +12,19 @@ import (
 )
 
 type Config struct {
-	App         AppConfig
-	Database    DatabaseConfig
-	SuperTokens SuperTokensConfig
-	Redis       RedisConfig
-	Asynq       AsynqConfig
-	Email       EmailConfig
-	Invitation  InvitationConfig
-	Log         LogConfig
-	TenantInit  TenantInitConfig
+	App             AppConfig
+	Database        DatabaseConfig
+	SuperTokens     SuperTokensConfig
+	Redis           RedisConfig
+	Asynq           AsynqConfig
+	Email           EmailConfig
+	Invitation      InvitationConfig
+	Log             LogConfig
+	TenantInit      TenantInitConfig
+	TenantLifecycle TenantLifecycleConfig
+	TenantRouting   TenantRoutingConfig
+	TenantExport    TenantExportConfig
+	AccessReview    AccessReviewConfig
 }
 
 type AppConfig struct {
@@ -79,7 +83,27 @@ type LogConfig struct {
 }
 
 type TenantInitConfig struct {
-	Services []string
+	Services               []string
+	SigningSecret          string
+	CallbackTimeoutMinutes int
+}
+
+type TenantLifecycleConfig struct {
+	RetentionDays int
+}
+
+type TenantRoutingConfig struct {
+	BaseDomain string
+}
+
+type TenantExportConfig struct {
+	Dir            string
+	LinkTTLMinutes int
+	RetentionDays  int
+}
+
+type AccessReviewConfig struct {
+	SigningSecret string
 }
 
 func Load() (*Config, error) {
@@ -143,7 +167,23 @@ func Load() (*Config, error) {
 			Format: viper.GetString("log.format"),
 		},
 		TenantInit: TenantInitConfig{
-			Services: parseServices(viper.GetString("tenant_init.services")),
+			Services:               parseServices(viper.GetString("tenant_init.services")),
+			SigningSecret:          viper.GetString("tenant_init.signing_secret"),
+			CallbackTimeoutMinutes: viper.GetInt("tenant_init.callback_timeout_minutes"),
+		},
+		TenantLifecycle: TenantLifecycleConfig{
+			RetentionDays: viper.GetInt("tenant_lifecycle.retention_days"),
+		},
+		TenantRouting: TenantRoutingConfig{
+			BaseDomain: strings.ToLower(strings.TrimSpace(viper.GetString("tenant_routing.base_domain"))),
+		},
+		TenantExport: TenantExportConfig{
+			Dir:            viper.GetString("tenant_export.dir"),
+			LinkTTLMinutes: viper.GetInt("tenant_export.link_ttl_minutes"),
+			RetentionDays:  viper.GetInt("tenant_export.retention_days"),
+		},
+		AccessReview: AccessReviewConfig{
+			SigningSecret: viper.GetString("access_review.signing_secret"),
 		},
 	}
 
@@ -191,6 +231,18 @@ func setDefaults() {
 	viper.SetDefault("log.format", "json")
 
 	viper.SetDefault("tenant_init.services", "")
+	viper.SetDefault("tenant_init.signing_secret", "")
+	viper.SetDefault("tenant_init.callback_timeout_minutes", 60)
+
+	viper.SetDefault("tenant_lifecycle.retention_days", 30)
+
+	viper.SetDefault("tenant_routing.base_domain", "")
+
+	viper.SetDefault("tenant_export.dir", "./data/exports")
+	viper.SetDefault("tenant_export.link_ttl_minutes", 15)
+	viper.SetDefault("tenant_export.retention_days", 7)
+
+	viper.SetDefault("access_review.signing_secret", "")
 
 	// Bind environment variables
 	viper.BindEnv("app.env", "APP_ENV")
@@ -225,6 +277,14 @@ func setDefaults() {
 	viper.BindEnv("log.level", "LOG_LEVEL")
 	viper.BindEnv("log.format", "LOG_FORMAT")
 	viper.BindEnv("tenant_init.services", "TENANT_INIT_SERVICES")
+	viper.BindEnv("tenant_init.signing_secret", "TENANT_PROVISIONING_SECRET")
+	viper.BindEnv("tenant_init.callback_timeout_minutes", "TENANT_PROVISIONING_CALLBACK_TIMEOUT_MINUTES")
+	viper.BindEnv("tenant_lifecycle.retention_days", "TENANT_RETENTION_DAYS")
+	viper.BindEnv("tenant_routing.base_domain", "TENANT_BASE_DOMAIN")
+	viper.BindEnv("tenant_export.dir", "TENANT_EXPORT_DIR")
+	viper.BindEnv("tenant_export.link_ttl_minutes", "TENANT_EXPORT_LINK_TTL_MINUTES")
+	viper.BindEnv("tenant_export.retention_days", "TENANT_EXPORT_RETENTION_DAYS")
+	viper.BindEnv("access_review.signing_secret", "ACCESS_REVIEW_SIGNING_SECRET")
 }
 
 func parseQueues(queueStr string) map[string]int {
@@ -277,6 +337,38 @@ func (c *Config) GetInvitationExpiry() time.Duration {
 	return time.Duration(c.Invitation.ExpiryHours) * time.Hour
 }
 
+// GetProvisioningCallbackTimeout returns how long Rex waits for a service that
+// accepted a provisioning request with 202 to report the outcome
+func (c *Config) GetProvisioningCallbackTimeout() time.Duration {
+	return time.Duration(c.TenantInit.CallbackTimeoutMinutes) * time.Minute
+}
+
+// GetProvisioningCallbackURL returns the endpoint services call to report
+// asynchronous provisioning results
+func (c *Config) GetProvisioningCallbackURL() string {
+	return strings.TrimSuffix(c.SuperTokens.APIDomain, "/") + "/api/v1/provisioning/callback"
+}
+
+// GetTenantRetention returns how long a soft-deleted tenant can be restored before it is purged
+func (c *Config) GetTenantRetention() time.Duration {
+	return time.Duration(c.TenantLifecycle.RetentionDays) * 24 * time.Hour
+}
+
+// GetExportLinkTTL returns how long a tenant export download link stays valid
+func (c *Config) GetExportLinkTTL() time.Duration {
+	return time.Duration(c.TenantExport.LinkTTLMinutes) * time.Minute
+}
+
+// GetExportRetention returns how long a finished export archive is kept
+func (c *Config) GetExportRetention() time.Duration {
+	return time.Duration(c.TenantExport.RetentionDays) * 24 * time.Hour
+}
+
+// GetExportDownloadURL returns the public download link for an export token
+func (c *Config) GetExportDownloadURL(token string) string {
+	return strings.TrimSuffix(c.SuperTokens.APIDomain, "/") + "/api/v1/exports/download/" + token
+}
+
 func IsDevelopment() bool {
 	return os.Getenv("APP_ENV") == "development"
 }
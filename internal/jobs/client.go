@@ -2,16 +2,38 @@ package jobs
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/hibiken/asynq"
 )
 
 const (
-	TypeTenantInitialization = "tenant:initialize"
-	TypeUserInvitation       = "user:invitation"
-	TypeSystemUserExpiry     = "system_user:expiry"
+	TypeTenantInitialization       = "tenant:initialize"
+	TypeUserInvitation             = "user:invitation"
+	TypeSystemUserExpiry           = "system_user:expiry"
+	TypeTenantStatusChange         = "tenant:status_change"
+	TypeTenantPurge                = "tenant:purge"
+	TypeMetadataRevalidation       = "tenant:metadata_revalidation"
+	TypeTenantDeprovisioning       = "tenant:deprovision"
+	TypeProvisioningTimeout        = "tenant:provisioning_callback_timeout"
+	TypeServiceHealthCheck         = "service_registry:health_check"
+	TypeTenantExport               = "tenant:export"
+	TypeTenantExportCleanup        = "tenant:export_cleanup"
+	TypeOwnershipTransfer          = "tenant:ownership_transfer"
+	TypeMemberBulkOperation        = "tenant:member_bulk_operation"
+	TypeMemberReactivation         = "tenant:member_scheduled_reactivation"
+	TypeInactiveMemberDeactivation = "tenant:inactive_member_deactivation"
+	TypeAccessRequest              = "tenant:access_request"
+	TypeAccessReview               = "tenant:access_review"
+
+	// Automatic retries of tenant provisioning and deprovisioning. They also
+	// cap the retries of steps whose service reported failure by callback or
+	// never called back, which are queued again with the same backoff.
+	TenantInitializationMaxRetry = 5
+	TenantDeprovisioningMaxRetry = 10
 
 	QueueCritical = "critical"
 	QueueDefault  = "default"
@@ -21,6 +43,16 @@ const (
 type Client interface {
 	EnqueueTenantInitialization(tenantID uuid.UUID) error
 	EnqueueUserInvitation(invitationID uuid.UUID) error
+	EnqueueTenantStatusChange(transitionID uuid.UUID) error
+	EnqueueMetadataRevalidation(runID uuid.UUID) error
+	EnqueueTenantDeprovisioning(tenantID uuid.UUID) error
+	RetryTenantInitialization(tenantID uuid.UUID, retried int) (bool, error)
+	RetryTenantDeprovisioning(tenantID uuid.UUID, retried int) (bool, error)
+	EnqueueTenantExport(exportID uuid.UUID) error
+	EnqueueOwnershipTransferNotification(transferID uuid.UUID) error
+	EnqueueMemberBulkOperation(operationID uuid.UUID) error
+	EnqueueAccessRequestNotification(requestID uuid.UUID) error
+	EnqueueAccessReviewNotification(campaignID uuid.UUID) error
 	Close() error
 }
 
@@ -52,7 +84,7 @@ func (c *client) EnqueueTenantInitialization(tenantID uuid.UUID) error {
 	info, err := c.asynqClient.Enqueue(
 		task,
 		asynq.Queue(QueueCritical),
-		asynq.MaxRetry(5),
+		asynq.MaxRetry(TenantInitializationMaxRetry),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to enqueue task: %w", err)
@@ -85,6 +117,237 @@ func (c *client) EnqueueUserInvitation(invitationID uuid.UUID) error {
 	return nil
 }
 
+func (c *client) EnqueueTenantStatusChange(transitionID uuid.UUID) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"transition_id": transitionID.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	task := asynq.NewTask(TypeTenantStatusChange, payload)
+
+	info, err := c.asynqClient.Enqueue(
+		task,
+		asynq.Queue(QueueDefault),
+		asynq.MaxRetry(5),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	fmt.Printf("Enqueued tenant status change task: id=%s, queue=%s\n", info.ID, info.Queue)
+	return nil
+}
+
+func (c *client) EnqueueMetadataRevalidation(runID uuid.UUID) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"run_id": runID.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	task := asynq.NewTask(TypeMetadataRevalidation, payload)
+
+	info, err := c.asynqClient.Enqueue(
+		task,
+		asynq.Queue(QueueLow),
+		asynq.MaxRetry(3),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	fmt.Printf("Enqueued metadata revalidation task: id=%s, queue=%s\n", info.ID, info.Queue)
+	return nil
+}
+
+// EnqueueTenantDeprovisioning queues teardown of a deleting tenant. Failed
+// services are retried with asynq's exponential backoff.
+func (c *client) EnqueueTenantDeprovisioning(tenantID uuid.UUID) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"tenant_id": tenantID.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	task := asynq.NewTask(TypeTenantDeprovisioning, payload)
+
+	info, err := c.asynqClient.Enqueue(
+		task,
+		asynq.Queue(QueueDefault),
+		asynq.MaxRetry(TenantDeprovisioningMaxRetry),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	fmt.Printf("Enqueued tenant deprovisioning task: id=%s, queue=%s\n", info.ID, info.Queue)
+	return nil
+}
+
+// RetryTenantInitialization queues initialization again after asynq's backoff
+// for a task retried that many times, so a step that failed outside the task
+// (by callback or callback timeout) is retried like one that failed inside
+// it. It reports false, queueing nothing, once the retries are exhausted.
+func (c *client) RetryTenantInitialization(tenantID uuid.UUID, retried int) (bool, error) {
+	return c.retryTenantProvisioning(TypeTenantInitialization, QueueCritical, tenantID, retried, TenantInitializationMaxRetry)
+}
+
+// RetryTenantDeprovisioning is RetryTenantInitialization for teardown
+func (c *client) RetryTenantDeprovisioning(tenantID uuid.UUID, retried int) (bool, error) {
+	return c.retryTenantProvisioning(TypeTenantDeprovisioning, QueueDefault, tenantID, retried, TenantDeprovisioningMaxRetry)
+}
+
+func (c *client) retryTenantProvisioning(taskType, queue string, tenantID uuid.UUID, retried, maxRetry int) (bool, error) {
+	if retried >= maxRetry {
+		return false, nil
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"tenant_id": tenantID.String(),
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	task := asynq.NewTask(taskType, payload)
+	delay := asynq.DefaultRetryDelayFunc(retried, errors.New("provisioning step failed"), task)
+
+	info, err := c.asynqClient.Enqueue(
+		task,
+		asynq.Queue(queue),
+		// The retry continues the budget rather than starting a new one
+		asynq.MaxRetry(maxRetry-retried-1),
+		asynq.ProcessIn(delay),
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	fmt.Printf("Enqueued %s retry %d in %s: id=%s, queue=%s\n", taskType, retried+1, delay.Round(time.Second), info.ID, info.Queue)
+	return true, nil
+}
+
+func (c *client) EnqueueTenantExport(exportID uuid.UUID) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"export_id": exportID.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	task := asynq.NewTask(TypeTenantExport, payload)
+
+	info, err := c.asynqClient.Enqueue(
+		task,
+		asynq.Queue(QueueLow),
+		asynq.MaxRetry(3),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	fmt.Printf("Enqueued tenant export task: id=%s, queue=%s\n", info.ID, info.Queue)
+	return nil
+}
+
+func (c *client) EnqueueOwnershipTransferNotification(transferID uuid.UUID) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"transfer_id": transferID.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	task := asynq.NewTask(TypeOwnershipTransfer, payload)
+
+	info, err := c.asynqClient.Enqueue(
+		task,
+		asynq.Queue(QueueDefault),
+		asynq.MaxRetry(3),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	fmt.Printf("Enqueued ownership transfer notification task: id=%s, queue=%s\n", info.ID, info.Queue)
+	return nil
+}
+
+// EnqueueMemberBulkOperation queues a bulk member operation. A retried job
+// resumes after the last row whose result was saved.
+func (c *client) EnqueueMemberBulkOperation(operationID uuid.UUID) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"operation_id": operationID.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	task := asynq.NewTask(TypeMemberBulkOperation, payload)
+
+	info, err := c.asynqClient.Enqueue(
+		task,
+		asynq.Queue(QueueDefault),
+		asynq.MaxRetry(3),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	fmt.Printf("Enqueued member bulk operation task: id=%s, queue=%s\n", info.ID, info.Queue)
+	return nil
+}
+
+func (c *client) EnqueueAccessRequestNotification(requestID uuid.UUID) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"request_id": requestID.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	task := asynq.NewTask(TypeAccessRequest, payload)
+
+	info, err := c.asynqClient.Enqueue(
+		task,
+		asynq.Queue(QueueDefault),
+		asynq.MaxRetry(3),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	fmt.Printf("Enqueued access request notification task: id=%s, queue=%s\n", info.ID, info.Queue)
+	return nil
+}
+
+func (c *client) EnqueueAccessReviewNotification(campaignID uuid.UUID) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"campaign_id": campaignID.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	task := asynq.NewTask(TypeAccessReview, payload)
+
+	info, err := c.asynqClient.Enqueue(
+		task,
+		asynq.Queue(QueueDefault),
+		asynq.MaxRetry(3),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	fmt.Printf("Enqueued access review notification task: id=%s, queue=%s\n", info.ID, info.Queue)
+	return nil
+}
+
 func (c *client) Close() error {
 	return c.asynqClient.Close()
 }
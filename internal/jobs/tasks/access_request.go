@@ -0,0 +1,121 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/ysaakpr/rex/internal/config"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/pkg/users"
+	"github.com/ysaakpr/rex/internal/repository"
+	"gorm.io/gorm"
+)
+
+// AccessRequestHandler emails the tenant's managers when someone asks to join
+// and the requester once their request is approved or denied
+type AccessRequestHandler struct {
+	db         *gorm.DB
+	cfg        *config.Config
+	memberRepo repository.MemberRepository
+}
+
+func NewAccessRequestHandler(db *gorm.DB, cfg *config.Config) *AccessRequestHandler {
+	return &AccessRequestHandler{
+		db:         db,
+		cfg:        cfg,
+		memberRepo: repository.NewMemberRepository(db),
+	}
+}
+
+type AccessRequestPayload struct {
+	RequestID string `json:"request_id"`
+}
+
+func (h *AccessRequestHandler) HandleAccessRequestNotification(ctx context.Context, task *asynq.Task) error {
+	var payload AccessRequestPayload
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	requestID, err := uuid.Parse(payload.RequestID)
+	if err != nil {
+		return fmt.Errorf("invalid request ID: %w", err)
+	}
+
+	var request models.TenantAccessRequest
+	if err := h.db.Where("id = ?", requestID).First(&request).Error; err != nil {
+		return fmt.Errorf("failed to get access request: %w", err)
+	}
+
+	var tenant models.Tenant
+	if err := h.db.Where("id = ?", request.TenantID).First(&tenant).Error; err != nil {
+		return fmt.Errorf("failed to get tenant: %w", err)
+	}
+
+	var recipients []string
+	switch request.Status {
+	case models.AccessRequestPending:
+		managers, err := h.memberRepo.ListManagerUserIDs(tenant.ID)
+		if err != nil {
+			return fmt.Errorf("failed to list tenant managers: %w", err)
+		}
+		recipients = managers
+	case models.AccessRequestApproved, models.AccessRequestDenied:
+		recipients = []string{request.UserID}
+	default:
+		return nil
+	}
+
+	subject, body := accessRequestEmail(&tenant, &request)
+
+	// Delivery is best effort so a retry never re-sends to recipients that
+	// already got the email
+	for _, userID := range recipients {
+		email, err := users.LookupEmail(userID)
+		if err != nil {
+			fmt.Printf("failed to resolve email for user %s: %v\n", userID, err)
+			continue
+		}
+		if err := sendEmail(h.cfg, email, subject, body); err != nil {
+			fmt.Printf("failed to notify %s about access request %s: %v\n", email, request.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func accessRequestEmail(tenant *models.Tenant, request *models.TenantAccessRequest) (string, string) {
+	var subject, summary string
+	switch request.Status {
+	case models.AccessRequestPending:
+		subject = fmt.Sprintf("%s asked to join %s", request.Email, tenant.Name)
+		summary = fmt.Sprintf("%s has asked to join %s (%s). Review the request from the tenant's access requests.",
+			request.Email, tenant.Name, tenant.Slug)
+		if request.Message != "" {
+			summary += "\n\nMessage: " + request.Message
+		}
+	case models.AccessRequestApproved:
+		subject = fmt.Sprintf("Your request to join %s was approved", tenant.Name)
+		summary = fmt.Sprintf("Your request to join %s (%s) was approved. You are now a member.", tenant.Name, tenant.Slug)
+	default:
+		subject = fmt.Sprintf("Your request to join %s was %s", tenant.Name, request.Status)
+		summary = fmt.Sprintf("Your request to join %s (%s) was %s.", tenant.Name, tenant.Slug, request.Status)
+		if request.Reason != "" {
+			summary += "\n\nReason: " + request.Reason
+		}
+	}
+
+	body := fmt.Sprintf(`
+Hello,
+
+%s
+
+Best regards,
+The Team
+	`, summary)
+
+	return subject, body
+}
@@ -0,0 +1,132 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/ysaakpr/rex/internal/config"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/pkg/users"
+	"github.com/ysaakpr/rex/internal/repository"
+	"gorm.io/gorm"
+)
+
+// AccessReviewHandler emails the reviewers of a new access review campaign
+// how many items are waiting for them
+type AccessReviewHandler struct {
+	db         *gorm.DB
+	cfg        *config.Config
+	memberRepo repository.MemberRepository
+}
+
+func NewAccessReviewHandler(db *gorm.DB, cfg *config.Config) *AccessReviewHandler {
+	return &AccessReviewHandler{
+		db:         db,
+		cfg:        cfg,
+		memberRepo: repository.NewMemberRepository(db),
+	}
+}
+
+type AccessReviewPayload struct {
+	CampaignID string `json:"campaign_id"`
+}
+
+func (h *AccessReviewHandler) HandleAccessReviewNotification(ctx context.Context, task *asynq.Task) error {
+	var payload AccessReviewPayload
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	campaignID, err := uuid.Parse(payload.CampaignID)
+	if err != nil {
+		return fmt.Errorf("invalid campaign ID: %w", err)
+	}
+
+	var campaign models.AccessReviewCampaign
+	if err := h.db.Where("id = ?", campaignID).First(&campaign).Error; err != nil {
+		return fmt.Errorf("failed to get access review campaign: %w", err)
+	}
+	if campaign.Status != models.AccessReviewOpen {
+		return nil
+	}
+
+	var counts []struct {
+		TenantID uuid.UUID
+		Count    int
+	}
+	err = h.db.Model(&models.AccessReviewItem{}).
+		Select("tenant_id, COUNT(*) AS count").
+		Where("campaign_id = ?", campaign.ID).
+		Group("tenant_id").
+		Scan(&counts).Error
+	if err != nil {
+		return fmt.Errorf("failed to count access review items: %w", err)
+	}
+
+	// Named reviewers review every item; otherwise each tenant's managers
+	// review that tenant's items
+	assigned := make(map[string]int)
+	for _, tenantCount := range counts {
+		reviewers := []string(campaign.ReviewerIDs)
+		if len(reviewers) == 0 {
+			reviewers, err = h.memberRepo.ListManagerUserIDs(tenantCount.TenantID)
+			if err != nil {
+				return fmt.Errorf("failed to list tenant managers: %w", err)
+			}
+		}
+		for _, userID := range reviewers {
+			assigned[userID] += tenantCount.Count
+		}
+	}
+
+	reviewers := make([]string, 0, len(assigned))
+	for userID := range assigned {
+		reviewers = append(reviewers, userID)
+	}
+	sort.Strings(reviewers)
+
+	// Delivery is best effort so a retry never re-sends to reviewers that
+	// already got the email
+	for _, userID := range reviewers {
+		email, err := users.LookupEmail(userID)
+		if err != nil {
+			fmt.Printf("failed to resolve email for user %s: %v\n", userID, err)
+			continue
+		}
+		subject, body := accessReviewEmail(&campaign, assigned[userID])
+		if err := sendEmail(h.cfg, email, subject, body); err != nil {
+			fmt.Printf("failed to notify %s about access review %s: %v\n", email, campaign.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func accessReviewEmail(campaign *models.AccessReviewCampaign, count int) (string, string) {
+	subject := fmt.Sprintf("Access review: %s", campaign.Name)
+	summary := fmt.Sprintf("You have been asked to review access in the campaign %q. Up to %d role assignments are waiting for you to certify or revoke.",
+		campaign.Name, count)
+	if campaign.DueAt != nil {
+		summary += fmt.Sprintf(" Please finish by %s.", campaign.DueAt.Format("January 2, 2006"))
+	}
+	if campaign.Description != "" {
+		summary += "\n\n" + campaign.Description
+	}
+
+	body := fmt.Sprintf(`
+Hello,
+
+%s
+
+Your open review items are listed under your access reviews.
+
+Best regards,
+The Team
+	`, summary)
+
+	return subject, body
+}
@@ -0,0 +1,117 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/repository"
+)
+
+// inactiveMemberBatchSize is how many inactive members are loaded at a time.
+// Each run pages through all of a tenant's inactive members, so members that
+// are kept, such as the owner, never hide the ones after them.
+const inactiveMemberBatchSize = 500
+
+// InactiveMemberDeactivationTask deactivates members of tenants whose security
+// settings set InactiveMemberDeactivationDays, once they have not used the
+// tenant for that long. The owner and the tenant's last manager are skipped.
+type InactiveMemberDeactivationTask struct {
+	logger     *zap.Logger
+	tenantRepo repository.TenantRepository
+	memberRepo repository.MemberRepository
+}
+
+func NewInactiveMemberDeactivationTask(db *gorm.DB, logger *zap.Logger) *InactiveMemberDeactivationTask {
+	return &InactiveMemberDeactivationTask{
+		logger:     logger,
+		tenantRepo: repository.NewTenantRepository(db),
+		memberRepo: repository.NewMemberRepository(db),
+	}
+}
+
+func (t *InactiveMemberDeactivationTask) HandleInactiveMemberDeactivation(ctx context.Context, task *asynq.Task) error {
+	tenants, err := t.tenantRepo.ListWithInactivityPolicy()
+	if err != nil {
+		return fmt.Errorf("failed to list tenants with an inactivity policy: %w", err)
+	}
+
+	now := time.Now()
+	for _, tenant := range tenants {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		days := tenant.SecuritySettings.InactiveMemberDeactivationDays
+		deactivated, err := t.deactivateInactive(ctx, tenant, now.AddDate(0, 0, -days))
+		if err != nil {
+			t.logger.Error("Failed to list inactive members",
+				zap.String("tenant_id", tenant.ID.String()),
+				zap.Error(err),
+			)
+		}
+
+		if deactivated > 0 {
+			t.logger.Info("Deactivated inactive members",
+				zap.String("tenant_id", tenant.ID.String()),
+				zap.Int("count", deactivated),
+				zap.Int("inactive_days", days),
+			)
+		}
+	}
+
+	return nil
+}
+
+// deactivateInactive deactivates the tenant's members not seen since before,
+// returning how many it deactivated
+func (t *InactiveMemberDeactivationTask) deactivateInactive(ctx context.Context, tenant *models.Tenant, before time.Time) (int, error) {
+	deactivated := 0
+	var after *models.TenantMember
+	for {
+		if err := ctx.Err(); err != nil {
+			return deactivated, err
+		}
+
+		members, err := t.memberRepo.ListDormantAfter(tenant.ID, before, after, inactiveMemberBatchSize)
+		if err != nil {
+			return deactivated, err
+		}
+
+		for _, member := range members {
+			if tenant.IsOwnedBy(member.UserID) {
+				continue
+			}
+
+			member.Status = models.MemberStatusInactive
+			if err := t.memberRepo.Update(member); err != nil {
+				var managerErr *models.LastManagerError
+				if errors.As(err, &managerErr) {
+					t.logger.Info("Kept inactive member who is the tenant's last manager",
+						zap.String("tenant_id", tenant.ID.String()),
+						zap.String("user_id", member.UserID),
+					)
+					continue
+				}
+				t.logger.Error("Failed to deactivate inactive member",
+					zap.String("tenant_id", tenant.ID.String()),
+					zap.String("user_id", member.UserID),
+					zap.Error(err),
+				)
+				continue
+			}
+			deactivated++
+		}
+
+		if len(members) < inactiveMemberBatchSize {
+			return deactivated, nil
+		}
+		after = members[len(members)-1]
+	}
+}
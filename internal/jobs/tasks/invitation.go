@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/smtp"
 
 	"github.com/google/uuid"
 	"github.com/hibiken/asynq"
@@ -88,47 +87,5 @@ Best regards,
 The Team
 	`, invitation.Tenant.Name, invitation.Role.Name, invitationURL, invitation.ExpiresAt.Format("Jan 02, 2006 at 3:04 PM"))
 
-	// Send email based on provider
-	switch h.cfg.Email.Provider {
-	case "smtp":
-		return h.sendSMTPEmail(invitation.Email, subject, body)
-	default:
-		// For development, just log the email
-		fmt.Printf("\n=== INVITATION EMAIL ===\n")
-		fmt.Printf("To: %s\n", invitation.Email)
-		fmt.Printf("Subject: %s\n", subject)
-		fmt.Printf("Body:\n%s\n", body)
-		fmt.Printf("========================\n\n")
-		return nil
-	}
-}
-
-func (h *InvitationHandler) sendSMTPEmail(to, subject, body string) error {
-	from := h.cfg.Email.FromAddress
-	smtpHost := h.cfg.Email.SMTPHost
-	smtpPort := h.cfg.Email.SMTPPort
-	smtpUser := h.cfg.Email.SMTPUser
-	smtpPassword := h.cfg.Email.SMTPPassword
-
-	// Compose message
-	message := fmt.Sprintf("From: %s\r\n", from)
-	message += fmt.Sprintf("To: %s\r\n", to)
-	message += fmt.Sprintf("Subject: %s\r\n", subject)
-	message += "\r\n"
-	message += body
-
-	// Setup authentication
-	var auth smtp.Auth
-	if smtpUser != "" && smtpPassword != "" {
-		auth = smtp.PlainAuth("", smtpUser, smtpPassword, smtpHost)
-	}
-
-	// Send email
-	addr := fmt.Sprintf("%s:%s", smtpHost, smtpPort)
-	err := smtp.SendMail(addr, auth, from, []string{to}, []byte(message))
-	if err != nil {
-		return fmt.Errorf("failed to send SMTP email: %w", err)
-	}
-
-	return nil
+	return sendEmail(h.cfg, invitation.Email, subject, body)
 }
@@ -0,0 +1,54 @@
+package tasks
+
+import (
+	"fmt"
+	"net/smtp"
+
+	"github.com/ysaakpr/rex/internal/config"
+)
+
+// sendEmail delivers a plain-text email using the configured provider.
+// Unknown providers fall back to logging the email, which is what we want in development.
+func sendEmail(cfg *config.Config, to, subject, body string) error {
+	switch cfg.Email.Provider {
+	case "smtp":
+		return sendSMTPEmail(cfg, to, subject, body)
+	default:
+		fmt.Printf("\n=== EMAIL ===\n")
+		fmt.Printf("To: %s\n", to)
+		fmt.Printf("Subject: %s\n", subject)
+		fmt.Printf("Body:\n%s\n", body)
+		fmt.Printf("=============\n\n")
+		return nil
+	}
+}
+
+func sendSMTPEmail(cfg *config.Config, to, subject, body string) error {
+	from := cfg.Email.FromAddress
+	smtpHost := cfg.Email.SMTPHost
+	smtpPort := cfg.Email.SMTPPort
+	smtpUser := cfg.Email.SMTPUser
+	smtpPassword := cfg.Email.SMTPPassword
+
+	// Compose message
+	message := fmt.Sprintf("From: %s\r\n", from)
+	message += fmt.Sprintf("To: %s\r\n", to)
+	message += fmt.Sprintf("Subject: %s\r\n", subject)
+	message += "\r\n"
+	message += body
+
+	// Setup authentication
+	var auth smtp.Auth
+	if smtpUser != "" && smtpPassword != "" {
+		auth = smtp.PlainAuth("", smtpUser, smtpPassword, smtpHost)
+	}
+
+	// Send email
+	addr := fmt.Sprintf("%s:%s", smtpHost, smtpPort)
+	err := smtp.SendMail(addr, auth, from, []string{to}, []byte(message))
+	if err != nil {
+		return fmt.Errorf("failed to send SMTP email: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,450 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/pkg/users"
+	"github.com/ysaakpr/rex/internal/repository"
+)
+
+// memberBulkProgressInterval is how many rows are processed between saves of
+// the results so far
+const memberBulkProgressInterval = 50
+
+// MemberBulkTask applies a bulk member operation row by row, recording an
+// outcome for each. Dry runs work out every outcome without writing anything.
+// Roles are checked with checkGrant and limits read with getEntitlements,
+// the member and plan services' own checks, so rows are held to the same
+// rules as single changes.
+type MemberBulkTask struct {
+	logger          *zap.Logger
+	bulkRepo        repository.MemberBulkRepository
+	tenantRepo      repository.TenantRepository
+	memberRepo      repository.MemberRepository
+	rbacRepo        repository.RBACRepository
+	planRepo        repository.PlanRepository
+	checkGrant      func(tenant *models.Tenant, actorID string, roleIDs []uuid.UUID) error
+	getEntitlements func(tenantID uuid.UUID) (*models.TenantEntitlements, error)
+}
+
+func NewMemberBulkTask(
+	db *gorm.DB,
+	logger *zap.Logger,
+	checkGrant func(tenant *models.Tenant, actorID string, roleIDs []uuid.UUID) error,
+	getEntitlements func(tenantID uuid.UUID) (*models.TenantEntitlements, error),
+) *MemberBulkTask {
+	return &MemberBulkTask{
+		logger:          logger,
+		bulkRepo:        repository.NewMemberBulkRepository(db),
+		tenantRepo:      repository.NewTenantRepository(db),
+		memberRepo:      repository.NewMemberRepository(db),
+		rbacRepo:        repository.NewRBACRepository(db),
+		planRepo:        repository.NewPlanRepository(db),
+		checkGrant:      checkGrant,
+		getEntitlements: getEntitlements,
+	}
+}
+
+type MemberBulkPayload struct {
+	OperationID string `json:"operation_id"`
+}
+
+// memberBulkRun is the state carried from row to row
+type memberBulkRun struct {
+	operation    *models.MemberBulkOperation
+	tenant       *models.Tenant
+	entitlements *models.TenantEntitlements
+	roles        map[string]*models.Role
+	// seen maps user IDs to the first row that named them
+	seen map[string]int
+	// grantBlocks is why the requester may not grant each role checked so
+	// far ("" when they may)
+	grantBlocks map[uuid.UUID]string
+}
+
+func (t *MemberBulkTask) HandleMemberBulkOperation(ctx context.Context, task *asynq.Task) error {
+	var payload MemberBulkPayload
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	operationID, err := uuid.Parse(payload.OperationID)
+	if err != nil {
+		return fmt.Errorf("invalid operation ID: %w", err)
+	}
+
+	operation, err := t.bulkRepo.GetByID(operationID)
+	if err != nil {
+		return fmt.Errorf("failed to get bulk operation: %w", err)
+	}
+	if operation.Status == models.MemberBulkCompleted || operation.Status == models.MemberBulkFailed {
+		return nil
+	}
+
+	if operation.Status == models.MemberBulkPending {
+		if err := t.bulkRepo.Start(operationID); err != nil {
+			return fmt.Errorf("failed to start bulk operation: %w", err)
+		}
+	}
+
+	results, runErr := t.process(ctx, operation)
+	succeeded, failed := countOutcomes(results)
+	if runErr != nil {
+		// Keep what was done so a retry resumes after it
+		if err := t.bulkRepo.UpdateProgress(operationID, results, succeeded, failed); err != nil {
+			return fmt.Errorf("failed to save bulk operation progress: %w", err)
+		}
+		retried, _ := asynq.GetRetryCount(ctx)
+		maxRetry, _ := asynq.GetMaxRetry(ctx)
+		if retried >= maxRetry {
+			if err := t.bulkRepo.Fail(operationID, runErr); err != nil {
+				return fmt.Errorf("failed to record bulk operation failure: %w", err)
+			}
+		}
+		t.logger.Error("Member bulk operation failed",
+			zap.String("operation_id", operationID.String()),
+			zap.String("tenant_id", operation.TenantID.String()),
+			zap.Int("processed", len(results)),
+			zap.Error(runErr),
+		)
+		return runErr
+	}
+
+	if err := t.bulkRepo.Complete(operationID, results, succeeded, failed); err != nil {
+		return fmt.Errorf("failed to complete bulk operation: %w", err)
+	}
+
+	t.logger.Info("Member bulk operation completed",
+		zap.String("operation_id", operationID.String()),
+		zap.String("tenant_id", operation.TenantID.String()),
+		zap.String("operation", string(operation.Operation)),
+		zap.Bool("dry_run", operation.DryRun),
+		zap.Int("succeeded", succeeded),
+		zap.Int("failed", failed),
+	)
+	return nil
+}
+
+// process returns the results of every row, starting after the rows a
+// previous attempt saved. An error means the job itself failed, not a row.
+func (t *MemberBulkTask) process(ctx context.Context, operation *models.MemberBulkOperation) (models.MemberBulkRowResults, error) {
+	results := operation.Results
+
+	tenant, err := t.tenantRepo.GetByID(operation.TenantID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return results, errors.New("tenant not found")
+		}
+		return results, fmt.Errorf("failed to get tenant: %w", err)
+	}
+
+	run := &memberBulkRun{
+		operation:   operation,
+		tenant:      tenant,
+		roles:       map[string]*models.Role{},
+		seen:        map[string]int{},
+		grantBlocks: map[uuid.UUID]string{},
+	}
+	if operation.Operation == models.MemberBulkAdd {
+		// Usage is then tracked as rows are added
+		if run.entitlements, err = t.getEntitlements(tenant.ID); err != nil {
+			return results, fmt.Errorf("failed to get entitlements: %w", err)
+		}
+	}
+
+	// Rows already processed still count towards duplicate detection, and in
+	// a dry run towards the projected usage
+	for _, result := range results {
+		if result.UserID != "" {
+			if _, ok := run.seen[result.UserID]; !ok {
+				run.seen[result.UserID] = result.Row
+			}
+		}
+		if operation.DryRun && run.entitlements != nil && result.Outcome == models.MemberBulkOutcomeAdded {
+			run.entitlements.Usage.Members++
+		}
+	}
+
+	for i := len(results); i < len(operation.Rows); i++ {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+
+		result, err := t.processRow(run, i+1, operation.Rows[i])
+		if err != nil {
+			return results, err
+		}
+		results = append(results, result)
+
+		if len(results)%memberBulkProgressInterval == 0 {
+			succeeded, failed := countOutcomes(results)
+			if err := t.bulkRepo.UpdateProgress(operation.ID, results, succeeded, failed); err != nil {
+				return results, fmt.Errorf("failed to save bulk operation progress: %w", err)
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// processRow returns the row's outcome. Problems with the row are outcomes;
+// an error is returned only when the database fails.
+func (t *MemberBulkTask) processRow(run *memberBulkRun, number int, row models.MemberBulkRow) (models.MemberBulkRowResult, error) {
+	result := models.MemberBulkRowResult{Row: number, UserID: row.UserID, Email: row.Email}
+
+	if row.UserID == "" && row.Email == "" {
+		return rowOutcome(result, models.MemberBulkOutcomeInvalidRow, "user_id or email is required"), nil
+	}
+
+	if result.UserID == "" {
+		userID, err := users.LookupUserID(row.Email)
+		if errors.Is(err, users.ErrUserNotFound) {
+			return rowOutcome(result, models.MemberBulkOutcomeUnknownUser, "no user has this email"), nil
+		}
+		if err != nil {
+			return rowOutcome(result, models.MemberBulkOutcomeFailed, err.Error()), nil
+		}
+		result.UserID = userID
+	}
+
+	if first, ok := run.seen[result.UserID]; ok {
+		return rowOutcome(result, models.MemberBulkOutcomeInvalidRow, fmt.Sprintf("duplicate of row %d", first)), nil
+	}
+	run.seen[result.UserID] = number
+
+	member, err := t.memberRepo.GetByTenantAndUser(run.tenant.ID, result.UserID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return result, fmt.Errorf("failed to get member: %w", err)
+	}
+	if err != nil {
+		member = nil
+	}
+
+	switch run.operation.Operation {
+	case models.MemberBulkAdd:
+		return t.addMember(run, result, member, row.Role)
+	case models.MemberBulkUpdateRole:
+		return t.updateRole(run, result, member, row.Role)
+	case models.MemberBulkRemove:
+		return t.removeMember(run, result, member)
+	}
+	return rowOutcome(result, models.MemberBulkOutcomeInvalidRow, "unknown operation"), nil
+}
+
+func (t *MemberBulkTask) addMember(run *memberBulkRun, result models.MemberBulkRowResult, member *models.TenantMember, roleRef string) (models.MemberBulkRowResult, error) {
+	if member != nil {
+		return rowOutcome(result, models.MemberBulkOutcomeAlreadyMember, ""), nil
+	}
+
+	// Confirms the user exists and gives the email the domain check needs
+	email, err := users.LookupEmail(result.UserID)
+	if errors.Is(err, users.ErrUserNotFound) {
+		return rowOutcome(result, models.MemberBulkOutcomeUnknownUser, "no user has this ID"), nil
+	}
+	if err != nil {
+		return rowOutcome(result, models.MemberBulkOutcomeFailed, err.Error()), nil
+	}
+	result.Email = email
+
+	if !run.tenant.SecuritySettings.AllowsEmail(email) {
+		return rowOutcome(result, models.MemberBulkOutcomeBlocked, "email domain is not allowed in this tenant"), nil
+	}
+
+	var role *models.Role
+	if roleRef == "" && run.tenant.DefaultRoleID != nil {
+		roleRef = run.tenant.DefaultRoleID.String()
+	}
+	if roleRef == "" {
+		return rowOutcome(result, models.MemberBulkOutcomeInvalidRole, "role is required, this tenant has no default role"), nil
+	}
+	if role, err = t.resolveRole(run, roleRef); err != nil {
+		return result, err
+	}
+	if role == nil {
+		return rowOutcome(result, models.MemberBulkOutcomeInvalidRole, fmt.Sprintf("role %q not found in this tenant", roleRef)), nil
+	}
+	if block, err := t.grantBlock(run, role); err != nil {
+		return result, err
+	} else if block != "" {
+		return rowOutcome(result, models.MemberBulkOutcomeBlocked, block), nil
+	}
+
+	entitlements := run.entitlements
+	if entitlements.AtLimit(models.QuotaMembers) {
+		return rowOutcome(result, models.MemberBulkOutcomeBlocked, fmt.Sprintf("plan limit of %d members reached", *entitlements.Limits.MaxMembers)), nil
+	}
+	quotas := []models.QuotaResource{models.QuotaMembers}
+	isSystemUser := false
+	if entitlements.Limits.MaxSystemUsers != nil {
+		if isSystemUser, err = t.planRepo.IsSystemUser(result.UserID); err != nil {
+			return result, fmt.Errorf("failed to check system user: %w", err)
+		}
+		if isSystemUser && entitlements.AtLimit(models.QuotaSystemUsers) {
+			return rowOutcome(result, models.MemberBulkOutcomeBlocked, fmt.Sprintf("plan limit of %d system users reached", *entitlements.Limits.MaxSystemUsers)), nil
+		}
+		if isSystemUser {
+			quotas = append(quotas, models.QuotaSystemUsers)
+		}
+	}
+
+	if !run.operation.DryRun {
+		requestedBy := run.operation.RequestedBy
+		member := &models.TenantMember{
+			TenantID:  run.tenant.ID,
+			UserID:    result.UserID,
+			RoleID:    role.ID,
+			Status:    models.MemberStatusActive,
+			InvitedBy: &requestedBy,
+			JoinedAt:  time.Now(),
+		}
+		// Members added elsewhere since the job started count too
+		if err := t.memberRepo.CreateWithinQuota(member, entitlements.QuotaCheck(quotas...)); err != nil {
+			var quotaErr *models.QuotaExceededError
+			if errors.As(err, &quotaErr) {
+				return rowOutcome(result, models.MemberBulkOutcomeBlocked, quotaErr.Error()), nil
+			}
+			return rowOutcome(result, models.MemberBulkOutcomeFailed, fmt.Sprintf("failed to add member: %v", err)), nil
+		}
+	}
+
+	entitlements.Usage.Members++
+	if isSystemUser {
+		entitlements.Usage.SystemUsers++
+	}
+	return rowOutcome(result, models.MemberBulkOutcomeAdded, ""), nil
+}
+
+func (t *MemberBulkTask) updateRole(run *memberBulkRun, result models.MemberBulkRowResult, member *models.TenantMember, roleRef string) (models.MemberBulkRowResult, error) {
+	if member == nil {
+		return rowOutcome(result, models.MemberBulkOutcomeNotMember, ""), nil
+	}
+	if roleRef == "" {
+		return rowOutcome(result, models.MemberBulkOutcomeInvalidRole, "role is required"), nil
+	}
+
+	role, err := t.resolveRole(run, roleRef)
+	if err != nil {
+		return result, err
+	}
+	if role == nil {
+		return rowOutcome(result, models.MemberBulkOutcomeInvalidRole, fmt.Sprintf("role %q not found in this tenant", roleRef)), nil
+	}
+	if member.RoleID == role.ID {
+		return rowOutcome(result, models.MemberBulkOutcomeRoleUpdated, "member already has this role"), nil
+	}
+	if block, err := t.grantBlock(run, role); err != nil {
+		return result, err
+	} else if block != "" {
+		return rowOutcome(result, models.MemberBulkOutcomeBlocked, block), nil
+	}
+
+	if !run.operation.DryRun {
+		member.RoleID = role.ID
+		member.Role = *role
+		if err := t.memberRepo.Update(member); err != nil {
+			var managerErr *models.LastManagerError
+			if errors.As(err, &managerErr) {
+				return rowOutcome(result, models.MemberBulkOutcomeBlocked, managerErr.Error()), nil
+			}
+			return rowOutcome(result, models.MemberBulkOutcomeFailed, fmt.Sprintf("failed to update member: %v", err)), nil
+		}
+	}
+	return rowOutcome(result, models.MemberBulkOutcomeRoleUpdated, ""), nil
+}
+
+func (t *MemberBulkTask) removeMember(run *memberBulkRun, result models.MemberBulkRowResult, member *models.TenantMember) (models.MemberBulkRowResult, error) {
+	if member == nil {
+		return rowOutcome(result, models.MemberBulkOutcomeNotMember, ""), nil
+	}
+	if run.tenant.IsOwnedBy(member.UserID) {
+		return rowOutcome(result, models.MemberBulkOutcomeBlocked, "the tenant owner cannot be removed, transfer ownership first"), nil
+	}
+
+	if !run.operation.DryRun {
+		if err := t.memberRepo.Delete(member.ID); err != nil {
+			var managerErr *models.LastManagerError
+			if errors.As(err, &managerErr) {
+				return rowOutcome(result, models.MemberBulkOutcomeBlocked, managerErr.Error()), nil
+			}
+			return rowOutcome(result, models.MemberBulkOutcomeFailed, fmt.Sprintf("failed to remove member: %v", err)), nil
+		}
+	}
+	return rowOutcome(result, models.MemberBulkOutcomeRemoved, ""), nil
+}
+
+// resolveRole finds a role of the tenant, or a system role, by ID or name.
+// It returns nil when there is no such role.
+func (t *MemberBulkTask) resolveRole(run *memberBulkRun, ref string) (*models.Role, error) {
+	if role, ok := run.roles[ref]; ok {
+		return role, nil
+	}
+
+	var role *models.Role
+	var err error
+	if id, parseErr := uuid.Parse(ref); parseErr == nil {
+		role, err = t.rbacRepo.GetRoleByID(id)
+	} else {
+		role, err = t.rbacRepo.GetRoleByName(ref, &run.tenant.ID)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		role, err = nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get role: %w", err)
+	}
+	if role != nil && role.TenantID != nil && *role.TenantID != run.tenant.ID {
+		role = nil
+	}
+
+	run.roles[ref] = role
+	return role, nil
+}
+
+// grantBlock returns why the requester may not grant role, or "" when they
+// may. It is checked when the job runs, as the requester's roles may have
+// changed since the operation was queued.
+func (t *MemberBulkTask) grantBlock(run *memberBulkRun, role *models.Role) (string, error) {
+	if block, ok := run.grantBlocks[role.ID]; ok {
+		return block, nil
+	}
+
+	var block string
+	err := t.checkGrant(run.tenant, run.operation.RequestedBy, []uuid.UUID{role.ID})
+	var grantErr *models.RoleGrantError
+	var policyErr *models.SecurityPolicyError
+	if errors.As(err, &grantErr) || errors.As(err, &policyErr) {
+		block = err.Error()
+	} else if err != nil {
+		return "", fmt.Errorf("failed to check role grant: %w", err)
+	}
+
+	run.grantBlocks[role.ID] = block
+	return block, nil
+}
+
+func rowOutcome(result models.MemberBulkRowResult, outcome models.MemberBulkOutcome, message string) models.MemberBulkRowResult {
+	result.Outcome = outcome
+	result.Message = message
+	return result
+}
+
+func countOutcomes(results models.MemberBulkRowResults) (succeeded, failed int) {
+	for _, result := range results {
+		if result.Outcome.Succeeded() {
+			succeeded++
+		} else {
+			failed++
+		}
+	}
+	return succeeded, failed
+}
@@ -0,0 +1,79 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/repository"
+)
+
+// memberReactivationBatchSize caps how many suspensions one run reactivates;
+// the rest are picked up by the next run
+const memberReactivationBatchSize = 500
+
+// MemberReactivationTask reactivates suspended members whose scheduled
+// reactivation time has passed
+type MemberReactivationTask struct {
+	logger     *zap.Logger
+	memberRepo repository.MemberRepository
+}
+
+func NewMemberReactivationTask(db *gorm.DB, logger *zap.Logger) *MemberReactivationTask {
+	return &MemberReactivationTask{
+		logger:     logger,
+		memberRepo: repository.NewMemberRepository(db),
+	}
+}
+
+func (t *MemberReactivationTask) HandleMemberReactivation(ctx context.Context, task *asynq.Task) error {
+	due, err := t.memberRepo.ListDueReactivations(time.Now(), memberReactivationBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to list due reactivations: %w", err)
+	}
+	if len(due) == 0 {
+		t.logger.Debug("No scheduled member reactivations due")
+		return nil
+	}
+
+	reactivated, closed := 0, 0
+	for _, suspension := range due {
+		err := t.memberRepo.Reactivate(suspension.MemberID, models.ScheduledReactivationActor, "Scheduled reactivation")
+		if errors.Is(err, repository.ErrMemberNotSuspended) {
+			// The member left suspension some other way, so only the record
+			// is still open; close it or it would be due on every run
+			err = t.memberRepo.CloseSuspension(suspension.ID, models.ScheduledReactivationActor,
+				"Member was no longer suspended at the scheduled reactivation")
+			if err != nil {
+				t.logger.Error("Failed to close stale suspension",
+					zap.String("suspension_id", suspension.ID.String()),
+					zap.Error(err),
+				)
+				continue
+			}
+			closed++
+			continue
+		}
+		if err != nil {
+			t.logger.Error("Failed to reactivate member",
+				zap.String("member_id", suspension.MemberID.String()),
+				zap.Error(err),
+			)
+			continue
+		}
+		reactivated++
+	}
+
+	t.logger.Info("Reactivated suspended members",
+		zap.Int("count", reactivated),
+		zap.Int("stale_closed", closed),
+		zap.Int("due", len(due)),
+	)
+	return nil
+}
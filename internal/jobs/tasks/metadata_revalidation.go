@@ -0,0 +1,143 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/pkg/jsonschema"
+	"github.com/ysaakpr/rex/internal/repository"
+)
+
+// metadataRevalidationBatchSize is how many tenants are loaded at a time
+const metadataRevalidationBatchSize = 200
+
+// MetadataRevalidationTask checks every tenant's metadata against the active
+// schemas and records the tenants that don't conform
+type MetadataRevalidationTask struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+func NewMetadataRevalidationTask(db *gorm.DB, logger *zap.Logger) *MetadataRevalidationTask {
+	return &MetadataRevalidationTask{
+		db:     db,
+		logger: logger,
+	}
+}
+
+type MetadataRevalidationPayload struct {
+	RunID string `json:"run_id"`
+}
+
+// compiledSchema pairs a stored schema with its compiled form
+type compiledSchema struct {
+	id     uuid.UUID
+	schema *jsonschema.Schema
+}
+
+func (t *MetadataRevalidationTask) HandleMetadataRevalidation(ctx context.Context, task *asynq.Task) error {
+	var payload MetadataRevalidationPayload
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	runID, err := uuid.Parse(payload.RunID)
+	if err != nil {
+		return fmt.Errorf("invalid run ID: %w", err)
+	}
+
+	schemaRepo := repository.NewMetadataSchemaRepository(t.db)
+	if err := schemaRepo.StartRun(runID); err != nil {
+		return fmt.Errorf("failed to start revalidation run: %w", err)
+	}
+
+	checked, nonConforming, runErr := t.revalidate(schemaRepo, runID)
+	if err := schemaRepo.CompleteRun(runID, checked, nonConforming, runErr); err != nil {
+		return fmt.Errorf("failed to complete revalidation run: %w", err)
+	}
+
+	t.logger.Info("Metadata revalidation finished",
+		zap.String("run_id", runID.String()),
+		zap.Int("checked", checked),
+		zap.Int("non_conforming", nonConforming),
+		zap.Error(runErr),
+	)
+
+	return runErr
+}
+
+func (t *MetadataRevalidationTask) revalidate(schemaRepo repository.MetadataSchemaRepository, runID uuid.UUID) (int, int, error) {
+	activeSchemas, err := schemaRepo.ListActive()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to load active schemas: %w", err)
+	}
+
+	// Keyed by tenant type; "" holds the default schema
+	schemas := make(map[string]compiledSchema, len(activeSchemas))
+	for _, stored := range activeSchemas {
+		compiled, err := jsonschema.Compile(stored.Schema)
+		if err != nil {
+			return 0, 0, fmt.Errorf("active schema %s is invalid: %w", stored.ID, err)
+		}
+		key := ""
+		if stored.TenantType != nil {
+			key = *stored.TenantType
+		}
+		schemas[key] = compiledSchema{id: stored.ID, schema: compiled}
+	}
+
+	if len(schemas) == 0 {
+		return 0, 0, nil
+	}
+
+	var checked, nonConforming int
+	var tenants []*models.Tenant
+	result := t.db.Model(&models.Tenant{}).FindInBatches(&tenants, metadataRevalidationBatchSize, func(tx *gorm.DB, batch int) error {
+		var failures []*models.MetadataValidationFailure
+
+		for _, tenant := range tenants {
+			schema, ok := schemaForTenant(schemas, tenant)
+			if !ok {
+				continue
+			}
+			checked++
+
+			metadata := tenant.Metadata
+			if metadata == nil {
+				metadata = models.JSONMap{}
+			}
+
+			if fieldErrors := schema.schema.Validate(map[string]interface{}(metadata)); len(fieldErrors) > 0 {
+				failures = append(failures, &models.MetadataValidationFailure{
+					RunID:    runID,
+					TenantID: tenant.ID,
+					SchemaID: schema.id,
+					Errors:   fieldErrors,
+				})
+			}
+		}
+
+		nonConforming += len(failures)
+		return schemaRepo.AddFailures(failures)
+	})
+
+	return checked, nonConforming, result.Error
+}
+
+// schemaForTenant picks the tenant type's schema, falling back to the default
+func schemaForTenant(schemas map[string]compiledSchema, tenant *models.Tenant) (compiledSchema, bool) {
+	if tenant.TenantType != nil {
+		if schema, ok := schemas[*tenant.TenantType]; ok {
+			return schema, true
+		}
+	}
+	schema, ok := schemas[""]
+	return schema, ok
+}
@@ -0,0 +1,113 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/ysaakpr/rex/internal/config"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/pkg/users"
+	"gorm.io/gorm"
+)
+
+// OwnershipTransferHandler emails the people involved in an ownership
+// transfer each time it is requested or resolved
+type OwnershipTransferHandler struct {
+	db  *gorm.DB
+	cfg *config.Config
+}
+
+func NewOwnershipTransferHandler(db *gorm.DB, cfg *config.Config) *OwnershipTransferHandler {
+	return &OwnershipTransferHandler{
+		db:  db,
+		cfg: cfg,
+	}
+}
+
+type OwnershipTransferPayload struct {
+	TransferID string `json:"transfer_id"`
+}
+
+func (h *OwnershipTransferHandler) HandleOwnershipTransferNotification(ctx context.Context, task *asynq.Task) error {
+	var payload OwnershipTransferPayload
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	transferID, err := uuid.Parse(payload.TransferID)
+	if err != nil {
+		return fmt.Errorf("invalid transfer ID: %w", err)
+	}
+
+	var transfer models.TenantOwnershipTransfer
+	if err := h.db.Where("id = ?", transferID).First(&transfer).Error; err != nil {
+		return fmt.Errorf("failed to get transfer: %w", err)
+	}
+
+	var tenant models.Tenant
+	if err := h.db.Where("id = ?", transfer.TenantID).First(&tenant).Error; err != nil {
+		return fmt.Errorf("failed to get tenant: %w", err)
+	}
+
+	subject, body := ownershipTransferEmail(&tenant, &transfer)
+
+	// Tell the current owner, the nominee and whoever started the transfer,
+	// once each. Delivery is best effort so a retry never re-sends to
+	// recipients that already got the email.
+	recipients := []string{transfer.ToUserID, transfer.InitiatedBy}
+	if transfer.FromUserID != nil {
+		recipients = append([]string{*transfer.FromUserID}, recipients...)
+	}
+
+	notified := make(map[string]bool)
+	for _, userID := range recipients {
+		if notified[userID] {
+			continue
+		}
+		notified[userID] = true
+
+		email, err := users.LookupEmail(userID)
+		if err != nil {
+			fmt.Printf("failed to resolve email for user %s: %v\n", userID, err)
+			continue
+		}
+		if err := sendEmail(h.cfg, email, subject, body); err != nil {
+			fmt.Printf("failed to notify %s about ownership transfer %s: %v\n", email, transfer.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func ownershipTransferEmail(tenant *models.Tenant, transfer *models.TenantOwnershipTransfer) (string, string) {
+	var subject, summary string
+	switch transfer.Status {
+	case models.OwnershipTransferPending:
+		subject = fmt.Sprintf("Ownership transfer of %s requested", tenant.Name)
+		summary = fmt.Sprintf("A transfer of ownership of %s (%s) has been requested. The nominated owner can accept or decline it until %s.",
+			tenant.Name, tenant.Slug, transfer.ExpiresAt.Format("Jan 02, 2006 at 3:04 PM"))
+		if transfer.Message != "" {
+			summary += "\n\nMessage: " + transfer.Message
+		}
+	case models.OwnershipTransferAccepted:
+		subject = fmt.Sprintf("Ownership of %s has been transferred", tenant.Name)
+		summary = fmt.Sprintf("The ownership transfer of %s (%s) was accepted. The new owner now has the Admin role.", tenant.Name, tenant.Slug)
+	default:
+		subject = fmt.Sprintf("Ownership transfer of %s %s", tenant.Name, transfer.Status)
+		summary = fmt.Sprintf("The ownership transfer of %s (%s) was %s. Ownership has not changed.", tenant.Name, tenant.Slug, transfer.Status)
+	}
+
+	body := fmt.Sprintf(`
+Hello,
+
+%s
+
+Best regards,
+The Team
+	`, summary)
+
+	return subject, body
+}
@@ -0,0 +1,238 @@
+package tasks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/config"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/pkg/signing"
+	"github.com/ysaakpr/rex/internal/repository"
+)
+
+// errUnsignedRequest is returned instead of sending a request to a service
+// when neither the service nor TENANT_PROVISIONING_SECRET provides a signing
+// secret, since services expect every request to be signed
+var errUnsignedRequest = errors.New("no signing secret configured; refusing to send an unsigned request")
+
+// provisioningPlan pairs the enabled services in the registry with a tenant's
+// steps for one operation
+type provisioningPlan struct {
+	operation models.ProvisioningOperation
+	services  map[string]*models.DownstreamService
+	steps     []*models.TenantProvisioningStep
+}
+
+// loadProvisioningPlan creates any missing steps for the enabled services the
+// tenant is provisioned in and returns them in registry order. Steps of
+// services that have since been disabled or removed are left out and do not
+// block completion.
+func loadProvisioningPlan(
+	serviceRepo repository.DownstreamServiceRepository,
+	provisioningRepo repository.ProvisioningRepository,
+	tenant *models.Tenant,
+	operation models.ProvisioningOperation,
+) (*provisioningPlan, error) {
+	registered, err := serviceRepo.ListEnabled()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load service registry: %w", err)
+	}
+	enabled := models.ServicesForTenant(registered, tenant)
+
+	names := make([]string, len(enabled))
+	plan := &provisioningPlan{
+		operation: operation,
+		services:  make(map[string]*models.DownstreamService, len(enabled)),
+	}
+	for i, service := range enabled {
+		names[i] = service.Name
+		plan.services[service.Name] = service
+	}
+
+	if err := provisioningRepo.EnsureSteps(tenant.ID, operation, names); err != nil {
+		return nil, fmt.Errorf("failed to create %s steps: %w", operation, err)
+	}
+
+	steps, err := provisioningRepo.ListByTenant(tenant.ID, operation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s steps: %w", operation, err)
+	}
+
+	byService := make(map[string]*models.TenantProvisioningStep, len(steps))
+	for _, step := range steps {
+		byService[step.Service] = step
+	}
+	for _, name := range names {
+		if step, ok := byService[name]; ok {
+			plan.steps = append(plan.steps, step)
+		}
+	}
+
+	return plan, nil
+}
+
+// ready reports whether step can run now. Provisioning waits for the services
+// the step's service depends on; deprovisioning waits for the services that
+// depend on it.
+func (p *provisioningPlan) ready(step *models.TenantProvisioningStep) bool {
+	status := make(map[string]models.ProvisioningStepStatus, len(p.steps))
+	for _, s := range p.steps {
+		status[s.Service] = s.Status
+	}
+
+	blocked := func(name string) bool {
+		s, ok := status[name]
+		return ok && s != models.ProvisioningStepSucceeded
+	}
+
+	if p.operation == models.ProvisioningOperationDeprovision {
+		for _, other := range p.services {
+			for _, dep := range other.DependsOn {
+				if dep.Name == step.Service && blocked(other.Name) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	for _, dep := range p.services[step.Service].DependsOn {
+		if blocked(dep.Name) {
+			return false
+		}
+	}
+	return true
+}
+
+// run calls each step that has neither succeeded nor been accepted for a
+// callback, in dependency order, and records the outcome. A failing step does
+// not stop independent ones; the failures are returned so the caller can fail
+// the task and let asynq retry just those steps. call reports accepted when the
+// service answered 202; such steps wait up to callbackTimeout for the service to
+// call back, and steps depending on them run when the job is queued again.
+func (p *provisioningPlan) run(
+	provisioningRepo repository.ProvisioningRepository,
+	callbackTimeout time.Duration,
+	call func(service *models.DownstreamService, step *models.TenantProvisioningStep) (accepted bool, err error),
+) ([]string, error) {
+	var failures []string
+	attempted := make(map[uuid.UUID]bool, len(p.steps))
+
+	// Keep passing over the steps while one finishing unblocks another
+	for progressed := true; progressed; {
+		progressed = false
+		for _, step := range p.steps {
+			if attempted[step.ID] ||
+				step.Status == models.ProvisioningStepSucceeded ||
+				step.Status == models.ProvisioningStepAccepted ||
+				!p.ready(step) {
+				continue
+			}
+			attempted[step.ID] = true
+			progressed = true
+
+			if err := provisioningRepo.RecordAttempt(step.ID); err != nil {
+				return nil, fmt.Errorf("failed to record %s attempt: %w", step.Operation, err)
+			}
+
+			accepted, err := call(p.services[step.Service], step)
+			if err != nil {
+				fmt.Printf("Failed to %s tenant in service %s: %v\n", step.Operation, step.Service, err)
+				if markErr := provisioningRepo.MarkFailed(step.ID, err); markErr != nil {
+					return nil, fmt.Errorf("failed to record %s failure: %w", step.Operation, markErr)
+				}
+				step.Status = models.ProvisioningStepFailed
+				failures = append(failures, fmt.Sprintf("%s: %v", step.Service, err))
+				continue
+			}
+
+			if accepted {
+				if err := provisioningRepo.MarkAccepted(step.ID, time.Now().Add(callbackTimeout)); err != nil {
+					return nil, fmt.Errorf("failed to record %s acceptance: %w", step.Operation, err)
+				}
+				step.Status = models.ProvisioningStepAccepted
+				fmt.Printf("Service %s accepted %s of tenant, awaiting callback\n", step.Service, step.Operation)
+				continue
+			}
+
+			if err := provisioningRepo.MarkSucceeded(step.ID); err != nil {
+				return nil, fmt.Errorf("failed to record %s success: %w", step.Operation, err)
+			}
+			step.Status = models.ProvisioningStepSucceeded
+			fmt.Printf("Successfully completed %s of tenant in service: %s\n", step.Operation, step.Service)
+		}
+	}
+
+	return failures, nil
+}
+
+// complete reports whether every step has been confirmed
+func (p *provisioningPlan) complete() bool {
+	for _, step := range p.steps {
+		if step.Status != models.ProvisioningStepSucceeded {
+			return false
+		}
+	}
+	return true
+}
+
+// postProvisioningRequest sends data as JSON to path on service with the step's
+// idempotency key, signing the body with the service's secret (or the global
+// one), and refusing to send when there is none. A 202 response means the service will report the outcome to the
+// callback URL included in the body.
+func postProvisioningRequest(
+	ctx context.Context,
+	cfg *config.Config,
+	service *models.DownstreamService,
+	path string,
+	step *models.TenantProvisioningStep,
+	data map[string]interface{},
+) (bool, error) {
+	secret := service.SecretOr(cfg.TenantInit.SigningSecret)
+	if secret == "" {
+		return false, errUnsignedRequest
+	}
+
+	data["idempotency_key"] = step.IdempotencyKey
+	data["callback_url"] = cfg.GetProvisioningCallbackURL()
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	client := &http.Client{
+		Timeout: 30 * time.Second,
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", service.URL(path), bytes.NewReader(jsonData))
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	// Stable across retries so services can ignore duplicate deliveries
+	req.Header.Set("Idempotency-Key", step.IdempotencyKey)
+	signing.SignRequest(req, secret, jsonData)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return false, fmt.Errorf("service returned error status %d: %s", resp.StatusCode, string(body))
+	}
+
+	return resp.StatusCode == http.StatusAccepted, nil
+}
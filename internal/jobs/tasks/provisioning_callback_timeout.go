@@ -0,0 +1,145 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/ysaakpr/rex/internal/config"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/pkg/users"
+	"github.com/ysaakpr/rex/internal/repository"
+)
+
+// callbackTimeoutBatchSize bounds how many overdue steps a single run handles
+const callbackTimeoutBatchSize = 200
+
+// provisioningRetryFunc queues a tenant's provisioning or deprovisioning job
+// again with backoff, reporting false once its retries are exhausted
+type provisioningRetryFunc func(tenantID uuid.UUID, retried int) (bool, error)
+
+// ProvisioningCallbackTimeoutTask fails accepted provisioning steps whose service
+// never called back and retries them with backoff. Platform admins are alerted
+// about the steps whose retries are exhausted.
+type ProvisioningCallbackTimeoutTask struct {
+	db                  *gorm.DB
+	cfg                 *config.Config
+	logger              *zap.Logger
+	provisioningRepo    repository.ProvisioningRepository
+	retryInitialization provisioningRetryFunc
+	retryDeprovisioning provisioningRetryFunc
+}
+
+func NewProvisioningCallbackTimeoutTask(
+	db *gorm.DB,
+	cfg *config.Config,
+	logger *zap.Logger,
+	retryInitialization, retryDeprovisioning provisioningRetryFunc,
+) *ProvisioningCallbackTimeoutTask {
+	return &ProvisioningCallbackTimeoutTask{
+		db:                  db,
+		cfg:                 cfg,
+		logger:              logger,
+		provisioningRepo:    repository.NewProvisioningRepository(db),
+		retryInitialization: retryInitialization,
+		retryDeprovisioning: retryDeprovisioning,
+	}
+}
+
+func (t *ProvisioningCallbackTimeoutTask) HandleProvisioningCallbackTimeout(ctx context.Context, task *asynq.Task) error {
+	steps, err := t.provisioningRepo.ListExpiredCallbacks(time.Now(), callbackTimeoutBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to list overdue provisioning callbacks: %w", err)
+	}
+
+	if len(steps) == 0 {
+		t.logger.Debug("No overdue provisioning callbacks")
+		return nil
+	}
+
+	message := fmt.Sprintf("no callback received within %s", t.cfg.GetProvisioningCallbackTimeout())
+
+	var exhausted []*models.TenantProvisioningStep
+	for _, step := range steps {
+		changed, err := t.provisioningRepo.ResolveAccepted(step.ID, models.ProvisioningStepFailed, message)
+		if err != nil {
+			return fmt.Errorf("failed to time out provisioning step %s: %w", step.ID, err)
+		}
+		if !changed {
+			// The callback arrived while this run was in progress
+			continue
+		}
+
+		t.logger.Error("Provisioning callback timed out",
+			zap.String("tenant_id", step.TenantID.String()),
+			zap.String("service", step.Service),
+			zap.String("operation", string(step.Operation)),
+			zap.Timep("callback_deadline", step.CallbackDeadline),
+		)
+
+		retry := t.retryInitialization
+		if step.Operation == models.ProvisioningOperationDeprovision {
+			retry = t.retryDeprovisioning
+		}
+		queued, err := retry(step.TenantID, step.Retries())
+		if err != nil {
+			return fmt.Errorf("failed to retry provisioning step %s: %w", step.ID, err)
+		}
+		if !queued {
+			exhausted = append(exhausted, step)
+		}
+	}
+
+	if len(exhausted) > 0 {
+		t.alertPlatformAdmins(exhausted)
+	}
+
+	return nil
+}
+
+// alertPlatformAdmins emails every platform admin a summary of the timed out
+// steps that will not be retried automatically
+func (t *ProvisioningCallbackTimeoutTask) alertPlatformAdmins(steps []*models.TenantProvisioningStep) {
+	var adminUserIDs []string
+	if err := t.db.Model(&models.PlatformAdmin{}).Pluck("user_id", &adminUserIDs).Error; err != nil {
+		t.logger.Error("Failed to load platform admins", zap.Error(err))
+		return
+	}
+
+	lines := make([]string, len(steps))
+	for i, step := range steps {
+		lines[i] = fmt.Sprintf("- tenant %s: %s in %s", step.TenantID, step.Operation, step.Service)
+	}
+
+	subject := fmt.Sprintf("%d provisioning callbacks timed out", len(steps))
+	body := fmt.Sprintf(`
+Hello,
+
+The following services accepted a tenant provisioning request but did not report
+the result within %s, and their automatic retries are exhausted. The steps have
+been marked failed and can be retried from the platform admin API once the
+services are healthy.
+
+%s
+
+Best regards,
+The Team
+	`, t.cfg.GetProvisioningCallbackTimeout(), strings.Join(lines, "\n"))
+
+	for _, userID := range adminUserIDs {
+		email, err := users.LookupEmail(userID)
+		if err != nil {
+			t.logger.Warn("Failed to resolve platform admin email", zap.String("user_id", userID), zap.Error(err))
+			continue
+		}
+		if err := sendEmail(t.cfg, email, subject, body); err != nil {
+			t.logger.Warn("Failed to send callback timeout alert", zap.String("email", email), zap.Error(err))
+		}
+	}
+}
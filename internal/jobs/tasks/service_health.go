@@ -0,0 +1,84 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/repository"
+)
+
+// ServiceHealthTask probes each registered service's health endpoint and
+// records the result in the registry
+type ServiceHealthTask struct {
+	serviceRepo repository.DownstreamServiceRepository
+	logger      *zap.Logger
+	client      *http.Client
+}
+
+func NewServiceHealthTask(db *gorm.DB, logger *zap.Logger) *ServiceHealthTask {
+	return &ServiceHealthTask{
+		serviceRepo: repository.NewDownstreamServiceRepository(db),
+		logger:      logger,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+func (t *ServiceHealthTask) HandleServiceHealthCheck(ctx context.Context, task *asynq.Task) error {
+	registered, err := t.serviceRepo.List()
+	if err != nil {
+		return fmt.Errorf("failed to load service registry: %w", err)
+	}
+
+	for _, service := range registered {
+		status := models.ServiceHealthHealthy
+		var healthErr string
+		if err := t.probe(ctx, service); err != nil {
+			status = models.ServiceHealthUnhealthy
+			healthErr = err.Error()
+		}
+
+		if status != service.HealthStatus {
+			t.logger.Info("Service health changed",
+				zap.String("service", service.Name),
+				zap.String("from", string(service.HealthStatus)),
+				zap.String("to", string(status)),
+				zap.String("error", healthErr),
+			)
+		}
+
+		if err := t.serviceRepo.UpdateHealth(service.ID, status, healthErr); err != nil {
+			return fmt.Errorf("failed to record health of %s: %w", service.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (t *ServiceHealthTask) probe(ctx context.Context, service *models.DownstreamService) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", service.URL(service.HealthPath), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("health check returned status %d", resp.StatusCode)
+	}
+	return nil
+}
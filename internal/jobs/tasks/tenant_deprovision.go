@@ -0,0 +1,126 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/ysaakpr/rex/internal/config"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/repository"
+)
+
+// TenantDeprovisionTask tears a deleting tenant down in every enabled service in
+// the registry, before the services each one depends on, and purges it once all
+// of them have confirmed
+type TenantDeprovisionTask struct {
+	db               *gorm.DB
+	cfg              *config.Config
+	logger           *zap.Logger
+	tenantRepo       repository.TenantRepository
+	provisioningRepo repository.ProvisioningRepository
+	serviceRepo      repository.DownstreamServiceRepository
+}
+
+func NewTenantDeprovisionTask(db *gorm.DB, cfg *config.Config, logger *zap.Logger) *TenantDeprovisionTask {
+	return &TenantDeprovisionTask{
+		db:               db,
+		cfg:              cfg,
+		logger:           logger,
+		tenantRepo:       repository.NewTenantRepository(db),
+		provisioningRepo: repository.NewProvisioningRepository(db),
+		serviceRepo:      repository.NewDownstreamServiceRepository(db),
+	}
+}
+
+type TenantDeprovisionPayload struct {
+	TenantID string `json:"tenant_id"`
+}
+
+func (t *TenantDeprovisionTask) HandleTenantDeprovisioning(ctx context.Context, task *asynq.Task) error {
+	var payload TenantDeprovisionPayload
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	tenantID, err := uuid.Parse(payload.TenantID)
+	if err != nil {
+		return fmt.Errorf("invalid tenant ID: %w", err)
+	}
+
+	tenant, err := t.tenantRepo.GetDeletedByID(tenantID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			t.logger.Info("Tenant already purged, skipping deprovisioning", zap.String("tenant_id", tenantID.String()))
+			return nil
+		}
+		return fmt.Errorf("failed to get tenant: %w", err)
+	}
+
+	if tenant.Status != models.TenantStatusDeleting {
+		t.logger.Info("Tenant is not being deleted, skipping deprovisioning",
+			zap.String("tenant_id", tenantID.String()),
+			zap.String("status", string(tenant.Status)),
+		)
+		return nil
+	}
+
+	plan, err := loadProvisioningPlan(t.serviceRepo, t.provisioningRepo, tenant, models.ProvisioningOperationDeprovision)
+	if err != nil {
+		return err
+	}
+
+	failures, err := plan.run(t.provisioningRepo, t.cfg.GetProvisioningCallbackTimeout(), func(service *models.DownstreamService, step *models.TenantProvisioningStep) (bool, error) {
+		return t.deprovisionTenantInService(ctx, service, step, tenant)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Returning an error makes asynq retry with backoff; confirmed services are
+	// skipped next time and the tenant stays in deleting until all confirm
+	if len(failures) > 0 {
+		return fmt.Errorf("tenant deprovisioning failed in %d of %d services: %s",
+			len(failures), len(plan.steps), strings.Join(failures, "; "))
+	}
+
+	// The job is queued again when an outstanding callback arrives
+	if !plan.complete() {
+		t.logger.Info("Tenant is waiting for deprovisioning callbacks", zap.String("tenant_id", tenantID.String()))
+		return nil
+	}
+
+	report, err := t.tenantRepo.Purge(tenant)
+	if err != nil {
+		if errors.Is(err, repository.ErrTenantStatusChanged) {
+			return nil
+		}
+		return fmt.Errorf("failed to purge tenant: %w", err)
+	}
+
+	t.logger.Info("Deprovisioned and purged tenant",
+		zap.String("tenant_id", report.TenantID.String()),
+		zap.String("slug", report.TenantSlug),
+		zap.Int("services", len(plan.steps)),
+	)
+
+	return nil
+}
+
+func (t *TenantDeprovisionTask) deprovisionTenantInService(ctx context.Context, service *models.DownstreamService, step *models.TenantProvisioningStep, tenant *models.Tenant) (bool, error) {
+	teardownData := map[string]interface{}{
+		"tenant_id":   tenant.ID,
+		"tenant_name": tenant.Name,
+		"tenant_slug": tenant.Slug,
+		"deleted_at":  tenant.DeletedAt.Time,
+	}
+
+	return postProvisioningRequest(ctx, t.cfg, service, service.DeprovisionPath, step, teardownData)
+}
@@ -0,0 +1,324 @@
+package tasks
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/ysaakpr/rex/internal/config"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/pkg/users"
+	"github.com/ysaakpr/rex/internal/repository"
+)
+
+// tenantExportCleanupBatchSize is how many expired archives are removed per run
+const tenantExportCleanupBatchSize = 100
+
+// TenantExportTask writes a tenant's identity data to a zip archive of JSON
+// files, recording progress as each part is written
+type TenantExportTask struct {
+	cfg        *config.Config
+	logger     *zap.Logger
+	exportRepo repository.TenantExportRepository
+	tenantRepo repository.TenantRepository
+}
+
+func NewTenantExportTask(db *gorm.DB, cfg *config.Config, logger *zap.Logger) *TenantExportTask {
+	return &TenantExportTask{
+		cfg:        cfg,
+		logger:     logger,
+		exportRepo: repository.NewTenantExportRepository(db),
+		tenantRepo: repository.NewTenantRepository(db),
+	}
+}
+
+type TenantExportPayload struct {
+	ExportID string `json:"export_id"`
+}
+
+func (t *TenantExportTask) HandleTenantExport(ctx context.Context, task *asynq.Task) error {
+	var payload TenantExportPayload
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	exportID, err := uuid.Parse(payload.ExportID)
+	if err != nil {
+		return fmt.Errorf("invalid export ID: %w", err)
+	}
+
+	export, err := t.exportRepo.GetByID(exportID)
+	if err != nil {
+		return fmt.Errorf("failed to get export: %w", err)
+	}
+	if export.Status == models.TenantExportCompleted || export.Status == models.TenantExportExpired {
+		return nil
+	}
+
+	if err := t.exportRepo.Start(exportID); err != nil {
+		return fmt.Errorf("failed to start export: %w", err)
+	}
+
+	path, size, exportErr := t.writeArchive(ctx, export)
+	if exportErr != nil {
+		if err := t.exportRepo.Fail(exportID, exportErr); err != nil {
+			return fmt.Errorf("failed to record export failure: %w", err)
+		}
+		t.logger.Error("Tenant export failed",
+			zap.String("export_id", exportID.String()),
+			zap.String("tenant_id", export.TenantID.String()),
+			zap.Error(exportErr),
+		)
+		return exportErr
+	}
+
+	if err := t.exportRepo.Complete(exportID, path, size, time.Now().Add(t.cfg.GetExportRetention())); err != nil {
+		os.Remove(path)
+		return fmt.Errorf("failed to complete export: %w", err)
+	}
+
+	t.logger.Info("Tenant export completed",
+		zap.String("export_id", exportID.String()),
+		zap.String("tenant_id", export.TenantID.String()),
+		zap.Int64("size", size),
+	)
+	return nil
+}
+
+// writeArchive writes the archive to a temporary file and moves it into place
+// once complete, so a failed attempt never leaves a partial archive behind
+func (t *TenantExportTask) writeArchive(ctx context.Context, export *models.TenantExport) (string, int64, error) {
+	if err := os.MkdirAll(t.cfg.TenantExport.Dir, 0o700); err != nil {
+		return "", 0, fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	path := filepath.Join(t.cfg.TenantExport.Dir, fmt.Sprintf("tenant-%s-export-%s.zip", export.TenantID, export.ID))
+	tmpPath := path + ".tmp"
+
+	file, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create archive: %w", err)
+	}
+	defer os.Remove(tmpPath)
+
+	archive := zip.NewWriter(file)
+	writeErr := t.writeParts(ctx, archive, export)
+	if err := archive.Close(); err != nil && writeErr == nil {
+		writeErr = fmt.Errorf("failed to finish archive: %w", err)
+	}
+	if err := file.Close(); err != nil && writeErr == nil {
+		writeErr = fmt.Errorf("failed to close archive: %w", err)
+	}
+	if writeErr != nil {
+		return "", 0, writeErr
+	}
+
+	info, err := os.Stat(tmpPath)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to stat archive: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return "", 0, fmt.Errorf("failed to move archive into place: %w", err)
+	}
+
+	return path, info.Size(), nil
+}
+
+func (t *TenantExportTask) writeParts(ctx context.Context, archive *zip.Writer, export *models.TenantExport) error {
+	// A tenant deleted after the export was queued can still be exported
+	// until it is purged
+	tenant, err := t.tenantRepo.GetByID(export.TenantID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		tenant, err = t.tenantRepo.GetDeletedByID(export.TenantID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get tenant: %w", err)
+	}
+
+	manifest := &models.TenantExportManifest{
+		ExportID:    export.ID,
+		TenantID:    export.TenantID,
+		GeneratedAt: time.Now(),
+		RequestedBy: export.RequestedBy,
+		Files:       make(map[string]int),
+	}
+	emails := make(map[string]string)
+
+	parts := []struct {
+		stage string
+		file  string
+		load  func() (interface{}, int, error)
+	}{
+		{"tenant", "tenant.json", func() (interface{}, int, error) {
+			return tenant.ToResponse(), 1, nil
+		}},
+		{"members", "members.json", func() (interface{}, int, error) {
+			members, err := t.exportMembers(ctx, tenant.ID, emails)
+			return members, len(members), err
+		}},
+		{"invitations", "invitations.json", func() (interface{}, int, error) {
+			invitations, err := t.exportInvitations(ctx, tenant.ID, emails)
+			return invitations, len(invitations), err
+		}},
+		{"roles", "roles.json", func() (interface{}, int, error) {
+			roles, err := t.exportRepo.ListTenantRoles(tenant.ID)
+			return roles, len(roles), err
+		}},
+		{"policies", "policies.json", func() (interface{}, int, error) {
+			policies, err := t.exportRepo.ListTenantPolicies(tenant.ID)
+			return policies, len(policies), err
+		}},
+	}
+
+	for i, part := range parts {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := t.exportRepo.UpdateProgress(export.ID, i*100/(len(parts)+1), part.stage); err != nil {
+			return fmt.Errorf("failed to record export progress: %w", err)
+		}
+
+		data, count, err := part.load()
+		if err != nil {
+			return fmt.Errorf("failed to export %s: %w", part.stage, err)
+		}
+		if err := writeJSONFile(archive, part.file, data); err != nil {
+			return err
+		}
+		manifest.Files[part.file] = count
+	}
+
+	return writeJSONFile(archive, "manifest.json", manifest)
+}
+
+// exportMembers lists the tenant's members with their role and email. Users
+// whose email cannot be resolved are exported without one.
+func (t *TenantExportTask) exportMembers(ctx context.Context, tenantID uuid.UUID, emails map[string]string) ([]*models.ExportedMember, error) {
+	members, err := t.exportRepo.ListTenantMembers(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	exported := make([]*models.ExportedMember, len(members))
+	for i, member := range members {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		exported[i] = &models.ExportedMember{
+			ID:        member.ID,
+			UserID:    member.UserID,
+			Email:     t.lookupEmail(member.UserID, emails),
+			Status:    member.Status,
+			Role:      exportedRoleRef(&member.Role),
+			InvitedBy: member.InvitedBy,
+			JoinedAt:  member.JoinedAt,
+			CreatedAt: member.CreatedAt,
+			UpdatedAt: member.UpdatedAt,
+		}
+	}
+	return exported, nil
+}
+
+// exportInvitations lists every invitation the tenant sent, whatever its
+// status, without the invitation token
+func (t *TenantExportTask) exportInvitations(ctx context.Context, tenantID uuid.UUID, emails map[string]string) ([]*models.ExportedInvitation, error) {
+	invitations, err := t.exportRepo.ListTenantInvitations(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	exported := make([]*models.ExportedInvitation, len(invitations))
+	for i, invitation := range invitations {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		exported[i] = &models.ExportedInvitation{
+			ID:             invitation.ID,
+			Email:          invitation.Email,
+			Role:           exportedRoleRef(&invitation.Role),
+			InvitedBy:      invitation.InvitedBy,
+			InvitedByEmail: t.lookupEmail(invitation.InvitedBy, emails),
+			Status:         invitation.Status,
+			SentAt:         invitation.CreatedAt,
+			AcceptedAt:     invitation.AcceptedAt,
+			ExpiresAt:      invitation.ExpiresAt,
+			LastUpdatedAt:  invitation.UpdatedAt,
+		}
+	}
+	return exported, nil
+}
+
+func (t *TenantExportTask) lookupEmail(userID string, emails map[string]string) string {
+	if email, ok := emails[userID]; ok {
+		return email
+	}
+	email, err := users.LookupEmail(userID)
+	if err != nil {
+		t.logger.Warn("Failed to resolve email for export", zap.String("user_id", userID), zap.Error(err))
+	}
+	emails[userID] = email
+	return email
+}
+
+func exportedRoleRef(role *models.Role) *models.ExportedRoleRef {
+	if role == nil || role.ID == uuid.Nil {
+		return nil
+	}
+	return &models.ExportedRoleRef{
+		ID:       role.ID,
+		Name:     role.Name,
+		IsSystem: role.TenantID == nil,
+	}
+}
+
+func writeJSONFile(archive *zip.Writer, name string, data interface{}) error {
+	w, err := archive.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to add %s to archive: %w", name, err)
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// HandleTenantExportCleanup deletes archives past their retention and revokes
+// their download links
+func (t *TenantExportTask) HandleTenantExportCleanup(ctx context.Context, task *asynq.Task) error {
+	expired, err := t.exportRepo.ListExpired(time.Now(), tenantExportCleanupBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to list expired exports: %w", err)
+	}
+
+	removed := 0
+	for _, export := range expired {
+		if err := os.Remove(export.FilePath); err != nil && !os.IsNotExist(err) {
+			t.logger.Error("Failed to remove expired export archive",
+				zap.String("export_id", export.ID.String()),
+				zap.Error(err),
+			)
+			continue
+		}
+		if err := t.exportRepo.MarkExpired(export.ID); err != nil {
+			return fmt.Errorf("failed to mark export expired: %w", err)
+		}
+		removed++
+	}
+
+	if removed > 0 {
+		t.logger.Info("Removed expired tenant exports", zap.Int("count", removed))
+	}
+	return nil
+}
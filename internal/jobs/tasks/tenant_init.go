@@ -3,27 +3,31 @@ package tasks
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
-	"time"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/hibiken/asynq"
 	"github.com/ysaakpr/rex/internal/config"
 	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/repository"
 	"gorm.io/gorm"
 )
 
 type TenantInitHandler struct {
-	db  *gorm.DB
-	cfg *config.Config
+	db               *gorm.DB
+	cfg              *config.Config
+	provisioningRepo repository.ProvisioningRepository
+	serviceRepo      repository.DownstreamServiceRepository
 }
 
 func NewTenantInitHandler(db *gorm.DB, cfg *config.Config) *TenantInitHandler {
 	return &TenantInitHandler{
-		db:  db,
-		cfg: cfg,
+		db:               db,
+		cfg:              cfg,
+		provisioningRepo: repository.NewProvisioningRepository(db),
+		serviceRepo:      repository.NewDownstreamServiceRepository(db),
 	}
 }
 
@@ -31,6 +35,11 @@ type TenantInitPayload struct {
 	TenantID string `json:"tenant_id"`
 }
 
+// HandleTenantInitialization provisions the tenant in every enabled service in
+// the registry, after the services each one depends on. Each service is tracked
+// as its own step: a failing service does not stop independent ones, and a
+// retry only calls the services that have not yet succeeded. The tenant is
+// activated once every step has succeeded.
 func (h *TenantInitHandler) HandleTenantInitialization(ctx context.Context, task *asynq.Task) error {
 	var payload TenantInitPayload
 	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
@@ -50,38 +59,41 @@ func (h *TenantInitHandler) HandleTenantInitialization(ctx context.Context, task
 		return fmt.Errorf("failed to get tenant: %w", err)
 	}
 
-	// Check if already initialized
-	if tenant.Status == models.TenantStatusActive {
-		fmt.Printf("Tenant %s is already active\n", tenantID)
+	// Only pending tenants are provisioned; anything else was activated already
+	// or moved on (suspended, deleted) while the job was queued
+	if tenant.Status != models.TenantStatusPending {
+		fmt.Printf("Tenant %s is %s, skipping initialization\n", tenantID, tenant.Status)
 		return nil
 	}
 
-	// Initialize tenant in each backend service
-	services := h.cfg.TenantInit.Services
-	if len(services) == 0 {
-		fmt.Println("No services configured for tenant initialization")
-		// Still mark as active
-		return h.markTenantActive(tenantID)
+	plan, err := loadProvisioningPlan(h.serviceRepo, h.provisioningRepo, &tenant, models.ProvisioningOperationProvision)
+	if err != nil {
+		return err
+	}
+
+	failures, err := plan.run(h.provisioningRepo, h.cfg.GetProvisioningCallbackTimeout(), func(service *models.DownstreamService, step *models.TenantProvisioningStep) (bool, error) {
+		return h.initializeTenantInService(ctx, service, step, &tenant)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Returning an error makes asynq retry; succeeded steps are skipped next time
+	if len(failures) > 0 {
+		return fmt.Errorf("tenant initialization failed in %d of %d services: %s",
+			len(failures), len(plan.steps), strings.Join(failures, "; "))
 	}
 
-	// Call each service sequentially for reliable initialization
-	for _, serviceURL := range services {
-		if err := h.initializeTenantInService(ctx, serviceURL, &tenant); err != nil {
-			// Log error but continue with other services
-			// In a production system, you might want to implement partial failure handling
-			fmt.Printf("Failed to initialize tenant in service %s: %v\n", serviceURL, err)
-			// Return error to trigger retry
-			return fmt.Errorf("failed to initialize in service %s: %w", serviceURL, err)
-		}
-		fmt.Printf("Successfully initialized tenant in service: %s\n", serviceURL)
+	// The job is queued again when an outstanding callback arrives
+	if !plan.complete() {
+		fmt.Printf("Tenant %s is waiting for provisioning callbacks\n", tenantID)
+		return nil
 	}
 
-	// Mark tenant as active
-	return h.markTenantActive(tenantID)
+	return h.markTenantActive(&tenant)
 }
 
-func (h *TenantInitHandler) initializeTenantInService(ctx context.Context, serviceURL string, tenant *models.Tenant) error {
-	// Prepare initialization request
+func (h *TenantInitHandler) initializeTenantInService(ctx context.Context, service *models.DownstreamService, step *models.TenantProvisioningStep, tenant *models.Tenant) (bool, error) {
 	initData := map[string]interface{}{
 		"tenant_id":   tenant.ID,
 		"tenant_name": tenant.Name,
@@ -90,48 +102,24 @@ func (h *TenantInitHandler) initializeTenantInService(ctx context.Context, servi
 		"created_at":  tenant.CreatedAt,
 	}
 
-	jsonData, err := json.Marshal(initData)
-	if err != nil {
-		return fmt.Errorf("failed to marshal init data: %w", err)
-	}
-
-	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-	}
-
-	// Construct the initialization endpoint
-	// Assuming each service has a POST /api/v1/tenants/initialize endpoint
-	url := fmt.Sprintf("%s/api/v1/tenants/initialize", serviceURL)
-
-	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-
-	// Add any authentication headers if needed
-	// req.Header.Set("Authorization", "Bearer " + token)
+	return postProvisioningRequest(ctx, h.cfg, service, service.ProvisionPath, step, initData)
+}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to send request: %w", err)
+func (h *TenantInitHandler) markTenantActive(tenant *models.Tenant) error {
+	if !tenant.Status.CanTransitionTo(models.TenantStatusActive) {
+		return fmt.Errorf("tenant %s cannot be activated from status %s", tenant.ID, tenant.Status)
 	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("service returned error status %d: %s", resp.StatusCode, string(body))
+	_, err := repository.NewTenantRepository(h.db).TransitionStatus(
+		tenant.ID,
+		tenant.Status,
+		models.TenantStatusActive,
+		"tenant initialization completed",
+		models.SystemActorID,
+	)
+	if errors.Is(err, repository.ErrTenantStatusChanged) {
+		// Another worker activated or moved the tenant concurrently
+		return nil
 	}
-
-	fmt.Printf("Service response: %s\n", string(jsonData))
-	return nil
-}
-
-func (h *TenantInitHandler) markTenantActive(tenantID uuid.UUID) error {
-	return h.db.Model(&models.Tenant{}).
-		Where("id = ?", tenantID).
-		Update("status", models.TenantStatusActive).Error
+	return err
 }
@@ -0,0 +1,179 @@
+package tasks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/ysaakpr/rex/internal/config"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/pkg/signing"
+	"github.com/ysaakpr/rex/internal/pkg/users"
+	"github.com/ysaakpr/rex/internal/repository"
+	"gorm.io/gorm"
+)
+
+// TenantLifecycleHandler reacts to tenant status transitions: it emails the
+// tenant admins and tells every downstream service about the new status.
+type TenantLifecycleHandler struct {
+	db         *gorm.DB
+	cfg        *config.Config
+	memberRepo repository.MemberRepository
+}
+
+func NewTenantLifecycleHandler(db *gorm.DB, cfg *config.Config) *TenantLifecycleHandler {
+	return &TenantLifecycleHandler{
+		db:         db,
+		cfg:        cfg,
+		memberRepo: repository.NewMemberRepository(db),
+	}
+}
+
+type TenantStatusChangePayload struct {
+	TransitionID string `json:"transition_id"`
+}
+
+func (h *TenantLifecycleHandler) HandleTenantStatusChange(ctx context.Context, task *asynq.Task) error {
+	var payload TenantStatusChangePayload
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	transitionID, err := uuid.Parse(payload.TransitionID)
+	if err != nil {
+		return fmt.Errorf("invalid transition ID: %w", err)
+	}
+
+	var transition models.TenantStatusTransition
+	if err := h.db.Where("id = ?", transitionID).First(&transition).Error; err != nil {
+		return fmt.Errorf("failed to get transition: %w", err)
+	}
+
+	var tenant models.Tenant
+	if err := h.db.Unscoped().Where("id = ?", transition.TenantID).First(&tenant).Error; err != nil {
+		return fmt.Errorf("failed to get tenant: %w", err)
+	}
+
+	fmt.Printf("Processing status change for tenant %s: %s -> %s\n", tenant.ID, transition.FromStatus, transition.ToStatus)
+
+	// Admin notifications are best effort; a retry must not re-send them
+	// just because a downstream service was unavailable.
+	h.notifyTenantAdmins(&tenant, &transition)
+
+	return h.notifyServices(ctx, &tenant, &transition)
+}
+
+func (h *TenantLifecycleHandler) notifyTenantAdmins(tenant *models.Tenant, transition *models.TenantStatusTransition) {
+	// Managers hold the management permission through any role, custom or
+	// granted to one of their groups
+	adminUserIDs, err := h.memberRepo.ListManagerUserIDs(tenant.ID)
+	if err != nil {
+		fmt.Printf("failed to load admins for tenant %s: %v\n", tenant.ID, err)
+		return
+	}
+
+	subject := fmt.Sprintf("%s is now %s", tenant.Name, transition.ToStatus)
+	body := fmt.Sprintf(`
+Hello,
+
+The status of %s (%s) changed from %s to %s.
+
+Reason: %s
+
+Best regards,
+The Team
+	`, tenant.Name, tenant.Slug, transition.FromStatus, transition.ToStatus, reasonOrDefault(transition.Reason))
+
+	for _, userID := range adminUserIDs {
+		email, err := users.LookupEmail(userID)
+		if err != nil {
+			fmt.Printf("failed to resolve email for user %s: %v\n", userID, err)
+			continue
+		}
+		if err := sendEmail(h.cfg, email, subject, body); err != nil {
+			fmt.Printf("failed to notify %s about tenant %s: %v\n", email, tenant.ID, err)
+		}
+	}
+}
+
+func (h *TenantLifecycleHandler) notifyServices(ctx context.Context, tenant *models.Tenant, transition *models.TenantStatusTransition) error {
+	statusData := map[string]interface{}{
+		"tenant_id":     tenant.ID,
+		"tenant_slug":   tenant.Slug,
+		"transition_id": transition.ID,
+		"from_status":   transition.FromStatus,
+		"to_status":     transition.ToStatus,
+		"reason":        transition.Reason,
+		"changed_at":    transition.CreatedAt,
+	}
+
+	registered, err := repository.NewDownstreamServiceRepository(h.db).ListEnabled()
+	if err != nil {
+		return fmt.Errorf("failed to load service registry: %w", err)
+	}
+
+	// Notify every service even if one fails, then retry the job as a whole
+	var errs []error
+	for _, service := range models.ServicesForTenant(registered, tenant) {
+		url := service.URL(models.ServiceStatusPath)
+		if err := postJSON(ctx, url, service.SecretOr(h.cfg.TenantInit.SigningSecret), statusData); err != nil {
+			fmt.Printf("Failed to notify service %s of tenant status change: %v\n", service.Name, err)
+			errs = append(errs, fmt.Errorf("service %s: %w", service.Name, err))
+			continue
+		}
+		fmt.Printf("Notified service %s of tenant status change\n", service.Name)
+	}
+
+	return errors.Join(errs...)
+}
+
+// postJSON sends data as JSON signed with secret, refusing to send it unsigned
+func postJSON(ctx context.Context, url string, secret string, data interface{}) error {
+	if secret == "" {
+		return errUnsignedRequest
+	}
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	client := &http.Client{
+		Timeout: 30 * time.Second,
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	signing.SignRequest(req, secret, jsonData)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("service returned error status %d: %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+func reasonOrDefault(reason string) string {
+	if reason == "" {
+		return "(no reason given)"
+	}
+	return reason
+}
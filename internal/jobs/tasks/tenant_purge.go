@@ -0,0 +1,131 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/ysaakpr/rex/internal/config"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/repository"
+)
+
+// tenantPurgeBatchSize bounds how many tenants a single run purges
+const tenantPurgeBatchSize = 100
+
+// TenantPurgeTask permanently removes soft-deleted tenants past the retention
+// window. When downstream services are registered the tenant is first moved to
+// deleting and handed to the deprovisioning job, which purges it once every
+// service has torn its data down.
+type TenantPurgeTask struct {
+	db                    *gorm.DB
+	cfg                   *config.Config
+	logger                *zap.Logger
+	enqueueDeprovisioning func(tenantID uuid.UUID) error
+}
+
+func NewTenantPurgeTask(db *gorm.DB, cfg *config.Config, logger *zap.Logger, enqueueDeprovisioning func(tenantID uuid.UUID) error) *TenantPurgeTask {
+	return &TenantPurgeTask{
+		db:                    db,
+		cfg:                   cfg,
+		logger:                logger,
+		enqueueDeprovisioning: enqueueDeprovisioning,
+	}
+}
+
+func (t *TenantPurgeTask) HandleTenantPurge(ctx context.Context, task *asynq.Task) error {
+	tenantRepo := repository.NewTenantRepository(t.db)
+	cutoff := time.Now().Add(-t.cfg.GetTenantRetention())
+
+	t.logger.Info("Starting tenant purge job", zap.Time("deleted_before", cutoff))
+
+	tenants, err := tenantRepo.ListPurgeable(cutoff, tenantPurgeBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to list purgeable tenants: %w", err)
+	}
+
+	if len(tenants) == 0 {
+		t.logger.Debug("No tenants past retention")
+		return nil
+	}
+
+	registered, err := repository.NewDownstreamServiceRepository(t.db).ListEnabled()
+	if err != nil {
+		return fmt.Errorf("failed to load service registry: %w", err)
+	}
+
+	var failed int
+	for _, tenant := range tenants {
+		if len(models.ServicesForTenant(registered, tenant)) > 0 {
+			if err := t.startDeprovisioning(tenantRepo, tenant); err != nil {
+				failed++
+				t.logger.Error("Failed to start tenant deprovisioning",
+					zap.String("tenant_id", tenant.ID.String()),
+					zap.Error(err),
+				)
+			}
+			continue
+		}
+
+		report, err := tenantRepo.Purge(tenant)
+		if err != nil {
+			if errors.Is(err, repository.ErrTenantStatusChanged) {
+				t.logger.Info("Tenant restored before purge, skipping", zap.String("tenant_id", tenant.ID.String()))
+				continue
+			}
+			failed++
+			t.logger.Error("Failed to purge tenant",
+				zap.String("tenant_id", tenant.ID.String()),
+				zap.Error(err),
+			)
+			continue
+		}
+
+		t.logger.Info("Purged tenant",
+			zap.String("tenant_id", report.TenantID.String()),
+			zap.String("slug", report.TenantSlug),
+			zap.Int64("members", report.MembersPurged),
+			zap.Int64("invitations", report.InvitationsPurged),
+			zap.Int64("roles", report.RolesPurged),
+			zap.Int64("policies", report.PoliciesPurged),
+			zap.Int64("transitions", report.TransitionsPurged),
+		)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to purge %d of %d tenants", failed, len(tenants))
+	}
+
+	return nil
+}
+
+// startDeprovisioning moves tenant to deleting and queues its teardown
+func (t *TenantPurgeTask) startDeprovisioning(tenantRepo repository.TenantRepository, tenant *models.Tenant) error {
+	_, err := tenantRepo.TransitionStatus(
+		tenant.ID,
+		models.TenantStatusDeleted,
+		models.TenantStatusDeleting,
+		"retention period expired",
+		models.SystemActorID,
+	)
+	if err != nil {
+		if errors.Is(err, repository.ErrTenantStatusChanged) {
+			t.logger.Info("Tenant restored before purge, skipping", zap.String("tenant_id", tenant.ID.String()))
+			return nil
+		}
+		return err
+	}
+
+	if err := t.enqueueDeprovisioning(tenant.ID); err != nil {
+		return err
+	}
+
+	t.logger.Info("Started tenant deprovisioning", zap.String("tenant_id", tenant.ID.String()))
+	return nil
+}
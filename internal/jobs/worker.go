@@ -5,21 +5,33 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/google/uuid"
 	"github.com/hibiken/asynq"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 
 	"github.com/ysaakpr/rex/internal/config"
 	"github.com/ysaakpr/rex/internal/jobs/tasks"
+	"github.com/ysaakpr/rex/internal/models"
 )
 
 type Worker struct {
 	server    *asynq.Server
 	mux       *asynq.ServeMux
 	scheduler *asynq.Scheduler
+	client    Client
 }
 
-func NewWorker(cfg *config.Config, db *gorm.DB, logger *zap.Logger) (*Worker, error) {
+// NewWorker registers every task handler. checkGrant and getEntitlements are
+// the services' role grant check and plan entitlements, which this package
+// cannot import.
+func NewWorker(
+	cfg *config.Config,
+	db *gorm.DB,
+	logger *zap.Logger,
+	checkGrant func(tenant *models.Tenant, actorID string, roleIDs []uuid.UUID) error,
+	getEntitlements func(tenantID uuid.UUID) (*models.TenantEntitlements, error),
+) (*Worker, error) {
 	redisOpt := asynq.RedisClientOpt{
 		Addr:     cfg.GetRedisAddr(),
 		Password: cfg.Redis.Password,
@@ -39,6 +51,12 @@ func NewWorker(cfg *config.Config, db *gorm.DB, logger *zap.Logger) (*Worker, er
 
 	mux := asynq.NewServeMux()
 
+	// Client for tasks that queue follow-up work
+	client, err := NewClient(cfg.GetRedisAddr(), cfg.Redis.Password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create job client: %w", err)
+	}
+
 	// Register task handlers
 	tenantInitHandler := tasks.NewTenantInitHandler(db, cfg)
 	mux.HandleFunc(TypeTenantInitialization, tenantInitHandler.HandleTenantInitialization)
@@ -46,17 +64,58 @@ func NewWorker(cfg *config.Config, db *gorm.DB, logger *zap.Logger) (*Worker, er
 	invitationHandler := tasks.NewInvitationHandler(db, cfg)
 	mux.HandleFunc(TypeUserInvitation, invitationHandler.HandleUserInvitation)
 
+	tenantLifecycleHandler := tasks.NewTenantLifecycleHandler(db, cfg)
+	mux.HandleFunc(TypeTenantStatusChange, tenantLifecycleHandler.HandleTenantStatusChange)
+
 	// Initialize system user expiry task
 	systemUserExpiryTask := tasks.NewSystemUserExpiryTask(db, logger)
 	mux.HandleFunc(TypeSystemUserExpiry, systemUserExpiryTask.HandleSystemUserExpiry)
 
+	tenantPurgeTask := tasks.NewTenantPurgeTask(db, cfg, logger, client.EnqueueTenantDeprovisioning)
+	mux.HandleFunc(TypeTenantPurge, tenantPurgeTask.HandleTenantPurge)
+
+	tenantDeprovisionTask := tasks.NewTenantDeprovisionTask(db, cfg, logger)
+	mux.HandleFunc(TypeTenantDeprovisioning, tenantDeprovisionTask.HandleTenantDeprovisioning)
+
+	provisioningTimeoutTask := tasks.NewProvisioningCallbackTimeoutTask(db, cfg, logger,
+		client.RetryTenantInitialization, client.RetryTenantDeprovisioning)
+	mux.HandleFunc(TypeProvisioningTimeout, provisioningTimeoutTask.HandleProvisioningCallbackTimeout)
+
+	serviceHealthTask := tasks.NewServiceHealthTask(db, logger)
+	mux.HandleFunc(TypeServiceHealthCheck, serviceHealthTask.HandleServiceHealthCheck)
+
+	metadataRevalidationTask := tasks.NewMetadataRevalidationTask(db, logger)
+	mux.HandleFunc(TypeMetadataRevalidation, metadataRevalidationTask.HandleMetadataRevalidation)
+
+	tenantExportTask := tasks.NewTenantExportTask(db, cfg, logger)
+	mux.HandleFunc(TypeTenantExport, tenantExportTask.HandleTenantExport)
+	mux.HandleFunc(TypeTenantExportCleanup, tenantExportTask.HandleTenantExportCleanup)
+
+	ownershipTransferHandler := tasks.NewOwnershipTransferHandler(db, cfg)
+	mux.HandleFunc(TypeOwnershipTransfer, ownershipTransferHandler.HandleOwnershipTransferNotification)
+
+	accessRequestHandler := tasks.NewAccessRequestHandler(db, cfg)
+	mux.HandleFunc(TypeAccessRequest, accessRequestHandler.HandleAccessRequestNotification)
+
+	accessReviewHandler := tasks.NewAccessReviewHandler(db, cfg)
+	mux.HandleFunc(TypeAccessReview, accessReviewHandler.HandleAccessReviewNotification)
+
+	memberBulkTask := tasks.NewMemberBulkTask(db, logger, checkGrant, getEntitlements)
+	mux.HandleFunc(TypeMemberBulkOperation, memberBulkTask.HandleMemberBulkOperation)
+
+	memberReactivationTask := tasks.NewMemberReactivationTask(db, logger)
+	mux.HandleFunc(TypeMemberReactivation, memberReactivationTask.HandleMemberReactivation)
+
+	inactiveMemberTask := tasks.NewInactiveMemberDeactivationTask(db, logger)
+	mux.HandleFunc(TypeInactiveMemberDeactivation, inactiveMemberTask.HandleInactiveMemberDeactivation)
+
 	// Initialize scheduler for periodic tasks
 	scheduler := asynq.NewScheduler(redisOpt, &asynq.SchedulerOpts{
 		Logger: logger.Sugar(),
 	})
 
 	// Schedule system user expiry check (runs every hour)
-	_, err := scheduler.Register(
+	_, err = scheduler.Register(
 		"@hourly",
 		asynq.NewTask(TypeSystemUserExpiry, nil),
 		asynq.Queue(QueueLow),
@@ -67,10 +126,89 @@ func NewWorker(cfg *config.Config, db *gorm.DB, logger *zap.Logger) (*Worker, er
 
 	logger.Info("Scheduled periodic task: system user expiry check (hourly)")
 
+	// Schedule purge of tenants past the retention window (runs daily)
+	_, err = scheduler.Register(
+		"@daily",
+		asynq.NewTask(TypeTenantPurge, nil),
+		asynq.Queue(QueueLow),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register periodic task: %w", err)
+	}
+
+	logger.Info("Scheduled periodic task: tenant purge (daily)",
+		zap.Int("retention_days", cfg.TenantLifecycle.RetentionDays),
+	)
+
+	// Fail provisioning steps whose service never called back (every 5 minutes)
+	_, err = scheduler.Register(
+		"@every 5m",
+		asynq.NewTask(TypeProvisioningTimeout, nil),
+		asynq.Queue(QueueLow),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register periodic task: %w", err)
+	}
+
+	logger.Info("Scheduled periodic task: provisioning callback timeouts (every 5 minutes)",
+		zap.Duration("callback_timeout", cfg.GetProvisioningCallbackTimeout()),
+	)
+
+	// Probe registered downstream services (every 5 minutes)
+	_, err = scheduler.Register(
+		"@every 5m",
+		asynq.NewTask(TypeServiceHealthCheck, nil),
+		asynq.Queue(QueueLow),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register periodic task: %w", err)
+	}
+
+	logger.Info("Scheduled periodic task: service registry health check (every 5 minutes)")
+
+	// Remove tenant export archives past their retention (runs hourly)
+	_, err = scheduler.Register(
+		"@hourly",
+		asynq.NewTask(TypeTenantExportCleanup, nil),
+		asynq.Queue(QueueLow),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register periodic task: %w", err)
+	}
+
+	logger.Info("Scheduled periodic task: tenant export cleanup (hourly)",
+		zap.Int("retention_days", cfg.TenantExport.RetentionDays),
+	)
+
+	// Reactivate suspended members whose scheduled reactivation is due (every 5 minutes)
+	_, err = scheduler.Register(
+		"@every 5m",
+		asynq.NewTask(TypeMemberReactivation, nil),
+		asynq.Queue(QueueLow),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register periodic task: %w", err)
+	}
+
+	logger.Info("Scheduled periodic task: scheduled member reactivation (every 5 minutes)")
+
+	// Deactivate members past their tenant's inactivity limit (runs daily)
+	_, err = scheduler.Register(
+		"@daily",
+		asynq.NewTask(TypeInactiveMemberDeactivation, nil),
+		asynq.Queue(QueueLow),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register periodic task: %w", err)
+	}
+
+	logger.Info("Scheduled periodic task: inactive member deactivation (daily)")
+
 	return &Worker{
 		server:    server,
 		mux:       mux,
 		scheduler: scheduler,
+		client:    client,
 	}, nil
 }
 
@@ -105,5 +243,6 @@ func (w *Worker) Shutdown() {
 
 	// Shutdown server
 	w.server.Shutdown()
+	w.client.Close()
 	fmt.Println("Worker shut down")
 }
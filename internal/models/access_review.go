@@ -0,0 +1,162 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AccessReviewStatus string
+
+const (
+	AccessReviewOpen   AccessReviewStatus = "open"
+	AccessReviewClosed AccessReviewStatus = "closed"
+)
+
+type AccessReviewDecision string
+
+const (
+	AccessReviewPending   AccessReviewDecision = "pending"
+	AccessReviewCertified AccessReviewDecision = "certified"
+	AccessReviewRevoked   AccessReviewDecision = "revoked"
+)
+
+// AccessReviewOutcome records what closing a campaign did with a revocation
+type AccessReviewOutcome string
+
+const (
+	AccessReviewApplied AccessReviewOutcome = "applied"
+	AccessReviewSkipped AccessReviewOutcome = "skipped"
+	AccessReviewFailed  AccessReviewOutcome = "failed"
+)
+
+// UUIDList is stored as a JSONB array of UUIDs; a nil list is stored as NULL
+type UUIDList []uuid.UUID
+
+// Value implements the driver.Valuer interface
+func (l UUIDList) Value() (driver.Value, error) {
+	if l == nil {
+		return nil, nil
+	}
+	return json.Marshal(l)
+}
+
+// Scan implements the sql.Scanner interface
+func (l *UUIDList) Scan(value interface{}) error {
+	return scanJSONArray(value, l, "UUIDList")
+}
+
+// AccessReviewCampaign is a review of who holds which role in one or more
+// tenants. Starting it snapshots each member's role, and each role they
+// inherit from a group, as an item; reviewers certify or revoke the items,
+// and closing the campaign removes the members whose own role was revoked and
+// takes members out of the groups whose role was revoked.
+type AccessReviewCampaign struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Name        string    `gorm:"type:varchar(255);not null" json:"name"`
+	Description string    `gorm:"type:text" json:"description,omitempty"`
+	// TenantID is the tenant that started the campaign, nil when a platform
+	// admin started it
+	TenantID *uuid.UUID `gorm:"type:uuid;index" json:"tenant_id,omitempty"`
+	// ScopeTenantIDs are the tenants covered; nil covers every active tenant
+	ScopeTenantIDs UUIDList   `gorm:"type:jsonb" json:"scope_tenant_ids,omitempty"`
+	RoleID         *uuid.UUID `gorm:"type:uuid" json:"role_id,omitempty"`
+	// ReviewerIDs decide every item; nil leaves each item to the managers of
+	// its tenant
+	ReviewerIDs StringList         `gorm:"type:jsonb" json:"reviewer_ids,omitempty"`
+	Status      AccessReviewStatus `gorm:"type:varchar(20);not null;default:'open'" json:"status"`
+	DueAt       *time.Time         `json:"due_at,omitempty"`
+	CreatedBy   string             `gorm:"type:varchar(255);not null" json:"created_by"`
+	ClosedBy    *string            `gorm:"type:varchar(255)" json:"closed_by,omitempty"`
+	ClosedAt    *time.Time         `json:"closed_at,omitempty"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+}
+
+func (AccessReviewCampaign) TableName() string {
+	return "access_review_campaigns"
+}
+
+// IsReviewer reports whether the campaign names userID as a reviewer
+func (c *AccessReviewCampaign) IsReviewer(userID string) bool {
+	for _, id := range c.ReviewerIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// AccessReviewItem is one role a member holds within a campaign, either
+// their own role or, when GroupID is set, a role inherited from a group
+type AccessReviewItem struct {
+	ID            uuid.UUID            `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CampaignID    uuid.UUID            `gorm:"type:uuid;not null;index" json:"campaign_id"`
+	TenantID      uuid.UUID            `gorm:"type:uuid;not null;index" json:"tenant_id"`
+	MemberID      uuid.UUID            `gorm:"type:uuid;not null" json:"member_id"`
+	UserID        string               `gorm:"type:varchar(255);not null" json:"user_id"`
+	RoleID        uuid.UUID            `gorm:"type:uuid;not null" json:"role_id"`
+	RoleName      string               `gorm:"type:varchar(255);not null" json:"role_name"`
+	GroupID       *uuid.UUID           `gorm:"type:uuid" json:"group_id,omitempty"`
+	GroupName     string               `gorm:"type:varchar(255)" json:"group_name,omitempty"`
+	Decision      AccessReviewDecision `gorm:"type:varchar(20);not null;default:'pending'" json:"decision"`
+	Comment       string               `gorm:"type:text" json:"comment,omitempty"`
+	DecidedBy     *string              `gorm:"type:varchar(255)" json:"decided_by,omitempty"`
+	DecidedAt     *time.Time           `json:"decided_at,omitempty"`
+	Outcome       AccessReviewOutcome  `gorm:"type:varchar(20)" json:"outcome,omitempty"`
+	OutcomeDetail string               `gorm:"type:text" json:"outcome_detail,omitempty"`
+	CreatedAt     time.Time            `json:"created_at"`
+	UpdatedAt     time.Time            `json:"updated_at"`
+}
+
+func (AccessReviewItem) TableName() string {
+	return "access_review_items"
+}
+
+type StartAccessReviewInput struct {
+	Name        string `json:"name" binding:"required,min=2,max=255"`
+	Description string `json:"description" binding:"omitempty,max=1000"`
+	// TenantIDs are only accepted from platform admins; tenant campaigns
+	// always cover their own tenant
+	TenantIDs   []uuid.UUID `json:"tenant_ids" binding:"omitempty,max=500"`
+	RoleID      *uuid.UUID  `json:"role_id"`
+	ReviewerIDs []string    `json:"reviewer_ids" binding:"omitempty,max=50,dive,required"`
+	DueAt       *time.Time  `json:"due_at"`
+}
+
+type DecideAccessReviewInput struct {
+	Decision AccessReviewDecision `json:"decision" binding:"required,oneof=certified revoked"`
+	Comment  string               `json:"comment" binding:"omitempty,max=1000"`
+}
+
+type AccessReviewItemParams struct {
+	Decision AccessReviewDecision `form:"decision" binding:"omitempty,oneof=pending certified revoked"`
+}
+
+// AccessReviewSummary counts a campaign's items by decision and, once it is
+// closed, by what happened to the revocations
+type AccessReviewSummary struct {
+	Total     int64 `json:"total"`
+	Pending   int64 `json:"pending"`
+	Certified int64 `json:"certified"`
+	Revoked   int64 `json:"revoked"`
+	Applied   int64 `json:"revocations_applied"`
+	Skipped   int64 `json:"revocations_skipped"`
+	Failed    int64 `json:"revocations_failed"`
+}
+
+type AccessReviewCampaignResponse struct {
+	*AccessReviewCampaign
+	Summary *AccessReviewSummary `json:"summary"`
+}
+
+// AccessReviewReport is the exported record of a campaign. It is signed so
+// auditors can check it was not edited after export.
+type AccessReviewReport struct {
+	Campaign    *AccessReviewCampaign `json:"campaign"`
+	Summary     *AccessReviewSummary  `json:"summary"`
+	Items       []*AccessReviewItem   `json:"items"`
+	GeneratedAt time.Time             `json:"generated_at"`
+}
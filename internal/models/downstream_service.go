@@ -0,0 +1,149 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ServiceHealthStatus string
+
+const (
+	ServiceHealthUnknown   ServiceHealthStatus = "unknown"
+	ServiceHealthHealthy   ServiceHealthStatus = "healthy"
+	ServiceHealthUnhealthy ServiceHealthStatus = "unhealthy"
+)
+
+const (
+	DefaultProvisionPath   = "/api/v1/tenants/initialize"
+	DefaultDeprovisionPath = "/api/v1/tenants/deprovision"
+	DefaultHealthPath      = "/health"
+	// ServiceStatusPath receives tenant lifecycle notifications
+	ServiceStatusPath = "/api/v1/tenants/status"
+)
+
+// DownstreamService is a service in the registry that tenants are provisioned in
+type DownstreamService struct {
+	ID                uuid.UUID            `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Name              string               `gorm:"type:varchar(100);unique;not null" json:"name"`
+	BaseURL           string               `gorm:"type:varchar(500);not null" json:"base_url"`
+	ProvisionPath     string               `gorm:"type:varchar(255);not null" json:"provision_path"`
+	DeprovisionPath   string               `gorm:"type:varchar(255);not null" json:"deprovision_path"`
+	HealthPath        string               `gorm:"type:varchar(255);not null" json:"health_path"`
+	SigningSecret     string               `gorm:"type:varchar(255)" json:"-"`
+	Enabled           bool                 `gorm:"not null;default:true" json:"enabled"`
+	Position          int                  `gorm:"not null;default:0" json:"position"`
+	HealthStatus      ServiceHealthStatus  `gorm:"type:varchar(20);not null;default:'unknown'" json:"health_status"`
+	HealthError       string               `gorm:"type:text" json:"health_error,omitempty"`
+	LastHealthCheckAt *time.Time           `json:"last_health_check_at"`
+	CreatedBy         string               `gorm:"type:varchar(255)" json:"created_by"`
+	CreatedAt         time.Time            `json:"created_at"`
+	UpdatedAt         time.Time            `json:"updated_at"`
+	DependsOn         []*DownstreamService `gorm:"many2many:downstream_service_dependencies;joinForeignKey:ServiceID;joinReferences:DependsOnID" json:"-"`
+}
+
+func (DownstreamService) TableName() string {
+	return "downstream_services"
+}
+
+// URL joins the service base URL with path
+func (s *DownstreamService) URL(path string) string {
+	return strings.TrimSuffix(s.BaseURL, "/") + "/" + strings.TrimPrefix(path, "/")
+}
+
+// SecretOr returns the service's signing secret, or fallback when it has none
+func (s *DownstreamService) SecretOr(fallback string) string {
+	if s.SigningSecret != "" {
+		return s.SigningSecret
+	}
+	return fallback
+}
+
+// ServicesForTenant narrows enabled services to the ones tenant is
+// provisioned in, adding the services those depend on. Tenants without a
+// service list use every enabled service. Order is preserved.
+func ServicesForTenant(enabled []*DownstreamService, tenant *Tenant) []*DownstreamService {
+	if tenant.ProvisionServices == nil {
+		return enabled
+	}
+
+	byName := make(map[string]*DownstreamService, len(enabled))
+	for _, service := range enabled {
+		byName[service.Name] = service
+	}
+
+	selected := make(map[string]bool)
+	var include func(name string)
+	include = func(name string) {
+		service, ok := byName[name]
+		if !ok || selected[name] {
+			return
+		}
+		selected[name] = true
+		for _, dep := range service.DependsOn {
+			include(dep.Name)
+		}
+	}
+	for _, name := range tenant.ProvisionServices {
+		include(name)
+	}
+
+	services := make([]*DownstreamService, 0, len(selected))
+	for _, service := range enabled {
+		if selected[service.Name] {
+			services = append(services, service)
+		}
+	}
+	return services
+}
+
+// DependencyNames returns the names of the services s depends on
+func (s *DownstreamService) DependencyNames() []string {
+	names := make([]string, len(s.DependsOn))
+	for i, dep := range s.DependsOn {
+		names[i] = dep.Name
+	}
+	return names
+}
+
+// CreateDownstreamServiceInput registers a service
+type CreateDownstreamServiceInput struct {
+	Name            string   `json:"name" binding:"required,min=2,max=100"`
+	BaseURL         string   `json:"base_url" binding:"required,url"`
+	ProvisionPath   string   `json:"provision_path" binding:"omitempty,startswith=/"`
+	DeprovisionPath string   `json:"deprovision_path" binding:"omitempty,startswith=/"`
+	HealthPath      string   `json:"health_path" binding:"omitempty,startswith=/"`
+	SigningSecret   string   `json:"signing_secret" binding:"omitempty,min=16"`
+	Enabled         *bool    `json:"enabled"`
+	Position        int      `json:"position"`
+	DependsOn       []string `json:"depends_on"`
+}
+
+// UpdateDownstreamServiceInput changes a registered service. DependsOn, when
+// set, replaces the service's dependencies.
+type UpdateDownstreamServiceInput struct {
+	BaseURL         *string   `json:"base_url,omitempty" binding:"omitempty,url"`
+	ProvisionPath   *string   `json:"provision_path,omitempty" binding:"omitempty,startswith=/"`
+	DeprovisionPath *string   `json:"deprovision_path,omitempty" binding:"omitempty,startswith=/"`
+	HealthPath      *string   `json:"health_path,omitempty" binding:"omitempty,startswith=/"`
+	SigningSecret   *string   `json:"signing_secret,omitempty" binding:"omitempty,min=16"`
+	Enabled         *bool     `json:"enabled,omitempty"`
+	Position        *int      `json:"position,omitempty"`
+	DependsOn       *[]string `json:"depends_on,omitempty"`
+}
+
+// DownstreamServiceResponse never includes the signing secret
+type DownstreamServiceResponse struct {
+	*DownstreamService
+	HasSigningSecret bool     `json:"has_signing_secret"`
+	DependsOn        []string `json:"depends_on"`
+}
+
+func (s *DownstreamService) ToResponse() *DownstreamServiceResponse {
+	return &DownstreamServiceResponse{
+		DownstreamService: s,
+		HasSigningSecret:  s.SigningSecret != "",
+		DependsOn:         s.DependencyNames(),
+	}
+}
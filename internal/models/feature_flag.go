@@ -0,0 +1,420 @@
+package models
+
+import (
+	"crypto/sha1"
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FeatureFlagType string
+
+const (
+	FeatureFlagBoolean      FeatureFlagType = "boolean"
+	FeatureFlagMultivariate FeatureFlagType = "multivariate"
+)
+
+// Variation keys of boolean flags
+const (
+	FlagVariationOn  = "on"
+	FlagVariationOff = "off"
+)
+
+// FlagBucketBy selects the identifier percentage rollouts hash on
+type FlagBucketBy string
+
+const (
+	FlagBucketByUser   FlagBucketBy = "user"
+	FlagBucketByTenant FlagBucketBy = "tenant"
+)
+
+// FlagMetadataOperator compares a tenant metadata value in a targeting rule
+type FlagMetadataOperator string
+
+const (
+	FlagMetadataEquals    FlagMetadataOperator = "equals"
+	FlagMetadataNotEquals FlagMetadataOperator = "not_equals"
+	FlagMetadataIn        FlagMetadataOperator = "in"
+	FlagMetadataExists    FlagMetadataOperator = "exists"
+)
+
+// FlagVariation is one value a flag can serve
+type FlagVariation struct {
+	Key   string      `json:"key" binding:"required,min=1,max=100"`
+	Value interface{} `json:"value"`
+}
+
+// FlagMetadataCondition matches a top-level key of the tenant's metadata. For
+// the in operator Value is a list of accepted values.
+type FlagMetadataCondition struct {
+	Key      string               `json:"key" binding:"required"`
+	Operator FlagMetadataOperator `json:"operator" binding:"required,oneof=equals not_equals in exists"`
+	Value    interface{}          `json:"value,omitempty"`
+}
+
+// FlagRollout serves Variation to Weight percent of the bucketed identifiers
+type FlagRollout struct {
+	Variation string `json:"variation" binding:"required"`
+	Weight    int    `json:"weight" binding:"min=0,max=100"`
+}
+
+// FlagRule targets tenants and users. Every condition that is set must match;
+// a rule without conditions matches everyone. A matching rule serves Variation,
+// or splits by Rollout when it is set.
+type FlagRule struct {
+	Description string                  `json:"description,omitempty"`
+	TenantIDs   []uuid.UUID             `json:"tenant_ids,omitempty"`
+	UserIDs     []string                `json:"user_ids,omitempty"`
+	Metadata    []FlagMetadataCondition `json:"metadata,omitempty" binding:"omitempty,dive"`
+	Variation   string                  `json:"variation,omitempty"`
+	Rollout     []FlagRollout           `json:"rollout,omitempty" binding:"omitempty,dive"`
+	BucketBy    FlagBucketBy            `json:"bucket_by,omitempty" binding:"omitempty,oneof=user tenant"`
+}
+
+// FlagVariations is stored as a JSONB array
+type FlagVariations []FlagVariation
+
+// Value implements the driver.Valuer interface
+func (v FlagVariations) Value() (driver.Value, error) {
+	if v == nil {
+		return "[]", nil
+	}
+	return json.Marshal(v)
+}
+
+// Scan implements the sql.Scanner interface
+func (v *FlagVariations) Scan(value interface{}) error {
+	return scanJSONArray(value, v, "FlagVariations")
+}
+
+// FlagRules is stored as a JSONB array, evaluated in order
+type FlagRules []FlagRule
+
+// Value implements the driver.Valuer interface
+func (r FlagRules) Value() (driver.Value, error) {
+	if r == nil {
+		return "[]", nil
+	}
+	return json.Marshal(r)
+}
+
+// Scan implements the sql.Scanner interface
+func (r *FlagRules) Scan(value interface{}) error {
+	return scanJSONArray(value, r, "FlagRules")
+}
+
+func scanJSONArray(value interface{}, dest interface{}, name string) error {
+	var bytes []byte
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return fmt.Errorf("failed to scan %s: value is not []byte or string", name)
+	}
+	if len(bytes) == 0 {
+		return nil
+	}
+	return json.Unmarshal(bytes, dest)
+}
+
+// FeatureFlag is a flag evaluated per tenant and user. A killed flag serves
+// OffVariation to everyone regardless of its rules.
+type FeatureFlag struct {
+	ID               uuid.UUID       `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Key              string          `gorm:"type:varchar(100);unique;not null" json:"key"`
+	Description      string          `gorm:"type:text" json:"description"`
+	Type             FeatureFlagType `gorm:"type:varchar(20);not null;default:'boolean'" json:"type"`
+	Variations       FlagVariations  `gorm:"type:jsonb;not null;default:'[]'" json:"variations"`
+	Rules            FlagRules       `gorm:"type:jsonb;not null;default:'[]'" json:"rules"`
+	DefaultVariation string          `gorm:"type:varchar(100);not null" json:"default_variation"`
+	OffVariation     string          `gorm:"type:varchar(100);not null" json:"off_variation"`
+	Killed           bool            `gorm:"not null;default:false" json:"killed"`
+	KilledAt         *time.Time      `json:"killed_at,omitempty"`
+	CreatedBy        string          `gorm:"type:varchar(255);not null" json:"created_by"`
+	UpdatedBy        string          `gorm:"type:varchar(255);not null" json:"updated_by"`
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+}
+
+func (FeatureFlag) TableName() string {
+	return "feature_flags"
+}
+
+// Variation returns the variation with the given key
+func (f *FeatureFlag) Variation(key string) (*FlagVariation, bool) {
+	for i := range f.Variations {
+		if f.Variations[i].Key == key {
+			return &f.Variations[i], true
+		}
+	}
+	return nil, false
+}
+
+// Validate checks that every variation the flag refers to exists and that
+// rollouts add up to 100 percent
+func (f *FeatureFlag) Validate() error {
+	if len(f.Variations) == 0 {
+		return errors.New("flag must have at least one variation")
+	}
+
+	seen := make(map[string]bool, len(f.Variations))
+	for _, variation := range f.Variations {
+		if seen[variation.Key] {
+			return fmt.Errorf("duplicate variation %q", variation.Key)
+		}
+		seen[variation.Key] = true
+		if f.Type == FeatureFlagBoolean {
+			if _, ok := variation.Value.(bool); !ok {
+				return fmt.Errorf("variation %q of a boolean flag must be true or false", variation.Key)
+			}
+		}
+	}
+
+	if !seen[f.DefaultVariation] {
+		return fmt.Errorf("default variation %q does not exist", f.DefaultVariation)
+	}
+	if !seen[f.OffVariation] {
+		return fmt.Errorf("off variation %q does not exist", f.OffVariation)
+	}
+
+	for i, rule := range f.Rules {
+		if len(rule.Rollout) == 0 {
+			if !seen[rule.Variation] {
+				return fmt.Errorf("rule %d: variation %q does not exist", i+1, rule.Variation)
+			}
+			continue
+		}
+
+		total := 0
+		for _, rollout := range rule.Rollout {
+			if !seen[rollout.Variation] {
+				return fmt.Errorf("rule %d: rollout variation %q does not exist", i+1, rollout.Variation)
+			}
+			total += rollout.Weight
+		}
+		if total != 100 {
+			return fmt.Errorf("rule %d: rollout weights add up to %d, not 100", i+1, total)
+		}
+	}
+
+	return nil
+}
+
+// FlagEvaluationContext is who a flag is evaluated for
+type FlagEvaluationContext struct {
+	TenantID       uuid.UUID
+	TenantMetadata JSONMap
+	UserID         string
+}
+
+type FlagEvaluationReason string
+
+const (
+	FlagReasonKilled    FlagEvaluationReason = "killed"
+	FlagReasonRuleMatch FlagEvaluationReason = "rule_match"
+	FlagReasonRollout   FlagEvaluationReason = "rollout"
+	FlagReasonDefault   FlagEvaluationReason = "default"
+)
+
+// FlagEvaluation is the variation a flag serves in a context
+type FlagEvaluation struct {
+	Key       string               `json:"key"`
+	Value     interface{}          `json:"value"`
+	Variation string               `json:"variation"`
+	Reason    FlagEvaluationReason `json:"reason"`
+	RuleIndex *int                 `json:"rule_index,omitempty"`
+}
+
+// Evaluate returns the variation the flag serves in ctx. Rules are checked in
+// order and the first match wins.
+func (f *FeatureFlag) Evaluate(ctx *FlagEvaluationContext) *FlagEvaluation {
+	if f.Killed {
+		return f.serve(f.OffVariation, FlagReasonKilled, nil)
+	}
+
+	for i, rule := range f.Rules {
+		if !rule.matches(ctx) {
+			continue
+		}
+		index := i
+		if len(rule.Rollout) == 0 {
+			return f.serve(rule.Variation, FlagReasonRuleMatch, &index)
+		}
+		return f.serve(rule.bucket(f.Key, ctx), FlagReasonRollout, &index)
+	}
+
+	return f.serve(f.DefaultVariation, FlagReasonDefault, nil)
+}
+
+func (f *FeatureFlag) serve(key string, reason FlagEvaluationReason, ruleIndex *int) *FlagEvaluation {
+	evaluation := &FlagEvaluation{
+		Key:       f.Key,
+		Variation: key,
+		Reason:    reason,
+		RuleIndex: ruleIndex,
+	}
+	if variation, ok := f.Variation(key); ok {
+		evaluation.Value = variation.Value
+	}
+	return evaluation
+}
+
+func (r *FlagRule) matches(ctx *FlagEvaluationContext) bool {
+	if len(r.TenantIDs) > 0 && !containsUUID(r.TenantIDs, ctx.TenantID) {
+		return false
+	}
+	if len(r.UserIDs) > 0 && !containsString(r.UserIDs, ctx.UserID) {
+		return false
+	}
+	for _, condition := range r.Metadata {
+		if !condition.matches(ctx.TenantMetadata) {
+			return false
+		}
+	}
+	return true
+}
+
+// bucket hashes the flag key with the user (or tenant) so the same identifier
+// always lands on the same variation, and raising a weight only adds to it
+func (r *FlagRule) bucket(flagKey string, ctx *FlagEvaluationContext) string {
+	id := ctx.UserID
+	if r.BucketBy == FlagBucketByTenant || id == "" {
+		id = ctx.TenantID.String()
+	}
+
+	sum := sha1.Sum([]byte(flagKey + ":" + id))
+	point := int(binary.BigEndian.Uint32(sum[:4]) % 100)
+
+	cumulative := 0
+	for _, rollout := range r.Rollout {
+		cumulative += rollout.Weight
+		if point < cumulative {
+			return rollout.Variation
+		}
+	}
+	return r.Rollout[len(r.Rollout)-1].Variation
+}
+
+func (c *FlagMetadataCondition) matches(metadata JSONMap) bool {
+	actual, exists := metadata[c.Key]
+
+	switch c.Operator {
+	case FlagMetadataExists:
+		return exists
+	case FlagMetadataEquals:
+		return exists && jsonEqual(actual, c.Value)
+	case FlagMetadataNotEquals:
+		return !exists || !jsonEqual(actual, c.Value)
+	case FlagMetadataIn:
+		values, ok := c.Value.([]interface{})
+		if !exists || !ok {
+			return false
+		}
+		for _, value := range values {
+			if jsonEqual(actual, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jsonEqual compares values decoded from JSON, where all numbers are float64
+func jsonEqual(a, b interface{}) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+func containsUUID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+type FeatureFlagAuditAction string
+
+const (
+	FeatureFlagAuditCreated  FeatureFlagAuditAction = "created"
+	FeatureFlagAuditUpdated  FeatureFlagAuditAction = "updated"
+	FeatureFlagAuditKilled   FeatureFlagAuditAction = "killed"
+	FeatureFlagAuditRestored FeatureFlagAuditAction = "restored"
+	FeatureFlagAuditDeleted  FeatureFlagAuditAction = "deleted"
+)
+
+// FeatureFlagAuditLog records a change to a flag with its state before and after
+type FeatureFlagAuditLog struct {
+	ID        uuid.UUID              `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	FlagID    uuid.UUID              `gorm:"type:uuid;not null;index" json:"flag_id"`
+	FlagKey   string                 `gorm:"type:varchar(100);not null" json:"flag_key"`
+	Action    FeatureFlagAuditAction `gorm:"type:varchar(20);not null" json:"action"`
+	Reason    string                 `gorm:"type:text" json:"reason,omitempty"`
+	Before    JSONMap                `gorm:"type:jsonb" json:"before,omitempty"`
+	After     JSONMap                `gorm:"type:jsonb" json:"after,omitempty"`
+	ActorID   string                 `gorm:"type:varchar(255);not null" json:"actor_id"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+func (FeatureFlagAuditLog) TableName() string {
+	return "feature_flag_audit_logs"
+}
+
+// Snapshot returns the flag as stored in audit logs
+func (f *FeatureFlag) Snapshot() JSONMap {
+	data, err := json.Marshal(f)
+	if err != nil {
+		return nil
+	}
+	var snapshot JSONMap
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil
+	}
+	return snapshot
+}
+
+// FeatureFlagInput defines a flag. Boolean flags get on/off variations when
+// none are given, and default to serving off.
+type FeatureFlagInput struct {
+	Description      string          `json:"description" binding:"omitempty,max=1000"`
+	Type             FeatureFlagType `json:"type" binding:"omitempty,oneof=boolean multivariate"`
+	Variations       FlagVariations  `json:"variations" binding:"omitempty,dive"`
+	Rules            FlagRules       `json:"rules" binding:"omitempty,dive"`
+	DefaultVariation string          `json:"default_variation"`
+	OffVariation     string          `json:"off_variation"`
+}
+
+type CreateFeatureFlagInput struct {
+	Key string `json:"key" binding:"required,min=2,max=100"`
+	FeatureFlagInput
+}
+
+// UpdateFeatureFlagInput replaces the flag's definition; the kill switch is
+// changed separately
+type UpdateFeatureFlagInput struct {
+	FeatureFlagInput
+	Reason string `json:"reason" binding:"omitempty,max=500"`
+}
+
+type FeatureFlagKillInput struct {
+	Reason string `json:"reason" binding:"omitempty,max=500"`
+}
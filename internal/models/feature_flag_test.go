@@ -0,0 +1,168 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func rolloutFlag(onWeight int, bucketBy FlagBucketBy) *FeatureFlag {
+	return &FeatureFlag{
+		Key:              "new-billing",
+		Type:             FeatureFlagBoolean,
+		Variations:       FlagVariations{{Key: FlagVariationOn, Value: true}, {Key: FlagVariationOff, Value: false}},
+		DefaultVariation: FlagVariationOff,
+		OffVariation:     FlagVariationOff,
+		Rules: FlagRules{{
+			BucketBy: bucketBy,
+			Rollout: []FlagRollout{
+				{Variation: FlagVariationOn, Weight: onWeight},
+				{Variation: FlagVariationOff, Weight: 100 - onWeight},
+			},
+		}},
+	}
+}
+
+func TestEvaluateBucketsConsistently(t *testing.T) {
+	flag := rolloutFlag(50, FlagBucketByUser)
+	tenantID := uuid.New()
+
+	for i := 0; i < 100; i++ {
+		ctx := &FlagEvaluationContext{TenantID: tenantID, UserID: fmt.Sprintf("user_%d", i)}
+		first := flag.Evaluate(ctx)
+		if first.Reason != FlagReasonRollout {
+			t.Fatalf("reason = %s, want %s", first.Reason, FlagReasonRollout)
+		}
+		for j := 0; j < 3; j++ {
+			if again := flag.Evaluate(ctx); again.Variation != first.Variation {
+				t.Fatalf("user_%d got %s then %s", i, first.Variation, again.Variation)
+			}
+		}
+	}
+}
+
+func TestEvaluateRolloutWeights(t *testing.T) {
+	tenantID := uuid.New()
+	const users = 2000
+
+	countOn := func(flag *FeatureFlag) int {
+		on := 0
+		for i := 0; i < users; i++ {
+			ctx := &FlagEvaluationContext{TenantID: tenantID, UserID: fmt.Sprintf("user_%d", i)}
+			if flag.Evaluate(ctx).Variation == FlagVariationOn {
+				on++
+			}
+		}
+		return on
+	}
+
+	if on := countOn(rolloutFlag(0, FlagBucketByUser)); on != 0 {
+		t.Errorf("0%% rollout served on to %d users, want 0", on)
+	}
+	if on := countOn(rolloutFlag(100, FlagBucketByUser)); on != users {
+		t.Errorf("100%% rollout served on to %d users, want %d", on, users)
+	}
+	if on := countOn(rolloutFlag(30, FlagBucketByUser)); on < users*25/100 || on > users*35/100 {
+		t.Errorf("30%% rollout served on to %d of %d users, want about 30%%", on, users)
+	}
+}
+
+func TestEvaluateRaisingWeightKeepsUsersOn(t *testing.T) {
+	tenantID := uuid.New()
+	low := rolloutFlag(20, FlagBucketByUser)
+	high := rolloutFlag(60, FlagBucketByUser)
+
+	for i := 0; i < 1000; i++ {
+		ctx := &FlagEvaluationContext{TenantID: tenantID, UserID: fmt.Sprintf("user_%d", i)}
+		if low.Evaluate(ctx).Variation == FlagVariationOn && high.Evaluate(ctx).Variation != FlagVariationOn {
+			t.Fatalf("user_%d was on at 20%% but off at 60%%", i)
+		}
+	}
+}
+
+func TestEvaluateBucketByTenant(t *testing.T) {
+	flag := rolloutFlag(50, FlagBucketByTenant)
+
+	for i := 0; i < 20; i++ {
+		tenantID := uuid.New()
+		want := flag.Evaluate(&FlagEvaluationContext{TenantID: tenantID}).Variation
+		for j := 0; j < 10; j++ {
+			ctx := &FlagEvaluationContext{TenantID: tenantID, UserID: fmt.Sprintf("user_%d", j)}
+			if got := flag.Evaluate(ctx).Variation; got != want {
+				t.Fatalf("tenant %s: user_%d got %s, want the tenant's %s", tenantID, j, got, want)
+			}
+		}
+	}
+}
+
+func TestEvaluateRulesAndKillSwitch(t *testing.T) {
+	tenantID := uuid.New()
+	otherTenant := uuid.New()
+	flag := &FeatureFlag{
+		Key:              "search",
+		Type:             FeatureFlagMultivariate,
+		Variations:       FlagVariations{{Key: "v1", Value: "v1"}, {Key: "v2", Value: "v2"}, {Key: "off", Value: nil}},
+		DefaultVariation: "v1",
+		OffVariation:     "off",
+		Rules: FlagRules{
+			{UserIDs: []string{"beta_user"}, Variation: "v2"},
+			{TenantIDs: []uuid.UUID{tenantID}, Metadata: []FlagMetadataCondition{
+				{Key: "tier", Operator: FlagMetadataIn, Value: []interface{}{"pro", "enterprise"}},
+			}, Variation: "v2"},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		ctx       *FlagEvaluationContext
+		variation string
+		reason    FlagEvaluationReason
+		ruleIndex int
+	}{
+		{"user rule", &FlagEvaluationContext{TenantID: otherTenant, UserID: "beta_user"}, "v2", FlagReasonRuleMatch, 0},
+		{"tenant and metadata rule", &FlagEvaluationContext{TenantID: tenantID, TenantMetadata: JSONMap{"tier": "pro"}}, "v2", FlagReasonRuleMatch, 1},
+		{"metadata does not match", &FlagEvaluationContext{TenantID: tenantID, TenantMetadata: JSONMap{"tier": "free"}}, "v1", FlagReasonDefault, -1},
+		{"metadata missing", &FlagEvaluationContext{TenantID: tenantID}, "v1", FlagReasonDefault, -1},
+		{"other tenant", &FlagEvaluationContext{TenantID: otherTenant, TenantMetadata: JSONMap{"tier": "pro"}}, "v1", FlagReasonDefault, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := flag.Evaluate(tt.ctx)
+			if got.Variation != tt.variation || got.Reason != tt.reason {
+				t.Fatalf("Evaluate = %s (%s), want %s (%s)", got.Variation, got.Reason, tt.variation, tt.reason)
+			}
+			if tt.ruleIndex < 0 && got.RuleIndex != nil {
+				t.Errorf("rule index = %d, want none", *got.RuleIndex)
+			}
+			if tt.ruleIndex >= 0 && (got.RuleIndex == nil || *got.RuleIndex != tt.ruleIndex) {
+				t.Errorf("rule index = %v, want %d", got.RuleIndex, tt.ruleIndex)
+			}
+		})
+	}
+
+	flag.Killed = true
+	got := flag.Evaluate(&FlagEvaluationContext{TenantID: otherTenant, UserID: "beta_user"})
+	if got.Variation != "off" || got.Reason != FlagReasonKilled {
+		t.Errorf("killed flag served %s (%s), want off (%s)", got.Variation, got.Reason, FlagReasonKilled)
+	}
+}
+
+func TestValidateRolloutWeights(t *testing.T) {
+	if err := rolloutFlag(40, FlagBucketByUser).Validate(); err != nil {
+		t.Errorf("Validate = %v, want nil", err)
+	}
+
+	flag := rolloutFlag(40, FlagBucketByUser)
+	flag.Rules[0].Rollout[1].Weight = 50
+	if err := flag.Validate(); err == nil {
+		t.Error("Validate accepted weights adding up to 90")
+	}
+
+	flag = rolloutFlag(40, FlagBucketByUser)
+	flag.Rules[0].Rollout[0].Variation = "maybe"
+	if err := flag.Validate(); err == nil {
+		t.Error("Validate accepted a rollout to an unknown variation")
+	}
+}
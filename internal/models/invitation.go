@@ -38,8 +38,9 @@ func (UserInvitation) TableName() string {
 }
 
 type CreateInvitationInput struct {
-	Email  string    `json:"email" binding:"required,email"`
-	RoleID uuid.UUID `json:"role_id" binding:"required"`
+	Email string `json:"email" binding:"required,email"`
+	// RoleID defaults to the tenant's default role when omitted
+	RoleID uuid.UUID `json:"role_id"`
 }
 
 type InvitationResponse struct {
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MemberActivityWindows are the periods, in days, the activity summary counts
+// active members over
+var MemberActivityWindows = []int{7, 30, 90}
+
+// DefaultDormantDays is how long without activity makes a member dormant when
+// the request does not say
+const DefaultDormantDays = 90
+
+// MaxDormantMembersListed caps the dormant members listed in a summary;
+// DormantCount still counts them all
+const MaxDormantMembersListed = 100
+
+type MemberActivityParams struct {
+	DormantDays int `form:"dormant_days" binding:"omitempty,min=1,max=3650"`
+}
+
+// MemberActivityWindow counts the active members seen within the last Days
+type MemberActivityWindow struct {
+	Days          int   `json:"days"`
+	ActiveMembers int64 `json:"active_members"`
+}
+
+// MemberActivitySummary reports how many of a tenant's active members used it
+// recently, and which have not for DormantDays. Members who were never seen
+// count from when they joined.
+type MemberActivitySummary struct {
+	TenantID       uuid.UUID              `json:"tenant_id"`
+	GeneratedAt    time.Time              `json:"generated_at"`
+	TotalMembers   int64                  `json:"total_members"`
+	Windows        []MemberActivityWindow `json:"windows"`
+	DormantDays    int                    `json:"dormant_days"`
+	DormantCount   int64                  `json:"dormant_count"`
+	DormantMembers []*MemberResponse      `json:"dormant_members"`
+	// InactiveMemberDeactivationDays echoes the tenant's automatic
+	// deactivation policy; 0 means it is off
+	InactiveMemberDeactivationDays int `json:"inactive_member_deactivation_days"`
+}
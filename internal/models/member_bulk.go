@@ -0,0 +1,233 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxMemberBulkRows is the most rows a single bulk operation may contain
+const MaxMemberBulkRows = 5000
+
+type MemberBulkOperationType string
+
+const (
+	MemberBulkAdd        MemberBulkOperationType = "add"
+	MemberBulkUpdateRole MemberBulkOperationType = "update_role"
+	MemberBulkRemove     MemberBulkOperationType = "remove"
+)
+
+// IsValid reports whether t is a known operation type
+func (t MemberBulkOperationType) IsValid() bool {
+	switch t {
+	case MemberBulkAdd, MemberBulkUpdateRole, MemberBulkRemove:
+		return true
+	}
+	return false
+}
+
+type MemberBulkOperationStatus string
+
+const (
+	MemberBulkPending   MemberBulkOperationStatus = "pending"
+	MemberBulkRunning   MemberBulkOperationStatus = "running"
+	MemberBulkCompleted MemberBulkOperationStatus = "completed"
+	MemberBulkFailed    MemberBulkOperationStatus = "failed"
+)
+
+// MemberBulkOutcome is what happened to one row. In a dry run it is what
+// would have happened.
+type MemberBulkOutcome string
+
+const (
+	MemberBulkOutcomeAdded         MemberBulkOutcome = "added"
+	MemberBulkOutcomeRoleUpdated   MemberBulkOutcome = "role_updated"
+	MemberBulkOutcomeRemoved       MemberBulkOutcome = "removed"
+	MemberBulkOutcomeAlreadyMember MemberBulkOutcome = "already_member"
+	MemberBulkOutcomeNotMember     MemberBulkOutcome = "not_member"
+	MemberBulkOutcomeInvalidRole   MemberBulkOutcome = "invalid_role"
+	MemberBulkOutcomeUnknownUser   MemberBulkOutcome = "unknown_user"
+	MemberBulkOutcomeInvalidRow    MemberBulkOutcome = "invalid_row"
+	// MemberBulkOutcomeBlocked covers rows refused by the tenant's plan,
+	// security settings, ownership rules, roles the requester may not grant
+	// or the rule that the tenant keeps a manager
+	MemberBulkOutcomeBlocked MemberBulkOutcome = "blocked"
+	MemberBulkOutcomeFailed  MemberBulkOutcome = "failed"
+)
+
+// Succeeded reports whether the row was (or would be) applied
+func (o MemberBulkOutcome) Succeeded() bool {
+	return o == MemberBulkOutcomeAdded || o == MemberBulkOutcomeRoleUpdated || o == MemberBulkOutcomeRemoved
+}
+
+// MemberBulkRow identifies a user by ID or email. Role is a role name or ID;
+// it is ignored for removals and defaults to the tenant's default role when
+// adding.
+type MemberBulkRow struct {
+	UserID string `json:"user_id,omitempty"`
+	Email  string `json:"email,omitempty"`
+	Role   string `json:"role,omitempty"`
+}
+
+// MemberBulkRows is stored as a JSONB array
+type MemberBulkRows []MemberBulkRow
+
+// Value implements the driver.Valuer interface
+func (r MemberBulkRows) Value() (driver.Value, error) {
+	if r == nil {
+		return "[]", nil
+	}
+	return json.Marshal(r)
+}
+
+// Scan implements the sql.Scanner interface
+func (r *MemberBulkRows) Scan(value interface{}) error {
+	return scanJSONArray(value, r, "MemberBulkRows")
+}
+
+// MemberBulkRowResult is the outcome of one row. Row is 1-based in the order
+// rows were submitted (the CSV header is not counted).
+type MemberBulkRowResult struct {
+	Row     int               `json:"row"`
+	UserID  string            `json:"user_id,omitempty"`
+	Email   string            `json:"email,omitempty"`
+	Outcome MemberBulkOutcome `json:"outcome"`
+	Message string            `json:"message,omitempty"`
+}
+
+// MemberBulkRowResults is stored as a JSONB array
+type MemberBulkRowResults []MemberBulkRowResult
+
+// Value implements the driver.Valuer interface
+func (r MemberBulkRowResults) Value() (driver.Value, error) {
+	if r == nil {
+		return "[]", nil
+	}
+	return json.Marshal(r)
+}
+
+// Scan implements the sql.Scanner interface
+func (r *MemberBulkRowResults) Scan(value interface{}) error {
+	return scanJSONArray(value, r, "MemberBulkRowResults")
+}
+
+// MemberBulkOperation is a job-backed add, role change or removal of many
+// members, with a result per row
+type MemberBulkOperation struct {
+	ID          uuid.UUID                 `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	TenantID    uuid.UUID                 `gorm:"type:uuid;not null;index" json:"tenant_id"`
+	Operation   MemberBulkOperationType   `gorm:"type:varchar(20);not null" json:"operation"`
+	Status      MemberBulkOperationStatus `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	DryRun      bool                      `gorm:"not null;default:false" json:"dry_run"`
+	Rows        MemberBulkRows            `gorm:"type:jsonb;not null;default:'[]'" json:"-"`
+	Results     MemberBulkRowResults      `gorm:"type:jsonb;not null;default:'[]'" json:"results"`
+	TotalRows   int                       `gorm:"not null;default:0" json:"total_rows"`
+	Processed   int                       `gorm:"not null;default:0" json:"processed"`
+	Succeeded   int                       `gorm:"not null;default:0" json:"succeeded"`
+	Failed      int                       `gorm:"not null;default:0" json:"failed"`
+	Error       string                    `gorm:"type:text" json:"error,omitempty"`
+	RequestedBy string                    `gorm:"type:varchar(255);not null" json:"requested_by"`
+	StartedAt   *time.Time                `json:"started_at"`
+	CompletedAt *time.Time                `json:"completed_at"`
+	CreatedAt   time.Time                 `json:"created_at"`
+	UpdatedAt   time.Time                 `json:"updated_at"`
+}
+
+func (MemberBulkOperation) TableName() string {
+	return "member_bulk_operations"
+}
+
+// MemberBulkOperationInput is the JSON form of a bulk operation request
+type MemberBulkOperationInput struct {
+	Operation MemberBulkOperationType `json:"operation" form:"operation"`
+	DryRun    bool                    `json:"dry_run" form:"dry_run"`
+	Rows      []MemberBulkRow         `json:"rows"`
+}
+
+// Validate checks the operation type and that there is at least one row and
+// no more than MaxMemberBulkRows. Individual rows are checked by the job.
+func (i *MemberBulkOperationInput) Validate() error {
+	if i.Operation == "" {
+		i.Operation = MemberBulkAdd
+	}
+	if !i.Operation.IsValid() {
+		return fmt.Errorf("invalid operation %q, must be add, update_role or remove", i.Operation)
+	}
+	if len(i.Rows) == 0 {
+		return errors.New("at least one row is required")
+	}
+	if len(i.Rows) > MaxMemberBulkRows {
+		return fmt.Errorf("a bulk operation can have at most %d rows", MaxMemberBulkRows)
+	}
+	return nil
+}
+
+// ParseMemberBulkCSV reads rows from a CSV with a header row. The user_id,
+// email and role columns are recognised in any order and case; other columns
+// are ignored. Each row needs a user_id or an email.
+func ParseMemberBulkCSV(r io.Reader) ([]MemberBulkRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("the CSV file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := map[string]int{"user_id": -1, "email": -1, "role": -1}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, ok := columns[name]; ok {
+			columns[name] = i
+		}
+	}
+	if columns["user_id"] < 0 && columns["email"] < 0 {
+		return nil, errors.New("the CSV header must include a user_id or email column")
+	}
+
+	field := func(record []string, column string) string {
+		i := columns[column]
+		if i < 0 || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var rows []MemberBulkRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+
+		row := MemberBulkRow{
+			UserID: field(record, "user_id"),
+			Email:  field(record, "email"),
+			Role:   field(record, "role"),
+		}
+		if row == (MemberBulkRow{}) {
+			continue
+		}
+
+		rows = append(rows, row)
+		if len(rows) > MaxMemberBulkRows {
+			return nil, fmt.Errorf("a bulk operation can have at most %d rows", MaxMemberBulkRows)
+		}
+	}
+
+	return rows, nil
+}
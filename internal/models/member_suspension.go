@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MemberSuspension records one suspension of a tenant member, from when it
+// started until the member was reactivated. The open suspension has no
+// ReactivatedAt.
+type MemberSuspension struct {
+	ID           uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	MemberID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"member_id"`
+	TenantID     uuid.UUID  `gorm:"type:uuid;not null" json:"tenant_id"`
+	UserID       string     `gorm:"type:varchar(255);not null" json:"user_id"`
+	Reason       string     `gorm:"type:text;not null" json:"reason"`
+	SuspendedBy  string     `gorm:"type:varchar(255);not null" json:"suspended_by"`
+	SuspendedAt  time.Time  `gorm:"not null" json:"suspended_at"`
+	ReactivateAt *time.Time `json:"reactivate_at,omitempty"`
+	// SessionsRevoked is set when the user had no other tenant access, so
+	// their sessions were revoked
+	SessionsRevoked    bool       `gorm:"not null;default:false" json:"sessions_revoked"`
+	ReactivatedAt      *time.Time `json:"reactivated_at,omitempty"`
+	ReactivatedBy      *string    `gorm:"type:varchar(255)" json:"reactivated_by,omitempty"`
+	ReactivationReason string     `gorm:"type:text" json:"reactivation_reason,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+func (MemberSuspension) TableName() string {
+	return "member_suspensions"
+}
+
+// ScheduledReactivationActor is recorded as ReactivatedBy when the scheduled
+// job reactivates a member
+const ScheduledReactivationActor = "system:scheduled-reactivation"
+
+// SuspendMemberInput suspends a member. ReactivateAt, when set, schedules an
+// automatic reactivation.
+type SuspendMemberInput struct {
+	Reason       string     `json:"reason" binding:"required,min=3,max=500"`
+	ReactivateAt *time.Time `json:"reactivate_at,omitempty"`
+}
+
+type ReactivateMemberInput struct {
+	Reason string `json:"reason" binding:"omitempty,max=500"`
+}
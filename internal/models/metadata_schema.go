@@ -0,0 +1,104 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/pkg/jsonschema"
+)
+
+// TenantMetadataSchema is one version of the JSON Schema that tenant metadata
+// must conform to. A nil TenantType is the default schema for all tenant types.
+type TenantMetadataSchema struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	TenantType  *string   `gorm:"type:varchar(100)" json:"tenant_type"`
+	Version     int       `gorm:"not null" json:"version"`
+	Schema      JSONMap   `gorm:"type:jsonb;not null" json:"schema"`
+	Description string    `gorm:"type:text" json:"description"`
+	IsActive    bool      `gorm:"not null;default:false" json:"is_active"`
+	CreatedBy   string    `gorm:"type:varchar(255);not null" json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func (TenantMetadataSchema) TableName() string {
+	return "tenant_metadata_schemas"
+}
+
+type CreateMetadataSchemaInput struct {
+	TenantType  *string `json:"tenant_type" binding:"omitempty,min=1,max=100"`
+	Schema      JSONMap `json:"schema" binding:"required"`
+	Description string  `json:"description" binding:"omitempty,max=1000"`
+}
+
+type MetadataValidationRunStatus string
+
+const (
+	MetadataValidationRunPending   MetadataValidationRunStatus = "pending"
+	MetadataValidationRunRunning   MetadataValidationRunStatus = "running"
+	MetadataValidationRunCompleted MetadataValidationRunStatus = "completed"
+	MetadataValidationRunFailed    MetadataValidationRunStatus = "failed"
+)
+
+// MetadataValidationRun is a background re-validation of all tenants' metadata
+type MetadataValidationRun struct {
+	ID                   uuid.UUID                   `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Status               MetadataValidationRunStatus `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	TenantsChecked       int                         `gorm:"not null;default:0" json:"tenants_checked"`
+	TenantsNonConforming int                         `gorm:"not null;default:0" json:"tenants_non_conforming"`
+	Error                string                      `gorm:"type:text" json:"error,omitempty"`
+	TriggeredBy          string                      `gorm:"type:varchar(255);not null" json:"triggered_by"`
+	StartedAt            *time.Time                  `json:"started_at"`
+	CompletedAt          *time.Time                  `json:"completed_at"`
+	CreatedAt            time.Time                   `json:"created_at"`
+
+	Failures []MetadataValidationFailure `gorm:"foreignKey:RunID" json:"failures,omitempty"`
+}
+
+func (MetadataValidationRun) TableName() string {
+	return "metadata_validation_runs"
+}
+
+// MetadataValidationFailure records a tenant whose metadata did not conform during a run
+type MetadataValidationFailure struct {
+	ID        uuid.UUID           `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	RunID     uuid.UUID           `gorm:"type:uuid;not null;index" json:"run_id"`
+	TenantID  uuid.UUID           `gorm:"type:uuid;not null;index" json:"tenant_id"`
+	SchemaID  uuid.UUID           `gorm:"type:uuid;not null" json:"schema_id"`
+	Errors    MetadataFieldErrors `gorm:"type:jsonb;not null" json:"errors"`
+	CreatedAt time.Time           `json:"created_at"`
+}
+
+func (MetadataValidationFailure) TableName() string {
+	return "metadata_validation_failures"
+}
+
+// MetadataFieldErrors stores schema validation errors in a JSONB column
+type MetadataFieldErrors []jsonschema.FieldError
+
+// Value implements the driver.Valuer interface
+func (e MetadataFieldErrors) Value() (driver.Value, error) {
+	if e == nil {
+		return "[]", nil
+	}
+	return json.Marshal(e)
+}
+
+// Scan implements the sql.Scanner interface
+func (e *MetadataFieldErrors) Scan(value interface{}) error {
+	var bytes []byte
+	switch v := value.(type) {
+	case nil:
+		*e = MetadataFieldErrors{}
+		return nil
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("failed to scan MetadataFieldErrors: value is not []byte or string")
+	}
+	return json.Unmarshal(bytes, e)
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -21,6 +22,15 @@ func (Permission) TableName() string {
 	return "permissions"
 }
 
+// ParsePermissionKey splits a service:entity:action permission key
+func ParsePermissionKey(key string) (string, string, string, error) {
+	parts := strings.Split(key, ":")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", fmt.Errorf("invalid permission %q, expected service:entity:action", key)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
 type CreatePermissionInput struct {
 	Service     string `json:"service" binding:"required,min=2,max=100"`
 	Entity      string `json:"entity" binding:"required,min=2,max=100"`
@@ -0,0 +1,260 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QuotaResource names a limit enforced by plans
+type QuotaResource string
+
+const (
+	QuotaMembers            QuotaResource = "max_members"
+	QuotaPendingInvitations QuotaResource = "max_pending_invitations"
+	QuotaCustomRoles        QuotaResource = "max_custom_roles"
+	QuotaSystemUsers        QuotaResource = "max_system_users"
+)
+
+// PlanLimits holds the quota limits of a plan or override. A nil limit is
+// unlimited on a plan and inherited from the plan on an override.
+type PlanLimits struct {
+	MaxMembers            *int `json:"max_members"`
+	MaxPendingInvitations *int `json:"max_pending_invitations"`
+	MaxCustomRoles        *int `json:"max_custom_roles"`
+	MaxSystemUsers        *int `json:"max_system_users"`
+}
+
+// Limit returns the limit for resource, or nil when it is not set
+func (l PlanLimits) Limit(resource QuotaResource) *int {
+	switch resource {
+	case QuotaMembers:
+		return l.MaxMembers
+	case QuotaPendingInvitations:
+		return l.MaxPendingInvitations
+	case QuotaCustomRoles:
+		return l.MaxCustomRoles
+	case QuotaSystemUsers:
+		return l.MaxSystemUsers
+	}
+	return nil
+}
+
+// Merge returns l with every limit set in override replacing l's value
+func (l PlanLimits) Merge(override PlanLimits) PlanLimits {
+	if override.MaxMembers != nil {
+		l.MaxMembers = override.MaxMembers
+	}
+	if override.MaxPendingInvitations != nil {
+		l.MaxPendingInvitations = override.MaxPendingInvitations
+	}
+	if override.MaxCustomRoles != nil {
+		l.MaxCustomRoles = override.MaxCustomRoles
+	}
+	if override.MaxSystemUsers != nil {
+		l.MaxSystemUsers = override.MaxSystemUsers
+	}
+	return l
+}
+
+// FeatureSet maps feature names to whether they are enabled
+type FeatureSet map[string]bool
+
+// Value implements the driver.Valuer interface
+func (f FeatureSet) Value() (driver.Value, error) {
+	if f == nil {
+		return "{}", nil
+	}
+	return json.Marshal(f)
+}
+
+// Scan implements the sql.Scanner interface
+func (f *FeatureSet) Scan(value interface{}) error {
+	var bytes []byte
+	switch v := value.(type) {
+	case nil:
+		*f = FeatureSet{}
+		return nil
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("failed to scan FeatureSet: value is not []byte or string")
+	}
+	return json.Unmarshal(bytes, f)
+}
+
+// Plan defines the limits and feature entitlements of the tenants on it
+type Plan struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Name        string    `gorm:"type:varchar(100);unique;not null" json:"name"`
+	Description string    `gorm:"type:text" json:"description"`
+	PlanLimits  `gorm:"embedded"`
+	Features    FeatureSet `gorm:"type:jsonb;not null;default:'{}'" json:"features"`
+	IsDefault   bool       `gorm:"not null;default:false" json:"is_default"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+func (Plan) TableName() string {
+	return "plans"
+}
+
+// TenantPlanOverride adjusts the plan limits and features for a single tenant
+type TenantPlanOverride struct {
+	TenantID   uuid.UUID `gorm:"type:uuid;primary_key" json:"tenant_id"`
+	PlanLimits `gorm:"embedded"`
+	Features   FeatureSet `gorm:"type:jsonb;not null;default:'{}'" json:"features"`
+	Reason     string     `gorm:"type:text" json:"reason,omitempty"`
+	UpdatedBy  string     `gorm:"type:varchar(255)" json:"updated_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+func (TenantPlanOverride) TableName() string {
+	return "tenant_plan_overrides"
+}
+
+type CreatePlanInput struct {
+	Name        string `json:"name" binding:"required,min=2,max=100"`
+	Description string `json:"description" binding:"omitempty,max=500"`
+	PlanLimits
+	Features  FeatureSet `json:"features"`
+	IsDefault bool       `json:"is_default"`
+}
+
+// UpdatePlanInput replaces the plan's limits and features. Omitted limits
+// become unlimited.
+type UpdatePlanInput struct {
+	Description *string `json:"description,omitempty" binding:"omitempty,max=500"`
+	PlanLimits
+	Features  FeatureSet `json:"features"`
+	IsDefault *bool      `json:"is_default,omitempty"`
+}
+
+type AssignPlanInput struct {
+	PlanID uuid.UUID `json:"plan_id" binding:"required"`
+}
+
+// SetPlanOverrideInput replaces the tenant's overrides. Omitted limits inherit
+// the plan's value; features listed here replace the plan's value.
+type SetPlanOverrideInput struct {
+	PlanLimits
+	Features FeatureSet `json:"features"`
+	Reason   string     `json:"reason" binding:"omitempty,max=500"`
+}
+
+// QuotaUsage counts what a tenant currently uses against each limit
+type QuotaUsage struct {
+	Members            int64 `json:"members"`
+	PendingInvitations int64 `json:"pending_invitations"`
+	CustomRoles        int64 `json:"custom_roles"`
+	SystemUsers        int64 `json:"system_users"`
+}
+
+// Used returns the usage counted against resource
+func (u QuotaUsage) Used(resource QuotaResource) int64 {
+	switch resource {
+	case QuotaMembers:
+		return u.Members
+	case QuotaPendingInvitations:
+		return u.PendingInvitations
+	case QuotaCustomRoles:
+		return u.CustomRoles
+	case QuotaSystemUsers:
+		return u.SystemUsers
+	}
+	return 0
+}
+
+// TenantEntitlements is the effective plan of a tenant after overrides
+type TenantEntitlements struct {
+	TenantID uuid.UUID           `json:"tenant_id"`
+	Plan     *Plan               `json:"plan"`
+	Limits   PlanLimits          `json:"limits"`
+	Usage    QuotaUsage          `json:"usage"`
+	Features FeatureSet          `json:"features"`
+	Override *TenantPlanOverride `json:"override,omitempty"`
+}
+
+// NewTenantEntitlements applies override on top of plan. Either may be nil; a
+// tenant with neither is unlimited.
+func NewTenantEntitlements(tenantID uuid.UUID, plan *Plan, override *TenantPlanOverride, usage QuotaUsage) *TenantEntitlements {
+	entitlements := &TenantEntitlements{
+		TenantID: tenantID,
+		Plan:     plan,
+		Usage:    usage,
+		Features: FeatureSet{},
+	}
+	if plan != nil {
+		entitlements.Limits = plan.PlanLimits
+		for feature, enabled := range plan.Features {
+			entitlements.Features[feature] = enabled
+		}
+	}
+	if override != nil {
+		entitlements.Override = override
+		entitlements.Limits = entitlements.Limits.Merge(override.PlanLimits)
+		for feature, enabled := range override.Features {
+			entitlements.Features[feature] = enabled
+		}
+	}
+	return entitlements
+}
+
+// AtLimit reports whether adding one more of resource would exceed the limit
+func (e *TenantEntitlements) AtLimit(resource QuotaResource) bool {
+	limit := e.Limits.Limit(resource)
+	return limit != nil && e.Usage.Used(resource) >= int64(*limit)
+}
+
+// CheckLimit returns a QuotaExceededError when adding one more of resource
+// would exceed the limit
+func (e *TenantEntitlements) CheckLimit(resource QuotaResource) error {
+	if e.AtLimit(resource) {
+		return &QuotaExceededError{Resource: resource, Limit: *e.Limits.Limit(resource)}
+	}
+	return nil
+}
+
+// QuotaCheck returns a check of the entitlements' limits for one more of
+// resources, against the usage it is given rather than the usage counted
+// when the entitlements were loaded
+func (e *TenantEntitlements) QuotaCheck(resources ...QuotaResource) QuotaCheck {
+	limits := *e
+	return func(usage QuotaUsage) error {
+		limits.Usage = usage
+		for _, resource := range resources {
+			if err := limits.CheckLimit(resource); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// QuotaCheck refuses an addition to a tenant when usage leaves no room for it.
+// Repositories call it with usage counted while the tenant row is locked, so
+// concurrent additions cannot both pass it.
+type QuotaCheck func(usage QuotaUsage) error
+
+// QuotaExceededError is returned when an action would take a tenant past a
+// limit of its plan
+type QuotaExceededError struct {
+	Resource QuotaResource
+	Limit    int
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("plan quota exceeded: %s is limited to %d on this tenant's plan", e.Resource, e.Limit)
+}
+
+// HasFeature reports whether the feature is enabled for the tenant
+func (e *TenantEntitlements) HasFeature(feature string) bool {
+	return e.Features[feature]
+}
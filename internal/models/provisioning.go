@@ -0,0 +1,116 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ProvisioningStepStatus string
+
+const (
+	ProvisioningStepPending ProvisioningStepStatus = "pending"
+	// ProvisioningStepAccepted means the service answered 202 and will report
+	// the outcome through the callback endpoint
+	ProvisioningStepAccepted  ProvisioningStepStatus = "accepted"
+	ProvisioningStepSucceeded ProvisioningStepStatus = "succeeded"
+	ProvisioningStepFailed    ProvisioningStepStatus = "failed"
+)
+
+// ProvisioningOperation distinguishes initializing a tenant in a service from
+// tearing it down
+type ProvisioningOperation string
+
+const (
+	ProvisioningOperationProvision   ProvisioningOperation = "provision"
+	ProvisioningOperationDeprovision ProvisioningOperation = "deprovision"
+)
+
+// provisioningNamespace scopes the deterministic idempotency keys
+var provisioningNamespace = uuid.MustParse("6f2b8f0e-3c1a-4b7e-9d55-2a4c8e1f7b90")
+
+// TenantProvisioningStep tracks provisioning of one tenant in one downstream service
+type TenantProvisioningStep struct {
+	ID               uuid.UUID              `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	TenantID         uuid.UUID              `gorm:"type:uuid;not null;index" json:"tenant_id"`
+	Service          string                 `gorm:"type:varchar(500);not null" json:"service"`
+	Operation        ProvisioningOperation  `gorm:"type:varchar(20);not null;default:'provision'" json:"operation"`
+	Status           ProvisioningStepStatus `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	Attempts         int                    `gorm:"not null;default:0" json:"attempts"`
+	LastError        string                 `gorm:"type:text" json:"last_error,omitempty"`
+	IdempotencyKey   string                 `gorm:"type:varchar(255);unique;not null" json:"idempotency_key"`
+	LastAttemptAt    *time.Time             `json:"last_attempt_at"`
+	CallbackDeadline *time.Time             `json:"callback_deadline,omitempty"`
+	CompletedAt      *time.Time             `json:"completed_at"`
+	CreatedAt        time.Time              `json:"created_at"`
+	UpdatedAt        time.Time              `json:"updated_at"`
+}
+
+func (TenantProvisioningStep) TableName() string {
+	return "tenant_provisioning_steps"
+}
+
+// Retries counts the attempts made after the first
+func (s *TenantProvisioningStep) Retries() int {
+	if s.Attempts < 1 {
+		return 0
+	}
+	return s.Attempts - 1
+}
+
+// ProvisioningIdempotencyKey returns the key sent with every request for a
+// tenant, service and operation. It is the same on every retry so services can
+// safely de-duplicate.
+func ProvisioningIdempotencyKey(tenantID uuid.UUID, operation ProvisioningOperation, service string) string {
+	name := tenantID.String() + "|" + service
+	if operation != ProvisioningOperationProvision {
+		// Provision keys predate operations and must not change
+		name += "|" + string(operation)
+	}
+	return uuid.NewSHA1(provisioningNamespace, []byte(name)).String()
+}
+
+// TenantProvisioningStatus summarizes a tenant's provisioning or teardown progress
+type TenantProvisioningStatus struct {
+	TenantID         uuid.UUID                 `json:"tenant_id"`
+	Status           TenantStatus              `json:"status"`
+	Operation        ProvisioningOperation     `json:"operation"`
+	Total            int                       `json:"total"`
+	Succeeded        int                       `json:"succeeded"`
+	Failed           int                       `json:"failed"`
+	Pending          int                       `json:"pending"`
+	AwaitingCallback int                       `json:"awaiting_callback"`
+	Steps            []*TenantProvisioningStep `json:"steps"`
+}
+
+// ProvisioningCallbackInput is the body services send to report the outcome of
+// a provisioning request they accepted with 202
+type ProvisioningCallbackInput struct {
+	IdempotencyKey string                 `json:"idempotency_key" binding:"required"`
+	Status         ProvisioningStepStatus `json:"status" binding:"required,oneof=succeeded failed"`
+	Error          string                 `json:"error"`
+}
+
+// NewTenantProvisioningStatus counts steps by status
+func NewTenantProvisioningStatus(tenant *Tenant, operation ProvisioningOperation, steps []*TenantProvisioningStep) *TenantProvisioningStatus {
+	status := &TenantProvisioningStatus{
+		TenantID:  tenant.ID,
+		Status:    tenant.Status,
+		Operation: operation,
+		Total:     len(steps),
+		Steps:     steps,
+	}
+	for _, step := range steps {
+		switch step.Status {
+		case ProvisioningStepSucceeded:
+			status.Succeeded++
+		case ProvisioningStepFailed:
+			status.Failed++
+		case ProvisioningStepAccepted:
+			status.AwaitingCallback++
+		default:
+			status.Pending++
+		}
+	}
+	return status
+}
@@ -26,6 +26,31 @@ func (Role) TableName() string {
 	return "roles"
 }
 
+// The permission that lets a member manage a tenant. Every active tenant must
+// keep at least one active member whose roles grant it.
+const (
+	TenantManagementService = "tenant-api"
+	TenantManagementEntity  = "tenant"
+	TenantManagementAction  = "manage"
+)
+
+// LastManagerErrorCode identifies LastManagerError in API responses
+const LastManagerErrorCode = "last_tenant_manager"
+
+// LastManagerError is returned when a change would leave an active tenant
+// without an active member whose roles grant tenant management. TenantIDs
+// lists the tenants that would be left unmanaged.
+type LastManagerError struct {
+	TenantIDs []uuid.UUID
+}
+
+func (e *LastManagerError) Error() string {
+	if len(e.TenantIDs) == 1 {
+		return "this change would leave the tenant without an active member who can manage it"
+	}
+	return "this change would leave tenants without an active member who can manage them"
+}
+
 type CreateRoleInput struct {
 	Name        string     `json:"name" binding:"required,min=2,max=100"`
 	Type        string     `json:"type" binding:"required,oneof=tenant platform"`
@@ -1,6 +1,9 @@
 package models
 
 import (
+	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,28 +17,76 @@ const (
 	TenantStatusActive    TenantStatus = "active"
 	TenantStatusSuspended TenantStatus = "suspended"
 	TenantStatusDeleted   TenantStatus = "deleted"
+	TenantStatusArchived  TenantStatus = "archived"
+	// TenantStatusDeleting marks a deleted tenant past retention whose data is
+	// being torn down in downstream services before it is purged
+	TenantStatusDeleting TenantStatus = "deleting"
 )
 
+// tenantStatusTransitions lists the statuses each status may move to
+var tenantStatusTransitions = map[TenantStatus][]TenantStatus{
+	TenantStatusPending:   {TenantStatusActive, TenantStatusDeleted},
+	TenantStatusActive:    {TenantStatusSuspended, TenantStatusArchived, TenantStatusDeleted},
+	TenantStatusSuspended: {TenantStatusActive, TenantStatusArchived, TenantStatusDeleted},
+	TenantStatusArchived:  {TenantStatusActive, TenantStatusDeleted},
+	TenantStatusDeleted:   {TenantStatusDeleting},
+	TenantStatusDeleting:  {},
+}
+
+// CanTransitionTo reports whether the lifecycle allows moving from s to target
+func (s TenantStatus) CanTransitionTo(target TenantStatus) bool {
+	for _, allowed := range tenantStatusTransitions[s] {
+		if allowed == target {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValid reports whether s is a known tenant status
+func (s TenantStatus) IsValid() bool {
+	_, ok := tenantStatusTransitions[s]
+	return ok
+}
+
 type Tenant struct {
-	ID        uuid.UUID      `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	Name      string         `gorm:"type:varchar(255);not null" json:"name"`
-	Slug      string         `gorm:"type:varchar(255);unique;not null" json:"slug"`
-	Status    TenantStatus   `gorm:"type:tenant_status;not null;default:'pending'" json:"status"`
-	Metadata  JSONMap        `gorm:"type:jsonb;default:'{}'" json:"metadata"`
-	CreatedBy string         `gorm:"type:varchar(255);not null" json:"created_by"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+	ID         uuid.UUID    `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Name       string       `gorm:"type:varchar(255);not null" json:"name"`
+	Slug       string       `gorm:"type:varchar(255);unique;not null" json:"slug"`
+	Status     TenantStatus `gorm:"type:tenant_status;not null;default:'pending'" json:"status"`
+	Metadata   JSONMap      `gorm:"type:jsonb;default:'{}'" json:"metadata"`
+	TenantType *string      `gorm:"type:varchar(100)" json:"tenant_type"`
+	PlanID     *uuid.UUID   `gorm:"type:uuid" json:"plan_id"`
+	TemplateID *uuid.UUID   `gorm:"type:uuid" json:"template_id"`
+	// DefaultRoleID is given to members and invitations that don't name a role
+	DefaultRoleID *uuid.UUID `gorm:"type:uuid" json:"default_role_id"`
+	// ProvisionServices limits provisioning to these services and their
+	// dependencies; nil means every enabled service
+	ProvisionServices StringList             `gorm:"type:jsonb" json:"provision_services,omitempty"`
+	SecuritySettings  TenantSecuritySettings `gorm:"type:jsonb;not null;default:'{}'" json:"security_settings"`
+	CreatedBy         string                 `gorm:"type:varchar(255);not null" json:"created_by"`
+	OwnerID           *string                `gorm:"type:varchar(255);index" json:"owner_id"`
+	CreatedAt         time.Time              `json:"created_at"`
+	UpdatedAt         time.Time              `json:"updated_at"`
+	DeletedAt         gorm.DeletedAt         `gorm:"index" json:"deleted_at,omitempty"`
 }
 
 func (Tenant) TableName() string {
 	return "tenants"
 }
 
+// IsOwnedBy reports whether userID is the tenant's current owner
+func (t *Tenant) IsOwnedBy(userID string) bool {
+	return t.OwnerID != nil && *t.OwnerID == userID
+}
+
 type CreateTenantInput struct {
-	Name     string  `json:"name" binding:"required,min=3,max=255"`
-	Slug     string  `json:"slug" binding:"required,min=3,max=255,alphanum"`
-	Metadata JSONMap `json:"metadata"`
+	Name       string  `json:"name" binding:"required,min=3,max=255"`
+	Slug       string  `json:"slug" binding:"required,min=3,max=255,alphanum"`
+	Metadata   JSONMap `json:"metadata"`
+	TenantType *string `json:"tenant_type" binding:"omitempty,min=1,max=100"`
+	// Template names a tenant template to apply when the tenant is created
+	Template string `json:"template" binding:"omitempty,max=100"`
 }
 
 type UpdateTenantInput struct {
@@ -44,27 +95,122 @@ type UpdateTenantInput struct {
 	Metadata JSONMap       `json:"metadata,omitempty"`
 }
 
+// TenantSort values accepted by tenant listings; prefix with - to sort
+// descending
+const (
+	TenantSortName        = "name"
+	TenantSortCreatedAt   = "created_at"
+	TenantSortMemberCount = "member_count"
+)
+
+// TenantListParams filters and sorts tenant listings. Every filter is
+// optional and they are combined with AND.
+type TenantListParams struct {
+	PaginationParams
+	// Status matches any of the given statuses
+	Status        []TenantStatus `form:"status"`
+	CreatedAfter  time.Time      `form:"created_after" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedBefore time.Time      `form:"created_before" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedBy     string         `form:"created_by"`
+	// Search matches a case-insensitive substring of the name or slug
+	Search string `form:"q" binding:"omitempty,max=255"`
+	// Metadata holds key:value pairs the tenant's metadata must contain
+	Metadata []string `form:"metadata"`
+	// Sort is name, created_at or member_count, descending with a - prefix.
+	// Defaults to -created_at.
+	Sort string `form:"sort"`
+}
+
+// Validate checks the statuses, date range and sort, and parses the metadata
+// filters into containment documents
+func (p *TenantListParams) Validate() error {
+	for _, status := range p.Status {
+		if !status.IsValid() {
+			return fmt.Errorf("invalid status %q", status)
+		}
+	}
+
+	if !p.CreatedAfter.IsZero() && !p.CreatedBefore.IsZero() && p.CreatedBefore.Before(p.CreatedAfter) {
+		return fmt.Errorf("created_before must not be before created_after")
+	}
+
+	switch strings.TrimPrefix(p.Sort, "-") {
+	case "", TenantSortName, TenantSortCreatedAt, TenantSortMemberCount:
+	default:
+		return fmt.Errorf("invalid sort %q, must be name, created_at or member_count", p.Sort)
+	}
+
+	_, err := p.MetadataFilters()
+	return err
+}
+
+// MetadataFilters returns one set of alternative containment documents per
+// metadata filter. The value is matched as a string and, when it is valid
+// JSON such as a number or boolean, also as that value.
+func (p *TenantListParams) MetadataFilters() ([][]string, error) {
+	filters := make([][]string, 0, len(p.Metadata))
+	for _, pair := range p.Metadata {
+		key, value, ok := strings.Cut(pair, ":")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid metadata filter %q, must be key:value", pair)
+		}
+
+		candidates := []interface{}{value}
+		var typed interface{}
+		if err := json.Unmarshal([]byte(value), &typed); err == nil {
+			if _, isString := typed.(string); !isString {
+				candidates = append(candidates, typed)
+			}
+		}
+
+		docs := make([]string, 0, len(candidates))
+		for _, candidate := range candidates {
+			doc, err := json.Marshal(map[string]interface{}{key: candidate})
+			if err != nil {
+				return nil, err
+			}
+			docs = append(docs, string(doc))
+		}
+		filters = append(filters, docs)
+	}
+	return filters, nil
+}
+
 type TenantResponse struct {
-	ID          uuid.UUID    `json:"id"`
-	Name        string       `json:"name"`
-	Slug        string       `json:"slug"`
-	Status      TenantStatus `json:"status"`
-	Metadata    JSONMap      `json:"metadata"`
-	CreatedBy   string       `json:"created_by"`
-	MemberCount int          `json:"member_count"`
-	CreatedAt   time.Time    `json:"created_at"`
-	UpdatedAt   time.Time    `json:"updated_at"`
+	ID                uuid.UUID              `json:"id"`
+	Name              string                 `json:"name"`
+	Slug              string                 `json:"slug"`
+	Status            TenantStatus           `json:"status"`
+	Metadata          JSONMap                `json:"metadata"`
+	TenantType        *string                `json:"tenant_type"`
+	PlanID            *uuid.UUID             `json:"plan_id"`
+	TemplateID        *uuid.UUID             `json:"template_id"`
+	DefaultRoleID     *uuid.UUID             `json:"default_role_id"`
+	ProvisionServices StringList             `json:"provision_services,omitempty"`
+	SecuritySettings  TenantSecuritySettings `json:"security_settings"`
+	CreatedBy         string                 `json:"created_by"`
+	OwnerID           *string                `json:"owner_id"`
+	MemberCount       int                    `json:"member_count"`
+	CreatedAt         time.Time              `json:"created_at"`
+	UpdatedAt         time.Time              `json:"updated_at"`
 }
 
 func (t *Tenant) ToResponse() *TenantResponse {
 	return &TenantResponse{
-		ID:        t.ID,
-		Name:      t.Name,
-		Slug:      t.Slug,
-		Status:    t.Status,
-		Metadata:  t.Metadata,
-		CreatedBy: t.CreatedBy,
-		CreatedAt: t.CreatedAt,
-		UpdatedAt: t.UpdatedAt,
+		ID:                t.ID,
+		Name:              t.Name,
+		Slug:              t.Slug,
+		Status:            t.Status,
+		Metadata:          t.Metadata,
+		TenantType:        t.TenantType,
+		PlanID:            t.PlanID,
+		TemplateID:        t.TemplateID,
+		DefaultRoleID:     t.DefaultRoleID,
+		ProvisionServices: t.ProvisionServices,
+		SecuritySettings:  t.SecuritySettings,
+		CreatedBy:         t.CreatedBy,
+		OwnerID:           t.OwnerID,
+		CreatedAt:         t.CreatedAt,
+		UpdatedAt:         t.UpdatedAt,
 	}
 }
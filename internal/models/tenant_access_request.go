@@ -0,0 +1,79 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AccessRequestStatus string
+
+const (
+	AccessRequestPending   AccessRequestStatus = "pending"
+	AccessRequestApproved  AccessRequestStatus = "approved"
+	AccessRequestDenied    AccessRequestStatus = "denied"
+	AccessRequestCancelled AccessRequestStatus = "cancelled"
+)
+
+// TenantAccessRequest is a user's request to join a tenant. Tenant admins
+// approve it, which adds the user as a member, or deny it with a reason;
+// resolved requests are kept so both sides can see what happened.
+type TenantAccessRequest struct {
+	ID         uuid.UUID           `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	TenantID   uuid.UUID           `gorm:"type:uuid;not null;index" json:"tenant_id"`
+	UserID     string              `gorm:"type:varchar(255);not null;index" json:"user_id"`
+	Email      string              `gorm:"type:varchar(255);not null" json:"email"`
+	Message    string              `gorm:"type:text" json:"message,omitempty"`
+	Status     AccessRequestStatus `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	RoleID     *uuid.UUID          `gorm:"type:uuid" json:"role_id,omitempty"`
+	Reason     string              `gorm:"type:text" json:"reason,omitempty"`
+	ResolvedBy *string             `gorm:"type:varchar(255)" json:"resolved_by,omitempty"`
+	ResolvedAt *time.Time          `json:"resolved_at,omitempty"`
+	CreatedAt  time.Time           `json:"created_at"`
+	UpdatedAt  time.Time           `json:"updated_at"`
+
+	// Relations
+	Tenant *Tenant `gorm:"foreignKey:TenantID" json:"-"`
+}
+
+func (TenantAccessRequest) TableName() string {
+	return "tenant_access_requests"
+}
+
+type CreateAccessRequestInput struct {
+	Message string `json:"message" binding:"omitempty,max=1000"`
+}
+
+type ApproveAccessRequestInput struct {
+	// RoleID defaults to the tenant's default role when omitted
+	RoleID uuid.UUID `json:"role_id"`
+}
+
+type DenyAccessRequestInput struct {
+	Reason string `json:"reason" binding:"omitempty,max=1000"`
+}
+
+type AccessRequestListParams struct {
+	Status AccessRequestStatus `form:"status" binding:"omitempty,oneof=pending approved denied cancelled"`
+}
+
+// MyAccessRequest is one of the current user's access requests with the
+// tenant it was sent to
+type MyAccessRequest struct {
+	*TenantAccessRequest
+	Tenant *MembershipTenant `json:"tenant,omitempty"`
+}
+
+// ToMyAccessRequest adds the tenant summary when the tenant was preloaded
+func (r *TenantAccessRequest) ToMyAccessRequest() *MyAccessRequest {
+	mine := &MyAccessRequest{TenantAccessRequest: r}
+	if r.Tenant != nil {
+		mine.Tenant = &MembershipTenant{
+			ID:     r.Tenant.ID,
+			Name:   r.Tenant.Name,
+			Slug:   r.Tenant.Slug,
+			Status: r.Tenant.Status,
+		}
+	}
+	return mine
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TenantDomain maps a custom domain to a tenant
+type TenantDomain struct {
+	ID                uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	TenantID          uuid.UUID  `gorm:"type:uuid;not null;index" json:"tenant_id"`
+	Domain            string     `gorm:"type:varchar(255);not null" json:"domain"`
+	VerificationToken string     `gorm:"type:varchar(255);not null" json:"-"`
+	VerifiedAt        *time.Time `json:"verified_at"`
+	LastCheckedAt     *time.Time `json:"last_checked_at"`
+	CreatedBy         string     `gorm:"type:varchar(255);not null" json:"created_by"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+func (TenantDomain) TableName() string {
+	return "tenant_domains"
+}
+
+// IsVerified reports whether domain ownership has been proven
+func (d *TenantDomain) IsVerified() bool {
+	return d.VerifiedAt != nil
+}
+
+type AddTenantDomainInput struct {
+	Domain string `json:"domain" binding:"required,fqdn,max=255"`
+}
+
+// DomainVerificationRecord is the DNS record a tenant must publish to verify a domain
+type DomainVerificationRecord struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type TenantDomainResponse struct {
+	ID            uuid.UUID                 `json:"id"`
+	TenantID      uuid.UUID                 `json:"tenant_id"`
+	Domain        string                    `json:"domain"`
+	Verified      bool                      `json:"verified"`
+	VerifiedAt    *time.Time                `json:"verified_at"`
+	LastCheckedAt *time.Time                `json:"last_checked_at"`
+	Verification  *DomainVerificationRecord `json:"verification,omitempty"`
+	CreatedBy     string                    `json:"created_by"`
+	CreatedAt     time.Time                 `json:"created_at"`
+	UpdatedAt     time.Time                 `json:"updated_at"`
+}
+
+// ToResponse converts the domain to its API response. The verification record is
+// included until the domain is verified so tenants know what to publish.
+func (d *TenantDomain) ToResponse(record *DomainVerificationRecord) *TenantDomainResponse {
+	resp := &TenantDomainResponse{
+		ID:            d.ID,
+		TenantID:      d.TenantID,
+		Domain:        d.Domain,
+		Verified:      d.IsVerified(),
+		VerifiedAt:    d.VerifiedAt,
+		LastCheckedAt: d.LastCheckedAt,
+		CreatedBy:     d.CreatedBy,
+		CreatedAt:     d.CreatedAt,
+		UpdatedAt:     d.UpdatedAt,
+	}
+	if !d.IsVerified() {
+		resp.Verification = record
+	}
+	return resp
+}
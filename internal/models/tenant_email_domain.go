@@ -0,0 +1,86 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// How users with a matching email address join the tenant
+const (
+	EmailDomainJoinAuto     = "auto"
+	EmailDomainJoinApproval = "approval"
+)
+
+// TenantEmailDomain lets users with an email address at Domain join the tenant
+// when they sign in, once the tenant has proven it owns the domain
+type TenantEmailDomain struct {
+	ID                uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	TenantID          uuid.UUID  `gorm:"type:uuid;not null;index" json:"tenant_id"`
+	Domain            string     `gorm:"type:varchar(255);not null" json:"domain"`
+	VerificationToken string     `gorm:"type:varchar(255);not null" json:"-"`
+	VerifiedAt        *time.Time `json:"verified_at"`
+	LastCheckedAt     *time.Time `json:"last_checked_at"`
+	RoleID            *uuid.UUID `gorm:"type:uuid" json:"role_id"`
+	JoinMode          string     `gorm:"type:varchar(20);not null;default:'approval'" json:"join_mode"`
+	CreatedBy         string     `gorm:"type:varchar(255);not null" json:"created_by"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+func (TenantEmailDomain) TableName() string {
+	return "tenant_email_domains"
+}
+
+// IsVerified reports whether domain ownership has been proven
+func (d *TenantEmailDomain) IsVerified() bool {
+	return d.VerifiedAt != nil
+}
+
+type AddTenantEmailDomainInput struct {
+	Domain   string     `json:"domain" binding:"required,fqdn,max=255"`
+	RoleID   *uuid.UUID `json:"role_id"` // Defaults to the tenant's default role
+	JoinMode string     `json:"join_mode" binding:"omitempty,oneof=auto approval"`
+}
+
+type UpdateTenantEmailDomainInput struct {
+	RoleID   *uuid.UUID `json:"role_id"`
+	JoinMode *string    `json:"join_mode" binding:"omitempty,oneof=auto approval"`
+}
+
+type TenantEmailDomainResponse struct {
+	ID            uuid.UUID                 `json:"id"`
+	TenantID      uuid.UUID                 `json:"tenant_id"`
+	Domain        string                    `json:"domain"`
+	Verified      bool                      `json:"verified"`
+	VerifiedAt    *time.Time                `json:"verified_at"`
+	LastCheckedAt *time.Time                `json:"last_checked_at"`
+	Verification  *DomainVerificationRecord `json:"verification,omitempty"`
+	RoleID        *uuid.UUID                `json:"role_id"`
+	JoinMode      string                    `json:"join_mode"`
+	CreatedBy     string                    `json:"created_by"`
+	CreatedAt     time.Time                 `json:"created_at"`
+	UpdatedAt     time.Time                 `json:"updated_at"`
+}
+
+// ToResponse converts the email domain to its API response. The verification
+// record is included until the domain is verified.
+func (d *TenantEmailDomain) ToResponse(record *DomainVerificationRecord) *TenantEmailDomainResponse {
+	resp := &TenantEmailDomainResponse{
+		ID:            d.ID,
+		TenantID:      d.TenantID,
+		Domain:        d.Domain,
+		Verified:      d.IsVerified(),
+		VerifiedAt:    d.VerifiedAt,
+		LastCheckedAt: d.LastCheckedAt,
+		RoleID:        d.RoleID,
+		JoinMode:      d.JoinMode,
+		CreatedBy:     d.CreatedBy,
+		CreatedAt:     d.CreatedAt,
+		UpdatedAt:     d.UpdatedAt,
+	}
+	if !d.IsVerified() {
+		resp.Verification = record
+	}
+	return resp
+}
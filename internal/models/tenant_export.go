@@ -0,0 +1,98 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TenantExportStatus string
+
+const (
+	TenantExportPending   TenantExportStatus = "pending"
+	TenantExportRunning   TenantExportStatus = "running"
+	TenantExportCompleted TenantExportStatus = "completed"
+	TenantExportFailed    TenantExportStatus = "failed"
+	TenantExportExpired   TenantExportStatus = "expired"
+)
+
+// TenantExport is a job-backed archive of a tenant's identity data. The
+// archive is removed once ExpiresAt passes.
+type TenantExport struct {
+	ID                uuid.UUID          `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	TenantID          uuid.UUID          `gorm:"type:uuid;not null;index" json:"tenant_id"`
+	Status            TenantExportStatus `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	Progress          int                `gorm:"not null;default:0" json:"progress"`
+	Stage             string             `gorm:"type:varchar(50)" json:"stage,omitempty"`
+	FilePath          string             `gorm:"type:varchar(500)" json:"-"`
+	FileSize          int64              `gorm:"not null;default:0" json:"file_size"`
+	Error             string             `gorm:"type:text" json:"error,omitempty"`
+	RequestedBy       string             `gorm:"type:varchar(255);not null" json:"requested_by"`
+	DownloadTokenHash *string            `gorm:"type:varchar(64)" json:"-"`
+	DownloadExpiresAt *time.Time         `json:"-"`
+	StartedAt         *time.Time         `json:"started_at"`
+	CompletedAt       *time.Time         `json:"completed_at"`
+	ExpiresAt         *time.Time         `json:"expires_at"`
+	CreatedAt         time.Time          `json:"created_at"`
+	UpdatedAt         time.Time          `json:"updated_at"`
+}
+
+func (TenantExport) TableName() string {
+	return "tenant_exports"
+}
+
+// Downloadable reports whether the archive can still be downloaded
+func (e *TenantExport) Downloadable() bool {
+	return e.Status == TenantExportCompleted && e.FilePath != "" &&
+		(e.ExpiresAt == nil || time.Now().Before(*e.ExpiresAt))
+}
+
+// TenantExportLink is a time-limited download link for an export
+type TenantExportLink struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// TenantExportManifest describes the files in an export archive
+type TenantExportManifest struct {
+	ExportID    uuid.UUID      `json:"export_id"`
+	TenantID    uuid.UUID      `json:"tenant_id"`
+	GeneratedAt time.Time      `json:"generated_at"`
+	RequestedBy string         `json:"requested_by"`
+	Files       map[string]int `json:"files"`
+}
+
+// ExportedMember is a member as written to an export archive
+type ExportedMember struct {
+	ID        uuid.UUID        `json:"id"`
+	UserID    string           `json:"user_id"`
+	Email     string           `json:"email,omitempty"`
+	Status    MemberStatus     `json:"status"`
+	Role      *ExportedRoleRef `json:"role,omitempty"`
+	InvitedBy *string          `json:"invited_by,omitempty"`
+	JoinedAt  time.Time        `json:"joined_at"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// ExportedRoleRef names the role of a member or invitation
+type ExportedRoleRef struct {
+	ID       uuid.UUID `json:"id"`
+	Name     string    `json:"name"`
+	IsSystem bool      `json:"is_system"`
+}
+
+// ExportedInvitation is an invitation with its lifecycle as written to an
+// export archive. The invitation token is left out.
+type ExportedInvitation struct {
+	ID             uuid.UUID        `json:"id"`
+	Email          string           `json:"email"`
+	Role           *ExportedRoleRef `json:"role,omitempty"`
+	InvitedBy      string           `json:"invited_by"`
+	InvitedByEmail string           `json:"invited_by_email,omitempty"`
+	Status         InvitationStatus `json:"status"`
+	SentAt         time.Time        `json:"sent_at"`
+	AcceptedAt     *time.Time       `json:"accepted_at,omitempty"`
+	ExpiresAt      time.Time        `json:"expires_at"`
+	LastUpdatedAt  time.Time        `json:"last_updated_at"`
+}
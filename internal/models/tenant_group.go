@@ -0,0 +1,89 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TenantGroup is a team within a tenant. Roles granted to the group are
+// inherited by every active member in it, on top of the member's own role.
+type TenantGroup struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	TenantID    uuid.UUID `gorm:"type:uuid;not null;index" json:"tenant_id"`
+	Name        string    `gorm:"type:varchar(100);not null" json:"name"`
+	Description string    `gorm:"type:text" json:"description"`
+	CreatedBy   string    `gorm:"type:varchar(255);not null" json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	// Associations
+	Roles []Role `gorm:"many2many:tenant_group_roles;foreignKey:ID;joinForeignKey:GroupID;References:ID;joinReferences:RoleID;" json:"roles"`
+
+	MemberCount int64 `gorm:"-" json:"member_count"`
+}
+
+func (TenantGroup) TableName() string {
+	return "tenant_groups"
+}
+
+// TenantGroupMember puts a tenant member in a group. Removing the member from
+// the tenant removes them from its groups.
+type TenantGroupMember struct {
+	GroupID   uuid.UUID `gorm:"type:uuid;primary_key" json:"group_id"`
+	MemberID  uuid.UUID `gorm:"type:uuid;primary_key" json:"member_id"`
+	AddedBy   string    `gorm:"type:varchar(255);not null" json:"added_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (TenantGroupMember) TableName() string {
+	return "tenant_group_members"
+}
+
+type CreateTenantGroupInput struct {
+	Name        string      `json:"name" binding:"required,min=2,max=100"`
+	Description string      `json:"description" binding:"omitempty,max=500"`
+	RoleIDs     []uuid.UUID `json:"role_ids"`
+}
+
+type UpdateTenantGroupInput struct {
+	Name        *string `json:"name,omitempty" binding:"omitempty,min=2,max=100"`
+	Description *string `json:"description,omitempty" binding:"omitempty,max=500"`
+}
+
+// AddGroupMembersInput adds tenant members to a group by user ID
+type AddGroupMembersInput struct {
+	UserIDs []string `json:"user_ids" binding:"required,min=1,max=100,dive,required"`
+}
+
+type AssignGroupRolesInput struct {
+	RoleIDs []uuid.UUID `json:"role_ids" binding:"required,min=1"`
+}
+
+// Where a user's permission comes from
+const (
+	PermissionSourceMember = "member"
+	PermissionSourceGroup  = "group"
+)
+
+// PermissionGrant is one path by which a user holds a permission: the role,
+// held directly or through a group, and the policy that includes it
+type PermissionGrant struct {
+	Source     string     `json:"source"`
+	GroupID    *uuid.UUID `json:"group_id,omitempty"`
+	GroupName  string     `json:"group_name,omitempty"`
+	RoleID     uuid.UUID  `json:"role_id"`
+	RoleName   string     `json:"role_name"`
+	PolicyID   uuid.UUID  `json:"policy_id"`
+	PolicyName string     `json:"policy_name"`
+}
+
+// PermissionExplanation says whether a user holds a permission in a tenant
+// and every grant that gives it to them
+type PermissionExplanation struct {
+	TenantID   uuid.UUID          `json:"tenant_id"`
+	UserID     string             `json:"user_id"`
+	Permission string             `json:"permission"`
+	Authorized bool               `json:"authorized"`
+	Grants     []*PermissionGrant `json:"grants"`
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SystemActorID is recorded as the actor for transitions made by background jobs
+const SystemActorID = "system"
+
+// TenantStatusTransition is one entry in a tenant's lifecycle history
+type TenantStatusTransition struct {
+	ID         uuid.UUID    `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	TenantID   uuid.UUID    `gorm:"type:uuid;not null;index" json:"tenant_id"`
+	FromStatus TenantStatus `gorm:"type:varchar(20);not null" json:"from_status"`
+	ToStatus   TenantStatus `gorm:"type:varchar(20);not null" json:"to_status"`
+	Reason     string       `gorm:"type:text" json:"reason"`
+	ActorID    string       `gorm:"type:varchar(255);not null" json:"actor_id"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+func (TenantStatusTransition) TableName() string {
+	return "tenant_status_transitions"
+}
+
+type SuspendTenantInput struct {
+	Reason string `json:"reason" binding:"required,min=3,max=1000"`
+}
+
+type TenantTransitionInput struct {
+	Reason string `json:"reason" binding:"omitempty,max=1000"`
+}
+
+type TenantStatusTransitionResponse struct {
+	ID         uuid.UUID    `json:"id"`
+	TenantID   uuid.UUID    `json:"tenant_id"`
+	FromStatus TenantStatus `json:"from_status"`
+	ToStatus   TenantStatus `json:"to_status"`
+	Reason     string       `json:"reason"`
+	ActorID    string       `json:"actor_id"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+func (t *TenantStatusTransition) ToResponse() *TenantStatusTransitionResponse {
+	return &TenantStatusTransitionResponse{
+		ID:         t.ID,
+		TenantID:   t.TenantID,
+		FromStatus: t.FromStatus,
+		ToStatus:   t.ToStatus,
+		Reason:     t.Reason,
+		ActorID:    t.ActorID,
+		CreatedAt:  t.CreatedAt,
+	}
+}
+
+// TenantPurgeReport records what was removed when a soft-deleted tenant was purged
+type TenantPurgeReport struct {
+	ID                uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	TenantID          uuid.UUID `gorm:"type:uuid;not null;index" json:"tenant_id"`
+	TenantName        string    `gorm:"type:varchar(255);not null" json:"tenant_name"`
+	TenantSlug        string    `gorm:"type:varchar(255);not null" json:"tenant_slug"`
+	DeletedAt         time.Time `gorm:"not null" json:"deleted_at"`
+	MembersPurged     int64     `gorm:"not null;default:0" json:"members_purged"`
+	InvitationsPurged int64     `gorm:"not null;default:0" json:"invitations_purged"`
+	RolesPurged       int64     `gorm:"not null;default:0" json:"roles_purged"`
+	PoliciesPurged    int64     `gorm:"not null;default:0" json:"policies_purged"`
+	TransitionsPurged int64     `gorm:"not null;default:0" json:"transitions_purged"`
+	PurgedAt          time.Time `gorm:"autoCreateTime" json:"purged_at"`
+}
+
+func (TenantPurgeReport) TableName() string {
+	return "tenant_purge_reports"
+}
+
+// DeletedTenantResponse describes a soft-deleted tenant that can still be restored
+type DeletedTenantResponse struct {
+	*TenantResponse
+	DeletedAt  time.Time `json:"deleted_at"`
+	PurgeAfter time.Time `json:"purge_after"`
+	Restorable bool      `json:"restorable"`
+}
+
+func (t *Tenant) ToDeletedResponse(retention time.Duration) *DeletedTenantResponse {
+	deletedAt := t.DeletedAt.Time
+	return &DeletedTenantResponse{
+		TenantResponse: t.ToResponse(),
+		DeletedAt:      deletedAt,
+		PurgeAfter:     deletedAt.Add(retention),
+		Restorable:     t.Status == TenantStatusDeleted && time.Since(deletedAt) <= retention,
+	}
+}
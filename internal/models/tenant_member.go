@@ -12,6 +12,9 @@ const (
 	MemberStatusActive   MemberStatus = "active"
 	MemberStatusInactive MemberStatus = "inactive"
 	MemberStatusPending  MemberStatus = "pending"
+	// MemberStatusSuspended is set and cleared only through suspension and
+	// reactivation, which record who did it and why
+	MemberStatusSuspended MemberStatus = "suspended"
 )
 
 type TenantMember struct {
@@ -22,21 +25,41 @@ type TenantMember struct {
 	Status    MemberStatus `gorm:"type:member_status;not null;default:'active'" json:"status"`
 	InvitedBy *string      `gorm:"type:varchar(255)" json:"invited_by"`
 	JoinedAt  time.Time    `gorm:"default:CURRENT_TIMESTAMP" json:"joined_at"`
-	CreatedAt time.Time    `json:"created_at"`
-	UpdatedAt time.Time    `json:"updated_at"`
+	// LastActiveAt is when the member last made a tenant-scoped request. It is
+	// written only by activity tracking, never by Save.
+	LastActiveAt *time.Time `gorm:"->" json:"last_active_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
 
 	// Associations
 	Tenant Tenant `gorm:"foreignKey:TenantID" json:"tenant,omitempty"`
 	Role   Role   `gorm:"foreignKey:RoleID" json:"role,omitempty"`
+
+	// Suspensions is the member's suspension history, newest first. It is only
+	// loaded for a single member.
+	Suspensions []MemberSuspension `gorm:"foreignKey:MemberID" json:"suspensions,omitempty"`
 }
 
 func (TenantMember) TableName() string {
 	return "tenant_members"
 }
 
+// TenantMemberRemoval records that a user's membership of a tenant was
+// removed, so joining by email domain does not add them back
+type TenantMemberRemoval struct {
+	TenantID  uuid.UUID `gorm:"type:uuid;primary_key" json:"tenant_id"`
+	UserID    string    `gorm:"type:varchar(255);primary_key" json:"user_id"`
+	RemovedAt time.Time `gorm:"not null" json:"removed_at"`
+}
+
+func (TenantMemberRemoval) TableName() string {
+	return "tenant_member_removals"
+}
+
 type AddMemberInput struct {
-	UserID string    `json:"user_id" binding:"required"`
-	RoleID uuid.UUID `json:"role_id" binding:"required"`
+	UserID string `json:"user_id" binding:"required"`
+	// RoleID defaults to the tenant's default role when omitted
+	RoleID uuid.UUID `json:"role_id"`
 }
 
 type UpdateMemberInput struct {
@@ -53,21 +76,28 @@ type MemberResponse struct {
 	Status    MemberStatus  `json:"status"`
 	InvitedBy *string       `json:"invited_by"`
 	JoinedAt  time.Time     `json:"joined_at"`
-	CreatedAt time.Time     `json:"created_at"`
-	UpdatedAt time.Time     `json:"updated_at"`
+	// LastActiveAt is when the member last made a tenant-scoped request
+	LastActiveAt *time.Time `json:"last_active_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+
+	Suspensions []MemberSuspension `json:"suspensions,omitempty"`
 }
 
 func (tm *TenantMember) ToResponse() *MemberResponse {
 	resp := &MemberResponse{
-		ID:        tm.ID,
-		TenantID:  tm.TenantID,
-		UserID:    tm.UserID,
-		RoleID:    tm.RoleID,
-		Status:    tm.Status,
-		InvitedBy: tm.InvitedBy,
-		JoinedAt:  tm.JoinedAt,
-		CreatedAt: tm.CreatedAt,
-		UpdatedAt: tm.UpdatedAt,
+		ID:           tm.ID,
+		TenantID:     tm.TenantID,
+		UserID:       tm.UserID,
+		RoleID:       tm.RoleID,
+		Status:       tm.Status,
+		InvitedBy:    tm.InvitedBy,
+		JoinedAt:     tm.JoinedAt,
+		LastActiveAt: tm.LastActiveAt,
+		CreatedAt:    tm.CreatedAt,
+		UpdatedAt:    tm.UpdatedAt,
+
+		Suspensions: tm.Suspensions,
 	}
 
 	if tm.Role.ID != uuid.Nil {
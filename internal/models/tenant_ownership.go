@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OwnershipTransferStatus string
+
+const (
+	OwnershipTransferPending   OwnershipTransferStatus = "pending"
+	OwnershipTransferAccepted  OwnershipTransferStatus = "accepted"
+	OwnershipTransferDeclined  OwnershipTransferStatus = "declined"
+	OwnershipTransferCancelled OwnershipTransferStatus = "cancelled"
+	OwnershipTransferExpired   OwnershipTransferStatus = "expired"
+)
+
+// TenantOwnershipTransfer nominates a new owner for a tenant. The nominee
+// must accept before ownership changes; resolved transfers are kept as the
+// tenant's ownership history.
+type TenantOwnershipTransfer struct {
+	ID                  uuid.UUID               `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	TenantID            uuid.UUID               `gorm:"type:uuid;not null;index" json:"tenant_id"`
+	FromUserID          *string                 `gorm:"type:varchar(255)" json:"from_user_id"`
+	ToUserID            string                  `gorm:"type:varchar(255);not null" json:"to_user_id"`
+	PreviousOwnerRoleID *uuid.UUID              `gorm:"type:uuid" json:"previous_owner_role_id,omitempty"`
+	Status              OwnershipTransferStatus `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	Message             string                  `gorm:"type:text" json:"message,omitempty"`
+	InitiatedBy         string                  `gorm:"type:varchar(255);not null" json:"initiated_by"`
+	ResolvedBy          *string                 `gorm:"type:varchar(255)" json:"resolved_by,omitempty"`
+	ResolvedAt          *time.Time              `json:"resolved_at,omitempty"`
+	ExpiresAt           time.Time               `gorm:"not null" json:"expires_at"`
+	CreatedAt           time.Time               `json:"created_at"`
+	UpdatedAt           time.Time               `json:"updated_at"`
+}
+
+func (TenantOwnershipTransfer) TableName() string {
+	return "tenant_ownership_transfers"
+}
+
+// IsExpired reports whether a pending transfer can no longer be accepted
+func (t *TenantOwnershipTransfer) IsExpired() bool {
+	return t.Status == OwnershipTransferPending && time.Now().After(t.ExpiresAt)
+}
+
+type InitiateOwnershipTransferInput struct {
+	NewOwnerID string `json:"new_owner_id" binding:"required"`
+	// PreviousOwnerRoleID moves the current owner to this role once the
+	// transfer is accepted; by default they keep their current role
+	PreviousOwnerRoleID *uuid.UUID `json:"previous_owner_role_id,omitempty"`
+	Message             string     `json:"message" binding:"omitempty,max=1000"`
+}
@@ -0,0 +1,193 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Login methods a tenant can require. A SuperTokens user signs in with exactly
+// one of them.
+const (
+	LoginMethodPassword = "password"
+	LoginMethodGoogle   = "google"
+)
+
+// TenantSecuritySettings is the security policy Rex enforces for a tenant's
+// members. Zero values impose no restriction. Platform admins are not subject
+// to it.
+type TenantSecuritySettings struct {
+	// AllowedEmailDomains restricts who can be invited, added or accepted as a
+	// member, and which members can access the tenant
+	AllowedEmailDomains []string `json:"allowed_email_domains"`
+	// MaxSessionLifetimeMinutes is how long after signing in a member can keep
+	// accessing the tenant before they must sign in again
+	MaxSessionLifetimeMinutes int `json:"max_session_lifetime_minutes"`
+	// AllowedLoginMethods restricts how members must have signed in
+	AllowedLoginMethods []string `json:"allowed_login_methods"`
+	// OnlyAdminsCanInvite stops members without the tenant management
+	// permission from inviting or adding members
+	OnlyAdminsCanInvite bool `json:"only_admins_can_invite"`
+	// InactiveMemberDeactivationDays deactivates members who have not used the
+	// tenant for this many days. The owner and the tenant's last manager are
+	// never deactivated.
+	InactiveMemberDeactivationDays int `json:"inactive_member_deactivation_days"`
+	// RoleGrantRules names which roles each role may grant. When set, members
+	// can only grant roles listed for one of their roles, on top of never
+	// granting a role with permissions they do not hold themselves.
+	RoleGrantRules []RoleGrantRule `json:"role_grant_rules"`
+}
+
+// RoleGrantRule lets members holding RoleID grant the roles in CanGrant
+type RoleGrantRule struct {
+	RoleID   uuid.UUID   `json:"role_id" binding:"required"`
+	CanGrant []uuid.UUID `json:"can_grant" binding:"max=100"`
+}
+
+// SecurityPolicyError is returned when a tenant's security settings block an
+// action. Setting names the setting responsible.
+type SecurityPolicyError struct {
+	Setting string
+	Message string
+}
+
+func (e *SecurityPolicyError) Error() string {
+	return e.Message
+}
+
+// RoleGrantError is returned when an actor tries to grant a role that gives
+// permissions they do not hold themselves. Missing lists those permissions.
+type RoleGrantError struct {
+	RoleID   uuid.UUID
+	RoleName string
+	Missing  []string
+}
+
+func (e *RoleGrantError) Error() string {
+	return fmt.Sprintf("you cannot grant the %s role, it has permissions you do not hold: %s",
+		e.RoleName, strings.Join(e.Missing, ", "))
+}
+
+// Value implements the driver.Valuer interface
+func (s TenantSecuritySettings) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
+
+// Scan implements the sql.Scanner interface
+func (s *TenantSecuritySettings) Scan(value interface{}) error {
+	return scanJSONArray(value, s, "TenantSecuritySettings")
+}
+
+// AllowsEmail reports whether email's domain is allowed
+func (s *TenantSecuritySettings) AllowsEmail(email string) bool {
+	if len(s.AllowedEmailDomains) == 0 {
+		return true
+	}
+
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := strings.ToLower(email[at+1:])
+
+	for _, allowed := range s.AllowedEmailDomains {
+		if domain == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsLoginMethod reports whether a session signed in with method is allowed
+func (s *TenantSecuritySettings) AllowsLoginMethod(method string) bool {
+	if len(s.AllowedLoginMethods) == 0 {
+		return true
+	}
+	for _, allowed := range s.AllowedLoginMethods {
+		if method == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// SessionExpired reports whether a session started at startedAt has outlived
+// the maximum session lifetime
+func (s *TenantSecuritySettings) SessionExpired(startedAt, now time.Time) bool {
+	if s.MaxSessionLifetimeMinutes <= 0 {
+		return false
+	}
+	return now.Sub(startedAt) > time.Duration(s.MaxSessionLifetimeMinutes)*time.Minute
+}
+
+// GrantableRoles returns the roles that holders of roleIDs may grant and
+// whether the tenant restricts grants at all
+func (s *TenantSecuritySettings) GrantableRoles(roleIDs []uuid.UUID) (map[uuid.UUID]bool, bool) {
+	if len(s.RoleGrantRules) == 0 {
+		return nil, false
+	}
+
+	held := make(map[uuid.UUID]bool, len(roleIDs))
+	for _, roleID := range roleIDs {
+		held[roleID] = true
+	}
+
+	grantable := make(map[uuid.UUID]bool)
+	for _, rule := range s.RoleGrantRules {
+		if !held[rule.RoleID] {
+			continue
+		}
+		for _, roleID := range rule.CanGrant {
+			grantable[roleID] = true
+		}
+	}
+	return grantable, true
+}
+
+// UpdateTenantSecuritySettingsInput replaces a tenant's security settings
+type UpdateTenantSecuritySettingsInput struct {
+	AllowedEmailDomains       []string `json:"allowed_email_domains" binding:"omitempty,max=50,dive,fqdn"`
+	MaxSessionLifetimeMinutes int      `json:"max_session_lifetime_minutes" binding:"min=0,max=525600"`
+	AllowedLoginMethods       []string `json:"allowed_login_methods" binding:"omitempty,dive,oneof=password google"`
+	OnlyAdminsCanInvite       bool     `json:"only_admins_can_invite"`
+	// InactiveMemberDeactivationDays of 0 turns automatic deactivation off
+	InactiveMemberDeactivationDays int `json:"inactive_member_deactivation_days" binding:"min=0,max=3650"`
+	// RoleGrantRules left empty lets members grant any role within their own permissions
+	RoleGrantRules []RoleGrantRule `json:"role_grant_rules" binding:"omitempty,max=100,dive"`
+}
+
+// Settings returns the settings with domains lowercased and duplicates removed
+func (i *UpdateTenantSecuritySettingsInput) Settings() TenantSecuritySettings {
+	return TenantSecuritySettings{
+		AllowedEmailDomains:       uniqueSorted(i.AllowedEmailDomains, strings.ToLower),
+		MaxSessionLifetimeMinutes: i.MaxSessionLifetimeMinutes,
+		AllowedLoginMethods:       uniqueSorted(i.AllowedLoginMethods, nil),
+		OnlyAdminsCanInvite:       i.OnlyAdminsCanInvite,
+
+		InactiveMemberDeactivationDays: i.InactiveMemberDeactivationDays,
+		RoleGrantRules:                 i.RoleGrantRules,
+	}
+}
+
+func uniqueSorted(values []string, normalize func(string) string) []string {
+	seen := make(map[string]bool, len(values))
+	var result []string
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if normalize != nil {
+			value = normalize(value)
+		}
+		if value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+		result = append(result, value)
+	}
+	sort.Strings(result)
+	return result
+}
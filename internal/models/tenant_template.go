@@ -0,0 +1,173 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StringList is stored as a JSONB array of strings; a nil list is stored as NULL
+type StringList []string
+
+// Value implements the driver.Valuer interface
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return nil, nil
+	}
+	return json.Marshal(l)
+}
+
+// Scan implements the sql.Scanner interface
+func (l *StringList) Scan(value interface{}) error {
+	return scanJSONArray(value, l, "StringList")
+}
+
+// TemplatePolicy is a tenant-scoped policy created from a template.
+// Permissions are written as service:entity:action.
+type TemplatePolicy struct {
+	Name        string   `json:"name" binding:"required,min=2,max=100"`
+	Description string   `json:"description,omitempty" binding:"omitempty,max=500"`
+	Permissions []string `json:"permissions"`
+}
+
+// TemplateRole is a tenant-scoped role created from a template. Policies name
+// template policies or system policies.
+type TemplateRole struct {
+	Name        string   `json:"name" binding:"required,min=2,max=100"`
+	Description string   `json:"description,omitempty" binding:"omitempty,max=500"`
+	Policies    []string `json:"policies"`
+}
+
+// TemplatePolicies is stored as a JSONB array
+type TemplatePolicies []TemplatePolicy
+
+// Value implements the driver.Valuer interface
+func (p TemplatePolicies) Value() (driver.Value, error) {
+	if p == nil {
+		return "[]", nil
+	}
+	return json.Marshal(p)
+}
+
+// Scan implements the sql.Scanner interface
+func (p *TemplatePolicies) Scan(value interface{}) error {
+	return scanJSONArray(value, p, "TemplatePolicies")
+}
+
+// TemplateRoles is stored as a JSONB array
+type TemplateRoles []TemplateRole
+
+// Value implements the driver.Valuer interface
+func (r TemplateRoles) Value() (driver.Value, error) {
+	if r == nil {
+		return "[]", nil
+	}
+	return json.Marshal(r)
+}
+
+// Scan implements the sql.Scanner interface
+func (r *TemplateRoles) Scan(value interface{}) error {
+	return scanJSONArray(value, r, "TemplateRoles")
+}
+
+// TenantTemplate pre-configures tenants created from it: their tenant-scoped
+// roles and policies, default metadata, the role new members get and the
+// downstream services they are provisioned in
+type TenantTemplate struct {
+	ID              uuid.UUID        `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Name            string           `gorm:"type:varchar(100);unique;not null" json:"name"`
+	Description     string           `gorm:"type:text" json:"description"`
+	TenantType      *string          `gorm:"type:varchar(100)" json:"tenant_type"`
+	DefaultMetadata JSONMap          `gorm:"type:jsonb;not null;default:'{}'" json:"default_metadata"`
+	Policies        TemplatePolicies `gorm:"type:jsonb;not null;default:'[]'" json:"policies"`
+	Roles           TemplateRoles    `gorm:"type:jsonb;not null;default:'[]'" json:"roles"`
+	DefaultRole     string           `gorm:"type:varchar(100)" json:"default_role,omitempty"`
+	Services        StringList       `gorm:"type:jsonb" json:"services"`
+	CreatedBy       string           `gorm:"type:varchar(255);not null" json:"created_by"`
+	CreatedAt       time.Time        `json:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at"`
+}
+
+func (TenantTemplate) TableName() string {
+	return "tenant_templates"
+}
+
+// Validate checks the template is self-consistent. References to system
+// policies, permissions and services are checked against the database by
+// the service.
+func (t *TenantTemplate) Validate() error {
+	policies := make(map[string]bool, len(t.Policies))
+	for _, policy := range t.Policies {
+		if policies[policy.Name] {
+			return fmt.Errorf("policy %q is defined more than once", policy.Name)
+		}
+		policies[policy.Name] = true
+		for _, key := range policy.Permissions {
+			if _, _, _, err := ParsePermissionKey(key); err != nil {
+				return fmt.Errorf("policy %q: %w", policy.Name, err)
+			}
+		}
+	}
+
+	roles := make(map[string]bool, len(t.Roles))
+	for _, role := range t.Roles {
+		if roles[role.Name] {
+			return fmt.Errorf("role %q is defined more than once", role.Name)
+		}
+		roles[role.Name] = true
+	}
+
+	services := make(map[string]bool, len(t.Services))
+	for _, name := range t.Services {
+		if services[name] {
+			return fmt.Errorf("service %q is listed more than once", name)
+		}
+		services[name] = true
+	}
+
+	return nil
+}
+
+// HasRole reports whether the template defines a role named name
+func (t *TenantTemplate) HasRole(name string) bool {
+	for _, role := range t.Roles {
+		if role.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPolicy reports whether the template defines a policy named name
+func (t *TenantTemplate) HasPolicy(name string) bool {
+	for _, policy := range t.Policies {
+		if policy.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+type TenantTemplateInput struct {
+	Description     string           `json:"description" binding:"omitempty,max=1000"`
+	TenantType      *string          `json:"tenant_type" binding:"omitempty,min=1,max=100"`
+	DefaultMetadata JSONMap          `json:"default_metadata"`
+	Policies        []TemplatePolicy `json:"policies" binding:"omitempty,dive"`
+	Roles           []TemplateRole   `json:"roles" binding:"omitempty,dive"`
+	DefaultRole     string           `json:"default_role" binding:"omitempty,max=100"`
+	Services        []string         `json:"services"`
+}
+
+type CreateTenantTemplateInput struct {
+	Name string `json:"name" binding:"required,min=2,max=100"`
+	TenantTemplateInput
+}
+
+// UpdateTenantTemplateInput replaces the template's definition. Tenants
+// already created from the template are not changed.
+type UpdateTenantTemplateInput struct {
+	TenantTemplateInput
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserProfile holds a user's preferences in Rex
+type UserProfile struct {
+	UserID string `gorm:"type:varchar(255);primary_key" json:"user_id"`
+	// DefaultTenantID is the tenant the frontend opens first
+	DefaultTenantID *uuid.UUID `gorm:"type:uuid" json:"default_tenant_id"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+func (UserProfile) TableName() string {
+	return "user_profiles"
+}
+
+// SetDefaultTenantInput sets the current user's default tenant; null clears it
+type SetDefaultTenantInput struct {
+	TenantID *uuid.UUID `json:"tenant_id"`
+}
+
+// MembershipTenant is the part of a tenant a member's tenant switcher shows
+type MembershipTenant struct {
+	ID     uuid.UUID    `json:"id"`
+	Name   string       `json:"name"`
+	Slug   string       `json:"slug"`
+	Status TenantStatus `json:"status"`
+}
+
+// MyMembership is one of the current user's active memberships with the
+// permissions it gives them, including those inherited from groups
+type MyMembership struct {
+	MemberID     uuid.UUID        `json:"member_id"`
+	Tenant       MembershipTenant `json:"tenant"`
+	Role         *RoleResponse    `json:"role,omitempty"`
+	Permissions  []string         `json:"permissions"`
+	IsOwner      bool             `json:"is_owner"`
+	IsDefault    bool             `json:"is_default"`
+	JoinedAt     time.Time        `json:"joined_at"`
+	LastActiveAt *time.Time       `json:"last_active_at"`
+}
+
+// MyMemberships lists the current user's memberships. DefaultTenantID is only
+// set while the user is still an active member of that tenant.
+type MyMemberships struct {
+	DefaultTenantID *uuid.UUID      `json:"default_tenant_id"`
+	Memberships     []*MyMembership `json:"memberships"`
+}
@@ -0,0 +1,68 @@
+// Package dnsverify checks domain ownership through DNS TXT records.
+package dnsverify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// RecordPrefix is prepended to a domain to form the name of its verification TXT record
+const RecordPrefix = "_rex-verification"
+
+// lookupTimeout bounds a single TXT lookup
+const lookupTimeout = 10 * time.Second
+
+// Resolver looks up TXT records. net.Resolver satisfies it; tests can substitute a stub.
+type Resolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// Verifier checks that a domain publishes an expected verification token
+type Verifier struct {
+	resolver Resolver
+}
+
+// NewVerifier creates a Verifier. A nil resolver uses the system DNS resolver.
+func NewVerifier(resolver Resolver) *Verifier {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	return &Verifier{resolver: resolver}
+}
+
+// RecordName returns the TXT record name that must hold the token for domain
+func RecordName(domain string) string {
+	return fmt.Sprintf("%s.%s", RecordPrefix, domain)
+}
+
+// RecordValue returns the TXT record value expected for token
+func RecordValue(token string) string {
+	return fmt.Sprintf("rex-verification=%s", token)
+}
+
+// Verify reports whether domain publishes the verification record for token
+func (v *Verifier) Verify(ctx context.Context, domain, token string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, lookupTimeout)
+	defer cancel()
+
+	records, err := v.resolver.LookupTXT(ctx, RecordName(domain))
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to look up TXT records for %s: %w", domain, err)
+	}
+
+	expected := RecordValue(token)
+	for _, record := range records {
+		if strings.TrimSpace(record) == expected {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,501 @@
+// Package jsonschema validates JSON documents against a practical subset of
+// JSON Schema (draft 2020-12): type, enum, const, properties, required,
+// additionalProperties, items, string/number/array bounds, pattern and a few
+// common formats. Schemas using any other keyword are rejected rather than
+// silently accepting documents the keyword was meant to refuse.
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FieldError describes one validation failure. Field is a dotted path to the
+// offending value ("" for the document root, "address.city", "tags[0]").
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// Schema is a compiled JSON Schema
+type Schema struct {
+	types                []string
+	enum                 []interface{}
+	constValue           interface{}
+	hasConst             bool
+	properties           map[string]*Schema
+	required             []string
+	additionalProperties *Schema
+	noAdditional         bool
+	items                *Schema
+	minLength            *int
+	maxLength            *int
+	pattern              *regexp.Regexp
+	format               string
+	minimum              *float64
+	maximum              *float64
+	exclusiveMinimum     *float64
+	exclusiveMaximum     *float64
+	minItems             *int
+	maxItems             *int
+	minProperties        *int
+	maxProperties        *int
+}
+
+var validTypes = map[string]bool{
+	"object": true, "array": true, "string": true, "number": true,
+	"integer": true, "boolean": true, "null": true,
+}
+
+// supportedKeywords are the keywords Compile understands. Annotations that do
+// not affect validation are accepted too.
+var supportedKeywords = map[string]bool{
+	"type": true, "enum": true, "const": true, "properties": true, "required": true,
+	"additionalProperties": true, "items": true, "pattern": true, "format": true,
+	"minLength": true, "maxLength": true, "minItems": true, "maxItems": true,
+	"minProperties": true, "maxProperties": true, "minimum": true, "maximum": true,
+	"exclusiveMinimum": true, "exclusiveMaximum": true,
+
+	"$schema": true, "$id": true, "$comment": true, "title": true, "description": true,
+	"default": true, "examples": true, "deprecated": true, "readOnly": true, "writeOnly": true,
+}
+
+// Compile checks a schema document and prepares it for validation. Keywords
+// outside the supported subset, such as $ref, oneOf or if, are an error.
+func Compile(doc map[string]interface{}) (*Schema, error) {
+	return compile(doc, "")
+}
+
+func compile(doc map[string]interface{}, path string) (*Schema, error) {
+	s := &Schema{}
+
+	var unsupported []string
+	for keyword := range doc {
+		if !supportedKeywords[keyword] {
+			unsupported = append(unsupported, keyword)
+		}
+	}
+	if len(unsupported) > 0 {
+		sort.Strings(unsupported)
+		return nil, schemaError(path, unsupported[0], "is not supported")
+	}
+
+	if raw, ok := doc["type"]; ok {
+		switch t := raw.(type) {
+		case string:
+			s.types = []string{t}
+		case []interface{}:
+			for _, v := range t {
+				name, ok := v.(string)
+				if !ok {
+					return nil, schemaError(path, "type", "must be a string or an array of strings")
+				}
+				s.types = append(s.types, name)
+			}
+		default:
+			return nil, schemaError(path, "type", "must be a string or an array of strings")
+		}
+		for _, t := range s.types {
+			if !validTypes[t] {
+				return nil, schemaError(path, "type", fmt.Sprintf("unknown type %q", t))
+			}
+		}
+	}
+
+	if raw, ok := doc["enum"]; ok {
+		values, ok := raw.([]interface{})
+		if !ok || len(values) == 0 {
+			return nil, schemaError(path, "enum", "must be a non-empty array")
+		}
+		s.enum = values
+	}
+
+	if raw, ok := doc["const"]; ok {
+		s.constValue = raw
+		s.hasConst = true
+	}
+
+	if raw, ok := doc["properties"]; ok {
+		props, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, schemaError(path, "properties", "must be an object")
+		}
+		s.properties = make(map[string]*Schema, len(props))
+		for name, rawProp := range props {
+			propDoc, ok := rawProp.(map[string]interface{})
+			if !ok {
+				return nil, schemaError(joinPath(path, name), "", "property schema must be an object")
+			}
+			prop, err := compile(propDoc, joinPath(path, name))
+			if err != nil {
+				return nil, err
+			}
+			s.properties[name] = prop
+		}
+	}
+
+	if raw, ok := doc["required"]; ok {
+		names, ok := raw.([]interface{})
+		if !ok {
+			return nil, schemaError(path, "required", "must be an array of strings")
+		}
+		for _, v := range names {
+			name, ok := v.(string)
+			if !ok {
+				return nil, schemaError(path, "required", "must be an array of strings")
+			}
+			s.required = append(s.required, name)
+		}
+	}
+
+	if raw, ok := doc["additionalProperties"]; ok {
+		switch v := raw.(type) {
+		case bool:
+			s.noAdditional = !v
+		case map[string]interface{}:
+			additional, err := compile(v, path)
+			if err != nil {
+				return nil, err
+			}
+			s.additionalProperties = additional
+		default:
+			return nil, schemaError(path, "additionalProperties", "must be a boolean or a schema")
+		}
+	}
+
+	if raw, ok := doc["items"]; ok {
+		itemsDoc, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, schemaError(path, "items", "must be a schema")
+		}
+		items, err := compile(itemsDoc, path+"[]")
+		if err != nil {
+			return nil, err
+		}
+		s.items = items
+	}
+
+	if raw, ok := doc["pattern"]; ok {
+		expr, ok := raw.(string)
+		if !ok {
+			return nil, schemaError(path, "pattern", "must be a string")
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, schemaError(path, "pattern", err.Error())
+		}
+		s.pattern = re
+	}
+
+	if raw, ok := doc["format"]; ok {
+		format, ok := raw.(string)
+		if !ok {
+			return nil, schemaError(path, "format", "must be a string")
+		}
+		s.format = format
+	}
+
+	var err error
+	if s.minLength, err = intKeyword(doc, "minLength", path); err != nil {
+		return nil, err
+	}
+	if s.maxLength, err = intKeyword(doc, "maxLength", path); err != nil {
+		return nil, err
+	}
+	if s.minItems, err = intKeyword(doc, "minItems", path); err != nil {
+		return nil, err
+	}
+	if s.maxItems, err = intKeyword(doc, "maxItems", path); err != nil {
+		return nil, err
+	}
+	if s.minProperties, err = intKeyword(doc, "minProperties", path); err != nil {
+		return nil, err
+	}
+	if s.maxProperties, err = intKeyword(doc, "maxProperties", path); err != nil {
+		return nil, err
+	}
+	if s.minimum, err = numberKeyword(doc, "minimum", path); err != nil {
+		return nil, err
+	}
+	if s.maximum, err = numberKeyword(doc, "maximum", path); err != nil {
+		return nil, err
+	}
+	if s.exclusiveMinimum, err = numberKeyword(doc, "exclusiveMinimum", path); err != nil {
+		return nil, err
+	}
+	if s.exclusiveMaximum, err = numberKeyword(doc, "exclusiveMaximum", path); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Validate checks value against the schema and returns every failure found,
+// sorted by field. A nil result means the value conforms.
+func (s *Schema) Validate(value interface{}) []FieldError {
+	var errs []FieldError
+	s.validate(normalize(value), "", &errs)
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Field < errs[j].Field })
+	return errs
+}
+
+func (s *Schema) validate(value interface{}, path string, errs *[]FieldError) {
+	add := func(format string, args ...interface{}) {
+		*errs = append(*errs, FieldError{Field: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(s.types) > 0 && !matchesAnyType(value, s.types) {
+		add("must be of type %s", strings.Join(s.types, " or "))
+		return
+	}
+
+	if s.hasConst && !reflect.DeepEqual(value, normalize(s.constValue)) {
+		add("must be %v", s.constValue)
+	}
+
+	if len(s.enum) > 0 {
+		found := false
+		for _, allowed := range s.enum {
+			if reflect.DeepEqual(value, normalize(allowed)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			add("must be one of %v", s.enum)
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		s.validateObject(v, path, errs)
+	case []interface{}:
+		s.validateArray(v, path, errs)
+	case string:
+		s.validateString(v, add)
+	case float64:
+		s.validateNumber(v, add)
+	}
+}
+
+func (s *Schema) validateObject(obj map[string]interface{}, path string, errs *[]FieldError) {
+	for _, name := range s.required {
+		if _, ok := obj[name]; !ok {
+			*errs = append(*errs, FieldError{Field: joinPath(path, name), Message: "is required"})
+		}
+	}
+
+	if s.minProperties != nil && len(obj) < *s.minProperties {
+		*errs = append(*errs, FieldError{Field: path, Message: fmt.Sprintf("must have at least %d properties", *s.minProperties)})
+	}
+	if s.maxProperties != nil && len(obj) > *s.maxProperties {
+		*errs = append(*errs, FieldError{Field: path, Message: fmt.Sprintf("must have at most %d properties", *s.maxProperties)})
+	}
+
+	for name, propValue := range obj {
+		propPath := joinPath(path, name)
+		if prop, ok := s.properties[name]; ok {
+			prop.validate(propValue, propPath, errs)
+			continue
+		}
+		if s.noAdditional {
+			*errs = append(*errs, FieldError{Field: propPath, Message: "is not allowed"})
+			continue
+		}
+		if s.additionalProperties != nil {
+			s.additionalProperties.validate(propValue, propPath, errs)
+		}
+	}
+}
+
+func (s *Schema) validateArray(arr []interface{}, path string, errs *[]FieldError) {
+	if s.minItems != nil && len(arr) < *s.minItems {
+		*errs = append(*errs, FieldError{Field: path, Message: fmt.Sprintf("must have at least %d items", *s.minItems)})
+	}
+	if s.maxItems != nil && len(arr) > *s.maxItems {
+		*errs = append(*errs, FieldError{Field: path, Message: fmt.Sprintf("must have at most %d items", *s.maxItems)})
+	}
+	if s.items != nil {
+		for i, item := range arr {
+			s.items.validate(item, fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	}
+}
+
+func (s *Schema) validateString(str string, add func(string, ...interface{})) {
+	length := len([]rune(str))
+	if s.minLength != nil && length < *s.minLength {
+		add("must be at least %d characters", *s.minLength)
+	}
+	if s.maxLength != nil && length > *s.maxLength {
+		add("must be at most %d characters", *s.maxLength)
+	}
+	if s.pattern != nil && !s.pattern.MatchString(str) {
+		add("must match pattern %s", s.pattern.String())
+	}
+	if s.format != "" && !matchesFormat(str, s.format) {
+		add("must be a valid %s", s.format)
+	}
+}
+
+func (s *Schema) validateNumber(n float64, add func(string, ...interface{})) {
+	if s.minimum != nil && n < *s.minimum {
+		add("must be >= %v", *s.minimum)
+	}
+	if s.maximum != nil && n > *s.maximum {
+		add("must be <= %v", *s.maximum)
+	}
+	if s.exclusiveMinimum != nil && n <= *s.exclusiveMinimum {
+		add("must be > %v", *s.exclusiveMinimum)
+	}
+	if s.exclusiveMaximum != nil && n >= *s.exclusiveMaximum {
+		add("must be < %v", *s.exclusiveMaximum)
+	}
+}
+
+func matchesAnyType(value interface{}, types []string) bool {
+	for _, t := range types {
+		if matchesType(value, t) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesType(value interface{}, t string) bool {
+	switch t {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	}
+	return false
+}
+
+// matchesFormat checks the formats in common use; unknown formats always pass
+func matchesFormat(value, format string) bool {
+	switch format {
+	case "email":
+		addr, err := mail.ParseAddress(value)
+		return err == nil && addr.Address == value
+	case "uri", "url":
+		u, err := url.Parse(value)
+		return err == nil && u.Scheme != "" && u.Host != ""
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, value)
+		return err == nil
+	case "date":
+		_, err := time.Parse("2006-01-02", value)
+		return err == nil
+	case "uuid":
+		return uuidPattern.MatchString(value)
+	}
+	return true
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// normalize converts a Go value into the shapes produced by encoding/json
+// (map[string]interface{}, []interface{}, float64, ...) so typed maps and
+// integers validate the same way decoded JSON does.
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil, string, bool, float64:
+		return v
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			out[k] = normalize(item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = normalize(item)
+		}
+		return out
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return value
+	}
+	return out
+}
+
+func intKeyword(doc map[string]interface{}, key, path string) (*int, error) {
+	n, err := numberKeyword(doc, key, path)
+	if err != nil || n == nil {
+		return nil, err
+	}
+	if *n < 0 || *n != math.Trunc(*n) {
+		return nil, schemaError(path, key, "must be a non-negative integer")
+	}
+	v := int(*n)
+	return &v, nil
+}
+
+func numberKeyword(doc map[string]interface{}, key, path string) (*float64, error) {
+	raw, ok := doc[key]
+	if !ok {
+		return nil, nil
+	}
+	n, ok := normalize(raw).(float64)
+	if !ok {
+		return nil, schemaError(path, key, "must be a number")
+	}
+	return &n, nil
+}
+
+func joinPath(base, name string) string {
+	if base == "" {
+		return name
+	}
+	return base + "." + name
+}
+
+func schemaError(path, keyword, msg string) error {
+	location := path
+	if location == "" {
+		location = "(root)"
+	}
+	if keyword == "" {
+		return fmt.Errorf("invalid schema at %s: %s", location, msg)
+	}
+	return fmt.Errorf("invalid schema at %s: %s %s", location, keyword, msg)
+}
@@ -0,0 +1,136 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func mustDecode(t *testing.T, raw string) map[string]interface{} {
+	t.Helper()
+	var doc map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &doc); err != nil {
+		t.Fatalf("invalid test JSON %s: %v", raw, err)
+	}
+	return doc
+}
+
+func TestCompileRejectsUnsupportedKeywords(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		keyword string
+	}{
+		{"ref", `{"$ref": "#/$defs/name"}`, "$ref"},
+		{"oneOf", `{"oneOf": [{"type": "string"}, {"type": "number"}]}`, "oneOf"},
+		{"anyOf", `{"anyOf": [{"type": "string"}]}`, "anyOf"},
+		{"allOf", `{"allOf": [{"type": "string"}]}`, "allOf"},
+		{"if", `{"if": {"type": "string"}, "then": {"minLength": 1}}`, "if"},
+		{"not", `{"not": {"type": "null"}}`, "not"},
+		{"typo", `{"type": "string", "maxLenght": 3}`, "maxLenght"},
+		{"nested property", `{"properties": {"name": {"type": "string", "oneOf": []}}}`, "oneOf"},
+		{"nested items", `{"items": {"$ref": "#"}}`, "$ref"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile(mustDecode(t, tt.schema))
+			if err == nil {
+				t.Fatalf("Compile(%s) succeeded, want an error for %s", tt.schema, tt.keyword)
+			}
+			if !strings.Contains(err.Error(), tt.keyword+" is not supported") {
+				t.Errorf("Compile(%s) error = %q, want it to name %s", tt.schema, err, tt.keyword)
+			}
+		})
+	}
+}
+
+func TestCompileAcceptsAnnotations(t *testing.T) {
+	schema := `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id": "https://example.com/tenant.json",
+		"title": "Tenant metadata",
+		"description": "Extra tenant fields",
+		"type": "object",
+		"properties": {
+			"plan": {"type": "string", "default": "free", "examples": ["free", "pro"], "deprecated": true}
+		}
+	}`
+	if _, err := Compile(mustDecode(t, schema)); err != nil {
+		t.Fatalf("Compile returned %v, want annotations to be accepted", err)
+	}
+}
+
+func TestCompileRejectsMalformedKeywords(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+	}{
+		{"unknown type", `{"type": "text"}`},
+		{"empty enum", `{"enum": []}`},
+		{"properties not an object", `{"properties": []}`},
+		{"required not strings", `{"required": [1]}`},
+		{"bad pattern", `{"pattern": "("}`},
+		{"negative length", `{"minLength": -1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Compile(mustDecode(t, tt.schema)); err == nil {
+				t.Errorf("Compile(%s) succeeded, want an error", tt.schema)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	schema, err := Compile(mustDecode(t, `{
+		"type": "object",
+		"required": ["name", "seats"],
+		"additionalProperties": false,
+		"properties": {
+			"name": {"type": "string", "minLength": 2, "maxLength": 10},
+			"seats": {"type": "integer", "minimum": 1, "exclusiveMaximum": 100},
+			"tier": {"enum": ["free", "pro"]},
+			"contact": {"type": "string", "format": "email"},
+			"tags": {"type": "array", "maxItems": 2, "items": {"type": "string", "pattern": "^[a-z]+$"}}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		doc    string
+		fields []string
+	}{
+		{"valid", `{"name": "acme", "seats": 10, "tier": "pro", "contact": "ops@acme.io", "tags": ["a", "b"]}`, nil},
+		{"missing required", `{"name": "acme"}`, []string{"seats"}},
+		{"wrong type", `{"name": 5, "seats": 1}`, []string{"name"}},
+		{"string too short", `{"name": "a", "seats": 1}`, []string{"name"}},
+		{"not an integer", `{"name": "acme", "seats": 1.5}`, []string{"seats"}},
+		{"below minimum", `{"name": "acme", "seats": 0}`, []string{"seats"}},
+		{"at exclusive maximum", `{"name": "acme", "seats": 100}`, []string{"seats"}},
+		{"not in enum", `{"name": "acme", "seats": 1, "tier": "gold"}`, []string{"tier"}},
+		{"bad format", `{"name": "acme", "seats": 1, "contact": "nobody"}`, []string{"contact"}},
+		{"too many items", `{"name": "acme", "seats": 1, "tags": ["a", "b", "c"]}`, []string{"tags"}},
+		{"item pattern", `{"name": "acme", "seats": 1, "tags": ["A"]}`, []string{"tags[0]"}},
+		{"additional property", `{"name": "acme", "seats": 1, "color": "red"}`, []string{"color"}},
+		{"several failures sorted by field", `{"name": 1, "seats": 0}`, []string{"name", "seats"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := schema.Validate(mustDecode(t, tt.doc))
+			if len(errs) != len(tt.fields) {
+				t.Fatalf("Validate(%s) = %v, want failures on %q", tt.doc, errs, tt.fields)
+			}
+			for i, field := range tt.fields {
+				if errs[i].Field != field {
+					t.Errorf("Validate(%s) failure %d is on %q, want %q", tt.doc, i, errs[i].Field, field)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package signing
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSignCoversTimestampAndBody(t *testing.T) {
+	timestamp := time.Unix(1760000000, 0)
+	body := []byte(`{"tenant_id":"abc"}`)
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte("1760000000." + string(body)))
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	if got := Sign("secret", timestamp, body); got != want {
+		t.Errorf("Sign = %s, want %s", got, want)
+	}
+	if Sign("secret", timestamp.Add(time.Second), body) == want {
+		t.Error("Sign gave the same signature for a different timestamp")
+	}
+}
+
+func TestSignRequestVerifies(t *testing.T) {
+	body := []byte(`{"event":"initialize"}`)
+	req, err := http.NewRequest(http.MethodPost, "http://service.internal/provision", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	SignRequest(req, "secret", body)
+
+	err = Verify("secret", req.Header.Get(TimestampHeader), req.Header.Get(SignatureHeader), body, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("Verify of a signed request = %v, want nil", err)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"status":"completed"}`)
+	now := time.Now()
+	stamp := func(at time.Time) string { return strconv.FormatInt(at.Unix(), 10) }
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		signature string
+		body      []byte
+		want      error
+	}{
+		{"valid", "secret", stamp(now), Sign("secret", now, body), body, nil},
+		{"within tolerance", "secret", stamp(now.Add(-4 * time.Minute)), Sign("secret", now.Add(-4*time.Minute), body), body, nil},
+		{"wrong secret", "other", stamp(now), Sign("secret", now, body), body, ErrInvalidSignature},
+		{"tampered body", "secret", stamp(now), Sign("secret", now, body), []byte(`{"status":"failed"}`), ErrInvalidSignature},
+		{"timestamp swapped", "secret", stamp(now.Add(-time.Minute)), Sign("secret", now, body), body, ErrInvalidSignature},
+		{"too old", "secret", stamp(now.Add(-6 * time.Minute)), Sign("secret", now.Add(-6*time.Minute), body), body, ErrExpiredSignature},
+		{"too far ahead", "secret", stamp(now.Add(6 * time.Minute)), Sign("secret", now.Add(6*time.Minute), body), body, ErrExpiredSignature},
+		{"missing timestamp", "secret", "", Sign("secret", now, body), body, ErrMissingSignature},
+		{"missing signature", "secret", stamp(now), "", body, ErrMissingSignature},
+		{"malformed timestamp", "secret", "yesterday", Sign("secret", now, body), body, ErrInvalidSignature},
+		{"missing prefix", "secret", stamp(now), Sign("secret", now, body)[len("sha256="):], body, ErrInvalidSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.secret, tt.timestamp, tt.signature, tt.body, 5*time.Minute)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Verify = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
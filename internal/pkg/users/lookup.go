@@ -0,0 +1,82 @@
+package users
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword"
+	"github.com/supertokens/supertokens-golang/recipe/thirdparty"
+)
+
+// ErrUserNotFound is returned when no SuperTokens user has the ID or email
+var ErrUserNotFound = errors.New("user not found")
+
+// LookupUserID resolves an email address to a SuperTokens user ID. The same
+// email can belong to both an email/password user and a third-party user; in
+// that case the lookup is ambiguous and fails.
+func LookupUserID(email string) (string, error) {
+	var userIDs []string
+
+	epUser, err := emailpassword.GetUserByEmail("public", email)
+	if err != nil {
+		return "", fmt.Errorf("failed to get user info: %w", err)
+	}
+	if epUser != nil {
+		userIDs = append(userIDs, epUser.ID)
+	}
+
+	tpUsers, err := thirdparty.GetUsersByEmail("public", email)
+	if err != nil {
+		return "", fmt.Errorf("failed to get user info: %w", err)
+	}
+	for _, tpUser := range tpUsers {
+		userIDs = append(userIDs, tpUser.ID)
+	}
+
+	switch len(userIDs) {
+	case 0:
+		return "", ErrUserNotFound
+	case 1:
+		return userIDs[0], nil
+	}
+	return "", fmt.Errorf("%d users sign in with %s, use the user ID instead", len(userIDs), email)
+}
+
+// LookupEmail resolves a SuperTokens user ID to an email address.
+// Email/password users are checked first, then third-party (Google) users.
+func LookupEmail(userID string) (string, error) {
+	epUser, err := emailpassword.GetUserByID(userID)
+	if err == nil && epUser != nil {
+		return epUser.Email, nil
+	}
+
+	tpUser, tpErr := thirdparty.GetUserByID(userID)
+	if tpErr == nil && tpUser != nil {
+		return tpUser.Email, nil
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed to get user info: %w", err)
+	}
+	return "", ErrUserNotFound
+}
+
+// LookupLoginMethod returns how a SuperTokens user signs in: "password" for
+// email/password users, otherwise the third-party provider ID such as "google".
+// Each user ID belongs to a single login method.
+func LookupLoginMethod(userID string) (string, error) {
+	epUser, err := emailpassword.GetUserByID(userID)
+	if err == nil && epUser != nil {
+		return "password", nil
+	}
+
+	tpUser, tpErr := thirdparty.GetUserByID(userID)
+	if tpErr == nil && tpUser != nil {
+		return tpUser.ThirdParty.ID, nil
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed to get user info: %w", err)
+	}
+	return "", ErrUserNotFound
+}
@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/models"
+	"gorm.io/gorm"
+)
+
+// ErrAccessReviewClosed is returned when deciding an item or closing a
+// campaign that has already been closed
+var ErrAccessReviewClosed = errors.New("access review campaign is closed")
+
+type AccessReviewRepository interface {
+	CreateCampaign(campaign *models.AccessReviewCampaign, items []*models.AccessReviewItem) error
+	GetCampaign(id uuid.UUID) (*models.AccessReviewCampaign, error)
+	ListCampaigns(tenantID *uuid.UUID) ([]*models.AccessReviewCampaign, error)
+	CloseCampaign(campaign *models.AccessReviewCampaign) error
+
+	// ListReviewableMembers returns an unsaved item for the role of each
+	// active or suspended member of the active tenants in tenantIDs, or of
+	// every active tenant when tenantIDs is nil, and one for each role they
+	// inherit from a group, optionally limited to one role
+	ListReviewableMembers(tenantIDs []uuid.UUID, roleID *uuid.UUID) ([]*models.AccessReviewItem, error)
+
+	GetItem(id uuid.UUID) (*models.AccessReviewItem, error)
+	ListItems(campaignID uuid.UUID, decision models.AccessReviewDecision) ([]*models.AccessReviewItem, error)
+	ListPendingForReviewer(userID string, managedTenantIDs []uuid.UUID) ([]*models.AccessReviewItem, error)
+	Summarize(campaignID uuid.UUID) (*models.AccessReviewSummary, error)
+	Decide(item *models.AccessReviewItem) error
+	SetOutcome(itemID uuid.UUID, outcome models.AccessReviewOutcome, detail string) error
+}
+
+type accessReviewRepository struct {
+	db *gorm.DB
+}
+
+func NewAccessReviewRepository(db *gorm.DB) AccessReviewRepository {
+	return &accessReviewRepository{db: db}
+}
+
+func (r *accessReviewRepository) CreateCampaign(campaign *models.AccessReviewCampaign, items []*models.AccessReviewItem) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(campaign).Error; err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		for _, item := range items {
+			item.CampaignID = campaign.ID
+			item.Decision = models.AccessReviewPending
+		}
+		return tx.CreateInBatches(items, 500).Error
+	})
+}
+
+func (r *accessReviewRepository) GetCampaign(id uuid.UUID) (*models.AccessReviewCampaign, error) {
+	var campaign models.AccessReviewCampaign
+	err := r.db.Where("id = ?", id).First(&campaign).Error
+	if err != nil {
+		return nil, err
+	}
+	return &campaign, nil
+}
+
+// ListCampaigns returns the campaigns the tenant started, or every campaign
+// when tenantID is nil, newest first
+func (r *accessReviewRepository) ListCampaigns(tenantID *uuid.UUID) ([]*models.AccessReviewCampaign, error) {
+	var campaigns []*models.AccessReviewCampaign
+	query := r.db.Model(&models.AccessReviewCampaign{})
+	if tenantID != nil {
+		query = query.Where("tenant_id = ?", *tenantID)
+	}
+	err := query.Order("created_at DESC").Find(&campaigns).Error
+	return campaigns, err
+}
+
+// CloseCampaign marks an open campaign closed, so no more decisions are
+// accepted while its revocations are applied
+func (r *accessReviewRepository) CloseCampaign(campaign *models.AccessReviewCampaign) error {
+	now := time.Now()
+	result := r.db.Model(&models.AccessReviewCampaign{}).
+		Where("id = ? AND status = ?", campaign.ID, models.AccessReviewOpen).
+		Updates(map[string]interface{}{
+			"status":    models.AccessReviewClosed,
+			"closed_by": campaign.ClosedBy,
+			"closed_at": now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAccessReviewClosed
+	}
+	campaign.Status = models.AccessReviewClosed
+	campaign.ClosedAt = &now
+	return nil
+}
+
+func (r *accessReviewRepository) ListReviewableMembers(tenantIDs []uuid.UUID, roleID *uuid.UUID) ([]*models.AccessReviewItem, error) {
+	reviewable := func(query *gorm.DB, roleColumn string) *gorm.DB {
+		query = query.
+			Joins("INNER JOIN tenants t ON t.id = tm.tenant_id").
+			Where("t.status = ? AND tm.status IN ?", models.TenantStatusActive,
+				[]models.MemberStatus{models.MemberStatusActive, models.MemberStatusSuspended})
+		if tenantIDs != nil {
+			query = query.Where("tm.tenant_id IN ?", tenantIDs)
+		}
+		if roleID != nil {
+			query = query.Where(roleColumn+" = ?", *roleID)
+		}
+		return query
+	}
+
+	direct := reviewable(r.db.Table("tenant_members tm").
+		Select("tm.tenant_id, tm.id AS member_id, tm.user_id, tm.role_id, r.name AS role_name, "+
+			"CAST(NULL AS uuid) AS group_id, CAST(NULL AS varchar) AS group_name").
+		Joins("INNER JOIN roles r ON r.id = tm.role_id"), "tm.role_id")
+
+	inherited := reviewable(r.db.Table("tenant_group_members tgm").
+		Select("tm.tenant_id, tm.id AS member_id, tm.user_id, tgr.role_id, r.name AS role_name, "+
+			"g.id AS group_id, g.name AS group_name").
+		Joins("INNER JOIN tenant_members tm ON tm.id = tgm.member_id").
+		Joins("INNER JOIN tenant_groups g ON g.id = tgm.group_id").
+		Joins("INNER JOIN tenant_group_roles tgr ON tgr.group_id = g.id").
+		Joins("INNER JOIN roles r ON r.id = tgr.role_id"), "tgr.role_id")
+
+	var items []*models.AccessReviewItem
+	err := r.db.Raw("? UNION ALL ? ORDER BY tenant_id, user_id, group_name NULLS FIRST, role_name", direct, inherited).
+		Scan(&items).Error
+	return items, err
+}
+
+func (r *accessReviewRepository) GetItem(id uuid.UUID) (*models.AccessReviewItem, error) {
+	var item models.AccessReviewItem
+	err := r.db.Where("id = ?", id).First(&item).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// ListItems returns the campaign's items, optionally with one decision
+func (r *accessReviewRepository) ListItems(campaignID uuid.UUID, decision models.AccessReviewDecision) ([]*models.AccessReviewItem, error) {
+	var items []*models.AccessReviewItem
+	query := r.db.Where("campaign_id = ?", campaignID)
+	if decision != "" {
+		query = query.Where("decision = ?", decision)
+	}
+	err := query.Order("tenant_id, user_id").Find(&items).Error
+	return items, err
+}
+
+// ListPendingForReviewer returns the undecided items of open campaigns the
+// user reviews: campaigns that name them as a reviewer, and campaigns without
+// named reviewers whose items belong to tenants in managedTenantIDs. Items
+// about the user's own access are left out.
+func (r *accessReviewRepository) ListPendingForReviewer(userID string, managedTenantIDs []uuid.UUID) ([]*models.AccessReviewItem, error) {
+	reviewer, err := json.Marshal([]string{userID})
+	if err != nil {
+		return nil, err
+	}
+
+	assigned := r.db.Where("c.reviewer_ids @> CAST(? AS jsonb)", string(reviewer))
+	if len(managedTenantIDs) > 0 {
+		assigned = assigned.Or("COALESCE(jsonb_array_length(c.reviewer_ids), 0) = 0 AND access_review_items.tenant_id IN ?", managedTenantIDs)
+	}
+
+	var items []*models.AccessReviewItem
+	err = r.db.
+		Joins("INNER JOIN access_review_campaigns c ON c.id = access_review_items.campaign_id").
+		Where("c.status = ? AND access_review_items.decision = ? AND access_review_items.user_id <> ?",
+			models.AccessReviewOpen, models.AccessReviewPending, userID).
+		Where(assigned).
+		Order("c.due_at ASC NULLS LAST, access_review_items.created_at").
+		Find(&items).Error
+	return items, err
+}
+
+func (r *accessReviewRepository) Summarize(campaignID uuid.UUID) (*models.AccessReviewSummary, error) {
+	var summary models.AccessReviewSummary
+	err := r.db.Model(&models.AccessReviewItem{}).
+		Select(`COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE decision = ?) AS pending,
+			COUNT(*) FILTER (WHERE decision = ?) AS certified,
+			COUNT(*) FILTER (WHERE decision = ?) AS revoked,
+			COUNT(*) FILTER (WHERE outcome = ?) AS applied,
+			COUNT(*) FILTER (WHERE outcome = ?) AS skipped,
+			COUNT(*) FILTER (WHERE outcome = ?) AS failed`,
+			models.AccessReviewPending, models.AccessReviewCertified, models.AccessReviewRevoked,
+			models.AccessReviewApplied, models.AccessReviewSkipped, models.AccessReviewFailed).
+		Where("campaign_id = ?", campaignID).
+		Scan(&summary).Error
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// Decide saves the item's decision, comment and reviewer, provided its
+// campaign is still open
+func (r *accessReviewRepository) Decide(item *models.AccessReviewItem) error {
+	now := time.Now()
+	result := r.db.Model(&models.AccessReviewItem{}).
+		Where("id = ? AND campaign_id IN (?)", item.ID,
+			r.db.Model(&models.AccessReviewCampaign{}).Select("id").Where("status = ?", models.AccessReviewOpen)).
+		Updates(map[string]interface{}{
+			"decision":   item.Decision,
+			"comment":    item.Comment,
+			"decided_by": item.DecidedBy,
+			"decided_at": now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAccessReviewClosed
+	}
+	item.DecidedAt = &now
+	return nil
+}
+
+func (r *accessReviewRepository) SetOutcome(itemID uuid.UUID, outcome models.AccessReviewOutcome, detail string) error {
+	return r.db.Model(&models.AccessReviewItem{}).
+		Where("id = ?", itemID).
+		Updates(map[string]interface{}{
+			"outcome":        outcome,
+			"outcome_detail": detail,
+		}).Error
+}
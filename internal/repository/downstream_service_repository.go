@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/models"
+	"gorm.io/gorm"
+)
+
+type DownstreamServiceRepository interface {
+	Create(service *models.DownstreamService) error
+	GetByID(id uuid.UUID) (*models.DownstreamService, error)
+	GetByName(name string) (*models.DownstreamService, error)
+	GetByNames(names []string) ([]*models.DownstreamService, error)
+	List() ([]*models.DownstreamService, error)
+	ListEnabled() ([]*models.DownstreamService, error)
+	Update(service *models.DownstreamService) error
+	Delete(id uuid.UUID) error
+	UpdateHealth(id uuid.UUID, status models.ServiceHealthStatus, healthErr string) error
+}
+
+type downstreamServiceRepository struct {
+	db *gorm.DB
+}
+
+func NewDownstreamServiceRepository(db *gorm.DB) DownstreamServiceRepository {
+	return &downstreamServiceRepository{db: db}
+}
+
+// Create stores the service together with its dependencies
+func (r *downstreamServiceRepository) Create(service *models.DownstreamService) error {
+	return r.db.Create(service).Error
+}
+
+func (r *downstreamServiceRepository) GetByID(id uuid.UUID) (*models.DownstreamService, error) {
+	var service models.DownstreamService
+	err := r.db.Preload("DependsOn").Where("id = ?", id).First(&service).Error
+	if err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
+
+func (r *downstreamServiceRepository) GetByName(name string) (*models.DownstreamService, error) {
+	var service models.DownstreamService
+	err := r.db.Preload("DependsOn").Where("name = ?", name).First(&service).Error
+	if err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
+
+func (r *downstreamServiceRepository) GetByNames(names []string) ([]*models.DownstreamService, error) {
+	var services []*models.DownstreamService
+	if len(names) == 0 {
+		return services, nil
+	}
+	err := r.db.Where("name IN ?", names).Find(&services).Error
+	return services, err
+}
+
+func (r *downstreamServiceRepository) List() ([]*models.DownstreamService, error) {
+	var services []*models.DownstreamService
+	err := r.db.Preload("DependsOn").Order("position ASC, name ASC").Find(&services).Error
+	return services, err
+}
+
+// ListEnabled returns the services tenants are provisioned in, in provisioning order
+func (r *downstreamServiceRepository) ListEnabled() ([]*models.DownstreamService, error) {
+	var services []*models.DownstreamService
+	err := r.db.Preload("DependsOn").
+		Where("enabled = ?", true).
+		Order("position ASC, name ASC").
+		Find(&services).Error
+	return services, err
+}
+
+// Update saves the service and replaces its dependencies with service.DependsOn
+func (r *downstreamServiceRepository) Update(service *models.DownstreamService) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("DependsOn").Save(service).Error; err != nil {
+			return err
+		}
+		return tx.Model(service).Association("DependsOn").Replace(service.DependsOn)
+	})
+}
+
+// Delete removes the service; dependency rows cascade
+func (r *downstreamServiceRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.DownstreamService{}, "id = ?", id).Error
+}
+
+func (r *downstreamServiceRepository) UpdateHealth(id uuid.UUID, status models.ServiceHealthStatus, healthErr string) error {
+	return r.db.Model(&models.DownstreamService{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"health_status":        status,
+			"health_error":         healthErr,
+			"last_health_check_at": time.Now(),
+		}).Error
+}
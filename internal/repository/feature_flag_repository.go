@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/models"
+	"gorm.io/gorm"
+)
+
+// FeatureFlagRepository writes every flag change together with its audit log entry
+type FeatureFlagRepository interface {
+	Create(flag *models.FeatureFlag, entry *models.FeatureFlagAuditLog) error
+	GetByID(id uuid.UUID) (*models.FeatureFlag, error)
+	GetByKey(key string) (*models.FeatureFlag, error)
+	List() ([]*models.FeatureFlag, error)
+	Update(flag *models.FeatureFlag, entry *models.FeatureFlagAuditLog) error
+	Delete(flag *models.FeatureFlag, entry *models.FeatureFlagAuditLog) error
+	ListAuditLogs(flagID uuid.UUID, pagination *models.PaginationParams) ([]*models.FeatureFlagAuditLog, int64, error)
+}
+
+type featureFlagRepository struct {
+	db *gorm.DB
+}
+
+func NewFeatureFlagRepository(db *gorm.DB) FeatureFlagRepository {
+	return &featureFlagRepository{db: db}
+}
+
+func (r *featureFlagRepository) Create(flag *models.FeatureFlag, entry *models.FeatureFlagAuditLog) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(flag).Error; err != nil {
+			return err
+		}
+		return tx.Create(entry).Error
+	})
+}
+
+func (r *featureFlagRepository) GetByID(id uuid.UUID) (*models.FeatureFlag, error) {
+	var flag models.FeatureFlag
+	err := r.db.Where("id = ?", id).First(&flag).Error
+	if err != nil {
+		return nil, err
+	}
+	return &flag, nil
+}
+
+func (r *featureFlagRepository) GetByKey(key string) (*models.FeatureFlag, error) {
+	var flag models.FeatureFlag
+	err := r.db.Where("key = ?", key).First(&flag).Error
+	if err != nil {
+		return nil, err
+	}
+	return &flag, nil
+}
+
+func (r *featureFlagRepository) List() ([]*models.FeatureFlag, error) {
+	var flags []*models.FeatureFlag
+	err := r.db.Order("key ASC").Find(&flags).Error
+	return flags, err
+}
+
+func (r *featureFlagRepository) Update(flag *models.FeatureFlag, entry *models.FeatureFlagAuditLog) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(flag).Error; err != nil {
+			return err
+		}
+		return tx.Create(entry).Error
+	})
+}
+
+// Delete removes the flag; its audit logs are kept
+func (r *featureFlagRepository) Delete(flag *models.FeatureFlag, entry *models.FeatureFlagAuditLog) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.FeatureFlag{}, "id = ?", flag.ID).Error; err != nil {
+			return err
+		}
+		return tx.Create(entry).Error
+	})
+}
+
+func (r *featureFlagRepository) ListAuditLogs(flagID uuid.UUID, pagination *models.PaginationParams) ([]*models.FeatureFlagAuditLog, int64, error) {
+	var entries []*models.FeatureFlagAuditLog
+	var total int64
+
+	query := r.db.Model(&models.FeatureFlagAuditLog{}).Where("flag_id = ?", flagID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	pagination.Normalize()
+	err := query.Order("created_at DESC").
+		Offset(pagination.GetOffset()).
+		Limit(pagination.PageSize).
+		Find(&entries).Error
+
+	return entries, total, err
+}
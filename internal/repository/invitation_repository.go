@@ -10,6 +10,9 @@ import (
 
 type InvitationRepository interface {
 	Create(invitation *models.UserInvitation) error
+	// CreateWithinQuota creates the invitation once check accepts the
+	// tenant's usage, counted while the tenant is locked
+	CreateWithinQuota(invitation *models.UserInvitation, check models.QuotaCheck) error
 	GetByID(id uuid.UUID) (*models.UserInvitation, error)
 	GetByToken(token string) (*models.UserInvitation, error)
 	GetByTenantID(tenantID uuid.UUID, pagination *models.PaginationParams) ([]*models.UserInvitation, int64, error)
@@ -33,6 +36,12 @@ func (r *invitationRepository) Create(invitation *models.UserInvitation) error {
 	return r.db.Create(invitation).Error
 }
 
+func (r *invitationRepository) CreateWithinQuota(invitation *models.UserInvitation, check models.QuotaCheck) error {
+	return guardTenantQuota(r.db, invitation.TenantID, check, func(tx *gorm.DB) error {
+		return tx.Create(invitation).Error
+	})
+}
+
 func (r *invitationRepository) GetByID(id uuid.UUID) (*models.UserInvitation, error) {
 	var invitation models.UserInvitation
 	err := r.db.
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/models"
+	"gorm.io/gorm"
+)
+
+// ErrBulkOperationActive is returned when creating a bulk operation on a
+// tenant that already has one queued or running
+var ErrBulkOperationActive = errors.New("a bulk operation on this tenant is already in progress")
+
+type MemberBulkRepository interface {
+	// Create returns ErrBulkOperationActive when the tenant already has an
+	// operation queued or running
+	Create(operation *models.MemberBulkOperation) error
+	GetByID(id uuid.UUID) (*models.MemberBulkOperation, error)
+	ListByTenant(tenantID uuid.UUID, pagination *models.PaginationParams) ([]*models.MemberBulkOperation, int64, error)
+	Start(id uuid.UUID) error
+	UpdateProgress(id uuid.UUID, results models.MemberBulkRowResults, succeeded, failed int) error
+	Complete(id uuid.UUID, results models.MemberBulkRowResults, succeeded, failed int) error
+	Fail(id uuid.UUID, operationErr error) error
+}
+
+type memberBulkRepository struct {
+	db *gorm.DB
+}
+
+func NewMemberBulkRepository(db *gorm.DB) MemberBulkRepository {
+	return &memberBulkRepository{db: db}
+}
+
+// Create locks the tenant so two requests cannot both find it idle
+func (r *memberBulkRepository) Create(operation *models.MemberBulkOperation) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockTenant(tx, operation.TenantID); err != nil {
+			return err
+		}
+
+		var active int64
+		err := tx.Model(&models.MemberBulkOperation{}).
+			Where("tenant_id = ? AND status IN ?", operation.TenantID, []models.MemberBulkOperationStatus{models.MemberBulkPending, models.MemberBulkRunning}).
+			Count(&active).Error
+		if err != nil {
+			return err
+		}
+		if active > 0 {
+			return ErrBulkOperationActive
+		}
+
+		return tx.Create(operation).Error
+	})
+}
+
+func (r *memberBulkRepository) GetByID(id uuid.UUID) (*models.MemberBulkOperation, error) {
+	var operation models.MemberBulkOperation
+	err := r.db.Where("id = ?", id).First(&operation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &operation, nil
+}
+
+// ListByTenant returns the tenant's operations without their row results,
+// which can be large; fetch a single operation for those
+func (r *memberBulkRepository) ListByTenant(tenantID uuid.UUID, pagination *models.PaginationParams) ([]*models.MemberBulkOperation, int64, error) {
+	var operations []*models.MemberBulkOperation
+	var total int64
+
+	query := r.db.Model(&models.MemberBulkOperation{}).Where("tenant_id = ?", tenantID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	pagination.Normalize()
+	err := query.Omit("rows", "results").
+		Order("created_at DESC").
+		Offset(pagination.GetOffset()).
+		Limit(pagination.PageSize).
+		Find(&operations).Error
+
+	return operations, total, err
+}
+
+func (r *memberBulkRepository) Start(id uuid.UUID) error {
+	return r.db.Model(&models.MemberBulkOperation{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     models.MemberBulkRunning,
+			"started_at": time.Now(),
+		}).Error
+}
+
+// UpdateProgress saves the results so far, so a retried job resumes after the
+// last saved row
+func (r *memberBulkRepository) UpdateProgress(id uuid.UUID, results models.MemberBulkRowResults, succeeded, failed int) error {
+	return r.db.Model(&models.MemberBulkOperation{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"results":   results,
+			"processed": len(results),
+			"succeeded": succeeded,
+			"failed":    failed,
+		}).Error
+}
+
+func (r *memberBulkRepository) Complete(id uuid.UUID, results models.MemberBulkRowResults, succeeded, failed int) error {
+	return r.db.Model(&models.MemberBulkOperation{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":       models.MemberBulkCompleted,
+			"results":      results,
+			"processed":    len(results),
+			"succeeded":    succeeded,
+			"failed":       failed,
+			"completed_at": time.Now(),
+		}).Error
+}
+
+func (r *memberBulkRepository) Fail(id uuid.UUID, operationErr error) error {
+	return r.db.Model(&models.MemberBulkOperation{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":       models.MemberBulkFailed,
+			"error":        operationErr.Error(),
+			"completed_at": time.Now(),
+		}).Error
+}
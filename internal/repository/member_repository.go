@@ -1,24 +1,57 @@
 package repository
 
 import (
+	"errors"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/ysaakpr/rex/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type MemberRepository interface {
 	Create(member *models.TenantMember) error
+	// CreateWithinQuota creates the member once check accepts the tenant's
+	// usage, counted while the tenant is locked
+	CreateWithinQuota(member *models.TenantMember, check models.QuotaCheck) error
 	GetByID(id uuid.UUID) (*models.TenantMember, error)
 	GetByTenantAndUser(tenantID uuid.UUID, userID string) (*models.TenantMember, error)
 	GetByTenantID(tenantID uuid.UUID, pagination *models.PaginationParams) ([]*models.TenantMember, int64, error)
 	GetByUserID(userID string) ([]*models.TenantMember, error)
+	ListByTenantID(tenantID uuid.UUID) ([]*models.TenantMember, error)
 	Update(member *models.TenantMember) error
+	// Delete removes the member and records the removal
 	Delete(id uuid.UUID) error
-	AssignRoles(memberID uuid.UUID, roleIDs []uuid.UUID) error
-	RemoveRole(memberID uuid.UUID, roleID uuid.UUID) error
+	WasRemoved(tenantID uuid.UUID, userID string) (bool, error)
 	GetMemberWithRoles(memberID uuid.UUID) (*models.TenantMember, error)
+
+	// Suspension
+	Suspend(suspension *models.MemberSuspension) error
+	Reactivate(memberID uuid.UUID, reactivatedBy string, reason string) error
+	// CloseSuspension ends a suspension record without changing the member
+	CloseSuspension(suspensionID uuid.UUID, closedBy string, reason string) error
+	SetSessionsRevoked(suspensionID uuid.UUID) error
+	ListSuspensions(memberID uuid.UUID) ([]models.MemberSuspension, error)
+	ListDueReactivations(now time.Time, limit int) ([]*models.MemberSuspension, error)
+
+	// Activity
+	TouchLastActive(memberID uuid.UUID, at time.Time, interval time.Duration) error
+	CountActive(tenantID uuid.UUID) (int64, error)
+	CountActiveSince(tenantID uuid.UUID, since time.Time) (int64, error)
+	ListDormant(tenantID uuid.UUID, before time.Time, limit int) ([]*models.TenantMember, int64, error)
+	ListDormantAfter(tenantID uuid.UUID, before time.Time, after *models.TenantMember, limit int) ([]*models.TenantMember, error)
+
+	// ListManagerUserIDs returns the users who can manage the tenant
+	ListManagerUserIDs(tenantID uuid.UUID) ([]string, error)
 }
 
+// ErrMemberNotActive is returned when suspending a member who is not active
+var ErrMemberNotActive = errors.New("member is not active")
+
+// ErrMemberNotSuspended is returned when reactivating a member who is not suspended
+var ErrMemberNotSuspended = errors.New("member is not suspended")
+
 type memberRepository struct {
 	db *gorm.DB
 }
@@ -31,6 +64,12 @@ func (r *memberRepository) Create(member *models.TenantMember) error {
 	return r.db.Create(member).Error
 }
 
+func (r *memberRepository) CreateWithinQuota(member *models.TenantMember, check models.QuotaCheck) error {
+	return guardTenantQuota(r.db, member.TenantID, check, func(tx *gorm.DB) error {
+		return tx.Create(member).Error
+	})
+}
+
 func (r *memberRepository) GetByID(id uuid.UUID) (*models.TenantMember, error) {
 	var member models.TenantMember
 	err := r.db.
@@ -86,23 +125,53 @@ func (r *memberRepository) GetByUserID(userID string) ([]*models.TenantMember, e
 	return members, err
 }
 
-func (r *memberRepository) Update(member *models.TenantMember) error {
-	return r.db.Save(member).Error
+func (r *memberRepository) ListByTenantID(tenantID uuid.UUID) ([]*models.TenantMember, error) {
+	var members []*models.TenantMember
+	err := r.db.
+		Preload("Role").
+		Where("tenant_id = ?", tenantID).
+		Find(&members).Error
+	return members, err
 }
 
-func (r *memberRepository) Delete(id uuid.UUID) error {
-	return r.db.Delete(&models.TenantMember{}, id).Error
+// Update saves the member. It returns a LastManagerError when the change
+// would leave the tenant without a manager.
+func (r *memberRepository) Update(member *models.TenantMember) error {
+	return guardTenantManagement(r.db, &member.TenantID, func(tx *gorm.DB) error {
+		return tx.Save(member).Error
+	})
 }
 
-func (r *memberRepository) AssignRoles(memberID uuid.UUID, roleIDs []uuid.UUID) error {
-	member := &models.TenantMember{ID: memberID}
-	return r.db.Model(member).Association("Roles").Append(convertToRoles(roleIDs))
+// Delete removes the member. It returns a LastManagerError when the member is
+// the tenant's last manager.
+func (r *memberRepository) Delete(id uuid.UUID) error {
+	var member models.TenantMember
+	if err := r.db.Select("id", "tenant_id", "user_id").Where("id = ?", id).First(&member).Error; err != nil {
+		return err
+	}
+	return guardTenantManagement(r.db, &member.TenantID, func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.TenantMember{}, id).Error; err != nil {
+			return err
+		}
+		removal := &models.TenantMemberRemoval{
+			TenantID:  member.TenantID,
+			UserID:    member.UserID,
+			RemovedAt: time.Now(),
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"removed_at"}),
+		}).Create(removal).Error
+	})
 }
 
-func (r *memberRepository) RemoveRole(memberID uuid.UUID, roleID uuid.UUID) error {
-	member := &models.TenantMember{ID: memberID}
-	role := &models.Role{ID: roleID}
-	return r.db.Model(member).Association("Roles").Delete(role)
+// WasRemoved reports whether the user's membership of the tenant was ever removed
+func (r *memberRepository) WasRemoved(tenantID uuid.UUID, userID string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.TenantMemberRemoval{}).
+		Where("tenant_id = ? AND user_id = ?", tenantID, userID).
+		Count(&count).Error
+	return count > 0, err
 }
 
 func (r *memberRepository) GetMemberWithRoles(memberID uuid.UUID) (*models.TenantMember, error) {
@@ -116,10 +185,170 @@ func (r *memberRepository) GetMemberWithRoles(memberID uuid.UUID) (*models.Tenan
 	return &member, err
 }
 
-func convertToRoles(roleIDs []uuid.UUID) []*models.Role {
-	roles := make([]*models.Role, len(roleIDs))
-	for i, id := range roleIDs {
-		roles[i] = &models.Role{ID: id}
+// Suspend marks an active member suspended and records the suspension in one
+// transaction. It returns a LastManagerError when the member is the tenant's
+// last manager.
+func (r *memberRepository) Suspend(suspension *models.MemberSuspension) error {
+	return guardTenantManagement(r.db, &suspension.TenantID, func(tx *gorm.DB) error {
+		result := tx.Model(&models.TenantMember{}).
+			Where("id = ? AND status = ?", suspension.MemberID, models.MemberStatusActive).
+			Update("status", models.MemberStatusSuspended)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrMemberNotActive
+		}
+		return tx.Create(suspension).Error
+	})
+}
+
+// Reactivate marks a suspended member active and closes the open suspension in
+// one transaction
+func (r *memberRepository) Reactivate(memberID uuid.UUID, reactivatedBy string, reason string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.TenantMember{}).
+			Where("id = ? AND status = ?", memberID, models.MemberStatusSuspended).
+			Update("status", models.MemberStatusActive)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrMemberNotSuspended
+		}
+		return tx.Model(&models.MemberSuspension{}).
+			Where("member_id = ? AND reactivated_at IS NULL", memberID).
+			Updates(map[string]interface{}{
+				"reactivated_at":      time.Now(),
+				"reactivated_by":      reactivatedBy,
+				"reactivation_reason": reason,
+			}).Error
+	})
+}
+
+func (r *memberRepository) CloseSuspension(suspensionID uuid.UUID, closedBy string, reason string) error {
+	return r.db.Model(&models.MemberSuspension{}).
+		Where("id = ? AND reactivated_at IS NULL", suspensionID).
+		Updates(map[string]interface{}{
+			"reactivated_at":      time.Now(),
+			"reactivated_by":      closedBy,
+			"reactivation_reason": reason,
+		}).Error
+}
+
+func (r *memberRepository) SetSessionsRevoked(suspensionID uuid.UUID) error {
+	return r.db.Model(&models.MemberSuspension{}).
+		Where("id = ?", suspensionID).
+		Update("sessions_revoked", true).Error
+}
+
+// ListSuspensions returns the member's suspension history, newest first
+func (r *memberRepository) ListSuspensions(memberID uuid.UUID) ([]models.MemberSuspension, error) {
+	var suspensions []models.MemberSuspension
+	err := r.db.
+		Where("member_id = ?", memberID).
+		Order("suspended_at DESC").
+		Find(&suspensions).Error
+	return suspensions, err
+}
+
+// ListDueReactivations returns open suspensions whose scheduled reactivation
+// time has passed, oldest first
+func (r *memberRepository) ListDueReactivations(now time.Time, limit int) ([]*models.MemberSuspension, error) {
+	var suspensions []*models.MemberSuspension
+	err := r.db.
+		Where("reactivated_at IS NULL AND reactivate_at IS NOT NULL AND reactivate_at <= ?", now).
+		Order("reactivate_at ASC").
+		Limit(limit).
+		Find(&suspensions).Error
+	return suspensions, err
+}
+
+// TouchLastActive records activity at at, unless the member's activity was
+// already recorded within interval
+func (r *memberRepository) TouchLastActive(memberID uuid.UUID, at time.Time, interval time.Duration) error {
+	return r.db.Exec(
+		"UPDATE tenant_members SET last_active_at = ? WHERE id = ? AND (last_active_at IS NULL OR last_active_at < ?)",
+		at, memberID, at.Add(-interval),
+	).Error
+}
+
+// CountActive counts the tenant's members with active status
+func (r *memberRepository) CountActive(tenantID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.TenantMember{}).
+		Where("tenant_id = ? AND status = ?", tenantID, models.MemberStatusActive).
+		Count(&count).Error
+	return count, err
+}
+
+// CountActiveSince counts the tenant's active members seen since since
+func (r *memberRepository) CountActiveSince(tenantID uuid.UUID, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.TenantMember{}).
+		Where("tenant_id = ? AND status = ? AND last_active_at >= ?", tenantID, models.MemberStatusActive, since).
+		Count(&count).Error
+	return count, err
+}
+
+// ListDormant returns the tenant's active members not seen since before,
+// least recently active first, with their total. Members never seen count
+// from when they joined; members who joined before activity was tracked were
+// backfilled as seen when tracking started.
+func (r *memberRepository) ListDormant(tenantID uuid.UUID, before time.Time, limit int) ([]*models.TenantMember, int64, error) {
+	var members []*models.TenantMember
+	var total int64
+
+	query := r.db.Model(&models.TenantMember{}).
+		Where("tenant_id = ? AND status = ?", tenantID, models.MemberStatusActive).
+		Where("COALESCE(last_active_at, joined_at) < ?", before)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
 	}
-	return roles
+
+	err := query.
+		Preload("Role").
+		Order("COALESCE(last_active_at, joined_at) ASC").
+		Limit(limit).
+		Find(&members).Error
+
+	return members, total, err
+}
+
+// ListDormantAfter pages through the members ListDormant returns, ordered by
+// when they were last seen and then by ID. after is the last member of the
+// previous page, or nil for the first page.
+func (r *memberRepository) ListDormantAfter(tenantID uuid.UUID, before time.Time, after *models.TenantMember, limit int) ([]*models.TenantMember, error) {
+	var members []*models.TenantMember
+
+	query := r.db.
+		Where("tenant_id = ? AND status = ?", tenantID, models.MemberStatusActive).
+		Where("COALESCE(last_active_at, joined_at) < ?", before)
+	if after != nil {
+		seen := after.JoinedAt
+		if after.LastActiveAt != nil {
+			seen = *after.LastActiveAt
+		}
+		query = query.Where("(COALESCE(last_active_at, joined_at), id) > (?, ?)", seen, after.ID)
+	}
+
+	err := query.
+		Order("COALESCE(last_active_at, joined_at) ASC, id ASC").
+		Limit(limit).
+		Find(&members).Error
+	return members, err
+}
+
+// ListManagerUserIDs returns the tenant's active members holding the tenant
+// management permission, directly or through a group
+func (r *memberRepository) ListManagerUserIDs(tenantID uuid.UUID) ([]string, error) {
+	var userIDs []string
+	err := r.db.Raw(tenantManagersQuery, map[string]interface{}{
+		"tenant_id": tenantID,
+		"service":   models.TenantManagementService,
+		"entity":    models.TenantManagementEntity,
+		"action":    models.TenantManagementAction,
+	}).Scan(&userIDs).Error
+	return userIDs, err
 }
@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/models"
+	"gorm.io/gorm"
+)
+
+type MetadataSchemaRepository interface {
+	// Schemas
+	CreateVersion(schema *models.TenantMetadataSchema) error
+	GetByID(id uuid.UUID) (*models.TenantMetadataSchema, error)
+	List(tenantType *string) ([]*models.TenantMetadataSchema, error)
+	GetActive(tenantType *string) (*models.TenantMetadataSchema, error)
+	ListActive() ([]*models.TenantMetadataSchema, error)
+	Activate(id uuid.UUID) error
+
+	// Validation runs
+	CreateRun(run *models.MetadataValidationRun) error
+	GetRun(id uuid.UUID) (*models.MetadataValidationRun, error)
+	ListRuns(pagination *models.PaginationParams) ([]*models.MetadataValidationRun, int64, error)
+	StartRun(id uuid.UUID) error
+	CompleteRun(id uuid.UUID, checked, nonConforming int, runErr error) error
+	AddFailures(failures []*models.MetadataValidationFailure) error
+}
+
+type metadataSchemaRepository struct {
+	db *gorm.DB
+}
+
+func NewMetadataSchemaRepository(db *gorm.DB) MetadataSchemaRepository {
+	return &metadataSchemaRepository{db: db}
+}
+
+// CreateVersion stores schema as the next version for its tenant type and makes
+// it the active version
+func (r *metadataSchemaRepository) CreateVersion(schema *models.TenantMetadataSchema) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var latest int
+		err := scopeTenantType(tx.Model(&models.TenantMetadataSchema{}), schema.TenantType).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&latest).Error
+		if err != nil {
+			return err
+		}
+
+		err = scopeTenantType(tx.Model(&models.TenantMetadataSchema{}), schema.TenantType).
+			Where("is_active = ?", true).
+			Update("is_active", false).Error
+		if err != nil {
+			return err
+		}
+
+		schema.Version = latest + 1
+		schema.IsActive = true
+		return tx.Create(schema).Error
+	})
+}
+
+func (r *metadataSchemaRepository) GetByID(id uuid.UUID) (*models.TenantMetadataSchema, error) {
+	var schema models.TenantMetadataSchema
+	err := r.db.Where("id = ?", id).First(&schema).Error
+	if err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
+
+// List returns every version, or only those for tenantType when it is set
+func (r *metadataSchemaRepository) List(tenantType *string) ([]*models.TenantMetadataSchema, error) {
+	var schemas []*models.TenantMetadataSchema
+	query := r.db.Model(&models.TenantMetadataSchema{})
+	if tenantType != nil {
+		query = scopeTenantType(query, tenantType)
+	}
+	err := query.Order("tenant_type ASC NULLS FIRST, version DESC").Find(&schemas).Error
+	return schemas, err
+}
+
+// GetActive returns the active schema for tenantType, falling back to the
+// default schema when the type has none
+func (r *metadataSchemaRepository) GetActive(tenantType *string) (*models.TenantMetadataSchema, error) {
+	var schema models.TenantMetadataSchema
+
+	if tenantType != nil {
+		err := scopeTenantType(r.db, tenantType).
+			Where("is_active = ?", true).
+			First(&schema).Error
+		if err == nil {
+			return &schema, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+
+	err := r.db.Where("tenant_type IS NULL AND is_active = ?", true).First(&schema).Error
+	if err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
+
+func (r *metadataSchemaRepository) ListActive() ([]*models.TenantMetadataSchema, error) {
+	var schemas []*models.TenantMetadataSchema
+	err := r.db.Where("is_active = ?", true).Find(&schemas).Error
+	return schemas, err
+}
+
+// Activate makes an existing version the active one for its tenant type
+func (r *metadataSchemaRepository) Activate(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var schema models.TenantMetadataSchema
+		if err := tx.Where("id = ?", id).First(&schema).Error; err != nil {
+			return err
+		}
+
+		err := scopeTenantType(tx.Model(&models.TenantMetadataSchema{}), schema.TenantType).
+			Where("is_active = ?", true).
+			Update("is_active", false).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&models.TenantMetadataSchema{}).
+			Where("id = ?", id).
+			Update("is_active", true).Error
+	})
+}
+
+func (r *metadataSchemaRepository) CreateRun(run *models.MetadataValidationRun) error {
+	return r.db.Create(run).Error
+}
+
+func (r *metadataSchemaRepository) GetRun(id uuid.UUID) (*models.MetadataValidationRun, error) {
+	var run models.MetadataValidationRun
+	err := r.db.Preload("Failures", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at ASC")
+	}).Where("id = ?", id).First(&run).Error
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+func (r *metadataSchemaRepository) ListRuns(pagination *models.PaginationParams) ([]*models.MetadataValidationRun, int64, error) {
+	var runs []*models.MetadataValidationRun
+	var total int64
+
+	query := r.db.Model(&models.MetadataValidationRun{})
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	pagination.Normalize()
+	err := query.
+		Offset(pagination.GetOffset()).
+		Limit(pagination.PageSize).
+		Order("created_at DESC").
+		Find(&runs).Error
+
+	return runs, total, err
+}
+
+// StartRun marks a run as running and clears failures recorded by an earlier
+// attempt, so a retried run reports each tenant once
+func (r *metadataSchemaRepository) StartRun(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("run_id = ?", id).Delete(&models.MetadataValidationFailure{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.MetadataValidationRun{}).
+			Where("id = ?", id).
+			Updates(map[string]interface{}{
+				"status":     models.MetadataValidationRunRunning,
+				"started_at": time.Now(),
+			}).Error
+	})
+}
+
+func (r *metadataSchemaRepository) CompleteRun(id uuid.UUID, checked, nonConforming int, runErr error) error {
+	updates := map[string]interface{}{
+		"status":                 models.MetadataValidationRunCompleted,
+		"tenants_checked":        checked,
+		"tenants_non_conforming": nonConforming,
+		"completed_at":           time.Now(),
+	}
+	if runErr != nil {
+		updates["status"] = models.MetadataValidationRunFailed
+		updates["error"] = runErr.Error()
+	}
+
+	return r.db.Model(&models.MetadataValidationRun{}).
+		Where("id = ?", id).
+		Updates(updates).Error
+}
+
+func (r *metadataSchemaRepository) AddFailures(failures []*models.MetadataValidationFailure) error {
+	if len(failures) == 0 {
+		return nil
+	}
+	return r.db.Create(&failures).Error
+}
+
+// scopeTenantType filters schemas by tenant type, treating nil as the default schema
+func scopeTenantType(query *gorm.DB, tenantType *string) *gorm.DB {
+	if tenantType == nil {
+		return query.Where("tenant_type IS NULL")
+	}
+	return query.Where("tenant_type = ?", *tenantType)
+}
@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PlanRepository interface {
+	Create(plan *models.Plan) error
+	GetByID(id uuid.UUID) (*models.Plan, error)
+	GetByName(name string) (*models.Plan, error)
+	GetDefault() (*models.Plan, error)
+	List() ([]*models.Plan, error)
+	Update(plan *models.Plan) error
+	Delete(id uuid.UUID) error
+	CountTenants(planID uuid.UUID) (int64, error)
+	AssignToTenant(tenantID uuid.UUID, planID uuid.UUID) error
+
+	// Overrides
+	GetOverride(tenantID uuid.UUID) (*models.TenantPlanOverride, error)
+	UpsertOverride(override *models.TenantPlanOverride) error
+	DeleteOverride(tenantID uuid.UUID) error
+
+	// Usage
+	GetUsage(tenantID uuid.UUID) (*models.QuotaUsage, error)
+	IsSystemUser(userID string) (bool, error)
+}
+
+type planRepository struct {
+	db *gorm.DB
+}
+
+func NewPlanRepository(db *gorm.DB) PlanRepository {
+	return &planRepository{db: db}
+}
+
+// Create stores the plan, clearing the previous default when it is the new one
+func (r *planRepository) Create(plan *models.Plan) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if plan.IsDefault {
+			if err := clearDefaultPlan(tx); err != nil {
+				return err
+			}
+		}
+		return tx.Create(plan).Error
+	})
+}
+
+func (r *planRepository) GetByID(id uuid.UUID) (*models.Plan, error) {
+	var plan models.Plan
+	err := r.db.Where("id = ?", id).First(&plan).Error
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+func (r *planRepository) GetByName(name string) (*models.Plan, error) {
+	var plan models.Plan
+	err := r.db.Where("name = ?", name).First(&plan).Error
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+func (r *planRepository) GetDefault() (*models.Plan, error) {
+	var plan models.Plan
+	err := r.db.Where("is_default = ?", true).First(&plan).Error
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+func (r *planRepository) List() ([]*models.Plan, error) {
+	var plans []*models.Plan
+	err := r.db.Order("name ASC").Find(&plans).Error
+	return plans, err
+}
+
+// Update saves the plan, clearing the previous default when it is the new one
+func (r *planRepository) Update(plan *models.Plan) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if plan.IsDefault {
+			if err := clearDefaultPlan(tx.Where("id <> ?", plan.ID)); err != nil {
+				return err
+			}
+		}
+		return tx.Save(plan).Error
+	})
+}
+
+// Delete removes the plan; its tenants fall back to the default plan
+func (r *planRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.Plan{}, "id = ?", id).Error
+}
+
+func (r *planRepository) CountTenants(planID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Tenant{}).Where("plan_id = ?", planID).Count(&count).Error
+	return count, err
+}
+
+// AssignToTenant sets only the tenant's plan so concurrent status changes are kept
+func (r *planRepository) AssignToTenant(tenantID uuid.UUID, planID uuid.UUID) error {
+	return r.db.Model(&models.Tenant{}).
+		Where("id = ?", tenantID).
+		Update("plan_id", planID).Error
+}
+
+func (r *planRepository) GetOverride(tenantID uuid.UUID) (*models.TenantPlanOverride, error) {
+	var override models.TenantPlanOverride
+	err := r.db.Where("tenant_id = ?", tenantID).First(&override).Error
+	if err != nil {
+		return nil, err
+	}
+	return &override, nil
+}
+
+// UpsertOverride replaces the tenant's override with the given one
+func (r *planRepository) UpsertOverride(override *models.TenantPlanOverride) error {
+	override.UpdatedAt = time.Now()
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "tenant_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"max_members", "max_pending_invitations", "max_custom_roles", "max_system_users",
+			"features", "reason", "updated_by", "updated_at",
+		}),
+	}).Create(override).Error
+}
+
+func (r *planRepository) DeleteOverride(tenantID uuid.UUID) error {
+	return r.db.Delete(&models.TenantPlanOverride{}, "tenant_id = ?", tenantID).Error
+}
+
+// GetUsage counts what the tenant uses against each plan limit. Invitations
+// count while they are pending and unexpired; system users count while they
+// are members of the tenant.
+func (r *planRepository) GetUsage(tenantID uuid.UUID) (*models.QuotaUsage, error) {
+	return countUsage(r.db, tenantID)
+}
+
+func countUsage(db *gorm.DB, tenantID uuid.UUID) (*models.QuotaUsage, error) {
+	var usage models.QuotaUsage
+
+	if err := db.Model(&models.TenantMember{}).
+		Where("tenant_id = ?", tenantID).
+		Count(&usage.Members).Error; err != nil {
+		return nil, err
+	}
+
+	if err := db.Model(&models.UserInvitation{}).
+		Where("tenant_id = ? AND status = ? AND expires_at > ?", tenantID, models.InvitationStatusPending, time.Now()).
+		Count(&usage.PendingInvitations).Error; err != nil {
+		return nil, err
+	}
+
+	if err := db.Model(&models.Role{}).
+		Where("tenant_id = ?", tenantID).
+		Count(&usage.CustomRoles).Error; err != nil {
+		return nil, err
+	}
+
+	if err := db.Model(&models.TenantMember{}).
+		Joins("JOIN system_users ON system_users.user_id = tenant_members.user_id").
+		Where("tenant_members.tenant_id = ?", tenantID).
+		Count(&usage.SystemUsers).Error; err != nil {
+		return nil, err
+	}
+
+	return &usage, nil
+}
+
+// guardTenantQuota applies change in a transaction that locks the tenant and
+// first passes check the tenant's usage counted inside it, so concurrent
+// additions to the tenant are counted one after the other
+func guardTenantQuota(db *gorm.DB, tenantID uuid.UUID, check models.QuotaCheck, change func(tx *gorm.DB) error) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := lockTenant(tx, tenantID); err != nil {
+			return err
+		}
+
+		usage, err := countUsage(tx, tenantID)
+		if err != nil {
+			return err
+		}
+		if err := check(*usage); err != nil {
+			return err
+		}
+
+		return change(tx)
+	})
+}
+
+func (r *planRepository) IsSystemUser(userID string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.SystemUser{}).Where("user_id = ?", userID).Count(&count).Error
+	return count > 0, err
+}
+
+func clearDefaultPlan(tx *gorm.DB) error {
+	return tx.Model(&models.Plan{}).Where("is_default = ?", true).Update("is_default", false).Error
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ProvisioningRepository interface {
+	EnsureSteps(tenantID uuid.UUID, operation models.ProvisioningOperation, services []string) error
+	ListByTenant(tenantID uuid.UUID, operation models.ProvisioningOperation) ([]*models.TenantProvisioningStep, error)
+	GetByIdempotencyKey(key string) (*models.TenantProvisioningStep, error)
+	ListExpiredCallbacks(now time.Time, limit int) ([]*models.TenantProvisioningStep, error)
+	RecordAttempt(id uuid.UUID) error
+	MarkSucceeded(id uuid.UUID) error
+	MarkFailed(id uuid.UUID, stepErr error) error
+	MarkAccepted(id uuid.UUID, callbackDeadline time.Time) error
+	ResolveAccepted(id uuid.UUID, status models.ProvisioningStepStatus, stepErr string) (bool, error)
+	ResetFailed(tenantID uuid.UUID, operation models.ProvisioningOperation) error
+}
+
+type provisioningRepository struct {
+	db *gorm.DB
+}
+
+func NewProvisioningRepository(db *gorm.DB) ProvisioningRepository {
+	return &provisioningRepository{db: db}
+}
+
+// EnsureSteps creates a pending step for each service the tenant does not have
+// one for yet. Existing steps, including succeeded ones, are left untouched.
+func (r *provisioningRepository) EnsureSteps(tenantID uuid.UUID, operation models.ProvisioningOperation, services []string) error {
+	if len(services) == 0 {
+		return nil
+	}
+
+	steps := make([]*models.TenantProvisioningStep, 0, len(services))
+	for _, service := range services {
+		steps = append(steps, &models.TenantProvisioningStep{
+			TenantID:       tenantID,
+			Service:        service,
+			Operation:      operation,
+			Status:         models.ProvisioningStepPending,
+			IdempotencyKey: models.ProvisioningIdempotencyKey(tenantID, operation, service),
+		})
+	}
+
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&steps).Error
+}
+
+func (r *provisioningRepository) ListByTenant(tenantID uuid.UUID, operation models.ProvisioningOperation) ([]*models.TenantProvisioningStep, error) {
+	var steps []*models.TenantProvisioningStep
+	err := r.db.Where("tenant_id = ? AND operation = ?", tenantID, operation).Order("created_at ASC, service ASC").Find(&steps).Error
+	return steps, err
+}
+
+func (r *provisioningRepository) GetByIdempotencyKey(key string) (*models.TenantProvisioningStep, error) {
+	var step models.TenantProvisioningStep
+	err := r.db.Where("idempotency_key = ?", key).First(&step).Error
+	if err != nil {
+		return nil, err
+	}
+	return &step, nil
+}
+
+// ListExpiredCallbacks returns accepted steps whose callback deadline has passed
+func (r *provisioningRepository) ListExpiredCallbacks(now time.Time, limit int) ([]*models.TenantProvisioningStep, error) {
+	var steps []*models.TenantProvisioningStep
+	err := r.db.Where("status = ? AND callback_deadline < ?", models.ProvisioningStepAccepted, now).
+		Order("callback_deadline ASC").
+		Limit(limit).
+		Find(&steps).Error
+	return steps, err
+}
+
+// RecordAttempt counts an attempt before the service is called, so attempts are
+// accurate even if the worker dies mid-request
+func (r *provisioningRepository) RecordAttempt(id uuid.UUID) error {
+	return r.db.Model(&models.TenantProvisioningStep{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"last_attempt_at": time.Now(),
+		}).Error
+}
+
+func (r *provisioningRepository) MarkSucceeded(id uuid.UUID) error {
+	return r.db.Model(&models.TenantProvisioningStep{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":            models.ProvisioningStepSucceeded,
+			"last_error":        "",
+			"callback_deadline": nil,
+			"completed_at":      time.Now(),
+		}).Error
+}
+
+func (r *provisioningRepository) MarkFailed(id uuid.UUID, stepErr error) error {
+	return r.db.Model(&models.TenantProvisioningStep{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":            models.ProvisioningStepFailed,
+			"last_error":        stepErr.Error(),
+			"callback_deadline": nil,
+		}).Error
+}
+
+// MarkAccepted records that the service will report the outcome by callback
+func (r *provisioningRepository) MarkAccepted(id uuid.UUID, callbackDeadline time.Time) error {
+	return r.db.Model(&models.TenantProvisioningStep{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":            models.ProvisioningStepAccepted,
+			"last_error":        "",
+			"callback_deadline": callbackDeadline,
+		}).Error
+}
+
+// ResolveAccepted settles an accepted step as succeeded or failed. It only
+// changes steps that are still accepted, so a duplicate callback or a callback
+// racing the timeout is applied at most once; the result reports whether it was.
+func (r *provisioningRepository) ResolveAccepted(id uuid.UUID, status models.ProvisioningStepStatus, stepErr string) (bool, error) {
+	updates := map[string]interface{}{
+		"status":            status,
+		"last_error":        stepErr,
+		"callback_deadline": nil,
+	}
+	if status == models.ProvisioningStepSucceeded {
+		updates["completed_at"] = time.Now()
+	}
+
+	result := r.db.Model(&models.TenantProvisioningStep{}).
+		Where("id = ? AND status = ?", id, models.ProvisioningStepAccepted).
+		Updates(updates)
+	return result.RowsAffected > 0, result.Error
+}
+
+// ResetFailed moves failed steps back to pending ahead of a manual retry,
+// giving them a fresh budget of automatic retries
+func (r *provisioningRepository) ResetFailed(tenantID uuid.UUID, operation models.ProvisioningOperation) error {
+	return r.db.Model(&models.TenantProvisioningStep{}).
+		Where("tenant_id = ? AND operation = ? AND status = ?", tenantID, operation, models.ProvisioningStepFailed).
+		Updates(map[string]interface{}{
+			"status":   models.ProvisioningStepPending,
+			"attempts": 0,
+		}).Error
+}
@@ -9,6 +9,9 @@ import (
 type RBACRepository interface {
 	// Roles (was Relations - user's role in tenant: Admin, Writer, etc.)
 	CreateRole(role *models.Role) error
+	// CreateTenantRole creates a role of role.TenantID once check accepts
+	// the tenant's usage, counted while the tenant is locked
+	CreateTenantRole(role *models.Role, check models.QuotaCheck) error
 	GetRoleByID(id uuid.UUID) (*models.Role, error)
 	GetRoleByName(name string, tenantID *uuid.UUID) (*models.Role, error)
 	GetRoleWithPolicies(id uuid.UUID) (*models.Role, error)
@@ -40,6 +43,9 @@ type RBACRepository interface {
 	// Authorization queries
 	GetUserPermissions(tenantID uuid.UUID, userID string) ([]*models.Permission, error)
 	CheckUserPermission(tenantID uuid.UUID, userID string, service, entity, action string) (bool, error)
+	ExplainUserPermission(tenantID uuid.UUID, userID string, service, entity, action string) ([]*models.PermissionGrant, error)
+	GetUserRoleIDs(tenantID uuid.UUID, userID string) ([]uuid.UUID, error)
+	GetRolePermissions(roleID uuid.UUID) ([]*models.Permission, error)
 
 	// Role-Policy assignments (was Relation-Role)
 	AssignPoliciesToRole(roleID uuid.UUID, policyIDs []uuid.UUID) error
@@ -60,6 +66,12 @@ func (r *rbacRepository) CreateRole(role *models.Role) error {
 	return r.db.Create(role).Error
 }
 
+func (r *rbacRepository) CreateTenantRole(role *models.Role, check models.QuotaCheck) error {
+	return guardTenantQuota(r.db, *role.TenantID, check, func(tx *gorm.DB) error {
+		return tx.Create(role).Error
+	})
+}
+
 func (r *rbacRepository) GetRoleByID(id uuid.UUID) (*models.Role, error) {
 	var role models.Role
 	err := r.db.Where("id = ?", id).First(&role).Error
@@ -101,8 +113,12 @@ func (r *rbacRepository) UpdateRole(role *models.Role) error {
 	return r.db.Save(role).Error
 }
 
+// DeleteRole deletes the role, rejecting with a LastManagerError a deletion
+// that would leave a tenant without a manager
 func (r *rbacRepository) DeleteRole(id uuid.UUID) error {
-	return r.db.Delete(&models.Role{}, id).Error
+	return guardTenantManagement(r.db, nil, func(tx *gorm.DB) error {
+		return tx.Delete(&models.Role{}, id).Error
+	})
 }
 
 // Policies (was Roles)
@@ -138,8 +154,12 @@ func (r *rbacRepository) UpdatePolicy(policy *models.Policy) error {
 	return r.db.Save(policy).Error
 }
 
+// DeletePolicy deletes the policy, rejecting with a LastManagerError a
+// deletion that would leave a tenant without a manager
 func (r *rbacRepository) DeletePolicy(id uuid.UUID) error {
-	return r.db.Delete(&models.Policy{}, id).Error
+	return guardTenantManagement(r.db, nil, func(tx *gorm.DB) error {
+		return tx.Delete(&models.Policy{}, id).Error
+	})
 }
 
 // Permissions
@@ -174,8 +194,12 @@ func (r *rbacRepository) ListPermissionsByService(service string) ([]*models.Per
 	return permissions, err
 }
 
+// DeletePermission deletes the permission, rejecting with a LastManagerError
+// the deletion of the management permission from a tenant that relies on it
 func (r *rbacRepository) DeletePermission(id uuid.UUID) error {
-	return r.db.Delete(&models.Permission{}, id).Error
+	return guardTenantManagement(r.db, nil, func(tx *gorm.DB) error {
+		return tx.Delete(&models.Permission{}, id).Error
+	})
 }
 
 // Policy-Permission assignments
@@ -184,10 +208,14 @@ func (r *rbacRepository) AssignPermissionsToPolicy(policyID uuid.UUID, permissio
 	return r.db.Model(policy).Association("Permissions").Append(convertToPermissions(permissionIDs))
 }
 
+// RevokePermissionFromPolicy removes a permission from a policy, rejecting with
+// a LastManagerError a change that would leave a tenant without a manager
 func (r *rbacRepository) RevokePermissionFromPolicy(policyID uuid.UUID, permissionID uuid.UUID) error {
 	policy := &models.Policy{ID: policyID}
 	permission := &models.Permission{ID: permissionID}
-	return r.db.Model(policy).Association("Permissions").Delete(permission)
+	return guardTenantManagement(r.db, nil, func(tx *gorm.DB) error {
+		return tx.Model(policy).Association("Permissions").Delete(permission)
+	})
 }
 
 func (r *rbacRepository) GetPolicyPermissions(policyID uuid.UUID) ([]*models.Permission, error) {
@@ -199,35 +227,52 @@ func (r *rbacRepository) GetPolicyPermissions(policyID uuid.UUID) ([]*models.Per
 	return permissions, err
 }
 
+// userRolesCTE selects the roles an active member holds in a tenant: their own
+// role and the roles granted to their groups. source and group_id say which.
+const userRolesCTE = `
+	WITH user_roles AS (
+		SELECT tm.role_id, 'member' AS source, NULL::uuid AS group_id
+		FROM tenant_members tm
+		WHERE tm.tenant_id = @tenant_id AND tm.user_id = @user_id AND tm.status = 'active'
+		UNION
+		SELECT tgr.role_id, 'group' AS source, tgm.group_id
+		FROM tenant_members tm
+		INNER JOIN tenant_group_members tgm ON tgm.member_id = tm.id
+		INNER JOIN tenant_group_roles tgr ON tgr.group_id = tgm.group_id
+		WHERE tm.tenant_id = @tenant_id AND tm.user_id = @user_id AND tm.status = 'active'
+	)`
+
 // Authorization queries
 func (r *rbacRepository) GetUserPermissions(tenantID uuid.UUID, userID string) ([]*models.Permission, error) {
 	var permissions []*models.Permission
-	err := r.db.Raw(`
+	err := r.db.Raw(userRolesCTE+`
 		SELECT DISTINCT p.*
 		FROM permissions p
 		INNER JOIN policy_permissions pp ON pp.permission_id = p.id
 		INNER JOIN role_policies rp ON rp.policy_id = pp.policy_id
-		INNER JOIN tenant_members tm ON tm.role_id = rp.role_id
-		WHERE tm.tenant_id = ? AND tm.user_id = ? AND tm.status = 'active'
-	`, tenantID, userID).Scan(&permissions).Error
+		INNER JOIN user_roles ur ON ur.role_id = rp.role_id
+	`, map[string]interface{}{"tenant_id": tenantID, "user_id": userID}).Scan(&permissions).Error
 	return permissions, err
 }
 
 func (r *rbacRepository) CheckUserPermission(tenantID uuid.UUID, userID string, service, entity, action string) (bool, error) {
 	var count int64
-	err := r.db.Raw(`
+	err := r.db.Raw(userRolesCTE+`
 		SELECT COUNT(DISTINCT p.id)
 		FROM permissions p
 		INNER JOIN policy_permissions pp ON pp.permission_id = p.id
 		INNER JOIN role_policies rp ON rp.policy_id = pp.policy_id
-		INNER JOIN tenant_members tm ON tm.role_id = rp.role_id
-		WHERE tm.tenant_id = ? 
-		  AND tm.user_id = ? 
-		  AND tm.status = 'active'
-		  AND p.service = ?
-		  AND p.entity = ?
-		  AND p.action = ?
-	`, tenantID, userID, service, entity, action).Scan(&count).Error
+		INNER JOIN user_roles ur ON ur.role_id = rp.role_id
+		WHERE p.service = @service
+		  AND p.entity = @entity
+		  AND p.action = @action
+	`, map[string]interface{}{
+		"tenant_id": tenantID,
+		"user_id":   userID,
+		"service":   service,
+		"entity":    entity,
+		"action":    action,
+	}).Scan(&count).Error
 
 	if err != nil {
 		return false, err
@@ -236,6 +281,56 @@ func (r *rbacRepository) CheckUserPermission(tenantID uuid.UUID, userID string,
 	return count > 0, nil
 }
 
+// ExplainUserPermission returns every role and policy that gives the user the
+// permission, whether the role is their own or granted to one of their groups
+func (r *rbacRepository) ExplainUserPermission(tenantID uuid.UUID, userID string, service, entity, action string) ([]*models.PermissionGrant, error) {
+	var grants []*models.PermissionGrant
+	err := r.db.Raw(userRolesCTE+`
+		SELECT DISTINCT ur.source, ur.group_id, COALESCE(g.name, '') AS group_name,
+			ro.id AS role_id, ro.name AS role_name, pol.id AS policy_id, pol.name AS policy_name
+		FROM permissions p
+		INNER JOIN policy_permissions pp ON pp.permission_id = p.id
+		INNER JOIN policies pol ON pol.id = pp.policy_id
+		INNER JOIN role_policies rp ON rp.policy_id = pol.id
+		INNER JOIN user_roles ur ON ur.role_id = rp.role_id
+		INNER JOIN roles ro ON ro.id = ur.role_id
+		LEFT JOIN tenant_groups g ON g.id = ur.group_id
+		WHERE p.service = @service
+		  AND p.entity = @entity
+		  AND p.action = @action
+		ORDER BY ur.source DESC, group_name, role_name, policy_name
+	`, map[string]interface{}{
+		"tenant_id": tenantID,
+		"user_id":   userID,
+		"service":   service,
+		"entity":    entity,
+		"action":    action,
+	}).Scan(&grants).Error
+	return grants, err
+}
+
+// GetUserRoleIDs returns the roles the user holds in the tenant, their own and
+// their groups'
+func (r *rbacRepository) GetUserRoleIDs(tenantID uuid.UUID, userID string) ([]uuid.UUID, error) {
+	var roleIDs []uuid.UUID
+	err := r.db.Raw(userRolesCTE+`
+		SELECT DISTINCT role_id FROM user_roles
+	`, map[string]interface{}{"tenant_id": tenantID, "user_id": userID}).Scan(&roleIDs).Error
+	return roleIDs, err
+}
+
+// GetRolePermissions returns every permission the role gives through its policies
+func (r *rbacRepository) GetRolePermissions(roleID uuid.UUID) ([]*models.Permission, error) {
+	var permissions []*models.Permission
+	err := r.db.
+		Distinct("permissions.*").
+		Joins("JOIN policy_permissions ON policy_permissions.permission_id = permissions.id").
+		Joins("JOIN role_policies ON role_policies.policy_id = policy_permissions.policy_id").
+		Where("role_policies.role_id = ?", roleID).
+		Find(&permissions).Error
+	return permissions, err
+}
+
 func convertToPermissions(permissionIDs []uuid.UUID) []*models.Permission {
 	permissions := make([]*models.Permission, len(permissionIDs))
 	for i, id := range permissionIDs {
@@ -271,10 +366,13 @@ func (r *rbacRepository) AssignPoliciesToRole(roleID uuid.UUID, policyIDs []uuid
 	return nil
 }
 
-// RevokePolicyFromRole removes a policy from a role
+// RevokePolicyFromRole removes a policy from a role, rejecting with a
+// LastManagerError a change that would leave a tenant without a manager
 func (r *rbacRepository) RevokePolicyFromRole(roleID uuid.UUID, policyID uuid.UUID) error {
-	return r.db.Where("role_id = ? AND policy_id = ?", roleID, policyID).
-		Delete(&models.RolePolicy{}).Error
+	return guardTenantManagement(r.db, nil, func(tx *gorm.DB) error {
+		return tx.Where("role_id = ? AND policy_id = ?", roleID, policyID).
+			Delete(&models.RolePolicy{}).Error
+	})
 }
 
 // GetRolePolicies retrieves all policies associated with a role
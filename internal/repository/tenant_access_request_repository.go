@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/models"
+	"gorm.io/gorm"
+)
+
+// ErrAccessRequestResolved is returned when an access request was approved,
+// denied or cancelled concurrently
+var ErrAccessRequestResolved = errors.New("access request is no longer pending")
+
+type TenantAccessRequestRepository interface {
+	Create(request *models.TenantAccessRequest) error
+	GetByID(id uuid.UUID) (*models.TenantAccessRequest, error)
+	GetPending(tenantID uuid.UUID, userID string) (*models.TenantAccessRequest, error)
+	GetLastDenied(tenantID uuid.UUID, userID string) (*models.TenantAccessRequest, error)
+	ListByTenant(tenantID uuid.UUID, status models.AccessRequestStatus) ([]*models.TenantAccessRequest, error)
+	ListByUser(userID string) ([]*models.TenantAccessRequest, error)
+	Resolve(request *models.TenantAccessRequest) error
+}
+
+type tenantAccessRequestRepository struct {
+	db *gorm.DB
+}
+
+func NewTenantAccessRequestRepository(db *gorm.DB) TenantAccessRequestRepository {
+	return &tenantAccessRequestRepository{db: db}
+}
+
+func (r *tenantAccessRequestRepository) Create(request *models.TenantAccessRequest) error {
+	return r.db.Create(request).Error
+}
+
+func (r *tenantAccessRequestRepository) GetByID(id uuid.UUID) (*models.TenantAccessRequest, error) {
+	var request models.TenantAccessRequest
+	err := r.db.Where("id = ?", id).First(&request).Error
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+func (r *tenantAccessRequestRepository) GetPending(tenantID uuid.UUID, userID string) (*models.TenantAccessRequest, error) {
+	var request models.TenantAccessRequest
+	err := r.db.
+		Where("tenant_id = ? AND user_id = ? AND status = ?", tenantID, userID, models.AccessRequestPending).
+		First(&request).Error
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// GetLastDenied returns the user's most recently denied request to the tenant
+func (r *tenantAccessRequestRepository) GetLastDenied(tenantID uuid.UUID, userID string) (*models.TenantAccessRequest, error) {
+	var request models.TenantAccessRequest
+	err := r.db.
+		Where("tenant_id = ? AND user_id = ? AND status = ?", tenantID, userID, models.AccessRequestDenied).
+		Order("resolved_at DESC").
+		First(&request).Error
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// ListByTenant returns the tenant's requests, newest first. An empty status
+// returns requests in every status.
+func (r *tenantAccessRequestRepository) ListByTenant(tenantID uuid.UUID, status models.AccessRequestStatus) ([]*models.TenantAccessRequest, error) {
+	var requests []*models.TenantAccessRequest
+	query := r.db.Where("tenant_id = ?", tenantID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("created_at DESC").Find(&requests).Error
+	return requests, err
+}
+
+// ListByUser returns the user's requests to every tenant, newest first, with
+// the tenant preloaded
+func (r *tenantAccessRequestRepository) ListByUser(userID string) ([]*models.TenantAccessRequest, error) {
+	var requests []*models.TenantAccessRequest
+	err := r.db.Preload("Tenant").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&requests).Error
+	return requests, err
+}
+
+// Resolve saves the request's status, role, reason and resolver, provided it
+// is still pending
+func (r *tenantAccessRequestRepository) Resolve(request *models.TenantAccessRequest) error {
+	now := time.Now()
+	result := r.db.Model(&models.TenantAccessRequest{}).
+		Where("id = ? AND status = ?", request.ID, models.AccessRequestPending).
+		Updates(map[string]interface{}{
+			"status":      request.Status,
+			"role_id":     request.RoleID,
+			"reason":      request.Reason,
+			"resolved_by": request.ResolvedBy,
+			"resolved_at": now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAccessRequestResolved
+	}
+	request.ResolvedAt = &now
+	return nil
+}
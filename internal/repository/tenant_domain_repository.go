@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/models"
+	"gorm.io/gorm"
+)
+
+type TenantDomainRepository interface {
+	Create(domain *models.TenantDomain) error
+	GetByID(id uuid.UUID) (*models.TenantDomain, error)
+	GetByTenantAndDomain(tenantID uuid.UUID, domain string) (*models.TenantDomain, error)
+	GetVerifiedByDomain(domain string) (*models.TenantDomain, error)
+	ListByTenant(tenantID uuid.UUID) ([]*models.TenantDomain, error)
+	Update(domain *models.TenantDomain) error
+	Delete(id uuid.UUID) error
+}
+
+type tenantDomainRepository struct {
+	db *gorm.DB
+}
+
+func NewTenantDomainRepository(db *gorm.DB) TenantDomainRepository {
+	return &tenantDomainRepository{db: db}
+}
+
+func (r *tenantDomainRepository) Create(domain *models.TenantDomain) error {
+	return r.db.Create(domain).Error
+}
+
+func (r *tenantDomainRepository) GetByID(id uuid.UUID) (*models.TenantDomain, error) {
+	var domain models.TenantDomain
+	err := r.db.Where("id = ?", id).First(&domain).Error
+	if err != nil {
+		return nil, err
+	}
+	return &domain, nil
+}
+
+func (r *tenantDomainRepository) GetByTenantAndDomain(tenantID uuid.UUID, domain string) (*models.TenantDomain, error) {
+	var tenantDomain models.TenantDomain
+	err := r.db.Where("tenant_id = ? AND domain = ?", tenantID, domain).First(&tenantDomain).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tenantDomain, nil
+}
+
+// GetVerifiedByDomain returns the tenant's verified claim on the domain; other
+// tenants may hold unverified claims on it at the same time
+func (r *tenantDomainRepository) GetVerifiedByDomain(domain string) (*models.TenantDomain, error) {
+	var tenantDomain models.TenantDomain
+	err := r.db.Where("domain = ? AND verified_at IS NOT NULL", domain).First(&tenantDomain).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tenantDomain, nil
+}
+
+func (r *tenantDomainRepository) ListByTenant(tenantID uuid.UUID) ([]*models.TenantDomain, error) {
+	var domains []*models.TenantDomain
+	err := r.db.Where("tenant_id = ?", tenantID).
+		Order("created_at ASC").
+		Find(&domains).Error
+	return domains, err
+}
+
+func (r *tenantDomainRepository) Update(domain *models.TenantDomain) error {
+	return r.db.Save(domain).Error
+}
+
+func (r *tenantDomainRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.TenantDomain{}, id).Error
+}
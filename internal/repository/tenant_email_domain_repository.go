@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/models"
+	"gorm.io/gorm"
+)
+
+type TenantEmailDomainRepository interface {
+	Create(domain *models.TenantEmailDomain) error
+	GetByID(id uuid.UUID) (*models.TenantEmailDomain, error)
+	GetByTenantAndDomain(tenantID uuid.UUID, domain string) (*models.TenantEmailDomain, error)
+	ListByTenant(tenantID uuid.UUID) ([]*models.TenantEmailDomain, error)
+	ListVerifiedByDomain(domain string) ([]*models.TenantEmailDomain, error)
+	Update(domain *models.TenantEmailDomain) error
+	Delete(id uuid.UUID) error
+}
+
+type tenantEmailDomainRepository struct {
+	db *gorm.DB
+}
+
+func NewTenantEmailDomainRepository(db *gorm.DB) TenantEmailDomainRepository {
+	return &tenantEmailDomainRepository{db: db}
+}
+
+func (r *tenantEmailDomainRepository) Create(domain *models.TenantEmailDomain) error {
+	return r.db.Create(domain).Error
+}
+
+func (r *tenantEmailDomainRepository) GetByID(id uuid.UUID) (*models.TenantEmailDomain, error) {
+	var domain models.TenantEmailDomain
+	err := r.db.Where("id = ?", id).First(&domain).Error
+	if err != nil {
+		return nil, err
+	}
+	return &domain, nil
+}
+
+func (r *tenantEmailDomainRepository) GetByTenantAndDomain(tenantID uuid.UUID, domain string) (*models.TenantEmailDomain, error) {
+	var emailDomain models.TenantEmailDomain
+	err := r.db.Where("tenant_id = ? AND domain = ?", tenantID, domain).First(&emailDomain).Error
+	if err != nil {
+		return nil, err
+	}
+	return &emailDomain, nil
+}
+
+func (r *tenantEmailDomainRepository) ListByTenant(tenantID uuid.UUID) ([]*models.TenantEmailDomain, error) {
+	var domains []*models.TenantEmailDomain
+	err := r.db.Where("tenant_id = ?", tenantID).
+		Order("created_at ASC").
+		Find(&domains).Error
+	return domains, err
+}
+
+// ListVerifiedByDomain returns every tenant's verified entry for the domain;
+// more than one tenant can prove ownership of the same email domain
+func (r *tenantEmailDomainRepository) ListVerifiedByDomain(domain string) ([]*models.TenantEmailDomain, error) {
+	var domains []*models.TenantEmailDomain
+	err := r.db.Where("domain = ? AND verified_at IS NOT NULL", domain).
+		Order("created_at ASC").
+		Find(&domains).Error
+	return domains, err
+}
+
+func (r *tenantEmailDomainRepository) Update(domain *models.TenantEmailDomain) error {
+	return r.db.Save(domain).Error
+}
+
+func (r *tenantEmailDomainRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.TenantEmailDomain{}, id).Error
+}
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/models"
+	"gorm.io/gorm"
+)
+
+type TenantExportRepository interface {
+	Create(export *models.TenantExport) error
+	GetByID(id uuid.UUID) (*models.TenantExport, error)
+	GetByDownloadTokenHash(hash string) (*models.TenantExport, error)
+	ListByTenant(tenantID uuid.UUID, pagination *models.PaginationParams) ([]*models.TenantExport, int64, error)
+	HasActive(tenantID uuid.UUID) (bool, error)
+	Start(id uuid.UUID) error
+	UpdateProgress(id uuid.UUID, progress int, stage string) error
+	Complete(id uuid.UUID, filePath string, fileSize int64, expiresAt time.Time) error
+	Fail(id uuid.UUID, exportErr error) error
+	SetDownloadToken(id uuid.UUID, tokenHash string, expiresAt time.Time) error
+	ListExpired(now time.Time, limit int) ([]*models.TenantExport, error)
+	MarkExpired(id uuid.UUID) error
+
+	// Tenant data included in exports
+	ListTenantMembers(tenantID uuid.UUID) ([]*models.TenantMember, error)
+	ListTenantInvitations(tenantID uuid.UUID) ([]*models.UserInvitation, error)
+	ListTenantRoles(tenantID uuid.UUID) ([]*models.Role, error)
+	ListTenantPolicies(tenantID uuid.UUID) ([]*models.Policy, error)
+}
+
+type tenantExportRepository struct {
+	db *gorm.DB
+}
+
+func NewTenantExportRepository(db *gorm.DB) TenantExportRepository {
+	return &tenantExportRepository{db: db}
+}
+
+func (r *tenantExportRepository) Create(export *models.TenantExport) error {
+	return r.db.Create(export).Error
+}
+
+func (r *tenantExportRepository) GetByID(id uuid.UUID) (*models.TenantExport, error) {
+	var export models.TenantExport
+	err := r.db.Where("id = ?", id).First(&export).Error
+	if err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (r *tenantExportRepository) GetByDownloadTokenHash(hash string) (*models.TenantExport, error) {
+	var export models.TenantExport
+	err := r.db.Where("download_token_hash = ?", hash).First(&export).Error
+	if err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (r *tenantExportRepository) ListByTenant(tenantID uuid.UUID, pagination *models.PaginationParams) ([]*models.TenantExport, int64, error) {
+	var exports []*models.TenantExport
+	var total int64
+
+	query := r.db.Model(&models.TenantExport{}).Where("tenant_id = ?", tenantID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	pagination.Normalize()
+	err := query.Order("created_at DESC").
+		Offset(pagination.GetOffset()).
+		Limit(pagination.PageSize).
+		Find(&exports).Error
+
+	return exports, total, err
+}
+
+// HasActive reports whether an export of the tenant is queued or running
+func (r *tenantExportRepository) HasActive(tenantID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.TenantExport{}).
+		Where("tenant_id = ? AND status IN ?", tenantID, []models.TenantExportStatus{models.TenantExportPending, models.TenantExportRunning}).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *tenantExportRepository) Start(id uuid.UUID) error {
+	return r.db.Model(&models.TenantExport{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     models.TenantExportRunning,
+			"progress":   0,
+			"error":      "",
+			"started_at": time.Now(),
+		}).Error
+}
+
+func (r *tenantExportRepository) UpdateProgress(id uuid.UUID, progress int, stage string) error {
+	return r.db.Model(&models.TenantExport{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"progress": progress,
+			"stage":    stage,
+		}).Error
+}
+
+func (r *tenantExportRepository) Complete(id uuid.UUID, filePath string, fileSize int64, expiresAt time.Time) error {
+	return r.db.Model(&models.TenantExport{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":       models.TenantExportCompleted,
+			"progress":     100,
+			"stage":        "",
+			"file_path":    filePath,
+			"file_size":    fileSize,
+			"completed_at": time.Now(),
+			"expires_at":   expiresAt,
+		}).Error
+}
+
+func (r *tenantExportRepository) Fail(id uuid.UUID, exportErr error) error {
+	return r.db.Model(&models.TenantExport{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":       models.TenantExportFailed,
+			"error":        exportErr.Error(),
+			"completed_at": time.Now(),
+		}).Error
+}
+
+// SetDownloadToken replaces the export's download link
+func (r *tenantExportRepository) SetDownloadToken(id uuid.UUID, tokenHash string, expiresAt time.Time) error {
+	return r.db.Model(&models.TenantExport{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"download_token_hash": tokenHash,
+			"download_expires_at": expiresAt,
+		}).Error
+}
+
+// ListExpired returns exports whose archive is past its retention
+func (r *tenantExportRepository) ListExpired(now time.Time, limit int) ([]*models.TenantExport, error) {
+	var exports []*models.TenantExport
+	err := r.db.Where("file_path IS NOT NULL AND file_path <> '' AND expires_at < ?", now).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&exports).Error
+	return exports, err
+}
+
+// MarkExpired records that the archive was removed and revokes its link
+func (r *tenantExportRepository) MarkExpired(id uuid.UUID) error {
+	return r.db.Model(&models.TenantExport{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":              models.TenantExportExpired,
+			"file_path":           "",
+			"download_token_hash": nil,
+			"download_expires_at": nil,
+		}).Error
+}
+
+func (r *tenantExportRepository) ListTenantMembers(tenantID uuid.UUID) ([]*models.TenantMember, error) {
+	var members []*models.TenantMember
+	err := r.db.Preload("Role").
+		Where("tenant_id = ?", tenantID).
+		Order("joined_at ASC").
+		Find(&members).Error
+	return members, err
+}
+
+func (r *tenantExportRepository) ListTenantInvitations(tenantID uuid.UUID) ([]*models.UserInvitation, error) {
+	var invitations []*models.UserInvitation
+	err := r.db.Preload("Role").
+		Where("tenant_id = ?", tenantID).
+		Order("created_at ASC").
+		Find(&invitations).Error
+	return invitations, err
+}
+
+func (r *tenantExportRepository) ListTenantRoles(tenantID uuid.UUID) ([]*models.Role, error) {
+	var roles []*models.Role
+	err := r.db.Preload("Policies").
+		Where("tenant_id = ?", tenantID).
+		Order("name ASC").
+		Find(&roles).Error
+	return roles, err
+}
+
+func (r *tenantExportRepository) ListTenantPolicies(tenantID uuid.UUID) ([]*models.Policy, error) {
+	var policies []*models.Policy
+	err := r.db.Preload("Permissions").
+		Where("tenant_id = ?", tenantID).
+		Order("name ASC").
+		Find(&policies).Error
+	return policies, err
+}
@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrGroupMemberNotFound is returned when removing a member who is not in the group
+var ErrGroupMemberNotFound = errors.New("member is not in this group")
+
+type TenantGroupRepository interface {
+	Create(group *models.TenantGroup, roleIDs []uuid.UUID) error
+	GetByID(id uuid.UUID) (*models.TenantGroup, error)
+	GetByName(tenantID uuid.UUID, name string) (*models.TenantGroup, error)
+	ListByTenant(tenantID uuid.UUID, pagination *models.PaginationParams) ([]*models.TenantGroup, int64, error)
+	Update(group *models.TenantGroup) error
+	Delete(id uuid.UUID) error
+
+	// Group membership
+	AddMembers(groupID uuid.UUID, memberIDs []uuid.UUID, addedBy string) error
+	RemoveMember(groupID uuid.UUID, memberID uuid.UUID) error
+	ListMembers(groupID uuid.UUID, pagination *models.PaginationParams) ([]*models.TenantMember, int64, error)
+
+	// Group role grants
+	AssignRoles(groupID uuid.UUID, roleIDs []uuid.UUID) error
+	RevokeRole(groupID uuid.UUID, roleID uuid.UUID) error
+}
+
+type tenantGroupRepository struct {
+	db *gorm.DB
+}
+
+func NewTenantGroupRepository(db *gorm.DB) TenantGroupRepository {
+	return &tenantGroupRepository{db: db}
+}
+
+// Create inserts the group and its role grants in one transaction
+func (r *tenantGroupRepository) Create(group *models.TenantGroup, roleIDs []uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Roles").Create(group).Error; err != nil {
+			return err
+		}
+		return insertGroupRoles(tx, group.ID, roleIDs)
+	})
+}
+
+func (r *tenantGroupRepository) GetByID(id uuid.UUID) (*models.TenantGroup, error) {
+	var group models.TenantGroup
+	err := r.db.Preload("Roles").Where("id = ?", id).First(&group).Error
+	if err != nil {
+		return nil, err
+	}
+	if err := r.countMembers([]*models.TenantGroup{&group}); err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+func (r *tenantGroupRepository) GetByName(tenantID uuid.UUID, name string) (*models.TenantGroup, error) {
+	var group models.TenantGroup
+	err := r.db.Where("tenant_id = ? AND name = ?", tenantID, name).First(&group).Error
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+func (r *tenantGroupRepository) ListByTenant(tenantID uuid.UUID, pagination *models.PaginationParams) ([]*models.TenantGroup, int64, error) {
+	var groups []*models.TenantGroup
+	var total int64
+
+	query := r.db.Model(&models.TenantGroup{}).Where("tenant_id = ?", tenantID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	pagination.Normalize()
+	err := query.Preload("Roles").
+		Order("name ASC").
+		Offset(pagination.GetOffset()).
+		Limit(pagination.PageSize).
+		Find(&groups).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := r.countMembers(groups); err != nil {
+		return nil, 0, err
+	}
+	return groups, total, nil
+}
+
+// countMembers fills in MemberCount for each group
+func (r *tenantGroupRepository) countMembers(groups []*models.TenantGroup) error {
+	if len(groups) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(groups))
+	for i, group := range groups {
+		ids[i] = group.ID
+	}
+
+	var counts []struct {
+		GroupID uuid.UUID
+		Count   int64
+	}
+	err := r.db.Model(&models.TenantGroupMember{}).
+		Select("group_id, COUNT(*) AS count").
+		Where("group_id IN ?", ids).
+		Group("group_id").
+		Scan(&counts).Error
+	if err != nil {
+		return err
+	}
+
+	byGroup := make(map[uuid.UUID]int64, len(counts))
+	for _, count := range counts {
+		byGroup[count.GroupID] = count.Count
+	}
+	for _, group := range groups {
+		group.MemberCount = byGroup[group.ID]
+	}
+	return nil
+}
+
+func (r *tenantGroupRepository) Update(group *models.TenantGroup) error {
+	return r.db.Omit("Roles").Save(group).Error
+}
+
+// Delete deletes the group. It returns a LastManagerError when the roles it
+// grants are what keeps the tenant managed.
+func (r *tenantGroupRepository) Delete(id uuid.UUID) error {
+	return r.guardGroupTenant(id, func(tx *gorm.DB) error {
+		return tx.Delete(&models.TenantGroup{}, "id = ?", id).Error
+	})
+}
+
+// AddMembers adds members to the group, skipping those already in it
+func (r *tenantGroupRepository) AddMembers(groupID uuid.UUID, memberIDs []uuid.UUID, addedBy string) error {
+	if len(memberIDs) == 0 {
+		return nil
+	}
+
+	rows := make([]models.TenantGroupMember, len(memberIDs))
+	for i, memberID := range memberIDs {
+		rows[i] = models.TenantGroupMember{GroupID: groupID, MemberID: memberID, AddedBy: addedBy}
+	}
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+}
+
+// RemoveMember takes the member out of the group. It returns a
+// LastManagerError when the group's roles make the member the tenant's last
+// manager.
+func (r *tenantGroupRepository) RemoveMember(groupID uuid.UUID, memberID uuid.UUID) error {
+	return r.guardGroupTenant(groupID, func(tx *gorm.DB) error {
+		result := tx.Where("group_id = ? AND member_id = ?", groupID, memberID).Delete(&models.TenantGroupMember{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrGroupMemberNotFound
+		}
+		return nil
+	})
+}
+
+func (r *tenantGroupRepository) ListMembers(groupID uuid.UUID, pagination *models.PaginationParams) ([]*models.TenantMember, int64, error) {
+	var members []*models.TenantMember
+	var total int64
+
+	query := r.db.Model(&models.TenantMember{}).
+		Joins("JOIN tenant_group_members tgm ON tgm.member_id = tenant_members.id").
+		Where("tgm.group_id = ?", groupID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	pagination.Normalize()
+	err := query.Select("tenant_members.*").
+		Preload("Role").
+		Order("tgm.created_at ASC").
+		Offset(pagination.GetOffset()).
+		Limit(pagination.PageSize).
+		Find(&members).Error
+
+	return members, total, err
+}
+
+// AssignRoles grants roles to the group, skipping those already granted
+func (r *tenantGroupRepository) AssignRoles(groupID uuid.UUID, roleIDs []uuid.UUID) error {
+	return insertGroupRoles(r.db, groupID, roleIDs)
+}
+
+// RevokeRole revokes the role from the group. It returns a LastManagerError
+// when the role is what keeps the tenant managed.
+func (r *tenantGroupRepository) RevokeRole(groupID uuid.UUID, roleID uuid.UUID) error {
+	return r.guardGroupTenant(groupID, func(tx *gorm.DB) error {
+		return tx.Exec("DELETE FROM tenant_group_roles WHERE group_id = ? AND role_id = ?", groupID, roleID).Error
+	})
+}
+
+// guardGroupTenant applies change with the management check scoped to the
+// group's tenant
+func (r *tenantGroupRepository) guardGroupTenant(groupID uuid.UUID, change func(tx *gorm.DB) error) error {
+	var group models.TenantGroup
+	if err := r.db.Select("id", "tenant_id").Where("id = ?", groupID).First(&group).Error; err != nil {
+		return err
+	}
+	return guardTenantManagement(r.db, &group.TenantID, change)
+}
+
+func insertGroupRoles(tx *gorm.DB, groupID uuid.UUID, roleIDs []uuid.UUID) error {
+	for _, roleID := range roleIDs {
+		err := tx.Exec(
+			"INSERT INTO tenant_group_roles (group_id, role_id) VALUES (?, ?) ON CONFLICT DO NOTHING",
+			groupID, roleID,
+		).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// unmanagedTenantsQuery selects active tenants with no active member holding
+// the management permission through their own role or a group's roles.
+// A NULL tenant_id checks every tenant.
+const unmanagedTenantsQuery = `
+	WITH effective_roles AS (
+		SELECT tm.tenant_id, tm.role_id
+		FROM tenant_members tm
+		WHERE tm.status = 'active' AND (CAST(@tenant_id AS uuid) IS NULL OR tm.tenant_id = @tenant_id)
+		UNION
+		SELECT tm.tenant_id, tgr.role_id
+		FROM tenant_members tm
+		INNER JOIN tenant_group_members tgm ON tgm.member_id = tm.id
+		INNER JOIN tenant_group_roles tgr ON tgr.group_id = tgm.group_id
+		WHERE tm.status = 'active' AND (CAST(@tenant_id AS uuid) IS NULL OR tm.tenant_id = @tenant_id)
+	), managed AS (
+		SELECT DISTINCT er.tenant_id
+		FROM effective_roles er
+		INNER JOIN role_policies rp ON rp.role_id = er.role_id
+		INNER JOIN policy_permissions pp ON pp.policy_id = rp.policy_id
+		INNER JOIN permissions p ON p.id = pp.permission_id
+		WHERE p.service = @service AND p.entity = @entity AND p.action = @action
+	)
+	SELECT t.id
+	FROM tenants t
+	WHERE t.status = 'active'
+	  AND (CAST(@tenant_id AS uuid) IS NULL OR t.id = @tenant_id)
+	  AND t.id NOT IN (SELECT tenant_id FROM managed)`
+
+// tenantManagersQuery selects the tenant's active members holding the
+// management permission through their own role or a group's roles
+const tenantManagersQuery = `
+	SELECT DISTINCT tm.user_id
+	FROM tenant_members tm
+	INNER JOIN (
+		SELECT tm2.id AS member_id, tm2.role_id
+		FROM tenant_members tm2
+		WHERE tm2.tenant_id = @tenant_id
+		UNION
+		SELECT tgm.member_id, tgr.role_id
+		FROM tenant_group_members tgm
+		INNER JOIN tenant_group_roles tgr ON tgr.group_id = tgm.group_id
+	) er ON er.member_id = tm.id
+	INNER JOIN role_policies rp ON rp.role_id = er.role_id
+	INNER JOIN policy_permissions pp ON pp.policy_id = rp.policy_id
+	INNER JOIN permissions p ON p.id = pp.permission_id
+	WHERE tm.tenant_id = @tenant_id AND tm.status = 'active'
+	  AND p.service = @service AND p.entity = @entity AND p.action = @action
+	ORDER BY tm.user_id`
+
+// tenantManagementLockKey is the transaction-level advisory lock taken by
+// every guarded change: shared by changes to one tenant and exclusive by
+// changes to shared roles, policies and permissions, so the two kinds never
+// check managers while the other is changing them
+const tenantManagementLockKey int64 = 0x7265785f6d676d74
+
+// guardTenantManagement applies change in a transaction and rolls it back with
+// a models.LastManagerError when it leaves an active tenant that had a manager
+// without one. tenantID limits the check to that tenant and locks it against
+// concurrent changes; nil checks every tenant, for changes to shared roles,
+// policies and permissions, and waits for changes to single tenants to
+// finish. Tenants already without a manager do not block the change.
+func guardTenantManagement(db *gorm.DB, tenantID *uuid.UUID, change func(tx *gorm.DB) error) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if tenantID == nil {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", tenantManagementLockKey).Error; err != nil {
+				return err
+			}
+		} else {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock_shared(?)", tenantManagementLockKey).Error; err != nil {
+				return err
+			}
+			if err := lockTenant(tx, *tenantID); err != nil {
+				return err
+			}
+		}
+
+		before, err := unmanagedTenants(tx, tenantID)
+		if err != nil {
+			return err
+		}
+
+		if err := change(tx); err != nil {
+			return err
+		}
+
+		after, err := unmanagedTenants(tx, tenantID)
+		if err != nil {
+			return err
+		}
+
+		var broken []uuid.UUID
+		for id := range after {
+			if _, ok := before[id]; !ok {
+				broken = append(broken, id)
+			}
+		}
+		if len(broken) > 0 {
+			return &models.LastManagerError{TenantIDs: broken}
+		}
+		return nil
+	})
+}
+
+func unmanagedTenants(tx *gorm.DB, tenantID *uuid.UUID) (map[uuid.UUID]struct{}, error) {
+	var ids []uuid.UUID
+	err := tx.Raw(unmanagedTenantsQuery, map[string]interface{}{
+		"tenant_id": tenantID,
+		"service":   models.TenantManagementService,
+		"entity":    models.TenantManagementEntity,
+		"action":    models.TenantManagementAction,
+	}).Scan(&ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	set := make(map[uuid.UUID]struct{}, len(ids))
+	for _, id := range ids {
+		set[id] = struct{}{}
+	}
+	return set, nil
+}
+
+// lockTenant locks the tenant row until the transaction ends, serializing
+// changes that must see each other, such as additions counted against a quota
+func lockTenant(tx *gorm.DB, tenantID uuid.UUID) error {
+	var locked models.Tenant
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		Where("id = ?", tenantID).
+		Find(&locked).Error
+}
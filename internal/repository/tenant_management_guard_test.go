@@ -0,0 +1,293 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/models"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// scriptedDB is a database/sql driver that answers the guard's unmanaged
+// tenants query from a script and records every other statement, so the
+// guard's transaction handling can be checked without Postgres
+type scriptedDB struct {
+	mu         sync.Mutex
+	unmanaged  [][]uuid.UUID
+	queries    []string
+	queryArgs  [][]driver.Value
+	committed  bool
+	rolledBack bool
+}
+
+type statement struct {
+	query string
+	args  []driver.Value
+}
+
+func (s *scriptedDB) statements() []statement {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	statements := make([]statement, len(s.queries))
+	for i := range s.queries {
+		statements[i] = statement{query: s.queries[i], args: s.queryArgs[i]}
+	}
+	return statements
+}
+
+func (s *scriptedDB) record(query string, args []driver.NamedValue) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	s.queries = append(s.queries, query)
+	s.queryArgs = append(s.queryArgs, values)
+}
+
+func (s *scriptedDB) Connect(context.Context) (driver.Conn, error) { return &scriptedConn{db: s}, nil }
+func (s *scriptedDB) Driver() driver.Driver                        { return nil }
+
+type scriptedConn struct {
+	db *scriptedDB
+}
+
+func (c *scriptedConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not scripted")
+}
+func (c *scriptedConn) Close() error              { return nil }
+func (c *scriptedConn) Begin() (driver.Tx, error) { return &scriptedTx{db: c.db}, nil }
+
+func (c *scriptedConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	return c.Begin()
+}
+
+func (c *scriptedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	c.db.record(query, args)
+
+	rows := &scriptedRows{columns: []string{"id"}}
+	if strings.Contains(query, "WITH effective_roles") {
+		if len(c.db.unmanaged) == 0 {
+			return nil, errors.New("unmanaged tenants queried more often than scripted")
+		}
+		for _, id := range c.db.unmanaged[0] {
+			rows.values = append(rows.values, []driver.Value{id.String()})
+		}
+		c.db.unmanaged = c.db.unmanaged[1:]
+	}
+	return rows, nil
+}
+
+func (c *scriptedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	c.db.record(query, args)
+	return driver.RowsAffected(1), nil
+}
+
+type scriptedTx struct {
+	db *scriptedDB
+}
+
+func (t *scriptedTx) Commit() error {
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+	t.db.committed = true
+	return nil
+}
+
+func (t *scriptedTx) Rollback() error {
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+	t.db.rolledBack = true
+	return nil
+}
+
+type scriptedRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *scriptedRows) Columns() []string { return r.columns }
+func (r *scriptedRows) Close() error      { return nil }
+
+func (r *scriptedRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+// openScripted returns a gorm connection whose unmanaged tenants query
+// answers with each of unmanaged in turn
+func openScripted(t *testing.T, unmanaged ...[]uuid.UUID) (*gorm.DB, *scriptedDB) {
+	t.Helper()
+	script := &scriptedDB{unmanaged: unmanaged}
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sql.OpenDB(script)}), &gorm.Config{
+		Logger:               logger.Default.LogMode(logger.Silent),
+		DisableAutomaticPing: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to open scripted database: %v", err)
+	}
+	return db, script
+}
+
+func removeMember(tx *gorm.DB) error {
+	return tx.Exec("DELETE FROM tenant_members WHERE id = ?", uuid.New()).Error
+}
+
+func sortedIDs(ids []uuid.UUID) []uuid.UUID {
+	sorted := append([]uuid.UUID(nil), ids...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].String() < sorted[j].String() })
+	return sorted
+}
+
+func TestGuardTenantManagementRejectsRemovingLastManager(t *testing.T) {
+	tenantID := uuid.New()
+	db, script := openScripted(t, nil, []uuid.UUID{tenantID})
+
+	err := guardTenantManagement(db, &tenantID, removeMember)
+
+	var managerErr *models.LastManagerError
+	if !errors.As(err, &managerErr) {
+		t.Fatalf("guardTenantManagement = %v, want a LastManagerError", err)
+	}
+	if !reflect.DeepEqual(managerErr.TenantIDs, []uuid.UUID{tenantID}) {
+		t.Errorf("TenantIDs = %v, want [%s]", managerErr.TenantIDs, tenantID)
+	}
+	if !script.rolledBack || script.committed {
+		t.Errorf("rolled back = %v, committed = %v, want the change rolled back", script.rolledBack, script.committed)
+	}
+}
+
+func TestGuardTenantManagementAllowsChanges(t *testing.T) {
+	unmanaged := uuid.New()
+	tests := []struct {
+		name   string
+		before []uuid.UUID
+		after  []uuid.UUID
+	}{
+		{"tenant stays managed", nil, nil},
+		{"tenant was already unmanaged", []uuid.UUID{unmanaged}, []uuid.UUID{unmanaged}},
+		{"change restores a manager", []uuid.UUID{unmanaged}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, script := openScripted(t, tt.before, tt.after)
+			if err := guardTenantManagement(db, &unmanaged, removeMember); err != nil {
+				t.Fatalf("guardTenantManagement = %v, want nil", err)
+			}
+			if !script.committed || script.rolledBack {
+				t.Errorf("committed = %v, rolled back = %v, want the change committed", script.committed, script.rolledBack)
+			}
+		})
+	}
+}
+
+func TestGuardTenantManagementChecksEveryTenantForSharedChanges(t *testing.T) {
+	alreadyUnmanaged := uuid.New()
+	broken := []uuid.UUID{uuid.New(), uuid.New()}
+	db, script := openScripted(t,
+		[]uuid.UUID{alreadyUnmanaged},
+		append([]uuid.UUID{alreadyUnmanaged}, broken...))
+
+	err := guardTenantManagement(db, nil, removeMember)
+
+	var managerErr *models.LastManagerError
+	if !errors.As(err, &managerErr) {
+		t.Fatalf("guardTenantManagement = %v, want a LastManagerError", err)
+	}
+	if got := sortedIDs(managerErr.TenantIDs); !reflect.DeepEqual(got, sortedIDs(broken)) {
+		t.Errorf("TenantIDs = %v, want only the newly unmanaged %v", got, broken)
+	}
+
+	statements := script.statements()
+	if len(statements) == 0 || !strings.Contains(statements[0].query, "pg_advisory_xact_lock(") {
+		t.Fatalf("shared change did not start by taking the exclusive management lock")
+	}
+	if statements[0].args[0] != tenantManagementLockKey {
+		t.Errorf("lock args = %v, want [%d]", statements[0].args, tenantManagementLockKey)
+	}
+	for _, statement := range statements {
+		if strings.Contains(statement.query, "FOR UPDATE") {
+			t.Errorf("shared change locked a tenant: %s", statement.query)
+		}
+		if strings.Contains(statement.query, "WITH effective_roles") {
+			for _, arg := range statement.args {
+				if _, ok := arg.(string); ok && arg != models.TenantManagementService &&
+					arg != models.TenantManagementEntity && arg != models.TenantManagementAction {
+					t.Errorf("shared change scoped the check to %v", arg)
+				}
+			}
+		}
+	}
+}
+
+func TestGuardTenantManagementLocksAndScopesToTenant(t *testing.T) {
+	tenantID := uuid.New()
+	db, script := openScripted(t, nil, nil)
+
+	if err := guardTenantManagement(db, &tenantID, removeMember); err != nil {
+		t.Fatalf("guardTenantManagement = %v, want nil", err)
+	}
+
+	statements := script.statements()
+	if len(statements) != 5 {
+		t.Fatalf("ran %d statements, want management lock, tenant lock, check, change, check", len(statements))
+	}
+	if !strings.Contains(statements[0].query, "pg_advisory_xact_lock_shared(") || statements[0].args[0] != tenantManagementLockKey {
+		t.Errorf("first statement = %s %v, want the shared management lock", statements[0].query, statements[0].args)
+	}
+	lock := statements[1]
+	if !strings.Contains(lock.query, `"tenants"`) || !strings.Contains(lock.query, "FOR UPDATE") {
+		t.Errorf("second statement = %s, want the tenant row locked", lock.query)
+	}
+	if len(lock.args) != 1 || lock.args[0] != tenantID.String() {
+		t.Errorf("lock args = %v, want [%s]", lock.args, tenantID)
+	}
+	for _, i := range []int{2, 4} {
+		if !strings.Contains(statements[i].query, "WITH effective_roles") {
+			t.Fatalf("statement %d = %s, want the unmanaged tenants check", i+1, statements[i].query)
+		}
+		found := false
+		for _, arg := range statements[i].args {
+			if arg == tenantID.String() {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("check %d args = %v, want them scoped to %s", i+1, statements[i].args, tenantID)
+		}
+	}
+}
+
+func TestGuardTenantManagementReturnsChangeError(t *testing.T) {
+	tenantID := uuid.New()
+	db, script := openScripted(t, nil)
+	failure := errors.New("constraint violated")
+
+	err := guardTenantManagement(db, &tenantID, func(tx *gorm.DB) error { return failure })
+
+	if !errors.Is(err, failure) {
+		t.Fatalf("guardTenantManagement = %v, want the change's error", err)
+	}
+	if !script.rolledBack {
+		t.Error("failed change was not rolled back")
+	}
+}
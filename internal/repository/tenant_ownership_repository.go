@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/models"
+	"gorm.io/gorm"
+)
+
+// ErrOwnershipTransferResolved is returned when a transfer was accepted,
+// declined, cancelled or expired concurrently
+var ErrOwnershipTransferResolved = errors.New("ownership transfer is no longer pending")
+
+// ErrOwnershipChanged is returned when the tenant's owner changed after the
+// transfer was nominated
+var ErrOwnershipChanged = errors.New("tenant owner changed since the transfer was requested")
+
+// ErrNomineeNotActive is returned when the nominee is no longer an active
+// member when accepting
+var ErrNomineeNotActive = errors.New("new owner is no longer an active member of the tenant")
+
+type TenantOwnershipRepository interface {
+	Create(transfer *models.TenantOwnershipTransfer) error
+	GetByID(id uuid.UUID) (*models.TenantOwnershipTransfer, error)
+	GetPending(tenantID uuid.UUID) (*models.TenantOwnershipTransfer, error)
+	ListByTenant(tenantID uuid.UUID) ([]*models.TenantOwnershipTransfer, error)
+	Resolve(id uuid.UUID, status models.OwnershipTransferStatus, actorID string) error
+	Accept(transfer *models.TenantOwnershipTransfer, adminRoleID uuid.UUID) error
+}
+
+type tenantOwnershipRepository struct {
+	db *gorm.DB
+}
+
+func NewTenantOwnershipRepository(db *gorm.DB) TenantOwnershipRepository {
+	return &tenantOwnershipRepository{db: db}
+}
+
+func (r *tenantOwnershipRepository) Create(transfer *models.TenantOwnershipTransfer) error {
+	return r.db.Create(transfer).Error
+}
+
+func (r *tenantOwnershipRepository) GetByID(id uuid.UUID) (*models.TenantOwnershipTransfer, error) {
+	var transfer models.TenantOwnershipTransfer
+	err := r.db.Where("id = ?", id).First(&transfer).Error
+	if err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+func (r *tenantOwnershipRepository) GetPending(tenantID uuid.UUID) (*models.TenantOwnershipTransfer, error) {
+	var transfer models.TenantOwnershipTransfer
+	err := r.db.
+		Where("tenant_id = ? AND status = ?", tenantID, models.OwnershipTransferPending).
+		First(&transfer).Error
+	if err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+func (r *tenantOwnershipRepository) ListByTenant(tenantID uuid.UUID) ([]*models.TenantOwnershipTransfer, error) {
+	var transfers []*models.TenantOwnershipTransfer
+	err := r.db.Where("tenant_id = ?", tenantID).
+		Order("created_at DESC").
+		Find(&transfers).Error
+	return transfers, err
+}
+
+// Resolve closes a pending transfer without changing ownership
+func (r *tenantOwnershipRepository) Resolve(id uuid.UUID, status models.OwnershipTransferStatus, actorID string) error {
+	result := r.db.Model(&models.TenantOwnershipTransfer{}).
+		Where("id = ? AND status = ?", id, models.OwnershipTransferPending).
+		Updates(map[string]interface{}{
+			"status":      status,
+			"resolved_by": actorID,
+			"resolved_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrOwnershipTransferResolved
+	}
+	return nil
+}
+
+// Accept completes a transfer in one transaction: the transfer is marked
+// accepted, the tenant's owner changes, the new owner gets the Admin role and
+// the previous owner is moved to the nominated role, if any. Every update is
+// guarded so a transfer raced by another ownership change fails as a whole.
+func (r *tenantOwnershipRepository) Accept(transfer *models.TenantOwnershipTransfer, adminRoleID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&models.TenantOwnershipTransfer{}).
+			Where("id = ? AND status = ?", transfer.ID, models.OwnershipTransferPending).
+			Updates(map[string]interface{}{
+				"status":      models.OwnershipTransferAccepted,
+				"resolved_by": transfer.ToUserID,
+				"resolved_at": now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrOwnershipTransferResolved
+		}
+
+		query := tx.Model(&models.Tenant{}).Where("id = ?", transfer.TenantID)
+		if transfer.FromUserID != nil {
+			query = query.Where("owner_id = ?", *transfer.FromUserID)
+		} else {
+			query = query.Where("owner_id IS NULL")
+		}
+		result = query.Update("owner_id", transfer.ToUserID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrOwnershipChanged
+		}
+
+		result = tx.Model(&models.TenantMember{}).
+			Where("tenant_id = ? AND user_id = ? AND status = ?", transfer.TenantID, transfer.ToUserID, models.MemberStatusActive).
+			Update("role_id", adminRoleID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNomineeNotActive
+		}
+
+		if transfer.FromUserID != nil && transfer.PreviousOwnerRoleID != nil {
+			err := tx.Model(&models.TenantMember{}).
+				Where("tenant_id = ? AND user_id = ?", transfer.TenantID, *transfer.FromUserID).
+				Update("role_id", *transfer.PreviousOwnerRoleID).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		transfer.Status = models.OwnershipTransferAccepted
+		transfer.ResolvedBy = &transfer.ToUserID
+		transfer.ResolvedAt = &now
+		return nil
+	})
+}
@@ -1,6 +1,10 @@
 package repository
 
 import (
+	"errors"
+	"strings"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/ysaakpr/rex/internal/models"
 	"gorm.io/gorm"
@@ -10,13 +14,29 @@ type TenantRepository interface {
 	Create(tenant *models.Tenant) error
 	GetByID(id uuid.UUID) (*models.Tenant, error)
 	GetBySlug(slug string) (*models.Tenant, error)
-	GetByCreatorID(creatorID string, pagination *models.PaginationParams) ([]*models.Tenant, int64, error)
-	List(pagination *models.PaginationParams) ([]*models.Tenant, int64, error)
+	ListByMember(userID string, params *models.TenantListParams) ([]*models.Tenant, int64, error)
+	List(params *models.TenantListParams) ([]*models.Tenant, int64, error)
 	Update(tenant *models.Tenant) error
 	Delete(id uuid.UUID) error
 	UpdateStatus(id uuid.UUID, status models.TenantStatus) error
+	UpdateSecuritySettings(id uuid.UUID, settings models.TenantSecuritySettings) error
+	ListWithInactivityPolicy() ([]*models.Tenant, error)
+	SetOwnerIfUnset(id uuid.UUID, userID string) error
+	TransitionStatus(id uuid.UUID, from, to models.TenantStatus, reason, actorID string) (*models.TenantStatusTransition, error)
+	ListTransitions(tenantID uuid.UUID) ([]*models.TenantStatusTransition, error)
+	GetDeletedByID(id uuid.UUID) (*models.Tenant, error)
+	ListDeleted(pagination *models.PaginationParams) ([]*models.Tenant, int64, error)
+	ListPurgeable(deletedBefore time.Time, limit int) ([]*models.Tenant, error)
+	Purge(tenant *models.Tenant) (*models.TenantPurgeReport, error)
+	ListPurgeReports(pagination *models.PaginationParams) ([]*models.TenantPurgeReport, int64, error)
 }
 
+// likeEscaper escapes LIKE wildcards so searches match them literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// ErrTenantStatusChanged is returned when a tenant's status changed between read and transition
+var ErrTenantStatusChanged = errors.New("tenant status was changed concurrently")
+
 type tenantRepository struct {
 	db *gorm.DB
 }
@@ -47,31 +67,223 @@ func (r *tenantRepository) GetBySlug(slug string) (*models.Tenant, error) {
 	return &tenant, nil
 }
 
-func (r *tenantRepository) GetByCreatorID(creatorID string, pagination *models.PaginationParams) ([]*models.Tenant, int64, error) {
+// ListByMember returns the tenants the user is an active member of that match
+// params
+func (r *tenantRepository) ListByMember(userID string, params *models.TenantListParams) ([]*models.Tenant, int64, error) {
+	query := r.db.Model(&models.Tenant{}).
+		Joins("JOIN tenant_members ON tenant_members.tenant_id = tenants.id").
+		Where("tenant_members.user_id = ? AND tenant_members.status = ?", userID, models.MemberStatusActive)
+
+	return r.listTenants(query, params)
+}
+
+// List returns the tenants matching params
+func (r *tenantRepository) List(params *models.TenantListParams) ([]*models.Tenant, int64, error) {
+	return r.listTenants(r.db.Model(&models.Tenant{}), params)
+}
+
+func (r *tenantRepository) listTenants(query *gorm.DB, params *models.TenantListParams) ([]*models.Tenant, int64, error) {
 	var tenants []*models.Tenant
 	var total int64
 
-	query := r.db.Model(&models.Tenant{}).Where("created_by = ?", creatorID)
+	query, err := filterTenants(query, params)
+	if err != nil {
+		return nil, 0, err
+	}
 
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	pagination.Normalize()
-	err := query.
-		Offset(pagination.GetOffset()).
-		Limit(pagination.PageSize).
-		Order("created_at DESC").
+	params.Normalize()
+	err = query.
+		Select("tenants.*").
+		Offset(params.GetOffset()).
+		Limit(params.PageSize).
+		Order(tenantOrder(params.Sort)).
 		Find(&tenants).Error
 
 	return tenants, total, err
 }
 
-func (r *tenantRepository) List(pagination *models.PaginationParams) ([]*models.Tenant, int64, error) {
+// filterTenants applies params' filters. Each is backed by an index: status,
+// created_at and created_by by b-tree indexes, the name/slug search by trigram
+// indexes and metadata containment by the GIN index on metadata.
+func filterTenants(query *gorm.DB, params *models.TenantListParams) (*gorm.DB, error) {
+	if len(params.Status) > 0 {
+		query = query.Where("tenants.status IN ?", params.Status)
+	}
+	if !params.CreatedAfter.IsZero() {
+		query = query.Where("tenants.created_at >= ?", params.CreatedAfter)
+	}
+	if !params.CreatedBefore.IsZero() {
+		query = query.Where("tenants.created_at < ?", params.CreatedBefore)
+	}
+	if params.CreatedBy != "" {
+		query = query.Where("tenants.created_by = ?", params.CreatedBy)
+	}
+	if search := strings.TrimSpace(params.Search); search != "" {
+		pattern := "%" + likeEscaper.Replace(search) + "%"
+		query = query.Where("(tenants.name ILIKE ? OR tenants.slug ILIKE ?)", pattern, pattern)
+	}
+
+	metadataFilters, err := params.MetadataFilters()
+	if err != nil {
+		return nil, err
+	}
+	for _, docs := range metadataFilters {
+		args := make([]interface{}, len(docs))
+		for i, doc := range docs {
+			args[i] = doc
+		}
+		query = query.Where(anyContainment(len(docs)), args...)
+	}
+
+	return query, nil
+}
+
+// anyContainment builds a condition matching metadata that contains any of n
+// documents
+func anyContainment(n int) string {
+	conditions := make([]string, n)
+	for i := range conditions {
+		conditions[i] = "tenants.metadata @> ?::jsonb"
+	}
+	return "(" + strings.Join(conditions, " OR ") + ")"
+}
+
+// tenantOrder returns the ORDER BY clause for sort, breaking ties by ID so
+// pages are stable
+func tenantOrder(sort string) string {
+	direction := "ASC"
+	if strings.HasPrefix(sort, "-") {
+		direction = "DESC"
+		sort = strings.TrimPrefix(sort, "-")
+	}
+
+	var column string
+	switch sort {
+	case models.TenantSortName:
+		column = "tenants.name"
+	case models.TenantSortMemberCount:
+		column = "(SELECT COUNT(*) FROM tenant_members active_members " +
+			"WHERE active_members.tenant_id = tenants.id AND active_members.status = 'active')"
+	case models.TenantSortCreatedAt:
+		column = "tenants.created_at"
+	default:
+		column, direction = "tenants.created_at", "DESC"
+	}
+
+	return column + " " + direction + ", tenants.id " + direction
+}
+
+func (r *tenantRepository) Update(tenant *models.Tenant) error {
+	return r.db.Save(tenant).Error
+}
+
+func (r *tenantRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.Tenant{}, id).Error
+}
+
+func (r *tenantRepository) UpdateStatus(id uuid.UUID, status models.TenantStatus) error {
+	return r.db.Model(&models.Tenant{}).
+		Where("id = ?", id).
+		Update("status", status).Error
+}
+
+func (r *tenantRepository) UpdateSecuritySettings(id uuid.UUID, settings models.TenantSecuritySettings) error {
+	return r.db.Model(&models.Tenant{}).
+		Where("id = ?", id).
+		Update("security_settings", settings).Error
+}
+
+// ListWithInactivityPolicy returns active tenants whose security settings
+// deactivate inactive members
+func (r *tenantRepository) ListWithInactivityPolicy() ([]*models.Tenant, error) {
+	var tenants []*models.Tenant
+	err := r.db.
+		Where("status = ?", models.TenantStatusActive).
+		Where("COALESCE((security_settings->>'inactive_member_deactivation_days')::int, 0) > 0").
+		Find(&tenants).Error
+	return tenants, err
+}
+
+// SetOwnerIfUnset makes userID the owner of a tenant that has none, such as a
+// managed tenant whose first admin has just joined
+func (r *tenantRepository) SetOwnerIfUnset(id uuid.UUID, userID string) error {
+	return r.db.Model(&models.Tenant{}).
+		Where("id = ? AND owner_id IS NULL", id).
+		Update("owner_id", userID).Error
+}
+
+// TransitionStatus moves a tenant from one status to another and records the
+// transition in a single transaction. The update is guarded on the expected
+// current status so concurrent transitions cannot both succeed. Moving to
+// deleted soft-deletes the tenant; moving to deleting keeps it soft-deleted; any
+// other status restores it.
+func (r *tenantRepository) TransitionStatus(id uuid.UUID, from, to models.TenantStatus, reason, actorID string) (*models.TenantStatusTransition, error) {
+	transition := &models.TenantStatusTransition{
+		TenantID:   id,
+		FromStatus: from,
+		ToStatus:   to,
+		Reason:     reason,
+		ActorID:    actorID,
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		updates := map[string]interface{}{"status": to}
+		switch to {
+		case models.TenantStatusDeleted:
+			updates["deleted_at"] = time.Now()
+		case models.TenantStatusDeleting:
+			// Still soft-deleted; deleted_at keeps the original deletion time
+		default:
+			updates["deleted_at"] = nil
+		}
+
+		result := tx.Unscoped().Model(&models.Tenant{}).
+			Where("id = ? AND status = ?", id, from).
+			Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrTenantStatusChanged
+		}
+
+		return tx.Create(transition).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transition, nil
+}
+
+func (r *tenantRepository) ListTransitions(tenantID uuid.UUID) ([]*models.TenantStatusTransition, error) {
+	var transitions []*models.TenantStatusTransition
+	err := r.db.Where("tenant_id = ?", tenantID).
+		Order("created_at DESC").
+		Find(&transitions).Error
+	return transitions, err
+}
+
+func (r *tenantRepository) GetDeletedByID(id uuid.UUID) (*models.Tenant, error) {
+	var tenant models.Tenant
+	err := r.db.Unscoped().
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		First(&tenant).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tenant, nil
+}
+
+func (r *tenantRepository) ListDeleted(pagination *models.PaginationParams) ([]*models.Tenant, int64, error) {
 	var tenants []*models.Tenant
 	var total int64
 
-	query := r.db.Model(&models.Tenant{})
+	query := r.db.Unscoped().Model(&models.Tenant{}).Where("deleted_at IS NOT NULL")
 
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -81,22 +293,110 @@ func (r *tenantRepository) List(pagination *models.PaginationParams) ([]*models.
 	err := query.
 		Offset(pagination.GetOffset()).
 		Limit(pagination.PageSize).
-		Order("created_at DESC").
+		Order("deleted_at DESC").
 		Find(&tenants).Error
 
 	return tenants, total, err
 }
 
-func (r *tenantRepository) Update(tenant *models.Tenant) error {
-	return r.db.Save(tenant).Error
+// ListPurgeable returns deleted tenants past the retention cutoff. Tenants
+// already being deprovisioned are excluded; their teardown job owns them.
+func (r *tenantRepository) ListPurgeable(deletedBefore time.Time, limit int) ([]*models.Tenant, error) {
+	var tenants []*models.Tenant
+	err := r.db.Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ? AND status = ?", deletedBefore, models.TenantStatusDeleted).
+		Order("deleted_at ASC").
+		Limit(limit).
+		Find(&tenants).Error
+	return tenants, err
 }
 
-func (r *tenantRepository) Delete(id uuid.UUID) error {
-	return r.db.Delete(&models.Tenant{}, id).Error
+// Purge permanently removes a soft-deleted tenant together with its members,
+// invitations, tenant-scoped roles and policies and lifecycle history, and
+// records a purge report, all in one transaction. Member role assignments live
+// on tenant_members.role_id since the RBAC refactor, so removing the members
+// also removes their role assignments.
+func (r *tenantRepository) Purge(tenant *models.Tenant) (*models.TenantPurgeReport, error) {
+	report := &models.TenantPurgeReport{
+		TenantID:   tenant.ID,
+		TenantName: tenant.Name,
+		TenantSlug: tenant.Slug,
+		DeletedAt:  tenant.DeletedAt.Time,
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Re-check inside the transaction so a tenant restored after it was
+		// selected for purging is left alone
+		result := tx.Unscoped().Model(&models.Tenant{}).
+			Where("id = ? AND deleted_at IS NOT NULL", tenant.ID).
+			Update("status", models.TenantStatusDeleted)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrTenantStatusChanged
+		}
+
+		result = tx.Where("tenant_id = ?", tenant.ID).Delete(&models.TenantMember{})
+		if result.Error != nil {
+			return result.Error
+		}
+		report.MembersPurged = result.RowsAffected
+
+		result = tx.Where("tenant_id = ?", tenant.ID).Delete(&models.UserInvitation{})
+		if result.Error != nil {
+			return result.Error
+		}
+		report.InvitationsPurged = result.RowsAffected
+
+		// role_policies and policy_permissions rows cascade from these deletes
+		result = tx.Where("tenant_id = ?", tenant.ID).Delete(&models.Role{})
+		if result.Error != nil {
+			return result.Error
+		}
+		report.RolesPurged = result.RowsAffected
+
+		result = tx.Where("tenant_id = ?", tenant.ID).Delete(&models.Policy{})
+		if result.Error != nil {
+			return result.Error
+		}
+		report.PoliciesPurged = result.RowsAffected
+
+		result = tx.Where("tenant_id = ?", tenant.ID).Delete(&models.TenantStatusTransition{})
+		if result.Error != nil {
+			return result.Error
+		}
+		report.TransitionsPurged = result.RowsAffected
+
+		if err := tx.Unscoped().Delete(&models.Tenant{}, "id = ?", tenant.ID).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(report).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
 }
 
-func (r *tenantRepository) UpdateStatus(id uuid.UUID, status models.TenantStatus) error {
-	return r.db.Model(&models.Tenant{}).
-		Where("id = ?", id).
-		Update("status", status).Error
+func (r *tenantRepository) ListPurgeReports(pagination *models.PaginationParams) ([]*models.TenantPurgeReport, int64, error) {
+	var reports []*models.TenantPurgeReport
+	var total int64
+
+	query := r.db.Model(&models.TenantPurgeReport{})
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	pagination.Normalize()
+	err := query.
+		Offset(pagination.GetOffset()).
+		Limit(pagination.PageSize).
+		Order("purged_at DESC").
+		Find(&reports).Error
+
+	return reports, total, err
 }
@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/models"
+	"gorm.io/gorm"
+)
+
+type TenantTemplateRepository interface {
+	Create(template *models.TenantTemplate) error
+	GetByID(id uuid.UUID) (*models.TenantTemplate, error)
+	GetByName(name string) (*models.TenantTemplate, error)
+	List() ([]*models.TenantTemplate, error)
+	Update(template *models.TenantTemplate) error
+	Delete(id uuid.UUID) error
+	CheckReferences(template *models.TenantTemplate) error
+	Instantiate(tenant *models.Tenant, template *models.TenantTemplate, founder *models.TenantMember, invitation *models.UserInvitation) error
+}
+
+type tenantTemplateRepository struct {
+	db *gorm.DB
+}
+
+func NewTenantTemplateRepository(db *gorm.DB) TenantTemplateRepository {
+	return &tenantTemplateRepository{db: db}
+}
+
+func (r *tenantTemplateRepository) Create(template *models.TenantTemplate) error {
+	return r.db.Create(template).Error
+}
+
+func (r *tenantTemplateRepository) GetByID(id uuid.UUID) (*models.TenantTemplate, error) {
+	var template models.TenantTemplate
+	err := r.db.Where("id = ?", id).First(&template).Error
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *tenantTemplateRepository) GetByName(name string) (*models.TenantTemplate, error) {
+	var template models.TenantTemplate
+	err := r.db.Where("name = ?", name).First(&template).Error
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *tenantTemplateRepository) List() ([]*models.TenantTemplate, error) {
+	var templates []*models.TenantTemplate
+	err := r.db.Order("name ASC").Find(&templates).Error
+	return templates, err
+}
+
+func (r *tenantTemplateRepository) Update(template *models.TenantTemplate) error {
+	return r.db.Save(template).Error
+}
+
+func (r *tenantTemplateRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.TenantTemplate{}, "id = ?", id).Error
+}
+
+// CheckReferences verifies that the permissions, system policies and system
+// default role the template refers to exist
+func (r *tenantTemplateRepository) CheckReferences(template *models.TenantTemplate) error {
+	for _, tp := range template.Policies {
+		for _, key := range tp.Permissions {
+			if _, err := findPermission(r.db, key); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, tr := range template.Roles {
+		for _, name := range tr.Policies {
+			if template.HasPolicy(name) {
+				continue
+			}
+			if _, err := findSystemPolicy(r.db, name); err != nil {
+				return err
+			}
+		}
+	}
+
+	if template.DefaultRole != "" && !template.HasRole(template.DefaultRole) {
+		if _, err := findSystemRole(r.db, template.DefaultRole); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Instantiate creates tenant from template in one transaction: the tenant,
+// the template's tenant-scoped policies and roles, the tenant's default role
+// and either the founding member or the invitation for the first admin. If
+// any part fails nothing is created.
+func (r *tenantTemplateRepository) Instantiate(tenant *models.Tenant, template *models.TenantTemplate, founder *models.TenantMember, invitation *models.UserInvitation) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(tenant).Error; err != nil {
+			return err
+		}
+
+		policyIDs := make(map[string]uuid.UUID, len(template.Policies))
+		for _, tp := range template.Policies {
+			policy := &models.Policy{
+				Name:        tp.Name,
+				Description: tp.Description,
+				TenantID:    &tenant.ID,
+			}
+			if err := tx.Omit("Permissions", "Roles").Create(policy).Error; err != nil {
+				return err
+			}
+			policyIDs[tp.Name] = policy.ID
+
+			for _, key := range tp.Permissions {
+				permission, err := findPermission(tx, key)
+				if err != nil {
+					return err
+				}
+				if err := tx.Model(policy).Association("Permissions").Append(permission); err != nil {
+					return err
+				}
+			}
+		}
+
+		roleIDs := make(map[string]uuid.UUID, len(template.Roles))
+		for _, tr := range template.Roles {
+			role := &models.Role{
+				Name:        tr.Name,
+				Type:        "tenant",
+				Description: tr.Description,
+				TenantID:    &tenant.ID,
+			}
+			if err := tx.Omit("Policies").Create(role).Error; err != nil {
+				return err
+			}
+			roleIDs[tr.Name] = role.ID
+
+			for _, name := range tr.Policies {
+				policyID, ok := policyIDs[name]
+				if !ok {
+					systemPolicy, err := findSystemPolicy(tx, name)
+					if err != nil {
+						return err
+					}
+					policyID = systemPolicy.ID
+				}
+				if err := tx.Create(&models.RolePolicy{RoleID: role.ID, PolicyID: policyID}).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		if template.DefaultRole != "" {
+			roleID, ok := roleIDs[template.DefaultRole]
+			if !ok {
+				systemRole, err := findSystemRole(tx, template.DefaultRole)
+				if err != nil {
+					return err
+				}
+				roleID = systemRole.ID
+			}
+			if err := tx.Model(tenant).Update("default_role_id", roleID).Error; err != nil {
+				return err
+			}
+			tenant.DefaultRoleID = &roleID
+		}
+
+		if founder != nil {
+			founder.TenantID = tenant.ID
+			if err := tx.Create(founder).Error; err != nil {
+				return err
+			}
+		}
+		if invitation != nil {
+			invitation.TenantID = tenant.ID
+			if err := tx.Create(invitation).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func findPermission(tx *gorm.DB, key string) (*models.Permission, error) {
+	service, entity, action, err := models.ParsePermissionKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var permission models.Permission
+	err = tx.Where("service = ? AND entity = ? AND action = ?", service, entity, action).
+		First(&permission).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("permission %s not found", key)
+	}
+	return &permission, err
+}
+
+func findSystemPolicy(tx *gorm.DB, name string) (*models.Policy, error) {
+	var policy models.Policy
+	err := tx.Where("name = ? AND tenant_id IS NULL", name).First(&policy).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("policy %s not found", name)
+	}
+	return &policy, err
+}
+
+func findSystemRole(tx *gorm.DB, name string) (*models.Role, error) {
+	var role models.Role
+	err := tx.Where("name = ? AND tenant_id IS NULL", name).First(&role).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("role %s not found", name)
+	}
+	return &role, err
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type UserProfileRepository interface {
+	// Get returns the user's profile, or an empty one when they have none yet
+	Get(userID string) (*models.UserProfile, error)
+	SetDefaultTenant(userID string, tenantID *uuid.UUID) error
+}
+
+type userProfileRepository struct {
+	db *gorm.DB
+}
+
+func NewUserProfileRepository(db *gorm.DB) UserProfileRepository {
+	return &userProfileRepository{db: db}
+}
+
+func (r *userProfileRepository) Get(userID string) (*models.UserProfile, error) {
+	profile := models.UserProfile{UserID: userID}
+	err := r.db.Where("user_id = ?", userID).Limit(1).Find(&profile).Error
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// SetDefaultTenant creates the profile if needed and sets its default tenant
+func (r *userProfileRepository) SetDefaultTenant(userID string, tenantID *uuid.UUID) error {
+	profile := &models.UserProfile{UserID: userID, DefaultTenantID: tenantID}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"default_tenant_id", "updated_at"}),
+	}).Create(profile).Error
+}
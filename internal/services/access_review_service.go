@@ -0,0 +1,439 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/jobs"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/pkg/signing"
+	"github.com/ysaakpr/rex/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrAccessReviewForbidden is returned when the actor is not a reviewer of
+	// the access review item
+	ErrAccessReviewForbidden = errors.New("not a reviewer for this access review item")
+	// ErrReportSigningDisabled is returned when exporting a report without a
+	// signing secret configured
+	ErrReportSigningDisabled = errors.New("access review reports are disabled: no signing secret is configured")
+	// ErrAccessReviewClosed is returned when the campaign no longer accepts
+	// decisions
+	ErrAccessReviewClosed = repository.ErrAccessReviewClosed
+)
+
+// AccessReviewReportFile is an exported report with its signature, computed
+// over "<unix SignedAt>.<Body>" like signed provisioning requests
+type AccessReviewReportFile struct {
+	Body      []byte
+	Signature string
+	SignedAt  time.Time
+}
+
+type AccessReviewService interface {
+	// StartCampaign starts a campaign covering tenantID, or the tenants and
+	// role in the input when tenantID is nil and the actor is a platform admin
+	StartCampaign(tenantID *uuid.UUID, input *models.StartAccessReviewInput, actorID string) (*models.AccessReviewCampaignResponse, error)
+	ListCampaigns(tenantID *uuid.UUID) ([]*models.AccessReviewCampaign, error)
+	GetCampaign(tenantID *uuid.UUID, campaignID uuid.UUID) (*models.AccessReviewCampaignResponse, error)
+	ListItems(tenantID *uuid.UUID, campaignID uuid.UUID, params *models.AccessReviewItemParams) ([]*models.AccessReviewItem, error)
+	CloseCampaign(tenantID *uuid.UUID, campaignID uuid.UUID, actorID string) (*models.AccessReviewCampaignResponse, error)
+	ExportReport(tenantID *uuid.UUID, campaignID uuid.UUID) (*AccessReviewReportFile, error)
+
+	ListMyItems(userID string) ([]*models.AccessReviewItem, error)
+	DecideItem(itemID uuid.UUID, input *models.DecideAccessReviewInput, actorID string) (*models.AccessReviewItem, error)
+}
+
+type accessReviewService struct {
+	reviewRepo        repository.AccessReviewRepository
+	tenantRepo        repository.TenantRepository
+	memberRepo        repository.MemberRepository
+	rbacRepo          repository.RBACRepository
+	platformAdminRepo repository.PlatformAdminRepository
+	groupRepo         repository.TenantGroupRepository
+	memberService     MemberService
+	jobClient         jobs.Client
+	signingSecret     string
+}
+
+// NewAccessReviewService creates the access review service. Revocations are
+// applied through memberService and groupRepo, so the tenant owner and last
+// manager are protected as they are when removing members by hand.
+func NewAccessReviewService(
+	reviewRepo repository.AccessReviewRepository,
+	tenantRepo repository.TenantRepository,
+	memberRepo repository.MemberRepository,
+	rbacRepo repository.RBACRepository,
+	platformAdminRepo repository.PlatformAdminRepository,
+	groupRepo repository.TenantGroupRepository,
+	memberService MemberService,
+	jobClient jobs.Client,
+	signingSecret string,
+) AccessReviewService {
+	return &accessReviewService{
+		reviewRepo:        reviewRepo,
+		tenantRepo:        tenantRepo,
+		memberRepo:        memberRepo,
+		rbacRepo:          rbacRepo,
+		platformAdminRepo: platformAdminRepo,
+		groupRepo:         groupRepo,
+		memberService:     memberService,
+		jobClient:         jobClient,
+		signingSecret:     signingSecret,
+	}
+}
+
+func (s *accessReviewService) StartCampaign(tenantID *uuid.UUID, input *models.StartAccessReviewInput, actorID string) (*models.AccessReviewCampaignResponse, error) {
+	var scope []uuid.UUID
+	if tenantID != nil {
+		if len(input.TenantIDs) > 0 {
+			return nil, errors.New("tenant_ids can only be set on campaigns started by a platform admin")
+		}
+		scope = []uuid.UUID{*tenantID}
+	} else {
+		if len(input.TenantIDs) == 0 && input.RoleID == nil {
+			return nil, errors.New("choose the tenants or the role to review")
+		}
+		seen := make(map[uuid.UUID]bool, len(input.TenantIDs))
+		for _, id := range input.TenantIDs {
+			if !seen[id] {
+				seen[id] = true
+				scope = append(scope, id)
+			}
+		}
+	}
+
+	for _, id := range scope {
+		if _, err := s.tenantRepo.GetByID(id); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("tenant %s not found", id)
+			}
+			return nil, err
+		}
+	}
+
+	if input.RoleID != nil {
+		role, err := s.rbacRepo.GetRoleByID(*input.RoleID)
+		if err != nil {
+			return nil, errors.New("invalid role")
+		}
+		if tenantID != nil && role.TenantID != nil && *role.TenantID != *tenantID {
+			return nil, errors.New("role does not belong to this tenant")
+		}
+	}
+
+	if input.DueAt != nil && !input.DueAt.After(time.Now()) {
+		return nil, errors.New("due_at must be in the future")
+	}
+
+	var reviewers models.StringList
+	seen := make(map[string]bool, len(input.ReviewerIDs))
+	for _, id := range input.ReviewerIDs {
+		id = strings.TrimSpace(id)
+		if id != "" && !seen[id] {
+			seen[id] = true
+			reviewers = append(reviewers, id)
+		}
+	}
+
+	items, err := s.reviewRepo.ListReviewableMembers(scope, input.RoleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list members to review: %w", err)
+	}
+	if len(items) == 0 {
+		return nil, errors.New("there are no memberships to review in this scope")
+	}
+
+	campaign := &models.AccessReviewCampaign{
+		Name:           strings.TrimSpace(input.Name),
+		Description:    input.Description,
+		TenantID:       tenantID,
+		ScopeTenantIDs: scope,
+		RoleID:         input.RoleID,
+		ReviewerIDs:    reviewers,
+		Status:         models.AccessReviewOpen,
+		DueAt:          input.DueAt,
+		CreatedBy:      actorID,
+	}
+
+	if err := s.reviewRepo.CreateCampaign(campaign, items); err != nil {
+		return nil, fmt.Errorf("failed to start access review: %w", err)
+	}
+
+	if err := s.jobClient.EnqueueAccessReviewNotification(campaign.ID); err != nil {
+		fmt.Printf("failed to enqueue access review notification: %v\n", err)
+	}
+
+	return s.withSummary(campaign)
+}
+
+func (s *accessReviewService) ListCampaigns(tenantID *uuid.UUID) ([]*models.AccessReviewCampaign, error) {
+	return s.reviewRepo.ListCampaigns(tenantID)
+}
+
+func (s *accessReviewService) GetCampaign(tenantID *uuid.UUID, campaignID uuid.UUID) (*models.AccessReviewCampaignResponse, error) {
+	campaign, err := s.getCampaign(tenantID, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	return s.withSummary(campaign)
+}
+
+func (s *accessReviewService) ListItems(tenantID *uuid.UUID, campaignID uuid.UUID, params *models.AccessReviewItemParams) ([]*models.AccessReviewItem, error) {
+	if _, err := s.getCampaign(tenantID, campaignID); err != nil {
+		return nil, err
+	}
+	return s.reviewRepo.ListItems(campaignID, params.Decision)
+}
+
+// CloseCampaign stops further decisions and removes the members whose own
+// role was revoked. A revoked group role takes the member out of that group,
+// so they lose every role it grants. Undecided items are left as they are. A
+// revocation is skipped when the member no longer holds the role the way it
+// was reviewed, and fails when the change is not allowed, for example because
+// it would remove the tenant owner or its last manager; either way the reason
+// is recorded on the item.
+func (s *accessReviewService) CloseCampaign(tenantID *uuid.UUID, campaignID uuid.UUID, actorID string) (*models.AccessReviewCampaignResponse, error) {
+	campaign, err := s.getCampaign(tenantID, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.Status != models.AccessReviewOpen {
+		return nil, ErrAccessReviewClosed
+	}
+
+	campaign.ClosedBy = &actorID
+	if err := s.reviewRepo.CloseCampaign(campaign); err != nil {
+		return nil, err
+	}
+
+	revoked, err := s.reviewRepo.ListItems(campaign.ID, models.AccessReviewRevoked)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list revoked items: %w", err)
+	}
+
+	for _, item := range revoked {
+		outcome, detail := s.applyRevocation(item)
+		if err := s.reviewRepo.SetOutcome(item.ID, outcome, detail); err != nil {
+			fmt.Printf("failed to record outcome of access review item %s: %v\n", item.ID, err)
+		}
+	}
+
+	return s.withSummary(campaign)
+}
+
+func (s *accessReviewService) applyRevocation(item *models.AccessReviewItem) (models.AccessReviewOutcome, string) {
+	if item.GroupID != nil {
+		return s.applyGroupRevocation(item)
+	}
+
+	member, err := s.memberRepo.GetByID(item.MemberID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.AccessReviewSkipped, "member was already removed"
+		}
+		return models.AccessReviewFailed, err.Error()
+	}
+
+	if member.RoleID != item.RoleID {
+		return models.AccessReviewSkipped, "member's role changed after the campaign started"
+	}
+
+	if err := s.memberService.RemoveMember(member.ID); err != nil {
+		return models.AccessReviewFailed, err.Error()
+	}
+
+	return models.AccessReviewApplied, ""
+}
+
+func (s *accessReviewService) applyGroupRevocation(item *models.AccessReviewItem) (models.AccessReviewOutcome, string) {
+	group, err := s.groupRepo.GetByID(*item.GroupID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.AccessReviewSkipped, "group was deleted"
+		}
+		return models.AccessReviewFailed, err.Error()
+	}
+
+	grants := false
+	for _, role := range group.Roles {
+		if role.ID == item.RoleID {
+			grants = true
+			break
+		}
+	}
+	if !grants {
+		return models.AccessReviewSkipped, "group no longer grants this role"
+	}
+
+	if err := s.groupRepo.RemoveMember(group.ID, item.MemberID); err != nil {
+		if errors.Is(err, repository.ErrGroupMemberNotFound) {
+			return models.AccessReviewSkipped, "member already left the group"
+		}
+		return models.AccessReviewFailed, err.Error()
+	}
+
+	return models.AccessReviewApplied, fmt.Sprintf("removed from the %s group", group.Name)
+}
+
+// ExportReport returns the campaign, its summary and every item as signed
+// JSON
+func (s *accessReviewService) ExportReport(tenantID *uuid.UUID, campaignID uuid.UUID) (*AccessReviewReportFile, error) {
+	if s.signingSecret == "" {
+		return nil, ErrReportSigningDisabled
+	}
+
+	campaign, err := s.getCampaign(tenantID, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := s.reviewRepo.Summarize(campaign.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.reviewRepo.ListItems(campaign.ID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	body, err := json.Marshal(&models.AccessReviewReport{
+		Campaign:    campaign,
+		Summary:     summary,
+		Items:       items,
+		GeneratedAt: now,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode report: %w", err)
+	}
+
+	return &AccessReviewReportFile{
+		Body:      body,
+		Signature: signing.Sign(s.signingSecret, now, body),
+		SignedAt:  now,
+	}, nil
+}
+
+// ListMyItems returns the undecided items the user can review: items of
+// campaigns naming them as a reviewer, and items of campaigns without named
+// reviewers in tenants they manage
+func (s *accessReviewService) ListMyItems(userID string) ([]*models.AccessReviewItem, error) {
+	memberships, err := s.memberRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var managed []uuid.UUID
+	for _, membership := range memberships {
+		if membership.Status != models.MemberStatusActive {
+			continue
+		}
+		manages, err := s.rbacRepo.CheckUserPermission(membership.TenantID, userID,
+			models.TenantManagementService, models.TenantManagementEntity, models.TenantManagementAction)
+		if err != nil {
+			return nil, err
+		}
+		if manages {
+			managed = append(managed, membership.TenantID)
+		}
+	}
+
+	return s.reviewRepo.ListPendingForReviewer(userID, managed)
+}
+
+// DecideItem certifies or revokes an item. Decisions can be changed until the
+// campaign closes; nobody reviews their own access.
+func (s *accessReviewService) DecideItem(itemID uuid.UUID, input *models.DecideAccessReviewInput, actorID string) (*models.AccessReviewItem, error) {
+	item, err := s.reviewRepo.GetItem(itemID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("access review item not found")
+		}
+		return nil, err
+	}
+
+	campaign, err := s.reviewRepo.GetCampaign(item.CampaignID)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.Status != models.AccessReviewOpen {
+		return nil, ErrAccessReviewClosed
+	}
+
+	if item.UserID == actorID {
+		return nil, fmt.Errorf("%w: you cannot review your own access", ErrAccessReviewForbidden)
+	}
+	if err := s.checkReviewer(campaign, item, actorID); err != nil {
+		return nil, err
+	}
+
+	item.Decision = input.Decision
+	item.Comment = input.Comment
+	item.DecidedBy = &actorID
+	if err := s.reviewRepo.Decide(item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// checkReviewer allows the campaign's named reviewers or, when it names none,
+// the managers of the item's tenant and platform admins
+func (s *accessReviewService) checkReviewer(campaign *models.AccessReviewCampaign, item *models.AccessReviewItem, actorID string) error {
+	if len(campaign.ReviewerIDs) > 0 {
+		if campaign.IsReviewer(actorID) {
+			return nil
+		}
+		return ErrAccessReviewForbidden
+	}
+
+	if isAdmin, err := s.platformAdminRepo.IsPlatformAdmin(actorID); err == nil && isAdmin {
+		return nil
+	}
+
+	manages, err := s.rbacRepo.CheckUserPermission(item.TenantID, actorID,
+		models.TenantManagementService, models.TenantManagementEntity, models.TenantManagementAction)
+	if err != nil {
+		return err
+	}
+	if !manages {
+		return ErrAccessReviewForbidden
+	}
+	return nil
+}
+
+// getCampaign loads the campaign, limited to the ones tenantID started when
+// it is set
+func (s *accessReviewService) getCampaign(tenantID *uuid.UUID, campaignID uuid.UUID) (*models.AccessReviewCampaign, error) {
+	campaign, err := s.reviewRepo.GetCampaign(campaignID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("access review campaign not found")
+		}
+		return nil, err
+	}
+
+	if tenantID != nil && (campaign.TenantID == nil || *campaign.TenantID != *tenantID) {
+		return nil, errors.New("access review campaign not found")
+	}
+
+	return campaign, nil
+}
+
+func (s *accessReviewService) withSummary(campaign *models.AccessReviewCampaign) (*models.AccessReviewCampaignResponse, error) {
+	summary, err := s.reviewRepo.Summarize(campaign.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.AccessReviewCampaignResponse{
+		AccessReviewCampaign: campaign,
+		Summary:              summary,
+	}, nil
+}
@@ -0,0 +1,279 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/repository"
+	"gorm.io/gorm"
+)
+
+type FeatureFlagService interface {
+	// Flag management
+	CreateFlag(input *models.CreateFeatureFlagInput, actorID string) (*models.FeatureFlag, error)
+	GetFlag(id uuid.UUID) (*models.FeatureFlag, error)
+	ListFlags() ([]*models.FeatureFlag, error)
+	UpdateFlag(id uuid.UUID, input *models.UpdateFeatureFlagInput, actorID string) (*models.FeatureFlag, error)
+	DeleteFlag(id uuid.UUID, actorID string) error
+	KillFlag(id uuid.UUID, reason string, actorID string) (*models.FeatureFlag, error)
+	RestoreFlag(id uuid.UUID, reason string, actorID string) (*models.FeatureFlag, error)
+	ListAuditLogs(id uuid.UUID, pagination *models.PaginationParams) ([]*models.FeatureFlagAuditLog, int64, error)
+
+	// Evaluation
+	Evaluate(tenantID uuid.UUID, userID string) (map[string]*models.FlagEvaluation, error)
+	EvaluateFlag(key string, tenantID uuid.UUID, userID string) (*models.FlagEvaluation, error)
+	IsEnabled(key string, tenantID uuid.UUID, userID string) bool
+}
+
+type featureFlagService struct {
+	flagRepo   repository.FeatureFlagRepository
+	tenantRepo repository.TenantRepository
+}
+
+func NewFeatureFlagService(flagRepo repository.FeatureFlagRepository, tenantRepo repository.TenantRepository) FeatureFlagService {
+	return &featureFlagService{
+		flagRepo:   flagRepo,
+		tenantRepo: tenantRepo,
+	}
+}
+
+func (s *featureFlagService) CreateFlag(input *models.CreateFeatureFlagInput, actorID string) (*models.FeatureFlag, error) {
+	key := strings.TrimSpace(input.Key)
+	if _, err := s.flagRepo.GetByKey(key); err == nil {
+		return nil, errors.New("a feature flag with this key already exists")
+	}
+
+	flag := &models.FeatureFlag{
+		ID:        uuid.New(),
+		Key:       key,
+		CreatedBy: actorID,
+	}
+	if err := applyFlagDefinition(flag, &input.FeatureFlagInput); err != nil {
+		return nil, err
+	}
+	flag.UpdatedBy = actorID
+
+	entry := &models.FeatureFlagAuditLog{
+		FlagID:  flag.ID,
+		FlagKey: flag.Key,
+		Action:  models.FeatureFlagAuditCreated,
+		After:   flag.Snapshot(),
+		ActorID: actorID,
+	}
+	if err := s.flagRepo.Create(flag, entry); err != nil {
+		return nil, fmt.Errorf("failed to create feature flag: %w", err)
+	}
+
+	return flag, nil
+}
+
+func (s *featureFlagService) GetFlag(id uuid.UUID) (*models.FeatureFlag, error) {
+	flag, err := s.flagRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("feature flag not found")
+		}
+		return nil, err
+	}
+	return flag, nil
+}
+
+func (s *featureFlagService) ListFlags() ([]*models.FeatureFlag, error) {
+	return s.flagRepo.List()
+}
+
+func (s *featureFlagService) UpdateFlag(id uuid.UUID, input *models.UpdateFeatureFlagInput, actorID string) (*models.FeatureFlag, error) {
+	flag, err := s.GetFlag(id)
+	if err != nil {
+		return nil, err
+	}
+	before := flag.Snapshot()
+
+	if err := applyFlagDefinition(flag, &input.FeatureFlagInput); err != nil {
+		return nil, err
+	}
+
+	if err := s.saveChange(flag, models.FeatureFlagAuditUpdated, input.Reason, before, actorID); err != nil {
+		return nil, err
+	}
+	return flag, nil
+}
+
+func (s *featureFlagService) DeleteFlag(id uuid.UUID, actorID string) error {
+	flag, err := s.GetFlag(id)
+	if err != nil {
+		return err
+	}
+
+	entry := &models.FeatureFlagAuditLog{
+		FlagID:  flag.ID,
+		FlagKey: flag.Key,
+		Action:  models.FeatureFlagAuditDeleted,
+		Before:  flag.Snapshot(),
+		ActorID: actorID,
+	}
+	return s.flagRepo.Delete(flag, entry)
+}
+
+// KillFlag makes the flag serve its off variation to everyone until restored
+func (s *featureFlagService) KillFlag(id uuid.UUID, reason string, actorID string) (*models.FeatureFlag, error) {
+	flag, err := s.GetFlag(id)
+	if err != nil {
+		return nil, err
+	}
+	if flag.Killed {
+		return nil, errors.New("feature flag is already killed")
+	}
+	before := flag.Snapshot()
+
+	now := time.Now()
+	flag.Killed = true
+	flag.KilledAt = &now
+
+	if err := s.saveChange(flag, models.FeatureFlagAuditKilled, reason, before, actorID); err != nil {
+		return nil, err
+	}
+	return flag, nil
+}
+
+// RestoreFlag turns the flag's rules back on after it was killed
+func (s *featureFlagService) RestoreFlag(id uuid.UUID, reason string, actorID string) (*models.FeatureFlag, error) {
+	flag, err := s.GetFlag(id)
+	if err != nil {
+		return nil, err
+	}
+	if !flag.Killed {
+		return nil, errors.New("feature flag is not killed")
+	}
+	before := flag.Snapshot()
+
+	flag.Killed = false
+	flag.KilledAt = nil
+
+	if err := s.saveChange(flag, models.FeatureFlagAuditRestored, reason, before, actorID); err != nil {
+		return nil, err
+	}
+	return flag, nil
+}
+
+func (s *featureFlagService) ListAuditLogs(id uuid.UUID, pagination *models.PaginationParams) ([]*models.FeatureFlagAuditLog, int64, error) {
+	return s.flagRepo.ListAuditLogs(id, pagination)
+}
+
+// Evaluate returns every flag's value for the user in the tenant, keyed by flag key
+func (s *featureFlagService) Evaluate(tenantID uuid.UUID, userID string) (map[string]*models.FlagEvaluation, error) {
+	ctx, err := s.evaluationContext(tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	flags, err := s.flagRepo.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load feature flags: %w", err)
+	}
+
+	evaluations := make(map[string]*models.FlagEvaluation, len(flags))
+	for _, flag := range flags {
+		evaluations[flag.Key] = flag.Evaluate(ctx)
+	}
+	return evaluations, nil
+}
+
+func (s *featureFlagService) EvaluateFlag(key string, tenantID uuid.UUID, userID string) (*models.FlagEvaluation, error) {
+	flag, err := s.flagRepo.GetByKey(key)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("feature flag not found")
+		}
+		return nil, err
+	}
+
+	ctx, err := s.evaluationContext(tenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return flag.Evaluate(ctx), nil
+}
+
+// IsEnabled reports whether a boolean flag is on. Unknown flags and lookup
+// errors count as off so callers can guard code paths without error handling.
+func (s *featureFlagService) IsEnabled(key string, tenantID uuid.UUID, userID string) bool {
+	evaluation, err := s.EvaluateFlag(key, tenantID, userID)
+	if err != nil {
+		return false
+	}
+	enabled, _ := evaluation.Value.(bool)
+	return enabled
+}
+
+func (s *featureFlagService) evaluationContext(tenantID uuid.UUID, userID string) (*models.FlagEvaluationContext, error) {
+	tenant, err := s.tenantRepo.GetByID(tenantID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tenant not found")
+		}
+		return nil, err
+	}
+
+	return &models.FlagEvaluationContext{
+		TenantID:       tenant.ID,
+		TenantMetadata: tenant.Metadata,
+		UserID:         userID,
+	}, nil
+}
+
+func (s *featureFlagService) saveChange(flag *models.FeatureFlag, action models.FeatureFlagAuditAction, reason string, before models.JSONMap, actorID string) error {
+	flag.UpdatedBy = actorID
+
+	entry := &models.FeatureFlagAuditLog{
+		FlagID:  flag.ID,
+		FlagKey: flag.Key,
+		Action:  action,
+		Reason:  reason,
+		Before:  before,
+		After:   flag.Snapshot(),
+		ActorID: actorID,
+	}
+	if err := s.flagRepo.Update(flag, entry); err != nil {
+		return fmt.Errorf("failed to update feature flag: %w", err)
+	}
+	return nil
+}
+
+// applyFlagDefinition replaces the flag's type, variations and rules with the
+// input, filling in on/off variations for boolean flags
+func applyFlagDefinition(flag *models.FeatureFlag, input *models.FeatureFlagInput) error {
+	flag.Description = input.Description
+	flag.Type = input.Type
+	if flag.Type == "" {
+		flag.Type = models.FeatureFlagBoolean
+	}
+	flag.Variations = input.Variations
+	flag.Rules = input.Rules
+	flag.DefaultVariation = input.DefaultVariation
+	flag.OffVariation = input.OffVariation
+
+	if flag.Type == models.FeatureFlagBoolean {
+		if len(flag.Variations) == 0 {
+			flag.Variations = models.FlagVariations{
+				{Key: models.FlagVariationOn, Value: true},
+				{Key: models.FlagVariationOff, Value: false},
+			}
+		}
+		if flag.DefaultVariation == "" {
+			flag.DefaultVariation = models.FlagVariationOff
+		}
+		if flag.OffVariation == "" {
+			flag.OffVariation = models.FlagVariationOff
+		}
+	}
+	if flag.Rules == nil {
+		flag.Rules = models.FlagRules{}
+	}
+
+	return flag.Validate()
+}
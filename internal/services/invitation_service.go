@@ -26,12 +26,15 @@ type InvitationService interface {
 }
 
 type invitationService struct {
-	invitationRepo repository.InvitationRepository
-	memberRepo     repository.MemberRepository
-	tenantRepo     repository.TenantRepository
-	rbacRepo       repository.RBACRepository
-	jobClient      jobs.Client
-	cfg            *config.Config
+	invitationRepo    repository.InvitationRepository
+	memberRepo        repository.MemberRepository
+	tenantRepo        repository.TenantRepository
+	rbacRepo          repository.RBACRepository
+	platformAdminRepo repository.PlatformAdminRepository
+	planService       PlanService
+	emailDomains      TenantEmailDomainService
+	jobClient         jobs.Client
+	cfg               *config.Config
 }
 
 func NewInvitationService(
@@ -39,16 +42,22 @@ func NewInvitationService(
 	memberRepo repository.MemberRepository,
 	tenantRepo repository.TenantRepository,
 	rbacRepo repository.RBACRepository,
+	platformAdminRepo repository.PlatformAdminRepository,
+	planService PlanService,
+	emailDomains TenantEmailDomainService,
 	jobClient jobs.Client,
 	cfg *config.Config,
 ) InvitationService {
 	return &invitationService{
-		invitationRepo: invitationRepo,
-		memberRepo:     memberRepo,
-		tenantRepo:     tenantRepo,
-		rbacRepo:       rbacRepo,
-		jobClient:      jobClient,
-		cfg:            cfg,
+		invitationRepo:    invitationRepo,
+		memberRepo:        memberRepo,
+		tenantRepo:        tenantRepo,
+		rbacRepo:          rbacRepo,
+		platformAdminRepo: platformAdminRepo,
+		planService:       planService,
+		emailDomains:      emailDomains,
+		jobClient:         jobClient,
+		cfg:               cfg,
 	}
 }
 
@@ -62,12 +71,29 @@ func (s *invitationService) CreateInvitation(tenantID uuid.UUID, input *models.C
 		return nil, err
 	}
 
+	if err := checkCanInvite(tenant, invitedBy, s.memberRepo, s.platformAdminRepo); err != nil {
+		return nil, err
+	}
+	if err := checkAllowedEmail(tenant, input.Email); err != nil {
+		return nil, err
+	}
+
+	roleID, err := memberRoleID(tenant, input.RoleID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Validate role exists
-	_, err = s.rbacRepo.GetRoleByID(input.RoleID)
+	_, err = s.rbacRepo.GetRoleByID(roleID)
 	if err != nil {
 		return nil, errors.New("invalid role")
 	}
 
+	// The invitee gets the role on acceptance, so the inviter must be able to grant it
+	if err := checkCanGrantRoles(tenant, invitedBy, []uuid.UUID{roleID}, s.rbacRepo, s.platformAdminRepo); err != nil {
+		return nil, err
+	}
+
 	// Check if there's already a pending invitation for this email
 	pendingInvitations, err := s.invitationRepo.GetPendingByEmail(input.Email)
 	if err == nil && len(pendingInvitations) > 0 {
@@ -78,6 +104,12 @@ func (s *invitationService) CreateInvitation(tenantID uuid.UUID, input *models.C
 		}
 	}
 
+	// Check plan limits
+	quota, err := s.planService.QuotaCheck(tenantID, models.QuotaPendingInvitations)
+	if err != nil {
+		return nil, err
+	}
+
 	// Generate invitation token
 	token, err := generateSecureToken()
 	if err != nil {
@@ -89,13 +121,13 @@ func (s *invitationService) CreateInvitation(tenantID uuid.UUID, input *models.C
 		TenantID:  tenant.ID,
 		Email:     input.Email,
 		InvitedBy: invitedBy,
-		RoleID:    input.RoleID,
+		RoleID:    roleID,
 		Token:     token,
 		Status:    models.InvitationStatusPending,
 		ExpiresAt: time.Now().Add(s.cfg.GetInvitationExpiry()),
 	}
 
-	if err := s.invitationRepo.Create(invitation); err != nil {
+	if err := s.invitationRepo.CreateWithinQuota(invitation, quota); err != nil {
 		return nil, fmt.Errorf("failed to create invitation: %w", err)
 	}
 
@@ -169,12 +201,31 @@ func (s *invitationService) AcceptInvitation(token string, userID string, userEm
 		return nil, errors.New("this invitation was sent to a different email address")
 	}
 
+	tenant, err := s.tenantRepo.GetByID(invitation.TenantID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tenant not found")
+		}
+		return nil, err
+	}
+
+	// The tenant's allowed domains may have changed since the invitation was sent
+	if err := checkAllowedEmail(tenant, userEmail); err != nil {
+		return nil, err
+	}
+
 	// Check if user is already a member
 	existing, err := s.memberRepo.GetByTenantAndUser(invitation.TenantID, userID)
 	if err == nil && existing != nil {
 		return nil, errors.New("user is already a member of this tenant")
 	}
 
+	// The member limit may have been reached since the invitation was sent
+	quota, err := s.planService.MemberQuotaCheck(invitation.TenantID, userID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create member
 	member := &models.TenantMember{
 		TenantID:  invitation.TenantID,
@@ -185,7 +236,7 @@ func (s *invitationService) AcceptInvitation(token string, userID string, userEm
 		JoinedAt:  time.Now(),
 	}
 
-	if err := s.memberRepo.Create(member); err != nil {
+	if err := s.memberRepo.CreateWithinQuota(member, quota); err != nil {
 		return nil, fmt.Errorf("failed to create member: %w", err)
 	}
 
@@ -198,8 +249,15 @@ func (s *invitationService) AcceptInvitation(token string, userID string, userEm
 	}
 
 	// If this was a managed tenant creation (first admin), trigger tenant initialization
-	tenant, _ := s.tenantRepo.GetByID(invitation.TenantID)
-	if tenant != nil && tenant.Status == models.TenantStatusPending {
+	if tenant.OwnerID == nil {
+		// The first admin to join a managed tenant becomes its owner
+		if adminRole, err := s.rbacRepo.GetRoleByName("Admin", nil); err == nil && adminRole.ID == invitation.RoleID {
+			if err := s.tenantRepo.SetOwnerIfUnset(tenant.ID, userID); err != nil {
+				fmt.Printf("failed to set tenant owner: %v\n", err)
+			}
+		}
+	}
+	if tenant.Status == models.TenantStatusPending {
 		if err := s.jobClient.EnqueueTenantInitialization(tenant.ID); err != nil {
 			fmt.Printf("failed to enqueue tenant initialization: %v\n", err)
 		}
@@ -224,6 +282,9 @@ func (s *invitationService) CancelInvitation(id uuid.UUID) error {
 	return s.invitationRepo.UpdateStatus(id, models.InvitationStatusCancelled)
 }
 
+// CheckAndAcceptPendingInvitations accepts the user's pending invitations, then
+// joins them to tenants that verified their email domain. Memberships created
+// through an email domain may be pending until a tenant admin approves them.
 func (s *invitationService) CheckAndAcceptPendingInvitations(email string, userID string) ([]*models.TenantMember, error) {
 	// Get all pending invitations for this email
 	invitations, err := s.invitationRepo.GetPendingByEmail(email)
@@ -243,7 +304,12 @@ func (s *invitationService) CheckAndAcceptPendingInvitations(email string, userI
 		}
 	}
 
-	return members, nil
+	domainMembers, err := s.emailDomains.JoinByEmail(userID, email)
+	if err != nil {
+		fmt.Printf("failed to join tenants by email domain: %v\n", err)
+	}
+
+	return append(members, domainMembers...), nil
 }
 
 func generateSecureToken() (string, error) {
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/jobs"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/repository"
+	"gorm.io/gorm"
+)
+
+type MemberBulkService interface {
+	CreateOperation(tenantID uuid.UUID, input *models.MemberBulkOperationInput, actorID string) (*models.MemberBulkOperation, error)
+	ListOperations(tenantID uuid.UUID, pagination *models.PaginationParams) ([]*models.MemberBulkOperation, int64, error)
+	GetOperation(tenantID uuid.UUID, operationID uuid.UUID) (*models.MemberBulkOperation, error)
+}
+
+type memberBulkService struct {
+	bulkRepo          repository.MemberBulkRepository
+	tenantRepo        repository.TenantRepository
+	memberRepo        repository.MemberRepository
+	rbacRepo          repository.RBACRepository
+	platformAdminRepo repository.PlatformAdminRepository
+	jobClient         jobs.Client
+}
+
+func NewMemberBulkService(
+	bulkRepo repository.MemberBulkRepository,
+	tenantRepo repository.TenantRepository,
+	memberRepo repository.MemberRepository,
+	rbacRepo repository.RBACRepository,
+	platformAdminRepo repository.PlatformAdminRepository,
+	jobClient jobs.Client,
+) MemberBulkService {
+	return &memberBulkService{
+		bulkRepo:          bulkRepo,
+		tenantRepo:        tenantRepo,
+		memberRepo:        memberRepo,
+		rbacRepo:          rbacRepo,
+		platformAdminRepo: platformAdminRepo,
+		jobClient:         jobClient,
+	}
+}
+
+// CreateOperation queues a bulk operation. Rows are checked by the job, which
+// records an outcome for each; one operation runs per tenant at a time so
+// their outcomes don't depend on each other. The actor must be allowed to
+// grant every role the rows name, and the job checks this again per row.
+func (s *memberBulkService) CreateOperation(tenantID uuid.UUID, input *models.MemberBulkOperationInput, actorID string) (*models.MemberBulkOperation, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	tenant, err := s.tenantRepo.GetByID(tenantID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tenant not found")
+		}
+		return nil, err
+	}
+
+	if input.Operation == models.MemberBulkAdd {
+		if err := checkCanInvite(tenant, actorID, s.memberRepo, s.platformAdminRepo); err != nil {
+			return nil, err
+		}
+	}
+	if input.Operation != models.MemberBulkRemove {
+		roleIDs, err := s.rowRoleIDs(tenant, input)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkCanGrantRoles(tenant, actorID, roleIDs, s.rbacRepo, s.platformAdminRepo); err != nil {
+			return nil, err
+		}
+	}
+
+	operation := &models.MemberBulkOperation{
+		ID:          uuid.New(),
+		TenantID:    tenantID,
+		Operation:   input.Operation,
+		Status:      models.MemberBulkPending,
+		DryRun:      input.DryRun,
+		Rows:        input.Rows,
+		Results:     models.MemberBulkRowResults{},
+		TotalRows:   len(input.Rows),
+		RequestedBy: actorID,
+	}
+	if err := s.bulkRepo.Create(operation); err != nil {
+		if errors.Is(err, repository.ErrBulkOperationActive) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to create bulk operation: %w", err)
+	}
+
+	if err := s.jobClient.EnqueueMemberBulkOperation(operation.ID); err != nil {
+		fmt.Printf("failed to enqueue member bulk operation: %v\n", err)
+		if failErr := s.bulkRepo.Fail(operation.ID, errors.New("failed to queue bulk operation")); failErr != nil {
+			fmt.Printf("failed to record bulk operation failure: %v\n", failErr)
+		}
+		return nil, fmt.Errorf("failed to queue bulk operation: %w", err)
+	}
+
+	return operation, nil
+}
+
+func (s *memberBulkService) ListOperations(tenantID uuid.UUID, pagination *models.PaginationParams) ([]*models.MemberBulkOperation, int64, error) {
+	return s.bulkRepo.ListByTenant(tenantID, pagination)
+}
+
+func (s *memberBulkService) GetOperation(tenantID uuid.UUID, operationID uuid.UUID) (*models.MemberBulkOperation, error) {
+	operation, err := s.bulkRepo.GetByID(operationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("bulk operation not found")
+		}
+		return nil, err
+	}
+	if operation.TenantID != tenantID {
+		return nil, errors.New("bulk operation not found")
+	}
+	return operation, nil
+}
+
+// rowRoleIDs returns the roles the rows would grant, including the tenant's
+// default role for additions that name none. Roles that can't be found are
+// left to the job, which reports them per row.
+func (s *memberBulkService) rowRoleIDs(tenant *models.Tenant, input *models.MemberBulkOperationInput) ([]uuid.UUID, error) {
+	// Refs keep row order so the first role refused is the first one named
+	var refs []string
+	seenRefs := make(map[string]bool)
+	for _, row := range input.Rows {
+		ref := row.Role
+		if ref == "" && input.Operation == models.MemberBulkAdd && tenant.DefaultRoleID != nil {
+			ref = tenant.DefaultRoleID.String()
+		}
+		if ref != "" && !seenRefs[ref] {
+			seenRefs[ref] = true
+			refs = append(refs, ref)
+		}
+	}
+
+	seen := make(map[uuid.UUID]bool)
+	roleIDs := make([]uuid.UUID, 0, len(refs))
+	for _, ref := range refs {
+		var role *models.Role
+		var err error
+		if id, parseErr := uuid.Parse(ref); parseErr == nil {
+			role, err = s.rbacRepo.GetRoleByID(id)
+		} else {
+			role, err = s.rbacRepo.GetRoleByName(ref, &tenant.ID)
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get role: %w", err)
+		}
+		if role.TenantID != nil && *role.TenantID != tenant.ID {
+			continue
+		}
+		if !seen[role.ID] {
+			seen[role.ID] = true
+			roleIDs = append(roleIDs, role.ID)
+		}
+	}
+	return roleIDs, nil
+}
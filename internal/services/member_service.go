@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/supertokens/supertokens-golang/recipe/session"
 	"github.com/ysaakpr/rex/internal/models"
 	"github.com/ysaakpr/rex/internal/repository"
 	"gorm.io/gorm"
@@ -15,28 +16,37 @@ type MemberService interface {
 	AddMember(tenantID uuid.UUID, input *models.AddMemberInput, invitedBy string) (*models.TenantMember, error)
 	GetMember(tenantID uuid.UUID, userID string) (*models.TenantMember, error)
 	GetTenantMembers(tenantID uuid.UUID, pagination *models.PaginationParams) ([]*models.TenantMember, int64, error)
-	UpdateMember(memberID uuid.UUID, input *models.UpdateMemberInput) (*models.TenantMember, error)
+	UpdateMember(memberID uuid.UUID, input *models.UpdateMemberInput, actorID string) (*models.TenantMember, error)
 	RemoveMember(memberID uuid.UUID) error
-	AssignRolesToMember(memberID uuid.UUID, roleIDs []uuid.UUID) error
+	AssignRolesToMember(memberID uuid.UUID, roleIDs []uuid.UUID, actorID string) error
 	RemoveRoleFromMember(memberID uuid.UUID, roleID uuid.UUID) error
 	GetMemberWithPermissions(memberID uuid.UUID) (*models.TenantMember, error)
+	SuspendMember(memberID uuid.UUID, input *models.SuspendMemberInput, actorID string) (*models.TenantMember, error)
+	ReactivateMember(memberID uuid.UUID, input *models.ReactivateMemberInput, actorID string) (*models.TenantMember, error)
+	GetActivitySummary(tenantID uuid.UUID, params *models.MemberActivityParams) (*models.MemberActivitySummary, error)
 }
 
 type memberService struct {
-	memberRepo repository.MemberRepository
-	tenantRepo repository.TenantRepository
-	rbacRepo   repository.RBACRepository
+	memberRepo        repository.MemberRepository
+	tenantRepo        repository.TenantRepository
+	rbacRepo          repository.RBACRepository
+	platformAdminRepo repository.PlatformAdminRepository
+	planService       PlanService
 }
 
 func NewMemberService(
 	memberRepo repository.MemberRepository,
 	tenantRepo repository.TenantRepository,
 	rbacRepo repository.RBACRepository,
+	platformAdminRepo repository.PlatformAdminRepository,
+	planService PlanService,
 ) MemberService {
 	return &memberService{
-		memberRepo: memberRepo,
-		tenantRepo: tenantRepo,
-		rbacRepo:   rbacRepo,
+		memberRepo:        memberRepo,
+		tenantRepo:        tenantRepo,
+		rbacRepo:          rbacRepo,
+		platformAdminRepo: platformAdminRepo,
+		planService:       planService,
 	}
 }
 
@@ -56,8 +66,20 @@ func (s *memberService) AddMember(tenantID uuid.UUID, input *models.AddMemberInp
 		return nil, errors.New("user is already a member of this tenant")
 	}
 
+	if err := checkCanInvite(tenant, invitedBy, s.memberRepo, s.platformAdminRepo); err != nil {
+		return nil, err
+	}
+	if err := checkAllowedUser(tenant, input.UserID); err != nil {
+		return nil, err
+	}
+
+	roleID, err := memberRoleID(tenant, input.RoleID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Validate role exists
-	role, err := s.rbacRepo.GetRoleByID(input.RoleID)
+	role, err := s.rbacRepo.GetRoleByID(roleID)
 	if err != nil {
 		return nil, errors.New("invalid role")
 	}
@@ -67,17 +89,27 @@ func (s *memberService) AddMember(tenantID uuid.UUID, input *models.AddMemberInp
 		return nil, errors.New("role does not belong to this tenant")
 	}
 
+	if err := checkCanGrantRoles(tenant, invitedBy, []uuid.UUID{role.ID}, s.rbacRepo, s.platformAdminRepo); err != nil {
+		return nil, err
+	}
+
+	// Check plan limits
+	quota, err := s.planService.MemberQuotaCheck(tenantID, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create member
 	member := &models.TenantMember{
 		TenantID:  tenant.ID,
 		UserID:    input.UserID,
-		RoleID:    input.RoleID,
+		RoleID:    roleID,
 		Status:    models.MemberStatusActive,
 		InvitedBy: &invitedBy,
 		JoinedAt:  time.Now(),
 	}
 
-	if err := s.memberRepo.Create(member); err != nil {
+	if err := s.memberRepo.CreateWithinQuota(member, quota); err != nil {
 		return nil, fmt.Errorf("failed to add member: %w", err)
 	}
 
@@ -92,6 +124,13 @@ func (s *memberService) GetMember(tenantID uuid.UUID, userID string) (*models.Te
 		}
 		return nil, err
 	}
+
+	suspensions, err := s.memberRepo.ListSuspensions(member.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load suspension history: %w", err)
+	}
+	member.Suspensions = suspensions
+
 	return member, nil
 }
 
@@ -99,7 +138,7 @@ func (s *memberService) GetTenantMembers(tenantID uuid.UUID, pagination *models.
 	return s.memberRepo.GetByTenantID(tenantID, pagination)
 }
 
-func (s *memberService) UpdateMember(memberID uuid.UUID, input *models.UpdateMemberInput) (*models.TenantMember, error) {
+func (s *memberService) UpdateMember(memberID uuid.UUID, input *models.UpdateMemberInput, actorID string) (*models.TenantMember, error) {
 	member, err := s.memberRepo.GetByID(memberID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -115,10 +154,26 @@ func (s *memberService) UpdateMember(memberID uuid.UUID, input *models.UpdateMem
 		if err != nil {
 			return nil, errors.New("invalid role")
 		}
+		if *input.RoleID != member.RoleID {
+			if err := s.checkCanGrant(member.TenantID, actorID, []uuid.UUID{*input.RoleID}); err != nil {
+				return nil, err
+			}
+		}
 		member.RoleID = *input.RoleID
 	}
 
 	if input.Status != nil {
+		if *input.Status == models.MemberStatusSuspended {
+			return nil, errors.New("use the suspend endpoint to suspend a member")
+		}
+		if member.Status == models.MemberStatusSuspended && *input.Status != member.Status {
+			return nil, errors.New("member is suspended, use the reactivate endpoint")
+		}
+		if *input.Status != models.MemberStatusActive {
+			if err := s.checkNotOwner(member); err != nil {
+				return nil, err
+			}
+		}
 		member.Status = *input.Status
 	}
 
@@ -138,32 +193,228 @@ func (s *memberService) RemoveMember(memberID uuid.UUID) error {
 		return err
 	}
 
-	// Check if this is the last admin (optional business logic)
-	// For now, we'll allow removal
+	if err := s.checkNotOwner(member); err != nil {
+		return err
+	}
 
+	// The repository rejects removing the tenant's last manager
 	return s.memberRepo.Delete(member.ID)
 }
 
-func (s *memberService) AssignRolesToMember(memberID uuid.UUID, roleIDs []uuid.UUID) error {
-	member, err := s.memberRepo.GetByID(memberID)
+// checkCanGrant loads the tenant and checks actorID may grant roleIDs in it
+func (s *memberService) checkCanGrant(tenantID uuid.UUID, actorID string, roleIDs []uuid.UUID) error {
+	tenant, err := s.tenantRepo.GetByID(tenantID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("member not found")
+			return errors.New("tenant not found")
 		}
 		return err
 	}
+	return checkCanGrantRoles(tenant, actorID, roleIDs, s.rbacRepo, s.platformAdminRepo)
+}
+
+// checkNotOwner stops the tenant owner from being removed or deactivated;
+// ownership has to be transferred first
+func (s *memberService) checkNotOwner(member *models.TenantMember) error {
+	tenant, err := s.tenantRepo.GetByID(member.TenantID)
+	if err != nil {
+		return err
+	}
+	if tenant.IsOwnedBy(member.UserID) {
+		return errors.New("the tenant owner cannot be removed or deactivated, transfer ownership first")
+	}
+	return nil
+}
 
-	// Validate all roles exist
-	for _, roleID := range roleIDs {
-		_, err := s.rbacRepo.GetRoleByID(roleID)
+// SuspendMember suspends an active member, recording the reason and the actor.
+// When the user has no other active tenant access their sessions are revoked.
+func (s *memberService) SuspendMember(memberID uuid.UUID, input *models.SuspendMemberInput, actorID string) (*models.TenantMember, error) {
+	member, err := s.memberRepo.GetByID(memberID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("member not found")
+		}
+		return nil, err
+	}
+
+	if member.UserID == actorID {
+		return nil, errors.New("you cannot suspend yourself")
+	}
+	if err := s.checkNotOwner(member); err != nil {
+		return nil, err
+	}
+	if input.ReactivateAt != nil && !input.ReactivateAt.After(time.Now()) {
+		return nil, errors.New("reactivate_at must be in the future")
+	}
+
+	suspension := &models.MemberSuspension{
+		MemberID:     member.ID,
+		TenantID:     member.TenantID,
+		UserID:       member.UserID,
+		Reason:       input.Reason,
+		SuspendedBy:  actorID,
+		SuspendedAt:  time.Now(),
+		ReactivateAt: input.ReactivateAt,
+	}
+	if err := s.memberRepo.Suspend(suspension); err != nil {
+		return nil, err
+	}
+
+	s.revokeSessionsWithoutOtherAccess(member, suspension)
+
+	return s.GetMember(member.TenantID, member.UserID)
+}
+
+// revokeSessionsWithoutOtherAccess revokes the user's sessions unless they are
+// a platform admin or still an active member of another active tenant.
+// Failures are logged; the suspension already stands.
+func (s *memberService) revokeSessionsWithoutOtherAccess(member *models.TenantMember, suspension *models.MemberSuspension) {
+	keep, err := hasAccessElsewhere(s.memberRepo, s.platformAdminRepo, member.UserID, member.TenantID)
+	if err != nil {
+		fmt.Printf("failed to check other access for user %s: %v\n", member.UserID, err)
+		return
+	}
+	if keep {
+		return
+	}
+
+	if _, err := session.RevokeAllSessionsForUser(member.UserID, nil); err != nil {
+		fmt.Printf("failed to revoke sessions for user %s: %v\n", member.UserID, err)
+		return
+	}
+	if err := s.memberRepo.SetSessionsRevoked(suspension.ID); err != nil {
+		fmt.Printf("failed to record session revocation for suspension %s: %v\n", suspension.ID, err)
+	}
+}
+
+// hasAccessElsewhere reports whether the user is a platform admin or an active
+// member of an active tenant other than tenantID, in which case losing access
+// to tenantID must not sign them out
+func hasAccessElsewhere(memberRepo repository.MemberRepository, platformAdminRepo repository.PlatformAdminRepository, userID string, tenantID uuid.UUID) (bool, error) {
+	isAdmin, err := platformAdminRepo.IsPlatformAdmin(userID)
+	if err != nil {
+		return false, err
+	}
+	if isAdmin {
+		return true, nil
+	}
+
+	memberships, err := memberRepo.GetByUserID(userID)
+	if err != nil {
+		return false, err
+	}
+	for _, other := range memberships {
+		if other.TenantID != tenantID && other.Tenant.Status == models.TenantStatusActive {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ReactivateMember lifts a member's suspension, recording the actor and reason
+func (s *memberService) ReactivateMember(memberID uuid.UUID, input *models.ReactivateMemberInput, actorID string) (*models.TenantMember, error) {
+	member, err := s.memberRepo.GetByID(memberID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("member not found")
+		}
+		return nil, err
+	}
+
+	if err := s.memberRepo.Reactivate(member.ID, actorID, input.Reason); err != nil {
+		return nil, err
+	}
+
+	return s.GetMember(member.TenantID, member.UserID)
+}
+
+// GetActivitySummary counts the tenant's active members seen over each
+// activity window and lists those dormant for params.DormantDays
+func (s *memberService) GetActivitySummary(tenantID uuid.UUID, params *models.MemberActivityParams) (*models.MemberActivitySummary, error) {
+	tenant, err := s.tenantRepo.GetByID(tenantID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tenant not found")
+		}
+		return nil, err
+	}
+
+	dormantDays := params.DormantDays
+	if dormantDays == 0 {
+		dormantDays = models.DefaultDormantDays
+	}
+
+	now := time.Now()
+	summary := &models.MemberActivitySummary{
+		TenantID:    tenantID,
+		GeneratedAt: now,
+		DormantDays: dormantDays,
+
+		InactiveMemberDeactivationDays: tenant.SecuritySettings.InactiveMemberDeactivationDays,
+	}
+
+	if summary.TotalMembers, err = s.memberRepo.CountActive(tenantID); err != nil {
+		return nil, fmt.Errorf("failed to count members: %w", err)
+	}
+
+	for _, days := range models.MemberActivityWindows {
+		count, err := s.memberRepo.CountActiveSince(tenantID, now.AddDate(0, 0, -days))
 		if err != nil {
-			return fmt.Errorf("invalid role: %s", roleID)
+			return nil, fmt.Errorf("failed to count active members: %w", err)
+		}
+		summary.Windows = append(summary.Windows, models.MemberActivityWindow{Days: days, ActiveMembers: count})
+	}
+
+	dormant, dormantCount, err := s.memberRepo.ListDormant(tenantID, now.AddDate(0, 0, -dormantDays), models.MaxDormantMembersListed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dormant members: %w", err)
+	}
+	summary.DormantCount = dormantCount
+	summary.DormantMembers = make([]*models.MemberResponse, len(dormant))
+	for i, member := range dormant {
+		summary.DormantMembers[i] = member.ToResponse()
+	}
+
+	return summary, nil
+}
+
+// AssignRolesToMember sets the member's role. A member holds exactly one
+// role, so roleIDs must name one; the change goes through Update and so
+// cannot leave the tenant without a manager.
+func (s *memberService) AssignRolesToMember(memberID uuid.UUID, roleIDs []uuid.UUID, actorID string) error {
+	if len(roleIDs) != 1 {
+		return errors.New("a member holds exactly one role")
+	}
+	roleID := roleIDs[0]
+
+	member, err := s.memberRepo.GetByID(memberID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("member not found")
 		}
+		return err
+	}
+
+	if _, err := s.rbacRepo.GetRoleByID(roleID); err != nil {
+		return fmt.Errorf("invalid role: %s", roleID)
+	}
+	if roleID == member.RoleID {
+		return nil
+	}
+
+	if err := s.checkCanGrant(member.TenantID, actorID, roleIDs); err != nil {
+		return err
 	}
 
-	return s.memberRepo.AssignRoles(member.ID, roleIDs)
+	member.RoleID = roleID
+	if err := s.memberRepo.Update(member); err != nil {
+		return fmt.Errorf("failed to assign role: %w", err)
+	}
+	return nil
 }
 
+// RemoveRoleFromMember is refused because a member must always hold a role;
+// assign the replacement role instead
 func (s *memberService) RemoveRoleFromMember(memberID uuid.UUID, roleID uuid.UUID) error {
 	member, err := s.memberRepo.GetByID(memberID)
 	if err != nil {
@@ -173,9 +424,24 @@ func (s *memberService) RemoveRoleFromMember(memberID uuid.UUID, roleID uuid.UUI
 		return err
 	}
 
-	return s.memberRepo.RemoveRole(member.ID, roleID)
+	if member.RoleID != roleID {
+		return errors.New("member does not hold this role")
+	}
+	return errors.New("a member must keep a role, assign another role instead")
 }
 
 func (s *memberService) GetMemberWithPermissions(memberID uuid.UUID) (*models.TenantMember, error) {
 	return s.memberRepo.GetMemberWithRoles(memberID)
 }
+
+// memberRoleID returns roleID, or the tenant's default role when none was
+// given
+func memberRoleID(tenant *models.Tenant, roleID uuid.UUID) (uuid.UUID, error) {
+	if roleID != uuid.Nil {
+		return roleID, nil
+	}
+	if tenant.DefaultRoleID == nil {
+		return uuid.Nil, errors.New("role_id is required, this tenant has no default role")
+	}
+	return *tenant.DefaultRoleID, nil
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/repository"
+	"gorm.io/gorm"
+)
+
+// MembershipService lets the current user manage their own memberships
+type MembershipService interface {
+	ListMyMemberships(userID string) (*models.MyMemberships, error)
+	LeaveTenant(userID string, tenantID uuid.UUID) error
+	SetDefaultTenant(userID string, tenantID *uuid.UUID) (*models.UserProfile, error)
+}
+
+type membershipService struct {
+	memberRepo  repository.MemberRepository
+	tenantRepo  repository.TenantRepository
+	rbacRepo    repository.RBACRepository
+	profileRepo repository.UserProfileRepository
+}
+
+func NewMembershipService(
+	memberRepo repository.MemberRepository,
+	tenantRepo repository.TenantRepository,
+	rbacRepo repository.RBACRepository,
+	profileRepo repository.UserProfileRepository,
+) MembershipService {
+	return &membershipService{
+		memberRepo:  memberRepo,
+		tenantRepo:  tenantRepo,
+		rbacRepo:    rbacRepo,
+		profileRepo: profileRepo,
+	}
+}
+
+// ListMyMemberships returns the user's active memberships, sorted by tenant
+// name, with their effective permissions in each tenant
+func (s *membershipService) ListMyMemberships(userID string) (*models.MyMemberships, error) {
+	members, err := s.memberRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list memberships: %w", err)
+	}
+
+	profile, err := s.profileRepo.Get(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load profile: %w", err)
+	}
+
+	result := &models.MyMemberships{Memberships: []*models.MyMembership{}}
+	for _, member := range members {
+		// Deleted tenants are not preloaded
+		if member.Tenant.ID == uuid.Nil {
+			continue
+		}
+
+		permissions, err := s.rbacRepo.GetUserPermissions(member.TenantID, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load permissions: %w", err)
+		}
+		keys := make([]string, len(permissions))
+		for i, permission := range permissions {
+			keys[i] = permission.GetKey()
+		}
+		sort.Strings(keys)
+
+		membership := &models.MyMembership{
+			MemberID: member.ID,
+			Tenant: models.MembershipTenant{
+				ID:     member.Tenant.ID,
+				Name:   member.Tenant.Name,
+				Slug:   member.Tenant.Slug,
+				Status: member.Tenant.Status,
+			},
+			Permissions:  keys,
+			IsOwner:      member.Tenant.IsOwnedBy(userID),
+			JoinedAt:     member.JoinedAt,
+			LastActiveAt: member.LastActiveAt,
+		}
+		if member.Role.ID != uuid.Nil {
+			membership.Role = member.Role.ToResponse()
+		}
+		if profile.DefaultTenantID != nil && *profile.DefaultTenantID == member.TenantID {
+			membership.IsDefault = true
+			result.DefaultTenantID = profile.DefaultTenantID
+		}
+
+		result.Memberships = append(result.Memberships, membership)
+	}
+
+	sort.SliceStable(result.Memberships, func(i, j int) bool {
+		return result.Memberships[i].Tenant.Name < result.Memberships[j].Tenant.Name
+	})
+
+	return result, nil
+}
+
+// LeaveTenant removes the user's own membership. The owner must transfer
+// ownership first, and the tenant's last manager cannot leave.
+func (s *membershipService) LeaveTenant(userID string, tenantID uuid.UUID) error {
+	member, err := s.memberRepo.GetByTenantAndUser(tenantID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("you are not a member of this tenant")
+		}
+		return err
+	}
+
+	tenant, err := s.tenantRepo.GetByID(tenantID)
+	if err != nil {
+		return err
+	}
+	if tenant.IsOwnedBy(userID) {
+		return errors.New("the tenant owner cannot leave, transfer ownership first")
+	}
+
+	if err := s.memberRepo.Delete(member.ID); err != nil {
+		return err
+	}
+
+	// The membership is gone either way; a stale default is ignored when
+	// listing memberships
+	profile, err := s.profileRepo.Get(userID)
+	if err == nil && profile.DefaultTenantID != nil && *profile.DefaultTenantID == tenantID {
+		if err := s.profileRepo.SetDefaultTenant(userID, nil); err != nil {
+			fmt.Printf("failed to clear default tenant for user %s: %v\n", userID, err)
+		}
+	}
+	return nil
+}
+
+// SetDefaultTenant sets the tenant the user's frontend opens first. The user
+// must be an active member of it; nil clears the default.
+func (s *membershipService) SetDefaultTenant(userID string, tenantID *uuid.UUID) (*models.UserProfile, error) {
+	if tenantID != nil {
+		member, err := s.memberRepo.GetByTenantAndUser(*tenantID, userID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("you are not a member of this tenant")
+			}
+			return nil, err
+		}
+		if member.Status != models.MemberStatusActive {
+			return nil, errors.New("your membership of this tenant is not active")
+		}
+	}
+
+	if err := s.profileRepo.SetDefaultTenant(userID, tenantID); err != nil {
+		return nil, fmt.Errorf("failed to set default tenant: %w", err)
+	}
+	return s.profileRepo.Get(userID)
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/jobs"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/pkg/jsonschema"
+	"github.com/ysaakpr/rex/internal/repository"
+	"gorm.io/gorm"
+)
+
+// MetadataValidationError is returned when tenant metadata does not conform to
+// the active schema. Handlers report Errors through response.ValidationError.
+type MetadataValidationError struct {
+	Errors []jsonschema.FieldError
+}
+
+func (e *MetadataValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, fieldErr := range e.Errors {
+		messages[i] = fieldErr.Error()
+	}
+	return "metadata does not match schema: " + strings.Join(messages, "; ")
+}
+
+type MetadataSchemaService interface {
+	CreateSchema(input *models.CreateMetadataSchemaInput, actorID string) (*models.TenantMetadataSchema, error)
+	ListSchemas(tenantType *string) ([]*models.TenantMetadataSchema, error)
+	GetSchema(id uuid.UUID) (*models.TenantMetadataSchema, error)
+	ActivateSchema(id uuid.UUID, actorID string) (*models.TenantMetadataSchema, error)
+	ValidateMetadata(tenantType *string, metadata models.JSONMap) error
+	StartRevalidation(actorID string) (*models.MetadataValidationRun, error)
+	ListRevalidationRuns(pagination *models.PaginationParams) ([]*models.MetadataValidationRun, int64, error)
+	GetRevalidationRun(id uuid.UUID) (*models.MetadataValidationRun, error)
+}
+
+type metadataSchemaService struct {
+	schemaRepo repository.MetadataSchemaRepository
+	jobClient  jobs.Client
+}
+
+func NewMetadataSchemaService(schemaRepo repository.MetadataSchemaRepository, jobClient jobs.Client) MetadataSchemaService {
+	return &metadataSchemaService{
+		schemaRepo: schemaRepo,
+		jobClient:  jobClient,
+	}
+}
+
+// CreateSchema registers a new schema version, makes it active and starts a
+// background re-validation of existing tenants against it
+func (s *metadataSchemaService) CreateSchema(input *models.CreateMetadataSchemaInput, actorID string) (*models.TenantMetadataSchema, error) {
+	if _, err := jsonschema.Compile(input.Schema); err != nil {
+		return nil, err
+	}
+
+	schema := &models.TenantMetadataSchema{
+		TenantType:  input.TenantType,
+		Schema:      input.Schema,
+		Description: input.Description,
+		CreatedBy:   actorID,
+	}
+
+	if err := s.schemaRepo.CreateVersion(schema); err != nil {
+		return nil, fmt.Errorf("failed to create metadata schema: %w", err)
+	}
+
+	s.revalidateInBackground(actorID)
+
+	return schema, nil
+}
+
+func (s *metadataSchemaService) ListSchemas(tenantType *string) ([]*models.TenantMetadataSchema, error) {
+	return s.schemaRepo.List(tenantType)
+}
+
+func (s *metadataSchemaService) GetSchema(id uuid.UUID) (*models.TenantMetadataSchema, error) {
+	schema, err := s.schemaRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("metadata schema not found")
+		}
+		return nil, err
+	}
+	return schema, nil
+}
+
+// ActivateSchema makes an earlier version active again, e.g. to roll back
+func (s *metadataSchemaService) ActivateSchema(id uuid.UUID, actorID string) (*models.TenantMetadataSchema, error) {
+	schema, err := s.GetSchema(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if schema.IsActive {
+		return schema, nil
+	}
+
+	if err := s.schemaRepo.Activate(id); err != nil {
+		return nil, fmt.Errorf("failed to activate metadata schema: %w", err)
+	}
+	schema.IsActive = true
+
+	s.revalidateInBackground(actorID)
+
+	return schema, nil
+}
+
+// ValidateMetadata checks metadata against the active schema for tenantType. It
+// returns a *MetadataValidationError when the metadata does not conform, and nil
+// when it does or no schema has been registered.
+func (s *metadataSchemaService) ValidateMetadata(tenantType *string, metadata models.JSONMap) error {
+	schema, err := s.schemaRepo.GetActive(tenantType)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to load metadata schema: %w", err)
+	}
+
+	compiled, err := jsonschema.Compile(schema.Schema)
+	if err != nil {
+		return fmt.Errorf("stored metadata schema %s is invalid: %w", schema.ID, err)
+	}
+
+	if metadata == nil {
+		metadata = models.JSONMap{}
+	}
+
+	if fieldErrors := compiled.Validate(map[string]interface{}(metadata)); len(fieldErrors) > 0 {
+		return &MetadataValidationError{Errors: fieldErrors}
+	}
+
+	return nil
+}
+
+func (s *metadataSchemaService) StartRevalidation(actorID string) (*models.MetadataValidationRun, error) {
+	run := &models.MetadataValidationRun{
+		Status:      models.MetadataValidationRunPending,
+		TriggeredBy: actorID,
+	}
+
+	if err := s.schemaRepo.CreateRun(run); err != nil {
+		return nil, fmt.Errorf("failed to create revalidation run: %w", err)
+	}
+
+	if err := s.jobClient.EnqueueMetadataRevalidation(run.ID); err != nil {
+		return nil, fmt.Errorf("failed to enqueue revalidation: %w", err)
+	}
+
+	return run, nil
+}
+
+func (s *metadataSchemaService) ListRevalidationRuns(pagination *models.PaginationParams) ([]*models.MetadataValidationRun, int64, error) {
+	return s.schemaRepo.ListRuns(pagination)
+}
+
+func (s *metadataSchemaService) GetRevalidationRun(id uuid.UUID) (*models.MetadataValidationRun, error) {
+	run, err := s.schemaRepo.GetRun(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("revalidation run not found")
+		}
+		return nil, err
+	}
+	return run, nil
+}
+
+// revalidateInBackground starts a revalidation after a schema change. Failures
+// are logged; the schema change itself has already been committed.
+func (s *metadataSchemaService) revalidateInBackground(actorID string) {
+	if _, err := s.StartRevalidation(actorID); err != nil {
+		fmt.Printf("failed to start metadata revalidation: %v\n", err)
+	}
+}
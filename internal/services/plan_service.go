@@ -0,0 +1,273 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/repository"
+	"gorm.io/gorm"
+)
+
+type PlanService interface {
+	// Plans
+	CreatePlan(input *models.CreatePlanInput) (*models.Plan, error)
+	GetPlan(id uuid.UUID) (*models.Plan, error)
+	ListPlans() ([]*models.Plan, error)
+	UpdatePlan(id uuid.UUID, input *models.UpdatePlanInput) (*models.Plan, error)
+	DeletePlan(id uuid.UUID) error
+
+	// Tenant entitlements
+	AssignPlan(tenantID uuid.UUID, planID uuid.UUID) (*models.TenantEntitlements, error)
+	GetEntitlements(tenantID uuid.UUID) (*models.TenantEntitlements, error)
+	SetOverride(tenantID uuid.UUID, input *models.SetPlanOverrideInput, actorID string) (*models.TenantEntitlements, error)
+	ClearOverride(tenantID uuid.UUID) error
+	HasFeature(tenantID uuid.UUID, feature string) (bool, error)
+
+	// Enforcement, by repositories that count usage while the tenant is locked
+	QuotaCheck(tenantID uuid.UUID, resource models.QuotaResource) (models.QuotaCheck, error)
+	MemberQuotaCheck(tenantID uuid.UUID, userID string) (models.QuotaCheck, error)
+}
+
+type planService struct {
+	planRepo   repository.PlanRepository
+	tenantRepo repository.TenantRepository
+}
+
+func NewPlanService(planRepo repository.PlanRepository, tenantRepo repository.TenantRepository) PlanService {
+	return &planService{
+		planRepo:   planRepo,
+		tenantRepo: tenantRepo,
+	}
+}
+
+func (s *planService) CreatePlan(input *models.CreatePlanInput) (*models.Plan, error) {
+	name := strings.TrimSpace(input.Name)
+	if _, err := s.planRepo.GetByName(name); err == nil {
+		return nil, errors.New("a plan with this name already exists")
+	}
+
+	plan := &models.Plan{
+		ID:          uuid.New(),
+		Name:        name,
+		Description: input.Description,
+		PlanLimits:  input.PlanLimits,
+		Features:    input.Features,
+		IsDefault:   input.IsDefault,
+	}
+	if plan.Features == nil {
+		plan.Features = models.FeatureSet{}
+	}
+
+	if err := s.planRepo.Create(plan); err != nil {
+		return nil, fmt.Errorf("failed to create plan: %w", err)
+	}
+
+	return plan, nil
+}
+
+func (s *planService) GetPlan(id uuid.UUID) (*models.Plan, error) {
+	plan, err := s.planRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("plan not found")
+		}
+		return nil, err
+	}
+	return plan, nil
+}
+
+func (s *planService) ListPlans() ([]*models.Plan, error) {
+	return s.planRepo.List()
+}
+
+func (s *planService) UpdatePlan(id uuid.UUID, input *models.UpdatePlanInput) (*models.Plan, error) {
+	plan, err := s.GetPlan(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Description != nil {
+		plan.Description = *input.Description
+	}
+	plan.PlanLimits = input.PlanLimits
+	plan.Features = input.Features
+	if plan.Features == nil {
+		plan.Features = models.FeatureSet{}
+	}
+	if input.IsDefault != nil {
+		if plan.IsDefault && !*input.IsDefault {
+			return nil, errors.New("mark another plan as default instead")
+		}
+		plan.IsDefault = *input.IsDefault
+	}
+
+	if err := s.planRepo.Update(plan); err != nil {
+		return nil, fmt.Errorf("failed to update plan: %w", err)
+	}
+
+	return plan, nil
+}
+
+func (s *planService) DeletePlan(id uuid.UUID) error {
+	plan, err := s.GetPlan(id)
+	if err != nil {
+		return err
+	}
+
+	if plan.IsDefault {
+		return errors.New("the default plan cannot be deleted")
+	}
+
+	count, err := s.planRepo.CountTenants(id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("plan is assigned to %d tenants", count)
+	}
+
+	return s.planRepo.Delete(id)
+}
+
+// AssignPlan moves the tenant to the plan. Existing usage above the new limits
+// is kept; only further growth is blocked.
+func (s *planService) AssignPlan(tenantID uuid.UUID, planID uuid.UUID) (*models.TenantEntitlements, error) {
+	if _, err := s.getTenant(tenantID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.GetPlan(planID); err != nil {
+		return nil, err
+	}
+
+	if err := s.planRepo.AssignToTenant(tenantID, planID); err != nil {
+		return nil, fmt.Errorf("failed to assign plan: %w", err)
+	}
+
+	return s.GetEntitlements(tenantID)
+}
+
+// GetEntitlements returns the tenant's plan with its override applied and the
+// current usage. Tenants without a plan use the default plan; without either
+// the tenant is unlimited.
+func (s *planService) GetEntitlements(tenantID uuid.UUID) (*models.TenantEntitlements, error) {
+	tenant, err := s.getTenant(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	var plan *models.Plan
+	if tenant.PlanID != nil {
+		plan, err = s.planRepo.GetByID(*tenant.PlanID)
+	} else {
+		plan, err = s.planRepo.GetDefault()
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to load plan: %w", err)
+	}
+
+	override, err := s.planRepo.GetOverride(tenantID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to load plan override: %w", err)
+	}
+
+	usage, err := s.planRepo.GetUsage(tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count usage: %w", err)
+	}
+
+	return models.NewTenantEntitlements(tenantID, plan, override, *usage), nil
+}
+
+func (s *planService) SetOverride(tenantID uuid.UUID, input *models.SetPlanOverrideInput, actorID string) (*models.TenantEntitlements, error) {
+	if _, err := s.getTenant(tenantID); err != nil {
+		return nil, err
+	}
+
+	override := &models.TenantPlanOverride{
+		TenantID:   tenantID,
+		PlanLimits: input.PlanLimits,
+		Features:   input.Features,
+		Reason:     input.Reason,
+		UpdatedBy:  actorID,
+	}
+	if override.Features == nil {
+		override.Features = models.FeatureSet{}
+	}
+
+	if err := s.planRepo.UpsertOverride(override); err != nil {
+		return nil, fmt.Errorf("failed to save plan override: %w", err)
+	}
+
+	return s.GetEntitlements(tenantID)
+}
+
+func (s *planService) ClearOverride(tenantID uuid.UUID) error {
+	if _, err := s.getTenant(tenantID); err != nil {
+		return err
+	}
+	return s.planRepo.DeleteOverride(tenantID)
+}
+
+func (s *planService) HasFeature(tenantID uuid.UUID, feature string) (bool, error) {
+	entitlements, err := s.GetEntitlements(tenantID)
+	if err != nil {
+		return false, err
+	}
+	return entitlements.HasFeature(feature), nil
+}
+
+// QuotaCheck returns the check that refuses one more of resource, with a
+// models.QuotaExceededError, once the tenant is at its limit. It fails early
+// when the tenant is already at the limit.
+func (s *planService) QuotaCheck(tenantID uuid.UUID, resource models.QuotaResource) (models.QuotaCheck, error) {
+	entitlements, err := s.GetEntitlements(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	check := entitlements.QuotaCheck(resource)
+	if err := check(entitlements.Usage); err != nil {
+		return nil, err
+	}
+	return check, nil
+}
+
+// MemberQuotaCheck is QuotaCheck for adding userID as a member. System users
+// also count against the system user limit.
+func (s *planService) MemberQuotaCheck(tenantID uuid.UUID, userID string) (models.QuotaCheck, error) {
+	entitlements, err := s.GetEntitlements(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := []models.QuotaResource{models.QuotaMembers}
+	if entitlements.Limits.MaxSystemUsers != nil {
+		isSystemUser, err := s.planRepo.IsSystemUser(userID)
+		if err != nil {
+			return nil, err
+		}
+		if isSystemUser {
+			resources = append(resources, models.QuotaSystemUsers)
+		}
+	}
+
+	check := entitlements.QuotaCheck(resources...)
+	if err := check(entitlements.Usage); err != nil {
+		return nil, err
+	}
+	return check, nil
+}
+
+func (s *planService) getTenant(tenantID uuid.UUID) (*models.Tenant, error) {
+	tenant, err := s.tenantRepo.GetByID(tenantID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tenant not found")
+		}
+		return nil, err
+	}
+	return tenant, nil
+}
@@ -0,0 +1,264 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/jobs"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/pkg/signing"
+	"github.com/ysaakpr/rex/internal/repository"
+	"gorm.io/gorm"
+)
+
+type ProvisioningService interface {
+	GetStatus(tenantID uuid.UUID) (*models.TenantProvisioningStatus, error)
+	RetryProvisioning(tenantID uuid.UUID) (*models.TenantProvisioningStatus, error)
+	GetDeprovisioningStatus(tenantID uuid.UUID) (*models.TenantProvisioningStatus, error)
+	RetryDeprovisioning(tenantID uuid.UUID) (*models.TenantProvisioningStatus, error)
+	HandleCallback(input *models.ProvisioningCallbackInput, body []byte, timestamp, signature string) (*models.TenantProvisioningStep, error)
+}
+
+var (
+	// ErrCallbacksDisabled is returned when neither the service nor the platform
+	// has a signing secret, so callbacks cannot be authenticated
+	ErrCallbacksDisabled = errors.New("provisioning callbacks are disabled: no signing secret is configured")
+	// ErrInvalidCallbackSignature is returned for callbacks that fail signature verification
+	ErrInvalidCallbackSignature = errors.New("invalid callback signature")
+)
+
+// callbackSignatureTolerance is how far a callback's signed timestamp may be from now
+const callbackSignatureTolerance = 5 * time.Minute
+
+type provisioningService struct {
+	provisioningRepo repository.ProvisioningRepository
+	tenantRepo       repository.TenantRepository
+	serviceRepo      repository.DownstreamServiceRepository
+	jobClient        jobs.Client
+	signingSecret    string
+}
+
+// NewProvisioningService creates the provisioning service. signingSecret is the
+// platform-wide secret used for services that do not have their own.
+func NewProvisioningService(
+	provisioningRepo repository.ProvisioningRepository,
+	tenantRepo repository.TenantRepository,
+	serviceRepo repository.DownstreamServiceRepository,
+	jobClient jobs.Client,
+	signingSecret string,
+) ProvisioningService {
+	return &provisioningService{
+		provisioningRepo: provisioningRepo,
+		tenantRepo:       tenantRepo,
+		serviceRepo:      serviceRepo,
+		jobClient:        jobClient,
+		signingSecret:    signingSecret,
+	}
+}
+
+// GetStatus reports the tenant status and per-service provisioning progress
+func (s *provisioningService) GetStatus(tenantID uuid.UUID) (*models.TenantProvisioningStatus, error) {
+	tenant, err := s.tenantRepo.GetByID(tenantID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tenant not found")
+		}
+		return nil, err
+	}
+
+	return s.status(tenant, models.ProvisioningOperationProvision, tenant.Status == models.TenantStatusPending)
+}
+
+// RetryProvisioning re-queues initialization for a pending tenant whose
+// automatic retries have been exhausted. Only failed steps are called again.
+func (s *provisioningService) RetryProvisioning(tenantID uuid.UUID) (*models.TenantProvisioningStatus, error) {
+	tenant, err := s.tenantRepo.GetByID(tenantID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tenant not found")
+		}
+		return nil, err
+	}
+
+	if tenant.Status != models.TenantStatusPending {
+		return nil, fmt.Errorf("only pending tenants can be re-provisioned, tenant is %s", tenant.Status)
+	}
+
+	if err := s.provisioningRepo.ResetFailed(tenantID, models.ProvisioningOperationProvision); err != nil {
+		return nil, fmt.Errorf("failed to reset provisioning steps: %w", err)
+	}
+
+	if err := s.jobClient.EnqueueTenantInitialization(tenantID); err != nil {
+		return nil, fmt.Errorf("failed to enqueue tenant initialization: %w", err)
+	}
+
+	return s.status(tenant, models.ProvisioningOperationProvision, true)
+}
+
+// GetDeprovisioningStatus reports per-service teardown progress of a deleted tenant
+func (s *provisioningService) GetDeprovisioningStatus(tenantID uuid.UUID) (*models.TenantProvisioningStatus, error) {
+	tenant, err := s.getDeletedTenant(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.status(tenant, models.ProvisioningOperationDeprovision, tenant.Status == models.TenantStatusDeleting)
+}
+
+// RetryDeprovisioning re-queues teardown for a deleting tenant whose automatic
+// retries have been exhausted. Only failed steps are called again.
+func (s *provisioningService) RetryDeprovisioning(tenantID uuid.UUID) (*models.TenantProvisioningStatus, error) {
+	tenant, err := s.getDeletedTenant(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if tenant.Status != models.TenantStatusDeleting {
+		return nil, fmt.Errorf("only deleting tenants can be deprovisioned, tenant is %s", tenant.Status)
+	}
+
+	if err := s.provisioningRepo.ResetFailed(tenantID, models.ProvisioningOperationDeprovision); err != nil {
+		return nil, fmt.Errorf("failed to reset deprovisioning steps: %w", err)
+	}
+
+	if err := s.jobClient.EnqueueTenantDeprovisioning(tenantID); err != nil {
+		return nil, fmt.Errorf("failed to enqueue tenant deprovisioning: %w", err)
+	}
+
+	return s.status(tenant, models.ProvisioningOperationDeprovision, true)
+}
+
+// HandleCallback verifies and records the outcome a service reports for a step
+// it accepted with 202. The body must be signed with the step's service secret,
+// or the platform secret when the service has none. On success the job is
+// queued again; it runs steps that were waiting on this one and finishes the
+// operation (activates or purges the tenant) once every step has succeeded.
+// On failure the job is queued again after a backoff, until the step's
+// automatic retries are exhausted. Repeated callbacks for a settled step are
+// acknowledged without changing it.
+func (s *provisioningService) HandleCallback(input *models.ProvisioningCallbackInput, body []byte, timestamp, signature string) (*models.TenantProvisioningStep, error) {
+	step, err := s.provisioningRepo.GetByIdempotencyKey(input.IdempotencyKey)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidCallbackSignature
+		}
+		return nil, err
+	}
+
+	secret := s.signingSecret
+	if service, err := s.serviceRepo.GetByName(step.Service); err == nil {
+		secret = service.SecretOr(s.signingSecret)
+	}
+	if secret == "" {
+		return nil, ErrCallbacksDisabled
+	}
+	if err := signing.Verify(secret, timestamp, signature, body, callbackSignatureTolerance); err != nil {
+		return nil, ErrInvalidCallbackSignature
+	}
+
+	stepErr := input.Error
+	if input.Status == models.ProvisioningStepFailed && stepErr == "" {
+		stepErr = "service reported failure"
+	}
+	if input.Status == models.ProvisioningStepSucceeded {
+		stepErr = ""
+	}
+
+	changed, err := s.provisioningRepo.ResolveAccepted(step.ID, input.Status, stepErr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record callback: %w", err)
+	}
+	if !changed {
+		if step.Status == models.ProvisioningStepAccepted || step.Status == input.Status {
+			return step, nil
+		}
+		return nil, fmt.Errorf("step is %s and is not awaiting a callback", step.Status)
+	}
+	step.Status = input.Status
+	step.LastError = stepErr
+
+	if input.Status != models.ProvisioningStepSucceeded {
+		if err := s.retryFailedStep(step); err != nil {
+			return nil, err
+		}
+		return step, nil
+	}
+
+	if step.Operation == models.ProvisioningOperationDeprovision {
+		err = s.jobClient.EnqueueTenantDeprovisioning(step.TenantID)
+	} else {
+		err = s.jobClient.EnqueueTenantInitialization(step.TenantID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to enqueue tenant %s: %w", step.Operation, err)
+	}
+
+	return step, nil
+}
+
+// retryFailedStep queues the step's job again with backoff, leaving the step
+// failed for a manual retry once its automatic retries are exhausted
+func (s *provisioningService) retryFailedStep(step *models.TenantProvisioningStep) error {
+	var queued bool
+	var err error
+	if step.Operation == models.ProvisioningOperationDeprovision {
+		queued, err = s.jobClient.RetryTenantDeprovisioning(step.TenantID, step.Retries())
+	} else {
+		queued, err = s.jobClient.RetryTenantInitialization(step.TenantID, step.Retries())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to retry tenant %s: %w", step.Operation, err)
+	}
+	if !queued {
+		fmt.Printf("Service %s failed %s of tenant %s after %d attempts, not retrying\n",
+			step.Service, step.Operation, step.TenantID, step.Attempts)
+	}
+	return nil
+}
+
+// status loads the tenant's steps for operation. When inProgress is set,
+// enabled services the worker has not reached yet are reported as pending.
+func (s *provisioningService) status(tenant *models.Tenant, operation models.ProvisioningOperation, inProgress bool) (*models.TenantProvisioningStatus, error) {
+	steps, err := s.provisioningRepo.ListByTenant(tenant.ID, operation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s steps: %w", operation, err)
+	}
+
+	if inProgress {
+		registered, err := s.serviceRepo.ListEnabled()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load service registry: %w", err)
+		}
+
+		known := make(map[string]bool, len(steps))
+		for _, step := range steps {
+			known[step.Service] = true
+		}
+		for _, service := range models.ServicesForTenant(registered, tenant) {
+			if !known[service.Name] {
+				steps = append(steps, &models.TenantProvisioningStep{
+					TenantID:       tenant.ID,
+					Service:        service.Name,
+					Operation:      operation,
+					Status:         models.ProvisioningStepPending,
+					IdempotencyKey: models.ProvisioningIdempotencyKey(tenant.ID, operation, service.Name),
+				})
+			}
+		}
+	}
+
+	return models.NewTenantProvisioningStatus(tenant, operation, steps), nil
+}
+
+func (s *provisioningService) getDeletedTenant(tenantID uuid.UUID) (*models.Tenant, error) {
+	tenant, err := s.tenantRepo.GetDeletedByID(tenantID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("deleted tenant not found")
+		}
+		return nil, err
+	}
+	return tenant, nil
+}
@@ -39,6 +39,7 @@ type RBACService interface {
 	// Authorization
 	CheckUserPermission(tenantID uuid.UUID, userID string, service, entity, action string) (bool, error)
 	GetUserPermissions(tenantID uuid.UUID, userID string) ([]*models.Permission, error)
+	ExplainUserPermission(tenantID uuid.UUID, userID string, service, entity, action string) (*models.PermissionExplanation, error)
 
 	// Role-Policy assignments (was Relation-Role)
 	AssignPoliciesToRole(roleID uuid.UUID, policyIDs []uuid.UUID) error
@@ -47,12 +48,14 @@ type RBACService interface {
 }
 
 type rbacService struct {
-	rbacRepo repository.RBACRepository
+	rbacRepo    repository.RBACRepository
+	planService PlanService
 }
 
-func NewRBACService(rbacRepo repository.RBACRepository) RBACService {
+func NewRBACService(rbacRepo repository.RBACRepository, planService PlanService) RBACService {
 	return &rbacService{
-		rbacRepo: rbacRepo,
+		rbacRepo:    rbacRepo,
+		planService: planService,
 	}
 }
 
@@ -72,7 +75,17 @@ func (s *rbacService) CreateRole(input *models.CreateRoleInput) (*models.Role, e
 		IsSystem:    input.TenantID == nil,
 	}
 
-	if err := s.rbacRepo.CreateRole(role); err != nil {
+	// Tenant-specific roles count against the tenant's plan
+	if input.TenantID != nil {
+		var quota models.QuotaCheck
+		if quota, err = s.planService.QuotaCheck(*input.TenantID, models.QuotaCustomRoles); err != nil {
+			return nil, err
+		}
+		err = s.rbacRepo.CreateTenantRole(role, quota)
+	} else {
+		err = s.rbacRepo.CreateRole(role)
+	}
+	if err != nil {
 		return nil, fmt.Errorf("failed to create role: %w", err)
 	}
 
@@ -329,6 +342,27 @@ func (s *rbacService) GetUserPermissions(tenantID uuid.UUID, userID string) ([]*
 	return permissions, nil
 }
 
+// ExplainUserPermission lists the grants that give the user the permission,
+// including roles inherited from groups
+func (s *rbacService) ExplainUserPermission(tenantID uuid.UUID, userID string, service, entity, action string) (*models.PermissionExplanation, error) {
+	grants, err := s.rbacRepo.ExplainUserPermission(tenantID, userID, service, entity, action)
+	if err != nil {
+		return nil, fmt.Errorf("failed to explain user permission: %w", err)
+	}
+	if grants == nil {
+		grants = []*models.PermissionGrant{}
+	}
+
+	permission := models.Permission{Service: service, Entity: entity, Action: action}
+	return &models.PermissionExplanation{
+		TenantID:   tenantID,
+		UserID:     userID,
+		Permission: permission.GetKey(),
+		Authorized: len(grants) > 0,
+		Grants:     grants,
+	}, nil
+}
+
 // Role-Policy assignments (was Relation-Role)
 func (s *rbacService) AssignPoliciesToRole(roleID uuid.UUID, policyIDs []uuid.UUID) error {
 	// Verify role exists
@@ -0,0 +1,282 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/repository"
+	"gorm.io/gorm"
+)
+
+type ServiceRegistryService interface {
+	CreateService(input *models.CreateDownstreamServiceInput, actorID string) (*models.DownstreamService, error)
+	ListServices() ([]*models.DownstreamService, error)
+	GetService(id uuid.UUID) (*models.DownstreamService, error)
+	UpdateService(id uuid.UUID, input *models.UpdateDownstreamServiceInput) (*models.DownstreamService, error)
+	DeleteService(id uuid.UUID) error
+	ImportLegacyServices(baseURLs []string) (int, error)
+}
+
+type serviceRegistryService struct {
+	serviceRepo repository.DownstreamServiceRepository
+}
+
+func NewServiceRegistryService(serviceRepo repository.DownstreamServiceRepository) ServiceRegistryService {
+	return &serviceRegistryService{
+		serviceRepo: serviceRepo,
+	}
+}
+
+func (s *serviceRegistryService) CreateService(input *models.CreateDownstreamServiceInput, actorID string) (*models.DownstreamService, error) {
+	name := strings.TrimSpace(input.Name)
+	if _, err := s.serviceRepo.GetByName(name); err == nil {
+		return nil, errors.New("a service with this name already exists")
+	}
+
+	service := &models.DownstreamService{
+		ID:              uuid.New(),
+		Name:            name,
+		BaseURL:         strings.TrimSuffix(input.BaseURL, "/"),
+		ProvisionPath:   pathOrDefault(input.ProvisionPath, models.DefaultProvisionPath),
+		DeprovisionPath: pathOrDefault(input.DeprovisionPath, models.DefaultDeprovisionPath),
+		HealthPath:      pathOrDefault(input.HealthPath, models.DefaultHealthPath),
+		SigningSecret:   input.SigningSecret,
+		Enabled:         true,
+		Position:        input.Position,
+		HealthStatus:    models.ServiceHealthUnknown,
+		CreatedBy:       actorID,
+	}
+	if input.Enabled != nil {
+		service.Enabled = *input.Enabled
+	}
+
+	dependsOn, err := s.resolveDependencies(service, input.DependsOn)
+	if err != nil {
+		return nil, err
+	}
+	service.DependsOn = dependsOn
+
+	if err := s.serviceRepo.Create(service); err != nil {
+		return nil, fmt.Errorf("failed to register service: %w", err)
+	}
+
+	return service, nil
+}
+
+func (s *serviceRegistryService) ListServices() ([]*models.DownstreamService, error) {
+	return s.serviceRepo.List()
+}
+
+func (s *serviceRegistryService) GetService(id uuid.UUID) (*models.DownstreamService, error) {
+	service, err := s.serviceRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("service not found")
+		}
+		return nil, err
+	}
+	return service, nil
+}
+
+func (s *serviceRegistryService) UpdateService(id uuid.UUID, input *models.UpdateDownstreamServiceInput) (*models.DownstreamService, error) {
+	service, err := s.GetService(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.BaseURL != nil {
+		service.BaseURL = strings.TrimSuffix(*input.BaseURL, "/")
+	}
+	if input.ProvisionPath != nil {
+		service.ProvisionPath = pathOrDefault(*input.ProvisionPath, models.DefaultProvisionPath)
+	}
+	if input.DeprovisionPath != nil {
+		service.DeprovisionPath = pathOrDefault(*input.DeprovisionPath, models.DefaultDeprovisionPath)
+	}
+	if input.HealthPath != nil {
+		service.HealthPath = pathOrDefault(*input.HealthPath, models.DefaultHealthPath)
+	}
+	if input.SigningSecret != nil {
+		service.SigningSecret = *input.SigningSecret
+	}
+	if input.Enabled != nil {
+		service.Enabled = *input.Enabled
+	}
+	if input.Position != nil {
+		service.Position = *input.Position
+	}
+	if input.DependsOn != nil {
+		dependsOn, err := s.resolveDependencies(service, *input.DependsOn)
+		if err != nil {
+			return nil, err
+		}
+		service.DependsOn = dependsOn
+	}
+
+	if err := s.serviceRepo.Update(service); err != nil {
+		return nil, fmt.Errorf("failed to update service: %w", err)
+	}
+
+	return service, nil
+}
+
+// DeleteService removes a service from the registry. Tenants are no longer
+// provisioned in it; provisioning steps already recorded for it are kept.
+func (s *serviceRegistryService) DeleteService(id uuid.UUID) error {
+	if _, err := s.GetService(id); err != nil {
+		return err
+	}
+	return s.serviceRepo.Delete(id)
+}
+
+// ImportLegacyServices registers the base URLs from TENANT_INIT_SERVICES that
+// are not in the registry yet, keeping their configured order. It returns the
+// number of services added.
+func (s *serviceRegistryService) ImportLegacyServices(baseURLs []string) (int, error) {
+	if len(baseURLs) == 0 {
+		return 0, nil
+	}
+
+	existing, err := s.serviceRepo.List()
+	if err != nil {
+		return 0, err
+	}
+
+	known := make(map[string]bool, len(existing))
+	names := make(map[string]bool, len(existing))
+	for _, service := range existing {
+		known[strings.TrimSuffix(service.BaseURL, "/")] = true
+		names[service.Name] = true
+	}
+
+	var added int
+	for i, baseURL := range baseURLs {
+		baseURL = strings.TrimSuffix(baseURL, "/")
+		if known[baseURL] {
+			continue
+		}
+
+		name := legacyServiceName(baseURL)
+		for suffix := 2; names[name]; suffix++ {
+			name = fmt.Sprintf("%s-%d", legacyServiceName(baseURL), suffix)
+		}
+
+		service := &models.DownstreamService{
+			Name:            name,
+			BaseURL:         baseURL,
+			ProvisionPath:   models.DefaultProvisionPath,
+			DeprovisionPath: models.DefaultDeprovisionPath,
+			HealthPath:      models.DefaultHealthPath,
+			Enabled:         true,
+			Position:        i,
+			HealthStatus:    models.ServiceHealthUnknown,
+			CreatedBy:       models.SystemActorID,
+		}
+		if err := s.serviceRepo.Create(service); err != nil {
+			return added, fmt.Errorf("failed to import service %s: %w", baseURL, err)
+		}
+
+		known[baseURL] = true
+		names[name] = true
+		added++
+	}
+
+	return added, nil
+}
+
+// resolveDependencies looks up the named services and rejects self-references,
+// unknown names and cycles
+func (s *serviceRegistryService) resolveDependencies(service *models.DownstreamService, names []string) ([]*models.DownstreamService, error) {
+	unique := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == service.Name {
+			return nil, errors.New("a service cannot depend on itself")
+		}
+		if name != "" && !seen[name] {
+			seen[name] = true
+			unique = append(unique, name)
+		}
+	}
+
+	dependsOn, err := s.serviceRepo.GetByNames(unique)
+	if err != nil {
+		return nil, err
+	}
+	if len(dependsOn) != len(unique) {
+		found := make(map[string]bool, len(dependsOn))
+		for _, dep := range dependsOn {
+			found[dep.Name] = true
+		}
+		for _, name := range unique {
+			if !found[name] {
+				return nil, fmt.Errorf("unknown dependency %q", name)
+			}
+		}
+	}
+
+	all, err := s.serviceRepo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	graph := make(map[string][]string, len(all)+1)
+	for _, other := range all {
+		graph[other.Name] = other.DependencyNames()
+	}
+	graph[service.Name] = unique
+
+	if cycle := findDependencyCycle(graph, service.Name); cycle != nil {
+		return nil, fmt.Errorf("dependency cycle: %s", strings.Join(cycle, " -> "))
+	}
+
+	return dependsOn, nil
+}
+
+// findDependencyCycle returns a dependency path from start back to itself, or nil
+func findDependencyCycle(graph map[string][]string, start string) []string {
+	visited := make(map[string]bool)
+	var path []string
+
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		path = append(path, name)
+		defer func() { path = path[:len(path)-1] }()
+
+		for _, dep := range graph[name] {
+			if dep == start {
+				return append(append([]string{}, path...), start)
+			}
+			if visited[dep] {
+				continue
+			}
+			visited[dep] = true
+			if cycle := visit(dep); cycle != nil {
+				return cycle
+			}
+		}
+		return nil
+	}
+
+	return visit(start)
+}
+
+func pathOrDefault(path, fallback string) string {
+	if path = strings.TrimSpace(path); path == "" {
+		return fallback
+	}
+	return path
+}
+
+// legacyServiceName derives a registry name from a service base URL
+func legacyServiceName(baseURL string) string {
+	if u, err := url.Parse(baseURL); err == nil && u.Hostname() != "" {
+		return u.Hostname()
+	}
+	return baseURL
+}
@@ -0,0 +1,231 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/jobs"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/pkg/users"
+	"github.com/ysaakpr/rex/internal/repository"
+	"gorm.io/gorm"
+)
+
+type TenantAccessRequestService interface {
+	RequestAccess(slug string, userID string, input *models.CreateAccessRequestInput) (*models.TenantAccessRequest, error)
+	ListRequests(tenantID uuid.UUID, params *models.AccessRequestListParams) ([]*models.TenantAccessRequest, error)
+	GetRequest(tenantID uuid.UUID, requestID uuid.UUID) (*models.TenantAccessRequest, error)
+	ApproveRequest(tenantID uuid.UUID, requestID uuid.UUID, input *models.ApproveAccessRequestInput, actorID string) (*models.TenantAccessRequest, error)
+	DenyRequest(tenantID uuid.UUID, requestID uuid.UUID, input *models.DenyAccessRequestInput, actorID string) (*models.TenantAccessRequest, error)
+	ListMyRequests(userID string) ([]*models.TenantAccessRequest, error)
+	CancelMyRequest(requestID uuid.UUID, userID string) (*models.TenantAccessRequest, error)
+}
+
+// accessRequestDenialCooldown is how long a user must wait after a denial
+// before asking the same tenant again
+const accessRequestDenialCooldown = 30 * 24 * time.Hour
+
+type tenantAccessRequestService struct {
+	requestRepo   repository.TenantAccessRequestRepository
+	tenantRepo    repository.TenantRepository
+	memberRepo    repository.MemberRepository
+	memberService MemberService
+	jobClient     jobs.Client
+}
+
+// NewTenantAccessRequestService creates the access request service. Approved
+// requests become memberships through memberService, so they are subject to
+// the same security settings and plan limits as adding a member directly.
+func NewTenantAccessRequestService(
+	requestRepo repository.TenantAccessRequestRepository,
+	tenantRepo repository.TenantRepository,
+	memberRepo repository.MemberRepository,
+	memberService MemberService,
+	jobClient jobs.Client,
+) TenantAccessRequestService {
+	return &tenantAccessRequestService{
+		requestRepo:   requestRepo,
+		tenantRepo:    tenantRepo,
+		memberRepo:    memberRepo,
+		memberService: memberService,
+		jobClient:     jobClient,
+	}
+}
+
+func (s *tenantAccessRequestService) RequestAccess(slug string, userID string, input *models.CreateAccessRequestInput) (*models.TenantAccessRequest, error) {
+	tenant, err := s.tenantRepo.GetBySlug(normalizeSlug(slug))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tenant not found")
+		}
+		return nil, err
+	}
+
+	if tenant.Status != models.TenantStatusActive {
+		return nil, errors.New("tenant is not accepting access requests")
+	}
+
+	if existing, err := s.memberRepo.GetByTenantAndUser(tenant.ID, userID); err == nil && existing != nil {
+		return nil, errors.New("you are already a member of this tenant")
+	}
+
+	if pending, err := s.requestRepo.GetPending(tenant.ID, userID); err == nil && pending != nil {
+		return nil, errors.New("you already have a pending access request for this tenant")
+	}
+
+	if denied, err := s.requestRepo.GetLastDenied(tenant.ID, userID); err == nil && denied.ResolvedAt != nil {
+		if retryAt := denied.ResolvedAt.Add(accessRequestDenialCooldown); time.Now().Before(retryAt) {
+			return nil, fmt.Errorf("your last access request to this tenant was denied, you can ask again after %s",
+				retryAt.Format("January 2, 2006"))
+		}
+	}
+
+	email, err := users.LookupEmail(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up your email: %w", err)
+	}
+
+	// Fail early rather than let an admin approve a request that cannot succeed
+	if err := checkAllowedEmail(tenant, email); err != nil {
+		return nil, err
+	}
+
+	request := &models.TenantAccessRequest{
+		TenantID: tenant.ID,
+		UserID:   userID,
+		Email:    email,
+		Message:  input.Message,
+		Status:   models.AccessRequestPending,
+	}
+
+	if err := s.requestRepo.Create(request); err != nil {
+		return nil, fmt.Errorf("failed to create access request: %w", err)
+	}
+
+	s.notify(request)
+
+	return request, nil
+}
+
+func (s *tenantAccessRequestService) ListRequests(tenantID uuid.UUID, params *models.AccessRequestListParams) ([]*models.TenantAccessRequest, error) {
+	return s.requestRepo.ListByTenant(tenantID, params.Status)
+}
+
+func (s *tenantAccessRequestService) GetRequest(tenantID uuid.UUID, requestID uuid.UUID) (*models.TenantAccessRequest, error) {
+	request, err := s.requestRepo.GetByID(requestID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("access request not found")
+		}
+		return nil, err
+	}
+
+	if request.TenantID != tenantID {
+		return nil, errors.New("access request not found")
+	}
+
+	return request, nil
+}
+
+// ApproveRequest adds the requester as a member with the chosen role, or the
+// tenant's default role, and closes the request
+func (s *tenantAccessRequestService) ApproveRequest(tenantID uuid.UUID, requestID uuid.UUID, input *models.ApproveAccessRequestInput, actorID string) (*models.TenantAccessRequest, error) {
+	request, err := s.getPending(tenantID, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := s.memberService.AddMember(tenantID, &models.AddMemberInput{
+		UserID: request.UserID,
+		RoleID: input.RoleID,
+	}, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Status = models.AccessRequestApproved
+	request.RoleID = &member.RoleID
+	request.ResolvedBy = &actorID
+	if err := s.requestRepo.Resolve(request); err != nil {
+		if errors.Is(err, repository.ErrAccessRequestResolved) {
+			// Cancelled while the membership was being created; the
+			// requester is a member either way
+			fmt.Printf("access request %s was resolved during approval, member %s was still added\n", request.ID, member.ID)
+			return s.requestRepo.GetByID(request.ID)
+		}
+		return nil, fmt.Errorf("failed to approve access request: %w", err)
+	}
+
+	s.notify(request)
+
+	return request, nil
+}
+
+func (s *tenantAccessRequestService) DenyRequest(tenantID uuid.UUID, requestID uuid.UUID, input *models.DenyAccessRequestInput, actorID string) (*models.TenantAccessRequest, error) {
+	request, err := s.getPending(tenantID, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Status = models.AccessRequestDenied
+	request.Reason = input.Reason
+	request.ResolvedBy = &actorID
+	if err := s.requestRepo.Resolve(request); err != nil {
+		return nil, err
+	}
+
+	s.notify(request)
+
+	return request, nil
+}
+
+func (s *tenantAccessRequestService) ListMyRequests(userID string) ([]*models.TenantAccessRequest, error) {
+	return s.requestRepo.ListByUser(userID)
+}
+
+// CancelMyRequest withdraws one of the user's pending requests
+func (s *tenantAccessRequestService) CancelMyRequest(requestID uuid.UUID, userID string) (*models.TenantAccessRequest, error) {
+	request, err := s.requestRepo.GetByID(requestID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("access request not found")
+		}
+		return nil, err
+	}
+
+	if request.UserID != userID {
+		return nil, errors.New("access request not found")
+	}
+	if request.Status != models.AccessRequestPending {
+		return nil, repository.ErrAccessRequestResolved
+	}
+
+	request.Status = models.AccessRequestCancelled
+	request.ResolvedBy = &userID
+	if err := s.requestRepo.Resolve(request); err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+func (s *tenantAccessRequestService) getPending(tenantID uuid.UUID, requestID uuid.UUID) (*models.TenantAccessRequest, error) {
+	request, err := s.GetRequest(tenantID, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if request.Status != models.AccessRequestPending {
+		return nil, repository.ErrAccessRequestResolved
+	}
+	return request, nil
+}
+
+// notify emails the tenant's managers about a new request, or the requester
+// about its outcome
+func (s *tenantAccessRequestService) notify(request *models.TenantAccessRequest) {
+	if err := s.jobClient.EnqueueAccessRequestNotification(request.ID); err != nil {
+		fmt.Printf("failed to enqueue access request notification: %v\n", err)
+	}
+}
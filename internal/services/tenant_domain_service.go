@@ -0,0 +1,250 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/pkg/dnsverify"
+	"github.com/ysaakpr/rex/internal/repository"
+	"gorm.io/gorm"
+)
+
+type TenantDomainService interface {
+	AddDomain(tenantID uuid.UUID, domain string, actorID string) (*models.TenantDomain, error)
+	ListDomains(tenantID uuid.UUID) ([]*models.TenantDomain, error)
+	VerifyDomain(tenantID uuid.UUID, domainID uuid.UUID) (*models.TenantDomain, error)
+	RemoveDomain(tenantID uuid.UUID, domainID uuid.UUID) error
+	VerificationRecord(domain *models.TenantDomain) *models.DomainVerificationRecord
+	ResolveSlug(slug string) (*models.Tenant, error)
+	ResolveHost(host string) (*models.Tenant, error)
+}
+
+type tenantDomainService struct {
+	domainRepo    repository.TenantDomainRepository
+	tenantRepo    repository.TenantRepository
+	verifier      *dnsverify.Verifier
+	baseDomain    string
+	reservedHosts map[string]bool
+}
+
+// NewTenantDomainService creates the tenant domain service. Subdomains of
+// baseDomain resolve to the tenant with the matching slug; reservedHosts (the
+// API and website hosts) never resolve to a tenant.
+func NewTenantDomainService(
+	domainRepo repository.TenantDomainRepository,
+	tenantRepo repository.TenantRepository,
+	verifier *dnsverify.Verifier,
+	baseDomain string,
+	reservedHosts ...string,
+) TenantDomainService {
+	reserved := map[string]bool{"localhost": true}
+	for _, host := range reservedHosts {
+		if h := hostFromURL(host); h != "" {
+			reserved[h] = true
+		}
+	}
+
+	return &tenantDomainService{
+		domainRepo:    domainRepo,
+		tenantRepo:    tenantRepo,
+		verifier:      verifier,
+		baseDomain:    normalizeHost(baseDomain),
+		reservedHosts: reserved,
+	}
+}
+
+func (s *tenantDomainService) AddDomain(tenantID uuid.UUID, domain string, actorID string) (*models.TenantDomain, error) {
+	domain = normalizeHost(domain)
+	if domain == "" {
+		return nil, errors.New("domain is required")
+	}
+
+	if s.reservedHosts[domain] {
+		return nil, errors.New("domain is reserved")
+	}
+	if s.baseDomain != "" && (domain == s.baseDomain || strings.HasSuffix(domain, "."+s.baseDomain)) {
+		return nil, fmt.Errorf("subdomains of %s are assigned by tenant slug and cannot be registered", s.baseDomain)
+	}
+
+	if _, err := s.tenantRepo.GetByID(tenantID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tenant not found")
+		}
+		return nil, err
+	}
+
+	if existing, err := s.domainRepo.GetByTenantAndDomain(tenantID, domain); err == nil && existing != nil {
+		return nil, errors.New("domain is already registered for this tenant")
+	}
+
+	// Unverified claims by other tenants don't block this one; whoever
+	// publishes the TXT record first owns the domain
+	if verified, err := s.domainRepo.GetVerifiedByDomain(domain); err == nil && verified != nil {
+		return nil, errors.New("domain is already registered")
+	}
+
+	tenantDomain := &models.TenantDomain{
+		TenantID:          tenantID,
+		Domain:            domain,
+		VerificationToken: strings.ReplaceAll(uuid.New().String(), "-", ""),
+		CreatedBy:         actorID,
+	}
+
+	if err := s.domainRepo.Create(tenantDomain); err != nil {
+		return nil, fmt.Errorf("failed to add domain: %w", err)
+	}
+
+	return tenantDomain, nil
+}
+
+func (s *tenantDomainService) ListDomains(tenantID uuid.UUID) ([]*models.TenantDomain, error) {
+	return s.domainRepo.ListByTenant(tenantID)
+}
+
+// VerifyDomain checks the domain's TXT record and marks it verified when the
+// expected token is published. The check time is recorded either way.
+func (s *tenantDomainService) VerifyDomain(tenantID uuid.UUID, domainID uuid.UUID) (*models.TenantDomain, error) {
+	tenantDomain, err := s.getTenantDomain(tenantID, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	if tenantDomain.IsVerified() {
+		return tenantDomain, nil
+	}
+
+	if owner, err := s.domainRepo.GetVerifiedByDomain(tenantDomain.Domain); err == nil && owner != nil {
+		return nil, errors.New("domain has already been verified by another tenant")
+	}
+
+	verified, err := s.verifier.Verify(context.Background(), tenantDomain.Domain, tenantDomain.VerificationToken)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	tenantDomain.LastCheckedAt = &now
+	if verified {
+		tenantDomain.VerifiedAt = &now
+	}
+
+	if err := s.domainRepo.Update(tenantDomain); err != nil {
+		return nil, fmt.Errorf("failed to update domain: %w", err)
+	}
+
+	if !verified {
+		record := s.VerificationRecord(tenantDomain)
+		return nil, fmt.Errorf("verification record not found: add a TXT record %s with value %s", record.Name, record.Value)
+	}
+
+	return tenantDomain, nil
+}
+
+func (s *tenantDomainService) RemoveDomain(tenantID uuid.UUID, domainID uuid.UUID) error {
+	if _, err := s.getTenantDomain(tenantID, domainID); err != nil {
+		return err
+	}
+	return s.domainRepo.Delete(domainID)
+}
+
+func (s *tenantDomainService) VerificationRecord(domain *models.TenantDomain) *models.DomainVerificationRecord {
+	return &models.DomainVerificationRecord{
+		Type:  "TXT",
+		Name:  dnsverify.RecordName(domain.Domain),
+		Value: dnsverify.RecordValue(domain.VerificationToken),
+	}
+}
+
+func (s *tenantDomainService) ResolveSlug(slug string) (*models.Tenant, error) {
+	tenant, err := s.tenantRepo.GetBySlug(normalizeSlug(slug))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tenant not found")
+		}
+		return nil, err
+	}
+	return tenant, nil
+}
+
+// ResolveHost maps a request host to a tenant, either as a subdomain of the base
+// domain or as a verified custom domain. It returns nil without an error when the
+// host does not identify a tenant.
+func (s *tenantDomainService) ResolveHost(host string) (*models.Tenant, error) {
+	host = normalizeHost(host)
+	if host == "" || s.reservedHosts[host] || net.ParseIP(host) != nil {
+		return nil, nil
+	}
+
+	if s.baseDomain != "" && strings.HasSuffix(host, "."+s.baseDomain) {
+		slug := strings.TrimSuffix(host, "."+s.baseDomain)
+		if strings.Contains(slug, ".") {
+			return nil, nil
+		}
+
+		tenant, err := s.tenantRepo.GetBySlug(slug)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		return tenant, nil
+	}
+
+	tenantDomain, err := s.domainRepo.GetVerifiedByDomain(host)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	tenant, err := s.tenantRepo.GetByID(tenantDomain.TenantID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return tenant, nil
+}
+
+func (s *tenantDomainService) getTenantDomain(tenantID uuid.UUID, domainID uuid.UUID) (*models.TenantDomain, error) {
+	tenantDomain, err := s.domainRepo.GetByID(domainID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("domain not found")
+		}
+		return nil, err
+	}
+
+	if tenantDomain.TenantID != tenantID {
+		return nil, errors.New("domain not found")
+	}
+
+	return tenantDomain, nil
+}
+
+// normalizeHost lowercases a host and strips any port and trailing dot
+func normalizeHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.TrimSuffix(host, ".")
+}
+
+// hostFromURL returns the normalized host of a URL such as the configured API domain
+func hostFromURL(raw string) string {
+	if u, err := url.Parse(raw); err == nil && u.Host != "" {
+		return normalizeHost(u.Host)
+	}
+	return normalizeHost(raw)
+}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/pkg/dnsverify"
+	"github.com/ysaakpr/rex/internal/repository"
+	"gorm.io/gorm"
+)
+
+// stubResolver answers TXT lookups from records, or fails them all with err
+type stubResolver struct {
+	records map[string][]string
+	err     error
+	lookups []string
+}
+
+func (r *stubResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	r.lookups = append(r.lookups, name)
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.records[name], nil
+}
+
+// fakeDomainRepo keeps domains in memory. Other methods panic through the nil
+// embedded interface.
+type fakeDomainRepo struct {
+	repository.TenantDomainRepository
+	domains []*models.TenantDomain
+	updates int
+}
+
+func (f *fakeDomainRepo) GetByID(id uuid.UUID) (*models.TenantDomain, error) {
+	for _, domain := range f.domains {
+		if domain.ID == id {
+			return domain, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeDomainRepo) GetByTenantAndDomain(tenantID uuid.UUID, name string) (*models.TenantDomain, error) {
+	for _, domain := range f.domains {
+		if domain.TenantID == tenantID && domain.Domain == name {
+			return domain, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeDomainRepo) GetVerifiedByDomain(name string) (*models.TenantDomain, error) {
+	for _, domain := range f.domains {
+		if domain.Domain == name && domain.IsVerified() {
+			return domain, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeDomainRepo) Create(domain *models.TenantDomain) error {
+	domain.ID = uuid.New()
+	f.domains = append(f.domains, domain)
+	return nil
+}
+
+func (f *fakeDomainRepo) Update(domain *models.TenantDomain) error {
+	f.updates++
+	return nil
+}
+
+// domainFixture is a tenant with an unverified claim on example.com
+type domainFixture struct {
+	tenant   *models.Tenant
+	claim    *models.TenantDomain
+	domains  *fakeDomainRepo
+	resolver *stubResolver
+	service  TenantDomainService
+}
+
+func newDomainFixture() *domainFixture {
+	f := &domainFixture{
+		tenant:   &models.Tenant{ID: uuid.New()},
+		resolver: &stubResolver{records: map[string][]string{}},
+	}
+	f.claim = &models.TenantDomain{
+		ID:                uuid.New(),
+		TenantID:          f.tenant.ID,
+		Domain:            "example.com",
+		VerificationToken: "token123",
+	}
+	f.domains = &fakeDomainRepo{domains: []*models.TenantDomain{f.claim}}
+	f.service = NewTenantDomainService(f.domains, &fakeTenantRepo{tenant: f.tenant},
+		dnsverify.NewVerifier(f.resolver), "rex.example.net")
+	return f
+}
+
+func (f *domainFixture) publish(values ...string) {
+	f.resolver.records[dnsverify.RecordName(f.claim.Domain)] = values
+}
+
+func TestVerifyDomainRecordMatches(t *testing.T) {
+	f := newDomainFixture()
+	f.publish("v=spf1 -all", " "+dnsverify.RecordValue("token123")+" ")
+
+	domain, err := f.service.VerifyDomain(f.tenant.ID, f.claim.ID)
+	if err != nil {
+		t.Fatalf("VerifyDomain = %v, want nil", err)
+	}
+	if !domain.IsVerified() || domain.LastCheckedAt == nil {
+		t.Errorf("verified at = %v, last checked at = %v, want both set", domain.VerifiedAt, domain.LastCheckedAt)
+	}
+	if f.domains.updates != 1 {
+		t.Errorf("domain saved %d times, want once", f.domains.updates)
+	}
+	if want := "_rex-verification.example.com"; len(f.resolver.lookups) != 1 || f.resolver.lookups[0] != want {
+		t.Errorf("looked up %v, want [%s]", f.resolver.lookups, want)
+	}
+}
+
+func TestVerifyDomainRecordMismatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		records []string
+	}{
+		{"no record", nil},
+		{"other token", []string{dnsverify.RecordValue("other")}},
+		{"token without prefix", []string{"token123"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newDomainFixture()
+			f.publish(tt.records...)
+
+			_, err := f.service.VerifyDomain(f.tenant.ID, f.claim.ID)
+			if err == nil || !strings.Contains(err.Error(), "verification record not found") {
+				t.Fatalf("VerifyDomain = %v, want a missing record error", err)
+			}
+			if f.claim.IsVerified() {
+				t.Error("domain verified without its record")
+			}
+			if f.claim.LastCheckedAt == nil || f.domains.updates != 1 {
+				t.Errorf("last checked at = %v after %d saves, want the check recorded", f.claim.LastCheckedAt, f.domains.updates)
+			}
+		})
+	}
+}
+
+func TestVerifyDomainLookupError(t *testing.T) {
+	f := newDomainFixture()
+	f.resolver.err = &net.DNSError{Err: "server misbehaving", Name: "_rex-verification.example.com", IsTemporary: true}
+
+	_, err := f.service.VerifyDomain(f.tenant.ID, f.claim.ID)
+	var dnsErr *net.DNSError
+	if !errors.As(err, &dnsErr) {
+		t.Fatalf("VerifyDomain = %v, want the lookup error", err)
+	}
+	if f.claim.IsVerified() || f.claim.LastCheckedAt != nil || f.domains.updates != 0 {
+		t.Errorf("failed lookup changed the domain: verified at = %v, last checked at = %v", f.claim.VerifiedAt, f.claim.LastCheckedAt)
+	}
+
+	// A missing record is not a lookup failure
+	f.resolver.err = &net.DNSError{Err: "no such host", Name: "_rex-verification.example.com", IsNotFound: true}
+	_, err = f.service.VerifyDomain(f.tenant.ID, f.claim.ID)
+	if err == nil || !strings.Contains(err.Error(), "verification record not found") {
+		t.Errorf("VerifyDomain with no such host = %v, want a missing record error", err)
+	}
+}
+
+func TestDomainClaimedByAnotherTenant(t *testing.T) {
+	f := newDomainFixture()
+	now := time.Now()
+	f.domains.domains = append(f.domains.domains, &models.TenantDomain{
+		ID:         uuid.New(),
+		TenantID:   uuid.New(),
+		Domain:     "example.com",
+		VerifiedAt: &now,
+	})
+	f.publish(dnsverify.RecordValue("token123"))
+
+	_, err := f.service.VerifyDomain(f.tenant.ID, f.claim.ID)
+	if err == nil || !strings.Contains(err.Error(), "another tenant") {
+		t.Fatalf("VerifyDomain = %v, want it refused", err)
+	}
+	if f.claim.IsVerified() || len(f.resolver.lookups) != 0 {
+		t.Errorf("claimed domain verified = %v after %d lookups, want it untouched", f.claim.IsVerified(), len(f.resolver.lookups))
+	}
+
+	f.domains.domains = f.domains.domains[1:]
+	if _, err := f.service.AddDomain(f.tenant.ID, "Example.com.", "owner_user"); err == nil {
+		t.Error("AddDomain accepted a domain another tenant has verified")
+	}
+}
@@ -0,0 +1,325 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/pkg/dnsverify"
+	"github.com/ysaakpr/rex/internal/pkg/users"
+	"github.com/ysaakpr/rex/internal/repository"
+	"gorm.io/gorm"
+)
+
+type TenantEmailDomainService interface {
+	AddDomain(tenantID uuid.UUID, input *models.AddTenantEmailDomainInput, actorID string) (*models.TenantEmailDomain, error)
+	ListDomains(tenantID uuid.UUID) ([]*models.TenantEmailDomain, error)
+	UpdateDomain(tenantID uuid.UUID, domainID uuid.UUID, input *models.UpdateTenantEmailDomainInput, actorID string) (*models.TenantEmailDomain, error)
+	VerifyDomain(tenantID uuid.UUID, domainID uuid.UUID) (*models.TenantEmailDomain, error)
+	RemoveDomain(tenantID uuid.UUID, domainID uuid.UUID) error
+	VerificationRecord(domain *models.TenantEmailDomain) *models.DomainVerificationRecord
+	JoinByEmail(userID string, email string) ([]*models.TenantMember, error)
+}
+
+type tenantEmailDomainService struct {
+	domainRepo        repository.TenantEmailDomainRepository
+	tenantRepo        repository.TenantRepository
+	memberRepo        repository.MemberRepository
+	rbacRepo          repository.RBACRepository
+	platformAdminRepo repository.PlatformAdminRepository
+	planService       PlanService
+	verifier          *dnsverify.Verifier
+}
+
+func NewTenantEmailDomainService(
+	domainRepo repository.TenantEmailDomainRepository,
+	tenantRepo repository.TenantRepository,
+	memberRepo repository.MemberRepository,
+	rbacRepo repository.RBACRepository,
+	platformAdminRepo repository.PlatformAdminRepository,
+	planService PlanService,
+	verifier *dnsverify.Verifier,
+) TenantEmailDomainService {
+	return &tenantEmailDomainService{
+		domainRepo:        domainRepo,
+		tenantRepo:        tenantRepo,
+		memberRepo:        memberRepo,
+		rbacRepo:          rbacRepo,
+		platformAdminRepo: platformAdminRepo,
+		planService:       planService,
+		verifier:          verifier,
+	}
+}
+
+func (s *tenantEmailDomainService) AddDomain(tenantID uuid.UUID, input *models.AddTenantEmailDomainInput, actorID string) (*models.TenantEmailDomain, error) {
+	domain := normalizeHost(input.Domain)
+	if domain == "" {
+		return nil, errors.New("domain is required")
+	}
+
+	tenant, err := s.tenantRepo.GetByID(tenantID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tenant not found")
+		}
+		return nil, err
+	}
+
+	existing, err := s.domainRepo.GetByTenantAndDomain(tenantID, domain)
+	if err == nil && existing != nil {
+		return nil, errors.New("email domain is already registered for this tenant")
+	}
+
+	if input.RoleID != nil {
+		if err := s.checkRole(tenantID, *input.RoleID); err != nil {
+			return nil, err
+		}
+		// Joining users get the role, so whoever sets it must be able to grant it
+		if err := checkCanGrantRoles(tenant, actorID, []uuid.UUID{*input.RoleID}, s.rbacRepo, s.platformAdminRepo); err != nil {
+			return nil, err
+		}
+	}
+
+	joinMode := input.JoinMode
+	if joinMode == "" {
+		joinMode = models.EmailDomainJoinApproval
+	}
+
+	emailDomain := &models.TenantEmailDomain{
+		TenantID:          tenantID,
+		Domain:            domain,
+		VerificationToken: strings.ReplaceAll(uuid.New().String(), "-", ""),
+		RoleID:            input.RoleID,
+		JoinMode:          joinMode,
+		CreatedBy:         actorID,
+	}
+
+	if err := s.domainRepo.Create(emailDomain); err != nil {
+		return nil, fmt.Errorf("failed to add email domain: %w", err)
+	}
+
+	return emailDomain, nil
+}
+
+func (s *tenantEmailDomainService) ListDomains(tenantID uuid.UUID) ([]*models.TenantEmailDomain, error) {
+	return s.domainRepo.ListByTenant(tenantID)
+}
+
+func (s *tenantEmailDomainService) UpdateDomain(tenantID uuid.UUID, domainID uuid.UUID, input *models.UpdateTenantEmailDomainInput, actorID string) (*models.TenantEmailDomain, error) {
+	emailDomain, err := s.getEmailDomain(tenantID, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.RoleID != nil {
+		if err := s.checkRole(tenantID, *input.RoleID); err != nil {
+			return nil, err
+		}
+		tenant, err := s.tenantRepo.GetByID(tenantID)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkCanGrantRoles(tenant, actorID, []uuid.UUID{*input.RoleID}, s.rbacRepo, s.platformAdminRepo); err != nil {
+			return nil, err
+		}
+		emailDomain.RoleID = input.RoleID
+	}
+	if input.JoinMode != nil {
+		emailDomain.JoinMode = *input.JoinMode
+	}
+
+	if err := s.domainRepo.Update(emailDomain); err != nil {
+		return nil, fmt.Errorf("failed to update email domain: %w", err)
+	}
+
+	return emailDomain, nil
+}
+
+// VerifyDomain checks the domain's TXT record and marks it verified when the
+// expected token is published. The check time is recorded either way.
+func (s *tenantEmailDomainService) VerifyDomain(tenantID uuid.UUID, domainID uuid.UUID) (*models.TenantEmailDomain, error) {
+	emailDomain, err := s.getEmailDomain(tenantID, domainID)
+	if err != nil {
+		return nil, err
+	}
+
+	if emailDomain.IsVerified() {
+		return emailDomain, nil
+	}
+
+	verified, err := s.verifier.Verify(context.Background(), emailDomain.Domain, emailDomain.VerificationToken)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	emailDomain.LastCheckedAt = &now
+	if verified {
+		emailDomain.VerifiedAt = &now
+	}
+
+	if err := s.domainRepo.Update(emailDomain); err != nil {
+		return nil, fmt.Errorf("failed to update email domain: %w", err)
+	}
+
+	if !verified {
+		record := s.VerificationRecord(emailDomain)
+		return nil, fmt.Errorf("verification record not found: add a TXT record %s with value %s", record.Name, record.Value)
+	}
+
+	return emailDomain, nil
+}
+
+func (s *tenantEmailDomainService) RemoveDomain(tenantID uuid.UUID, domainID uuid.UUID) error {
+	if _, err := s.getEmailDomain(tenantID, domainID); err != nil {
+		return err
+	}
+	return s.domainRepo.Delete(domainID)
+}
+
+func (s *tenantEmailDomainService) VerificationRecord(domain *models.TenantEmailDomain) *models.DomainVerificationRecord {
+	return &models.DomainVerificationRecord{
+		Type:  "TXT",
+		Name:  dnsverify.RecordName(domain.Domain),
+		Value: dnsverify.RecordValue(domain.VerificationToken),
+	}
+}
+
+// JoinByEmail adds the user to every active tenant that has verified the
+// domain of their email address and returns the memberships it created.
+// Tenants in auto mode add the user as an active member, tenants in approval
+// mode add them as pending until an admin activates them. Rex does not verify
+// email/password addresses, so only users vouched for by a third-party
+// provider join automatically; everyone else waits for approval. Users whose
+// membership was removed, including pending members an admin turned down, are
+// not added again; they need an invitation.
+func (s *tenantEmailDomainService) JoinByEmail(userID string, email string) ([]*models.TenantMember, error) {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return nil, nil
+	}
+
+	emailDomains, err := s.domainRepo.ListVerifiedByDomain(strings.ToLower(email[at+1:]))
+	if err != nil {
+		return nil, err
+	}
+	if len(emailDomains) == 0 {
+		return nil, nil
+	}
+
+	emailVerified := false
+	if method, err := users.LookupLoginMethod(userID); err == nil && method != models.LoginMethodPassword {
+		emailVerified = true
+	}
+
+	var members []*models.TenantMember
+	for _, emailDomain := range emailDomains {
+		member, err := s.join(emailDomain, userID, email, emailVerified)
+		if err != nil {
+			fmt.Printf("failed to join tenant %s by email domain %s: %v\n", emailDomain.TenantID, emailDomain.Domain, err)
+			continue
+		}
+		if member != nil {
+			members = append(members, member)
+		}
+	}
+
+	return members, nil
+}
+
+// join adds the user to the email domain's tenant. It returns nil without an
+// error when the tenant is not active, the user already has a membership or
+// the user was removed from the tenant before.
+func (s *tenantEmailDomainService) join(emailDomain *models.TenantEmailDomain, userID string, email string, emailVerified bool) (*models.TenantMember, error) {
+	tenant, err := s.tenantRepo.GetByID(emailDomain.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	if tenant.Status != models.TenantStatusActive {
+		return nil, nil
+	}
+
+	if existing, err := s.memberRepo.GetByTenantAndUser(tenant.ID, userID); err == nil && existing != nil {
+		return nil, nil
+	}
+
+	removed, err := s.memberRepo.WasRemoved(tenant.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if removed {
+		return nil, nil
+	}
+
+	if err := checkAllowedEmail(tenant, email); err != nil {
+		return nil, err
+	}
+
+	var roleID uuid.UUID
+	if emailDomain.RoleID != nil {
+		roleID = *emailDomain.RoleID
+	}
+	roleID, err = memberRoleID(tenant, roleID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkRole(tenant.ID, roleID); err != nil {
+		return nil, err
+	}
+
+	quota, err := s.planService.MemberQuotaCheck(tenant.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := models.MemberStatusPending
+	if emailDomain.JoinMode == models.EmailDomainJoinAuto && emailVerified {
+		status = models.MemberStatusActive
+	}
+
+	member := &models.TenantMember{
+		TenantID: tenant.ID,
+		UserID:   userID,
+		RoleID:   roleID,
+		Status:   status,
+		JoinedAt: time.Now(),
+	}
+
+	if err := s.memberRepo.CreateWithinQuota(member, quota); err != nil {
+		return nil, fmt.Errorf("failed to create member: %w", err)
+	}
+
+	return s.memberRepo.GetByID(member.ID)
+}
+
+// checkRole makes sure the role exists and is system-wide or belongs to the tenant
+func (s *tenantEmailDomainService) checkRole(tenantID uuid.UUID, roleID uuid.UUID) error {
+	role, err := s.rbacRepo.GetRoleByID(roleID)
+	if err != nil {
+		return errors.New("invalid role")
+	}
+	if role.TenantID != nil && *role.TenantID != tenantID {
+		return errors.New("role does not belong to this tenant")
+	}
+	return nil
+}
+
+func (s *tenantEmailDomainService) getEmailDomain(tenantID uuid.UUID, domainID uuid.UUID) (*models.TenantEmailDomain, error) {
+	emailDomain, err := s.domainRepo.GetByID(domainID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("email domain not found")
+		}
+		return nil, err
+	}
+
+	if emailDomain.TenantID != tenantID {
+		return nil, errors.New("email domain not found")
+	}
+
+	return emailDomain, nil
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/config"
+	"github.com/ysaakpr/rex/internal/jobs"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/repository"
+	"gorm.io/gorm"
+)
+
+// ErrExportLinkInvalid is returned for download links that are unknown,
+// expired or replaced by a newer link
+var ErrExportLinkInvalid = errors.New("download link is invalid or has expired")
+
+type TenantExportService interface {
+	RequestExport(tenantID uuid.UUID, actorID string) (*models.TenantExport, error)
+	ListExports(tenantID uuid.UUID, pagination *models.PaginationParams) ([]*models.TenantExport, int64, error)
+	GetExport(tenantID uuid.UUID, exportID uuid.UUID) (*models.TenantExport, error)
+	CreateDownloadLink(tenantID uuid.UUID, exportID uuid.UUID) (*models.TenantExportLink, error)
+	ResolveDownload(token string) (*models.TenantExport, error)
+}
+
+type tenantExportService struct {
+	exportRepo repository.TenantExportRepository
+	tenantRepo repository.TenantRepository
+	jobClient  jobs.Client
+	cfg        *config.Config
+}
+
+func NewTenantExportService(
+	exportRepo repository.TenantExportRepository,
+	tenantRepo repository.TenantRepository,
+	jobClient jobs.Client,
+	cfg *config.Config,
+) TenantExportService {
+	return &tenantExportService{
+		exportRepo: exportRepo,
+		tenantRepo: tenantRepo,
+		jobClient:  jobClient,
+		cfg:        cfg,
+	}
+}
+
+// RequestExport queues an export of the tenant
+func (s *tenantExportService) RequestExport(tenantID uuid.UUID, actorID string) (*models.TenantExport, error) {
+	if _, err := s.tenantRepo.GetByID(tenantID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tenant not found")
+		}
+		return nil, err
+	}
+
+	active, err := s.exportRepo.HasActive(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if active {
+		return nil, errors.New("an export of this tenant is already in progress")
+	}
+
+	export := &models.TenantExport{
+		ID:          uuid.New(),
+		TenantID:    tenantID,
+		Status:      models.TenantExportPending,
+		RequestedBy: actorID,
+	}
+	if err := s.exportRepo.Create(export); err != nil {
+		return nil, fmt.Errorf("failed to create export: %w", err)
+	}
+
+	if err := s.jobClient.EnqueueTenantExport(export.ID); err != nil {
+		fmt.Printf("failed to enqueue tenant export: %v\n", err)
+		if failErr := s.exportRepo.Fail(export.ID, errors.New("failed to queue export")); failErr != nil {
+			fmt.Printf("failed to record export failure: %v\n", failErr)
+		}
+		return nil, fmt.Errorf("failed to queue export: %w", err)
+	}
+
+	return export, nil
+}
+
+func (s *tenantExportService) ListExports(tenantID uuid.UUID, pagination *models.PaginationParams) ([]*models.TenantExport, int64, error) {
+	return s.exportRepo.ListByTenant(tenantID, pagination)
+}
+
+func (s *tenantExportService) GetExport(tenantID uuid.UUID, exportID uuid.UUID) (*models.TenantExport, error) {
+	export, err := s.exportRepo.GetByID(exportID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("export not found")
+		}
+		return nil, err
+	}
+	if export.TenantID != tenantID {
+		return nil, errors.New("export not found")
+	}
+	return export, nil
+}
+
+// CreateDownloadLink issues a link valid for the configured TTL, or until the
+// archive expires if that is sooner. Issuing a link revokes the previous one.
+func (s *tenantExportService) CreateDownloadLink(tenantID uuid.UUID, exportID uuid.UUID) (*models.TenantExportLink, error) {
+	export, err := s.GetExport(tenantID, exportID)
+	if err != nil {
+		return nil, err
+	}
+	if !export.Downloadable() {
+		return nil, fmt.Errorf("export is %s and cannot be downloaded", export.Status)
+	}
+
+	token, err := generateSecureToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	expiresAt := time.Now().Add(s.cfg.GetExportLinkTTL())
+	if export.ExpiresAt != nil && export.ExpiresAt.Before(expiresAt) {
+		expiresAt = *export.ExpiresAt
+	}
+
+	if err := s.exportRepo.SetDownloadToken(export.ID, hashExportToken(token), expiresAt); err != nil {
+		return nil, fmt.Errorf("failed to create download link: %w", err)
+	}
+
+	return &models.TenantExportLink{
+		URL:       s.cfg.GetExportDownloadURL(token),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// ResolveDownload returns the export a download link points to
+func (s *tenantExportService) ResolveDownload(token string) (*models.TenantExport, error) {
+	export, err := s.exportRepo.GetByDownloadTokenHash(hashExportToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrExportLinkInvalid
+		}
+		return nil, err
+	}
+
+	if export.DownloadExpiresAt == nil || time.Now().After(*export.DownloadExpiresAt) || !export.Downloadable() {
+		return nil, ErrExportLinkInvalid
+	}
+	return export, nil
+}
+
+// hashExportToken is how download tokens are stored, so a database leak does
+// not expose working links
+func hashExportToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
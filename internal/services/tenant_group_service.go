@@ -0,0 +1,253 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/repository"
+	"gorm.io/gorm"
+)
+
+type TenantGroupService interface {
+	CreateGroup(tenantID uuid.UUID, input *models.CreateTenantGroupInput, actorID string) (*models.TenantGroup, error)
+	GetGroup(tenantID uuid.UUID, groupID uuid.UUID) (*models.TenantGroup, error)
+	ListGroups(tenantID uuid.UUID, pagination *models.PaginationParams) ([]*models.TenantGroup, int64, error)
+	UpdateGroup(tenantID uuid.UUID, groupID uuid.UUID, input *models.UpdateTenantGroupInput) (*models.TenantGroup, error)
+	DeleteGroup(tenantID uuid.UUID, groupID uuid.UUID) error
+
+	AddMembers(tenantID uuid.UUID, groupID uuid.UUID, input *models.AddGroupMembersInput, actorID string) (*models.TenantGroup, error)
+	RemoveMember(tenantID uuid.UUID, groupID uuid.UUID, userID string) error
+	ListMembers(tenantID uuid.UUID, groupID uuid.UUID, pagination *models.PaginationParams) ([]*models.TenantMember, int64, error)
+
+	AssignRoles(tenantID uuid.UUID, groupID uuid.UUID, roleIDs []uuid.UUID, actorID string) (*models.TenantGroup, error)
+	RevokeRole(tenantID uuid.UUID, groupID uuid.UUID, roleID uuid.UUID) error
+}
+
+type tenantGroupService struct {
+	groupRepo         repository.TenantGroupRepository
+	tenantRepo        repository.TenantRepository
+	memberRepo        repository.MemberRepository
+	rbacRepo          repository.RBACRepository
+	platformAdminRepo repository.PlatformAdminRepository
+}
+
+func NewTenantGroupService(
+	groupRepo repository.TenantGroupRepository,
+	tenantRepo repository.TenantRepository,
+	memberRepo repository.MemberRepository,
+	rbacRepo repository.RBACRepository,
+	platformAdminRepo repository.PlatformAdminRepository,
+) TenantGroupService {
+	return &tenantGroupService{
+		groupRepo:         groupRepo,
+		tenantRepo:        tenantRepo,
+		memberRepo:        memberRepo,
+		rbacRepo:          rbacRepo,
+		platformAdminRepo: platformAdminRepo,
+	}
+}
+
+func (s *tenantGroupService) CreateGroup(tenantID uuid.UUID, input *models.CreateTenantGroupInput, actorID string) (*models.TenantGroup, error) {
+	tenant, err := s.tenantRepo.GetByID(tenantID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tenant not found")
+		}
+		return nil, err
+	}
+
+	if _, err := s.groupRepo.GetByName(tenantID, input.Name); err == nil {
+		return nil, errors.New("a group with this name already exists")
+	}
+
+	if err := s.checkRoles(tenantID, input.RoleIDs); err != nil {
+		return nil, err
+	}
+	if err := checkCanGrantRoles(tenant, actorID, input.RoleIDs, s.rbacRepo, s.platformAdminRepo); err != nil {
+		return nil, err
+	}
+
+	group := &models.TenantGroup{
+		TenantID:    tenantID,
+		Name:        input.Name,
+		Description: input.Description,
+		CreatedBy:   actorID,
+	}
+	if err := s.groupRepo.Create(group, input.RoleIDs); err != nil {
+		return nil, fmt.Errorf("failed to create group: %w", err)
+	}
+
+	return s.groupRepo.GetByID(group.ID)
+}
+
+func (s *tenantGroupService) GetGroup(tenantID uuid.UUID, groupID uuid.UUID) (*models.TenantGroup, error) {
+	group, err := s.groupRepo.GetByID(groupID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("group not found")
+		}
+		return nil, err
+	}
+	if group.TenantID != tenantID {
+		return nil, errors.New("group not found")
+	}
+	return group, nil
+}
+
+func (s *tenantGroupService) ListGroups(tenantID uuid.UUID, pagination *models.PaginationParams) ([]*models.TenantGroup, int64, error) {
+	return s.groupRepo.ListByTenant(tenantID, pagination)
+}
+
+func (s *tenantGroupService) UpdateGroup(tenantID uuid.UUID, groupID uuid.UUID, input *models.UpdateTenantGroupInput) (*models.TenantGroup, error) {
+	group, err := s.GetGroup(tenantID, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Name != nil && *input.Name != group.Name {
+		if _, err := s.groupRepo.GetByName(tenantID, *input.Name); err == nil {
+			return nil, errors.New("a group with this name already exists")
+		}
+		group.Name = *input.Name
+	}
+	if input.Description != nil {
+		group.Description = *input.Description
+	}
+
+	if err := s.groupRepo.Update(group); err != nil {
+		return nil, fmt.Errorf("failed to update group: %w", err)
+	}
+
+	return s.groupRepo.GetByID(groupID)
+}
+
+// DeleteGroup deletes the group; its members lose the roles it granted
+func (s *tenantGroupService) DeleteGroup(tenantID uuid.UUID, groupID uuid.UUID) error {
+	if _, err := s.GetGroup(tenantID, groupID); err != nil {
+		return err
+	}
+	return s.groupRepo.Delete(groupID)
+}
+
+// AddMembers adds tenant members to the group. Every user must already be a
+// member of the tenant; users already in the group are skipped. Joining the
+// group grants its roles, so actorID must be allowed to grant each of them.
+func (s *tenantGroupService) AddMembers(tenantID uuid.UUID, groupID uuid.UUID, input *models.AddGroupMembersInput, actorID string) (*models.TenantGroup, error) {
+	group, err := s.GetGroup(tenantID, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	roleIDs := make([]uuid.UUID, 0, len(group.Roles))
+	for _, role := range group.Roles {
+		roleIDs = append(roleIDs, role.ID)
+	}
+	if err := s.checkCanGrant(tenantID, actorID, roleIDs); err != nil {
+		return nil, err
+	}
+
+	memberIDs := make([]uuid.UUID, 0, len(input.UserIDs))
+	for _, userID := range input.UserIDs {
+		member, err := s.memberRepo.GetByTenantAndUser(tenantID, userID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("user %s is not a member of this tenant", userID)
+			}
+			return nil, err
+		}
+		memberIDs = append(memberIDs, member.ID)
+	}
+
+	if err := s.groupRepo.AddMembers(groupID, memberIDs, actorID); err != nil {
+		return nil, fmt.Errorf("failed to add group members: %w", err)
+	}
+
+	return s.groupRepo.GetByID(groupID)
+}
+
+func (s *tenantGroupService) RemoveMember(tenantID uuid.UUID, groupID uuid.UUID, userID string) error {
+	if _, err := s.GetGroup(tenantID, groupID); err != nil {
+		return err
+	}
+
+	member, err := s.memberRepo.GetByTenantAndUser(tenantID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return repository.ErrGroupMemberNotFound
+		}
+		return err
+	}
+
+	return s.groupRepo.RemoveMember(groupID, member.ID)
+}
+
+func (s *tenantGroupService) ListMembers(tenantID uuid.UUID, groupID uuid.UUID, pagination *models.PaginationParams) ([]*models.TenantMember, int64, error) {
+	if _, err := s.GetGroup(tenantID, groupID); err != nil {
+		return nil, 0, err
+	}
+	return s.groupRepo.ListMembers(groupID, pagination)
+}
+
+// AssignRoles grants roles to the group; its members inherit them
+func (s *tenantGroupService) AssignRoles(tenantID uuid.UUID, groupID uuid.UUID, roleIDs []uuid.UUID, actorID string) (*models.TenantGroup, error) {
+	if _, err := s.GetGroup(tenantID, groupID); err != nil {
+		return nil, err
+	}
+	if err := s.checkRoles(tenantID, roleIDs); err != nil {
+		return nil, err
+	}
+	if err := s.checkCanGrant(tenantID, actorID, roleIDs); err != nil {
+		return nil, err
+	}
+
+	if err := s.groupRepo.AssignRoles(groupID, roleIDs); err != nil {
+		return nil, fmt.Errorf("failed to assign roles to group: %w", err)
+	}
+
+	return s.groupRepo.GetByID(groupID)
+}
+
+func (s *tenantGroupService) RevokeRole(tenantID uuid.UUID, groupID uuid.UUID, roleID uuid.UUID) error {
+	if _, err := s.GetGroup(tenantID, groupID); err != nil {
+		return err
+	}
+	if err := s.groupRepo.RevokeRole(groupID, roleID); err != nil {
+		return fmt.Errorf("failed to revoke role from group: %w", err)
+	}
+	return nil
+}
+
+// checkRoles verifies each role exists and is a system role or one of the
+// tenant's own
+func (s *tenantGroupService) checkRoles(tenantID uuid.UUID, roleIDs []uuid.UUID) error {
+	for _, roleID := range roleIDs {
+		role, err := s.rbacRepo.GetRoleByID(roleID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("role %s not found", roleID)
+			}
+			return fmt.Errorf("failed to verify role: %w", err)
+		}
+		if role.TenantID != nil && *role.TenantID != tenantID {
+			return fmt.Errorf("role %s does not belong to this tenant", roleID)
+		}
+	}
+	return nil
+}
+
+// checkCanGrant loads the tenant and checks actorID may grant roleIDs in it
+func (s *tenantGroupService) checkCanGrant(tenantID uuid.UUID, actorID string, roleIDs []uuid.UUID) error {
+	if len(roleIDs) == 0 {
+		return nil
+	}
+	tenant, err := s.tenantRepo.GetByID(tenantID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("tenant not found")
+		}
+		return err
+	}
+	return checkCanGrantRoles(tenant, actorID, roleIDs, s.rbacRepo, s.platformAdminRepo)
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/repository"
+	"gorm.io/gorm"
+)
+
+type fakeTenantRepo struct {
+	repository.TenantRepository
+	tenant *models.Tenant
+}
+
+func (f *fakeTenantRepo) GetByID(id uuid.UUID) (*models.Tenant, error) {
+	if f.tenant.ID != id {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return f.tenant, nil
+}
+
+type fakeMemberRepo struct {
+	repository.MemberRepository
+	managers []string
+}
+
+func (f *fakeMemberRepo) GetByTenantAndUser(tenantID uuid.UUID, userID string) (*models.TenantMember, error) {
+	return &models.TenantMember{ID: uuid.New(), TenantID: tenantID, UserID: userID}, nil
+}
+
+func (f *fakeMemberRepo) ListManagerUserIDs(tenantID uuid.UUID) ([]string, error) {
+	return f.managers, nil
+}
+
+type fakeGroupRepo struct {
+	repository.TenantGroupRepository
+	group    *models.TenantGroup
+	added    []uuid.UUID
+	assigned []uuid.UUID
+}
+
+func (f *fakeGroupRepo) GetByID(id uuid.UUID) (*models.TenantGroup, error) {
+	if f.group.ID != id {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return f.group, nil
+}
+
+func (f *fakeGroupRepo) AddMembers(groupID uuid.UUID, memberIDs []uuid.UUID, addedBy string) error {
+	f.added = append(f.added, memberIDs...)
+	return nil
+}
+
+func (f *fakeGroupRepo) AssignRoles(groupID uuid.UUID, roleIDs []uuid.UUID) error {
+	f.assigned = append(f.assigned, roleIDs...)
+	return nil
+}
+
+func TestGroupChangesRequireGrantPermission(t *testing.T) {
+	f := newGrantFixture()
+	groups := &fakeGroupRepo{group: &models.TenantGroup{
+		ID:       uuid.New(),
+		TenantID: f.tenant.ID,
+		Roles:    []models.Role{*f.rbac.roles[f.admin]},
+	}}
+	service := NewTenantGroupService(groups, &fakeTenantRepo{tenant: f.tenant}, &fakeMemberRepo{}, f.rbac, f.admins)
+	input := &models.AddGroupMembersInput{UserIDs: []string{"new_user"}}
+
+	_, err := service.AddMembers(f.tenant.ID, groups.group.ID, input, "writer_user")
+	var grantErr *models.RoleGrantError
+	if !errors.As(err, &grantErr) {
+		t.Fatalf("AddMembers to an admin group by a writer = %v, want a RoleGrantError", err)
+	}
+	if len(groups.added) != 0 {
+		t.Errorf("AddMembers added %d members despite the refusal", len(groups.added))
+	}
+
+	if _, err := service.AddMembers(f.tenant.ID, groups.group.ID, input, "admin_user"); err != nil {
+		t.Fatalf("AddMembers by an admin = %v, want nil", err)
+	}
+	if len(groups.added) != 1 {
+		t.Errorf("AddMembers added %d members, want 1", len(groups.added))
+	}
+
+	_, err = service.AssignRoles(f.tenant.ID, groups.group.ID, []uuid.UUID{f.admin}, "writer_user")
+	if !errors.As(err, &grantErr) {
+		t.Fatalf("AssignRoles of admin by a writer = %v, want a RoleGrantError", err)
+	}
+	if len(groups.assigned) != 0 {
+		t.Errorf("AssignRoles assigned %d roles despite the refusal", len(groups.assigned))
+	}
+}
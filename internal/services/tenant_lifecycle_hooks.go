@@ -0,0 +1,51 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/ysaakpr/rex/internal/jobs"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/repository"
+)
+
+// NewSessionRevocationHook revokes the SuperTokens sessions of the members of
+// a suspended tenant, forcing them to sign in again. Platform admins and
+// members of another active tenant keep their sessions, since revocation
+// would sign them out of every tenant.
+func NewSessionRevocationHook(memberRepo repository.MemberRepository, platformAdminRepo repository.PlatformAdminRepository) TenantTransitionHook {
+	return TenantTransitionHookFunc(func(tenant *models.Tenant, transition *models.TenantStatusTransition) error {
+		if transition.ToStatus != models.TenantStatusSuspended {
+			return nil
+		}
+
+		members, err := memberRepo.ListByTenantID(tenant.ID)
+		if err != nil {
+			return fmt.Errorf("failed to list tenant members: %w", err)
+		}
+
+		for _, member := range members {
+			keep, err := hasAccessElsewhere(memberRepo, platformAdminRepo, member.UserID, tenant.ID)
+			if err != nil {
+				fmt.Printf("failed to check other access for user %s: %v\n", member.UserID, err)
+				continue
+			}
+			if keep {
+				continue
+			}
+			if _, err := session.RevokeAllSessionsForUser(member.UserID, nil); err != nil {
+				fmt.Printf("failed to revoke sessions for user %s: %v\n", member.UserID, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// NewTransitionJobHook enqueues the background job that notifies tenant admins
+// and fans the status change out to downstream services.
+func NewTransitionJobHook(jobClient jobs.Client) TenantTransitionHook {
+	return TenantTransitionHookFunc(func(tenant *models.Tenant, transition *models.TenantStatusTransition) error {
+		return jobClient.EnqueueTenantStatusChange(transition.ID)
+	})
+}
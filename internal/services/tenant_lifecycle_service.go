@@ -0,0 +1,205 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/repository"
+	"gorm.io/gorm"
+)
+
+// TenantTransitionHook is notified after a tenant status transition has been committed.
+// Hook errors are logged and never roll back the transition.
+type TenantTransitionHook interface {
+	OnTenantTransition(tenant *models.Tenant, transition *models.TenantStatusTransition) error
+}
+
+// TenantTransitionHookFunc adapts a plain function to TenantTransitionHook
+type TenantTransitionHookFunc func(tenant *models.Tenant, transition *models.TenantStatusTransition) error
+
+func (f TenantTransitionHookFunc) OnTenantTransition(tenant *models.Tenant, transition *models.TenantStatusTransition) error {
+	return f(tenant, transition)
+}
+
+type TenantLifecycleService interface {
+	SuspendTenant(id uuid.UUID, reason string, actorID string) (*models.Tenant, error)
+	ReactivateTenant(id uuid.UUID, reason string, actorID string) (*models.Tenant, error)
+	ArchiveTenant(id uuid.UUID, reason string, actorID string) (*models.Tenant, error)
+	TransitionTenant(id uuid.UUID, to models.TenantStatus, reason string, actorID string) (*models.Tenant, error)
+	GetTransitionHistory(id uuid.UUID) ([]*models.TenantStatusTransition, error)
+	DeleteTenant(id uuid.UUID, reason string, actorID string) error
+	RestoreTenant(id uuid.UUID, reason string, actorID string) (*models.Tenant, error)
+	ListDeletedTenants(pagination *models.PaginationParams) ([]*models.Tenant, int64, error)
+	ListPurgeReports(pagination *models.PaginationParams) ([]*models.TenantPurgeReport, int64, error)
+	Retention() time.Duration
+}
+
+type tenantLifecycleService struct {
+	tenantRepo repository.TenantRepository
+	retention  time.Duration
+	hooks      []TenantTransitionHook
+}
+
+// NewTenantLifecycleService creates the lifecycle service. Deleted tenants can be
+// restored until retention has passed, after which the purge job removes them.
+func NewTenantLifecycleService(tenantRepo repository.TenantRepository, retention time.Duration, hooks ...TenantTransitionHook) TenantLifecycleService {
+	return &tenantLifecycleService{
+		tenantRepo: tenantRepo,
+		retention:  retention,
+		hooks:      hooks,
+	}
+}
+
+func (s *tenantLifecycleService) SuspendTenant(id uuid.UUID, reason string, actorID string) (*models.Tenant, error) {
+	if reason == "" {
+		return nil, errors.New("a reason is required to suspend a tenant")
+	}
+	return s.TransitionTenant(id, models.TenantStatusSuspended, reason, actorID)
+}
+
+func (s *tenantLifecycleService) ReactivateTenant(id uuid.UUID, reason string, actorID string) (*models.Tenant, error) {
+	tenant, err := s.getTenant(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if tenant.Status != models.TenantStatusSuspended && tenant.Status != models.TenantStatusArchived {
+		return nil, fmt.Errorf("only suspended or archived tenants can be reactivated (current status: %s)", tenant.Status)
+	}
+
+	return s.transition(tenant, models.TenantStatusActive, reason, actorID)
+}
+
+func (s *tenantLifecycleService) ArchiveTenant(id uuid.UUID, reason string, actorID string) (*models.Tenant, error) {
+	return s.TransitionTenant(id, models.TenantStatusArchived, reason, actorID)
+}
+
+func (s *tenantLifecycleService) TransitionTenant(id uuid.UUID, to models.TenantStatus, reason string, actorID string) (*models.Tenant, error) {
+	tenant, err := s.getTenant(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.transition(tenant, to, reason, actorID)
+}
+
+func (s *tenantLifecycleService) GetTransitionHistory(id uuid.UUID) ([]*models.TenantStatusTransition, error) {
+	if _, err := s.getTenant(id); err != nil {
+		return nil, err
+	}
+
+	transitions, err := s.tenantRepo.ListTransitions(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transition history: %w", err)
+	}
+	return transitions, nil
+}
+
+func (s *tenantLifecycleService) DeleteTenant(id uuid.UUID, reason string, actorID string) error {
+	_, err := s.TransitionTenant(id, models.TenantStatusDeleted, reason, actorID)
+	return err
+}
+
+// RestoreTenant undoes a soft delete within the retention window. The tenant
+// returns to the status it had before it was deleted.
+func (s *tenantLifecycleService) RestoreTenant(id uuid.UUID, reason string, actorID string) (*models.Tenant, error) {
+	tenant, err := s.tenantRepo.GetDeletedByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("deleted tenant not found")
+		}
+		return nil, err
+	}
+
+	if tenant.Status == models.TenantStatusDeleting {
+		return nil, errors.New("tenant is being deprovisioned and can no longer be restored")
+	}
+
+	if time.Since(tenant.DeletedAt.Time) > s.retention {
+		return nil, fmt.Errorf("tenant was deleted more than %d days ago and can no longer be restored",
+			int(s.retention.Hours()/24))
+	}
+
+	restoreTo := models.TenantStatusSuspended
+	transitions, err := s.tenantRepo.ListTransitions(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transition history: %w", err)
+	}
+	for _, t := range transitions {
+		if t.ToStatus == models.TenantStatusDeleted {
+			restoreTo = t.FromStatus
+			break
+		}
+	}
+
+	transition, err := s.tenantRepo.TransitionStatus(id, tenant.Status, restoreTo, reason, actorID)
+	if err != nil {
+		if errors.Is(err, repository.ErrTenantStatusChanged) {
+			return nil, errors.New("tenant status changed while processing the request, please retry")
+		}
+		return nil, fmt.Errorf("failed to restore tenant: %w", err)
+	}
+
+	tenant.Status = restoreTo
+	tenant.DeletedAt = gorm.DeletedAt{}
+	s.runHooks(tenant, transition)
+
+	return tenant, nil
+}
+
+func (s *tenantLifecycleService) ListDeletedTenants(pagination *models.PaginationParams) ([]*models.Tenant, int64, error) {
+	return s.tenantRepo.ListDeleted(pagination)
+}
+
+func (s *tenantLifecycleService) ListPurgeReports(pagination *models.PaginationParams) ([]*models.TenantPurgeReport, int64, error) {
+	return s.tenantRepo.ListPurgeReports(pagination)
+}
+
+func (s *tenantLifecycleService) Retention() time.Duration {
+	return s.retention
+}
+
+func (s *tenantLifecycleService) transition(tenant *models.Tenant, to models.TenantStatus, reason string, actorID string) (*models.Tenant, error) {
+	if !to.IsValid() {
+		return nil, fmt.Errorf("unknown tenant status: %s", to)
+	}
+
+	if !tenant.Status.CanTransitionTo(to) {
+		return nil, fmt.Errorf("cannot transition tenant from %s to %s", tenant.Status, to)
+	}
+
+	transition, err := s.tenantRepo.TransitionStatus(tenant.ID, tenant.Status, to, reason, actorID)
+	if err != nil {
+		if errors.Is(err, repository.ErrTenantStatusChanged) {
+			return nil, errors.New("tenant status changed while processing the request, please retry")
+		}
+		return nil, fmt.Errorf("failed to transition tenant: %w", err)
+	}
+
+	tenant.Status = to
+	s.runHooks(tenant, transition)
+
+	return tenant, nil
+}
+
+func (s *tenantLifecycleService) runHooks(tenant *models.Tenant, transition *models.TenantStatusTransition) {
+	for _, hook := range s.hooks {
+		if err := hook.OnTenantTransition(tenant, transition); err != nil {
+			fmt.Printf("tenant transition hook failed for tenant %s (%s -> %s): %v\n",
+				tenant.ID, transition.FromStatus, transition.ToStatus, err)
+		}
+	}
+}
+
+func (s *tenantLifecycleService) getTenant(id uuid.UUID) (*models.Tenant, error) {
+	tenant, err := s.tenantRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tenant not found")
+		}
+		return nil, err
+	}
+	return tenant, nil
+}
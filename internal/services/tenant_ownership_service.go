@@ -0,0 +1,264 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/jobs"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/repository"
+	"gorm.io/gorm"
+)
+
+// ownershipTransferTTL is how long a nominee has to accept a transfer
+const ownershipTransferTTL = 7 * 24 * time.Hour
+
+// ErrOwnershipTransferForbidden is returned when the actor may not act on an
+// ownership transfer
+var ErrOwnershipTransferForbidden = errors.New("not allowed to manage this ownership transfer")
+
+type TenantOwnershipService interface {
+	InitiateTransfer(tenantID uuid.UUID, input *models.InitiateOwnershipTransferInput, actorID string) (*models.TenantOwnershipTransfer, error)
+	GetPendingTransfer(tenantID uuid.UUID) (*models.TenantOwnershipTransfer, error)
+	ListTransfers(tenantID uuid.UUID) ([]*models.TenantOwnershipTransfer, error)
+	AcceptTransfer(tenantID uuid.UUID, userID string) (*models.TenantOwnershipTransfer, error)
+	DeclineTransfer(tenantID uuid.UUID, userID string) (*models.TenantOwnershipTransfer, error)
+	CancelTransfer(tenantID uuid.UUID, actorID string) (*models.TenantOwnershipTransfer, error)
+}
+
+type tenantOwnershipService struct {
+	ownershipRepo     repository.TenantOwnershipRepository
+	tenantRepo        repository.TenantRepository
+	memberRepo        repository.MemberRepository
+	rbacRepo          repository.RBACRepository
+	platformAdminRepo repository.PlatformAdminRepository
+	jobClient         jobs.Client
+}
+
+func NewTenantOwnershipService(
+	ownershipRepo repository.TenantOwnershipRepository,
+	tenantRepo repository.TenantRepository,
+	memberRepo repository.MemberRepository,
+	rbacRepo repository.RBACRepository,
+	platformAdminRepo repository.PlatformAdminRepository,
+	jobClient jobs.Client,
+) TenantOwnershipService {
+	return &tenantOwnershipService{
+		ownershipRepo:     ownershipRepo,
+		tenantRepo:        tenantRepo,
+		memberRepo:        memberRepo,
+		rbacRepo:          rbacRepo,
+		platformAdminRepo: platformAdminRepo,
+		jobClient:         jobClient,
+	}
+}
+
+// InitiateTransfer nominates an active member as the tenant's new owner.
+// Only the current owner or a platform admin can start a transfer.
+func (s *tenantOwnershipService) InitiateTransfer(tenantID uuid.UUID, input *models.InitiateOwnershipTransferInput, actorID string) (*models.TenantOwnershipTransfer, error) {
+	tenant, err := s.getTenant(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeOwnerOrPlatformAdmin(tenant, actorID); err != nil {
+		return nil, err
+	}
+
+	if tenant.IsOwnedBy(input.NewOwnerID) {
+		return nil, errors.New("user is already the owner of this tenant")
+	}
+
+	nominee, err := s.memberRepo.GetByTenantAndUser(tenantID, input.NewOwnerID)
+	if err != nil || nominee.Status != models.MemberStatusActive {
+		return nil, errors.New("new owner must be an active member of the tenant")
+	}
+
+	if input.PreviousOwnerRoleID != nil {
+		if tenant.OwnerID == nil {
+			return nil, errors.New("tenant has no current owner to move to another role")
+		}
+		role, err := s.rbacRepo.GetRoleByID(*input.PreviousOwnerRoleID)
+		if err != nil {
+			return nil, errors.New("role not found")
+		}
+		if role.TenantID != nil && *role.TenantID != tenantID {
+			return nil, errors.New("role does not belong to this tenant")
+		}
+	}
+
+	pending, err := s.pendingTransfer(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if pending != nil {
+		return nil, errors.New("an ownership transfer is already pending for this tenant")
+	}
+
+	transfer := &models.TenantOwnershipTransfer{
+		TenantID:            tenantID,
+		FromUserID:          tenant.OwnerID,
+		ToUserID:            input.NewOwnerID,
+		PreviousOwnerRoleID: input.PreviousOwnerRoleID,
+		Status:              models.OwnershipTransferPending,
+		Message:             input.Message,
+		InitiatedBy:         actorID,
+		ExpiresAt:           time.Now().Add(ownershipTransferTTL),
+	}
+	if err := s.ownershipRepo.Create(transfer); err != nil {
+		return nil, fmt.Errorf("failed to create ownership transfer: %w", err)
+	}
+
+	s.notify(transfer)
+	return transfer, nil
+}
+
+func (s *tenantOwnershipService) GetPendingTransfer(tenantID uuid.UUID) (*models.TenantOwnershipTransfer, error) {
+	transfer, err := s.pendingTransfer(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if transfer == nil {
+		return nil, errors.New("no pending ownership transfer")
+	}
+	return transfer, nil
+}
+
+func (s *tenantOwnershipService) ListTransfers(tenantID uuid.UUID) ([]*models.TenantOwnershipTransfer, error) {
+	// Close a lapsed transfer first so the history shows it as expired
+	if _, err := s.pendingTransfer(tenantID); err != nil {
+		return nil, err
+	}
+	return s.ownershipRepo.ListByTenant(tenantID)
+}
+
+// AcceptTransfer makes the nominee the owner and gives them the Admin role
+func (s *tenantOwnershipService) AcceptTransfer(tenantID uuid.UUID, userID string) (*models.TenantOwnershipTransfer, error) {
+	transfer, err := s.GetPendingTransfer(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if transfer.ToUserID != userID {
+		return nil, ErrOwnershipTransferForbidden
+	}
+
+	adminRole, err := s.rbacRepo.GetRoleByName("Admin", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get admin role: %w", err)
+	}
+
+	if err := s.ownershipRepo.Accept(transfer, adminRole.ID); err != nil {
+		if errors.Is(err, repository.ErrOwnershipChanged) || errors.Is(err, repository.ErrNomineeNotActive) {
+			// The transfer can never succeed now, so close it
+			if resolveErr := s.ownershipRepo.Resolve(transfer.ID, models.OwnershipTransferCancelled, models.SystemActorID); resolveErr == nil {
+				transfer.Status = models.OwnershipTransferCancelled
+				s.notify(transfer)
+			}
+		}
+		return nil, err
+	}
+
+	s.notify(transfer)
+	return transfer, nil
+}
+
+func (s *tenantOwnershipService) DeclineTransfer(tenantID uuid.UUID, userID string) (*models.TenantOwnershipTransfer, error) {
+	transfer, err := s.GetPendingTransfer(tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if transfer.ToUserID != userID {
+		return nil, ErrOwnershipTransferForbidden
+	}
+
+	return s.resolve(transfer, models.OwnershipTransferDeclined, userID)
+}
+
+// CancelTransfer withdraws a pending transfer. The owner, whoever started
+// the transfer and platform admins can cancel it.
+func (s *tenantOwnershipService) CancelTransfer(tenantID uuid.UUID, actorID string) (*models.TenantOwnershipTransfer, error) {
+	transfer, err := s.GetPendingTransfer(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if transfer.InitiatedBy != actorID {
+		tenant, err := s.getTenant(tenantID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.authorizeOwnerOrPlatformAdmin(tenant, actorID); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.resolve(transfer, models.OwnershipTransferCancelled, actorID)
+}
+
+func (s *tenantOwnershipService) resolve(transfer *models.TenantOwnershipTransfer, status models.OwnershipTransferStatus, actorID string) (*models.TenantOwnershipTransfer, error) {
+	if err := s.ownershipRepo.Resolve(transfer.ID, status, actorID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	transfer.Status = status
+	transfer.ResolvedBy = &actorID
+	transfer.ResolvedAt = &now
+
+	s.notify(transfer)
+	return transfer, nil
+}
+
+// pendingTransfer returns the tenant's open transfer, or nil. A transfer past
+// its deadline is marked expired instead.
+func (s *tenantOwnershipService) pendingTransfer(tenantID uuid.UUID) (*models.TenantOwnershipTransfer, error) {
+	transfer, err := s.ownershipRepo.GetPending(tenantID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if transfer.IsExpired() {
+		if _, err := s.resolve(transfer, models.OwnershipTransferExpired, models.SystemActorID); err != nil && !errors.Is(err, repository.ErrOwnershipTransferResolved) {
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	return transfer, nil
+}
+
+func (s *tenantOwnershipService) authorizeOwnerOrPlatformAdmin(tenant *models.Tenant, actorID string) error {
+	if tenant.IsOwnedBy(actorID) {
+		return nil
+	}
+
+	isAdmin, err := s.platformAdminRepo.IsPlatformAdmin(actorID)
+	if err != nil {
+		return err
+	}
+	if !isAdmin {
+		return ErrOwnershipTransferForbidden
+	}
+	return nil
+}
+
+func (s *tenantOwnershipService) getTenant(id uuid.UUID) (*models.Tenant, error) {
+	tenant, err := s.tenantRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tenant not found")
+		}
+		return nil, err
+	}
+	return tenant, nil
+}
+
+func (s *tenantOwnershipService) notify(transfer *models.TenantOwnershipTransfer) {
+	if err := s.jobClient.EnqueueOwnershipTransferNotification(transfer.ID); err != nil {
+		fmt.Printf("failed to enqueue ownership transfer notification: %v\n", err)
+	}
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/pkg/users"
+	"github.com/ysaakpr/rex/internal/repository"
+	"gorm.io/gorm"
+)
+
+type TenantSecurityService interface {
+	GetSettings(tenantID uuid.UUID) (*models.TenantSecuritySettings, error)
+	UpdateSettings(tenantID uuid.UUID, input *models.UpdateTenantSecuritySettingsInput) (*models.TenantSecuritySettings, error)
+}
+
+type tenantSecurityService struct {
+	tenantRepo repository.TenantRepository
+	rbacRepo   repository.RBACRepository
+}
+
+func NewTenantSecurityService(tenantRepo repository.TenantRepository, rbacRepo repository.RBACRepository) TenantSecurityService {
+	return &tenantSecurityService{
+		tenantRepo: tenantRepo,
+		rbacRepo:   rbacRepo,
+	}
+}
+
+func (s *tenantSecurityService) GetSettings(tenantID uuid.UUID) (*models.TenantSecuritySettings, error) {
+	tenant, err := s.tenantRepo.GetByID(tenantID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tenant not found")
+		}
+		return nil, err
+	}
+	return &tenant.SecuritySettings, nil
+}
+
+// UpdateSettings replaces the tenant's security settings. They apply to
+// existing members from their next request.
+func (s *tenantSecurityService) UpdateSettings(tenantID uuid.UUID, input *models.UpdateTenantSecuritySettingsInput) (*models.TenantSecuritySettings, error) {
+	if _, err := s.GetSettings(tenantID); err != nil {
+		return nil, err
+	}
+
+	settings := input.Settings()
+	for _, rule := range settings.RoleGrantRules {
+		for _, roleID := range append([]uuid.UUID{rule.RoleID}, rule.CanGrant...) {
+			role, err := s.rbacRepo.GetRoleByID(roleID)
+			if err != nil {
+				return nil, fmt.Errorf("invalid role in role_grant_rules: %s", roleID)
+			}
+			if role.TenantID != nil && *role.TenantID != tenantID {
+				return nil, fmt.Errorf("role %s in role_grant_rules does not belong to this tenant", roleID)
+			}
+		}
+	}
+
+	if err := s.tenantRepo.UpdateSecuritySettings(tenantID, settings); err != nil {
+		return nil, fmt.Errorf("failed to update security settings: %w", err)
+	}
+
+	return &settings, nil
+}
+
+// checkAllowedEmail returns a SecurityPolicyError when email's domain is not
+// allowed in tenant
+func checkAllowedEmail(tenant *models.Tenant, email string) error {
+	if tenant.SecuritySettings.AllowsEmail(email) {
+		return nil
+	}
+	return &models.SecurityPolicyError{
+		Setting: "allowed_email_domains",
+		Message: fmt.Sprintf("%s is not allowed in this tenant, members must have an email address at %s",
+			email, strings.Join(tenant.SecuritySettings.AllowedEmailDomains, ", ")),
+	}
+}
+
+// checkAllowedUser looks up the user's email and checks its domain is allowed
+// in tenant. The lookup is skipped when the tenant allows every domain.
+func checkAllowedUser(tenant *models.Tenant, userID string) error {
+	if len(tenant.SecuritySettings.AllowedEmailDomains) == 0 {
+		return nil
+	}
+
+	email, err := users.LookupEmail(userID)
+	if err != nil {
+		return fmt.Errorf("failed to look up the user's email: %w", err)
+	}
+	return checkAllowedEmail(tenant, email)
+}
+
+// checkCanInvite returns a SecurityPolicyError when tenant only lets admins
+// invite or add members and actorID is neither the owner, an active member
+// holding the tenant management permission, directly or through a group, nor
+// a platform admin
+func checkCanInvite(
+	tenant *models.Tenant,
+	actorID string,
+	memberRepo repository.MemberRepository,
+	platformAdminRepo repository.PlatformAdminRepository,
+) error {
+	if !tenant.SecuritySettings.OnlyAdminsCanInvite || tenant.IsOwnedBy(actorID) {
+		return nil
+	}
+
+	if isAdmin, err := platformAdminRepo.IsPlatformAdmin(actorID); err != nil {
+		return err
+	} else if isAdmin {
+		return nil
+	}
+
+	managers, err := memberRepo.ListManagerUserIDs(tenant.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get tenant managers: %w", err)
+	}
+	for _, managerID := range managers {
+		if managerID == actorID {
+			return nil
+		}
+	}
+
+	return &models.SecurityPolicyError{
+		Setting: "only_admins_can_invite",
+		Message: "only tenant admins can invite or add members to this tenant",
+	}
+}
+
+// checkCanGrantRoles stops actorID from granting roles more powerful than
+// their own: every permission of each role must be one the actor holds in
+// tenant, directly or through a group. When the tenant has role grant rules
+// the role must also be listed for one of the actor's roles. The tenant owner,
+// platform admins and background jobs may grant any role.
+func checkCanGrantRoles(
+	tenant *models.Tenant,
+	actorID string,
+	roleIDs []uuid.UUID,
+	rbacRepo repository.RBACRepository,
+	platformAdminRepo repository.PlatformAdminRepository,
+) error {
+	if actorID == models.SystemActorID || tenant.IsOwnedBy(actorID) {
+		return nil
+	}
+
+	if isAdmin, err := platformAdminRepo.IsPlatformAdmin(actorID); err != nil {
+		return err
+	} else if isAdmin {
+		return nil
+	}
+
+	held, err := rbacRepo.GetUserPermissions(tenant.ID, actorID)
+	if err != nil {
+		return fmt.Errorf("failed to get your permissions: %w", err)
+	}
+	heldKeys := make(map[string]bool, len(held))
+	for _, permission := range held {
+		heldKeys[permission.GetKey()] = true
+	}
+
+	actorRoles, err := rbacRepo.GetUserRoleIDs(tenant.ID, actorID)
+	if err != nil {
+		return fmt.Errorf("failed to get your roles: %w", err)
+	}
+	grantable, restricted := tenant.SecuritySettings.GrantableRoles(actorRoles)
+
+	for _, roleID := range roleIDs {
+		role, err := rbacRepo.GetRoleByID(roleID)
+		if err != nil {
+			return fmt.Errorf("invalid role: %s", roleID)
+		}
+
+		if restricted && !grantable[roleID] {
+			return &models.SecurityPolicyError{
+				Setting: "role_grant_rules",
+				Message: fmt.Sprintf("your roles in this tenant are not allowed to grant the %s role", role.Name),
+			}
+		}
+
+		permissions, err := rbacRepo.GetRolePermissions(roleID)
+		if err != nil {
+			return fmt.Errorf("failed to get the %s role's permissions: %w", role.Name, err)
+		}
+		var missing []string
+		for _, permission := range permissions {
+			if key := permission.GetKey(); !heldKeys[key] {
+				missing = append(missing, key)
+			}
+		}
+		if len(missing) > 0 {
+			sort.Strings(missing)
+			return &models.RoleGrantError{RoleID: role.ID, RoleName: role.Name, Missing: missing}
+		}
+	}
+
+	return nil
+}
+
+// NewRoleGrantCheck returns checkCanGrantRoles for callers outside this
+// package, such as background jobs acting for a member
+func NewRoleGrantCheck(
+	rbacRepo repository.RBACRepository,
+	platformAdminRepo repository.PlatformAdminRepository,
+) func(tenant *models.Tenant, actorID string, roleIDs []uuid.UUID) error {
+	return func(tenant *models.Tenant, actorID string, roleIDs []uuid.UUID) error {
+		return checkCanGrantRoles(tenant, actorID, roleIDs, rbacRepo, platformAdminRepo)
+	}
+}
@@ -0,0 +1,242 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/repository"
+	"gorm.io/gorm"
+)
+
+// fakeRBACRepo serves the role and permission lookups the grant check makes.
+// Other methods panic through the nil embedded interface.
+type fakeRBACRepo struct {
+	repository.RBACRepository
+	roles           map[uuid.UUID]*models.Role
+	rolePermissions map[uuid.UUID][]*models.Permission
+	userRoles       map[string][]uuid.UUID
+}
+
+func (f *fakeRBACRepo) GetRoleByID(id uuid.UUID) (*models.Role, error) {
+	role, ok := f.roles[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return role, nil
+}
+
+func (f *fakeRBACRepo) GetRolePermissions(roleID uuid.UUID) ([]*models.Permission, error) {
+	return f.rolePermissions[roleID], nil
+}
+
+func (f *fakeRBACRepo) GetUserRoleIDs(tenantID uuid.UUID, userID string) ([]uuid.UUID, error) {
+	return f.userRoles[userID], nil
+}
+
+// GetUserPermissions returns the permissions of every role the user holds,
+// as the real repository does across their own role and their groups
+func (f *fakeRBACRepo) GetUserPermissions(tenantID uuid.UUID, userID string) ([]*models.Permission, error) {
+	var permissions []*models.Permission
+	for _, roleID := range f.userRoles[userID] {
+		permissions = append(permissions, f.rolePermissions[roleID]...)
+	}
+	return permissions, nil
+}
+
+type fakePlatformAdminRepo struct {
+	repository.PlatformAdminRepository
+	admins map[string]bool
+}
+
+func (f *fakePlatformAdminRepo) IsPlatformAdmin(userID string) (bool, error) {
+	return f.admins[userID], nil
+}
+
+func permission(action string) *models.Permission {
+	return &models.Permission{Service: "tenant-api", Entity: "member", Action: action}
+}
+
+// grantFixture is a tenant with viewer < writer < admin roles, owned by
+// owner_user, with writer_user holding writer and admin_user holding admin
+type grantFixture struct {
+	tenant *models.Tenant
+	rbac   *fakeRBACRepo
+	admins *fakePlatformAdminRepo
+	viewer uuid.UUID
+	writer uuid.UUID
+	admin  uuid.UUID
+}
+
+func newGrantFixture() *grantFixture {
+	owner := "owner_user"
+	f := &grantFixture{
+		tenant: &models.Tenant{ID: uuid.New(), OwnerID: &owner},
+		viewer: uuid.New(),
+		writer: uuid.New(),
+		admin:  uuid.New(),
+		admins: &fakePlatformAdminRepo{admins: map[string]bool{"platform_admin": true}},
+	}
+	f.rbac = &fakeRBACRepo{
+		roles: map[uuid.UUID]*models.Role{
+			f.viewer: {ID: f.viewer, Name: "Viewer"},
+			f.writer: {ID: f.writer, Name: "Writer"},
+			f.admin:  {ID: f.admin, Name: "Admin"},
+		},
+		rolePermissions: map[uuid.UUID][]*models.Permission{
+			f.viewer: {permission("read")},
+			f.writer: {permission("read"), permission("update")},
+			f.admin:  {permission("read"), permission("update"), permission("delete"), permission("create")},
+		},
+		userRoles: map[string][]uuid.UUID{
+			"writer_user": {f.writer},
+			"admin_user":  {f.admin},
+		},
+	}
+	return f
+}
+
+func (f *grantFixture) check(actorID string, roleIDs ...uuid.UUID) error {
+	return checkCanGrantRoles(f.tenant, actorID, roleIDs, f.rbac, f.admins)
+}
+
+func TestCheckCanGrantRolesWithinOwnPermissions(t *testing.T) {
+	f := newGrantFixture()
+
+	tests := []struct {
+		name    string
+		actorID string
+		roleIDs []uuid.UUID
+		ok      bool
+	}{
+		{"lower role", "writer_user", []uuid.UUID{f.viewer}, true},
+		{"own role", "writer_user", []uuid.UUID{f.writer}, true},
+		{"higher role", "writer_user", []uuid.UUID{f.admin}, false},
+		{"one role of several too high", "writer_user", []uuid.UUID{f.viewer, f.admin}, false},
+		{"admin grants anything below", "admin_user", []uuid.UUID{f.viewer, f.writer, f.admin}, true},
+		{"no roles in the tenant", "stranger", []uuid.UUID{f.viewer}, false},
+		{"owner", "owner_user", []uuid.UUID{f.admin}, true},
+		{"platform admin", "platform_admin", []uuid.UUID{f.admin}, true},
+		{"background job", models.SystemActorID, []uuid.UUID{f.admin}, true},
+		{"nothing to grant", "stranger", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := f.check(tt.actorID, tt.roleIDs...)
+			if tt.ok && err != nil {
+				t.Fatalf("checkCanGrantRoles = %v, want nil", err)
+			}
+			if !tt.ok {
+				var grantErr *models.RoleGrantError
+				if !errors.As(err, &grantErr) {
+					t.Fatalf("checkCanGrantRoles = %v, want a RoleGrantError", err)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckCanGrantRolesReportsMissingPermissions(t *testing.T) {
+	f := newGrantFixture()
+
+	err := f.check("writer_user", f.admin)
+	var grantErr *models.RoleGrantError
+	if !errors.As(err, &grantErr) {
+		t.Fatalf("checkCanGrantRoles = %v, want a RoleGrantError", err)
+	}
+	if grantErr.RoleID != f.admin || grantErr.RoleName != "Admin" {
+		t.Errorf("error names role %s (%s), want Admin", grantErr.RoleName, grantErr.RoleID)
+	}
+	want := []string{"tenant-api:member:create", "tenant-api:member:delete"}
+	if !reflect.DeepEqual(grantErr.Missing, want) {
+		t.Errorf("Missing = %v, want %v", grantErr.Missing, want)
+	}
+}
+
+func TestCheckCanGrantRolesAppliesGrantRules(t *testing.T) {
+	f := newGrantFixture()
+	f.tenant.SecuritySettings.RoleGrantRules = []models.RoleGrantRule{
+		{RoleID: f.admin, CanGrant: []uuid.UUID{f.writer}},
+	}
+
+	tests := []struct {
+		name    string
+		actorID string
+		roleID  uuid.UUID
+		policy  bool
+	}{
+		{"listed role", "admin_user", f.writer, false},
+		{"role not listed", "admin_user", f.viewer, true},
+		{"actor role without a rule", "writer_user", f.viewer, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := f.check(tt.actorID, tt.roleID)
+			var policyErr *models.SecurityPolicyError
+			if tt.policy {
+				if !errors.As(err, &policyErr) || policyErr.Setting != "role_grant_rules" {
+					t.Fatalf("checkCanGrantRoles = %v, want a role_grant_rules SecurityPolicyError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("checkCanGrantRoles = %v, want nil", err)
+			}
+		})
+	}
+
+	if err := f.check("owner_user", f.viewer); err != nil {
+		t.Errorf("owner blocked by grant rules: %v", err)
+	}
+}
+
+func TestCheckCanGrantRolesUnknownRole(t *testing.T) {
+	f := newGrantFixture()
+	err := f.check("admin_user", uuid.New())
+	if err == nil {
+		t.Fatal("checkCanGrantRoles accepted an unknown role")
+	}
+	var grantErr *models.RoleGrantError
+	if errors.As(err, &grantErr) {
+		t.Errorf("unknown role reported as a RoleGrantError: %v", err)
+	}
+}
+
+func TestCheckCanInvite(t *testing.T) {
+	f := newGrantFixture()
+	// group_manager holds the management permission only through a group,
+	// which the managers lookup covers
+	members := &fakeMemberRepo{managers: []string{"admin_user", "group_manager"}}
+
+	tests := []struct {
+		name     string
+		actorID  string
+		adminsOn bool
+		ok       bool
+	}{
+		{"setting off", "writer_user", false, true},
+		{"manager", "admin_user", true, true},
+		{"manager through a group", "group_manager", true, true},
+		{"member without the management permission", "writer_user", true, false},
+		{"owner", "owner_user", true, true},
+		{"platform admin", "platform_admin", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f.tenant.SecuritySettings.OnlyAdminsCanInvite = tt.adminsOn
+			err := checkCanInvite(f.tenant, tt.actorID, members, f.admins)
+			if tt.ok && err != nil {
+				t.Fatalf("checkCanInvite = %v, want nil", err)
+			}
+			var policyErr *models.SecurityPolicyError
+			if !tt.ok && (!errors.As(err, &policyErr) || policyErr.Setting != "only_admins_can_invite") {
+				t.Fatalf("checkCanInvite = %v, want an only_admins_can_invite SecurityPolicyError", err)
+			}
+		})
+	}
+}
@@ -18,11 +18,9 @@ type TenantService interface {
 	CreateManagedTenant(input *models.CreateTenantInput, adminEmail string, creatorID string) (*models.Tenant, error)
 	GetTenant(id uuid.UUID) (*models.Tenant, error)
 	GetTenantBySlug(slug string) (*models.Tenant, error)
-	GetUserTenants(userID string, pagination *models.PaginationParams) ([]*models.Tenant, int64, error)
-	GetAllTenants(pagination *models.PaginationParams) ([]*models.Tenant, int64, error)
+	GetUserTenants(userID string, params *models.TenantListParams) ([]*models.Tenant, int64, error)
+	GetAllTenants(params *models.TenantListParams) ([]*models.Tenant, int64, error)
 	UpdateTenant(id uuid.UUID, input *models.UpdateTenantInput) (*models.Tenant, error)
-	DeleteTenant(id uuid.UUID) error
-	GetTenantStatus(id uuid.UUID) (models.TenantStatus, error)
 }
 
 type tenantService struct {
@@ -30,7 +28,9 @@ type tenantService struct {
 	memberRepo     repository.MemberRepository
 	invitationRepo repository.InvitationRepository
 	rbacRepo       repository.RBACRepository
+	templateRepo   repository.TenantTemplateRepository
 	jobClient      jobs.Client
+	schemaService  MetadataSchemaService
 }
 
 func NewTenantService(
@@ -38,14 +38,18 @@ func NewTenantService(
 	memberRepo repository.MemberRepository,
 	invitationRepo repository.InvitationRepository,
 	rbacRepo repository.RBACRepository,
+	templateRepo repository.TenantTemplateRepository,
 	jobClient jobs.Client,
+	schemaService MetadataSchemaService,
 ) TenantService {
 	return &tenantService{
 		tenantRepo:     tenantRepo,
 		memberRepo:     memberRepo,
 		invitationRepo: invitationRepo,
 		rbacRepo:       rbacRepo,
+		templateRepo:   templateRepo,
 		jobClient:      jobClient,
+		schemaService:  schemaService,
 	}
 }
 
@@ -58,15 +62,22 @@ func (s *tenantService) CreateTenant(input *models.CreateTenantInput, creatorID
 
 	// Create tenant
 	tenant := &models.Tenant{
-		Name:      input.Name,
-		Slug:      normalizeSlug(input.Slug),
-		Status:    models.TenantStatusPending,
-		Metadata:  input.Metadata,
-		CreatedBy: creatorID,
+		Name:       input.Name,
+		Slug:       normalizeSlug(input.Slug),
+		Status:     models.TenantStatusPending,
+		Metadata:   input.Metadata,
+		TenantType: input.TenantType,
+		CreatedBy:  creatorID,
+		OwnerID:    &creatorID,
 	}
 
-	if err := s.tenantRepo.Create(tenant); err != nil {
-		return nil, fmt.Errorf("failed to create tenant: %w", err)
+	template, err := s.applyTemplate(tenant, input.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.schemaService.ValidateMetadata(tenant.TenantType, tenant.Metadata); err != nil {
+		return nil, err
 	}
 
 	// Get Admin role
@@ -77,15 +88,25 @@ func (s *tenantService) CreateTenant(input *models.CreateTenantInput, creatorID
 
 	// Add creator as admin member
 	member := &models.TenantMember{
-		TenantID: tenant.ID,
 		UserID:   creatorID,
 		RoleID:   adminRole.ID,
 		Status:   models.MemberStatusActive,
 		JoinedAt: time.Now(),
 	}
 
-	if err := s.memberRepo.Create(member); err != nil {
-		return nil, fmt.Errorf("failed to add creator as admin: %w", err)
+	if template != nil {
+		if err := s.templateRepo.Instantiate(tenant, template, member, nil); err != nil {
+			return nil, fmt.Errorf("failed to create tenant from template: %w", err)
+		}
+	} else {
+		if err := s.tenantRepo.Create(tenant); err != nil {
+			return nil, fmt.Errorf("failed to create tenant: %w", err)
+		}
+
+		member.TenantID = tenant.ID
+		if err := s.memberRepo.Create(member); err != nil {
+			return nil, fmt.Errorf("failed to add creator as admin: %w", err)
+		}
 	}
 
 	// Enqueue tenant initialization job
@@ -104,17 +125,23 @@ func (s *tenantService) CreateManagedTenant(input *models.CreateTenantInput, adm
 		return nil, errors.New("tenant slug already exists")
 	}
 
-	// Create tenant
+	// Create tenant. It has no owner until the invited admin joins.
 	tenant := &models.Tenant{
-		Name:      input.Name,
-		Slug:      normalizeSlug(input.Slug),
-		Status:    models.TenantStatusPending,
-		Metadata:  input.Metadata,
-		CreatedBy: creatorID,
+		Name:       input.Name,
+		Slug:       normalizeSlug(input.Slug),
+		Status:     models.TenantStatusPending,
+		Metadata:   input.Metadata,
+		TenantType: input.TenantType,
+		CreatedBy:  creatorID,
 	}
 
-	if err := s.tenantRepo.Create(tenant); err != nil {
-		return nil, fmt.Errorf("failed to create tenant: %w", err)
+	template, err := s.applyTemplate(tenant, input.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.schemaService.ValidateMetadata(tenant.TenantType, tenant.Metadata); err != nil {
+		return nil, err
 	}
 
 	// Get Admin role
@@ -126,7 +153,6 @@ func (s *tenantService) CreateManagedTenant(input *models.CreateTenantInput, adm
 	// Create invitation for the admin user
 	invitationToken := generateInvitationToken()
 	invitation := &models.UserInvitation{
-		TenantID:  tenant.ID,
 		Email:     adminEmail,
 		InvitedBy: creatorID,
 		RoleID:    adminRole.ID,
@@ -135,8 +161,19 @@ func (s *tenantService) CreateManagedTenant(input *models.CreateTenantInput, adm
 		ExpiresAt: time.Now().Add(72 * time.Hour),
 	}
 
-	if err := s.invitationRepo.Create(invitation); err != nil {
-		return nil, fmt.Errorf("failed to create invitation: %w", err)
+	if template != nil {
+		if err := s.templateRepo.Instantiate(tenant, template, nil, invitation); err != nil {
+			return nil, fmt.Errorf("failed to create tenant from template: %w", err)
+		}
+	} else {
+		if err := s.tenantRepo.Create(tenant); err != nil {
+			return nil, fmt.Errorf("failed to create tenant: %w", err)
+		}
+
+		invitation.TenantID = tenant.ID
+		if err := s.invitationRepo.Create(invitation); err != nil {
+			return nil, fmt.Errorf("failed to create invitation: %w", err)
+		}
 	}
 
 	// Enqueue invitation email job
@@ -149,6 +186,42 @@ func (s *tenantService) CreateManagedTenant(input *models.CreateTenantInput, adm
 	return tenant, nil
 }
 
+// applyTemplate looks up the named template and applies its defaults to
+// tenant: its type unless one was given, its default metadata under the
+// metadata given and its service selection. It returns nil when no template
+// was requested.
+func (s *tenantService) applyTemplate(tenant *models.Tenant, name string) (*models.TenantTemplate, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	template, err := s.templateRepo.GetByName(name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("template not found")
+		}
+		return nil, err
+	}
+
+	if tenant.TenantType == nil {
+		tenant.TenantType = template.TenantType
+	}
+
+	metadata := make(models.JSONMap, len(template.DefaultMetadata)+len(tenant.Metadata))
+	for key, value := range template.DefaultMetadata {
+		metadata[key] = value
+	}
+	for key, value := range tenant.Metadata {
+		metadata[key] = value
+	}
+	tenant.Metadata = metadata
+
+	tenant.TemplateID = &template.ID
+	tenant.ProvisionServices = template.Services
+
+	return template, nil
+}
+
 func (s *tenantService) GetTenant(id uuid.UUID) (*models.Tenant, error) {
 	tenant, err := s.tenantRepo.GetByID(id)
 	if err != nil {
@@ -171,31 +244,16 @@ func (s *tenantService) GetTenantBySlug(slug string) (*models.Tenant, error) {
 	return tenant, nil
 }
 
-func (s *tenantService) GetUserTenants(userID string, pagination *models.PaginationParams) ([]*models.Tenant, int64, error) {
-	// Get all memberships for the user
-	members, err := s.memberRepo.GetByUserID(userID)
-	if err != nil {
-		return nil, 0, err
-	}
-
-	if len(members) == 0 {
-		return []*models.Tenant{}, 0, nil
-	}
-
-	// Extract tenant IDs
-	tenantIDs := make([]uuid.UUID, len(members))
-	for i, member := range members {
-		tenantIDs[i] = member.TenantID
-	}
-
-	// For simplicity, we'll return the tenants the user created
-	// In a full implementation, you'd query by tenant IDs
-	return s.tenantRepo.GetByCreatorID(userID, pagination)
+// GetUserTenants returns the tenants the user is an active member of that
+// match params
+func (s *tenantService) GetUserTenants(userID string, params *models.TenantListParams) ([]*models.Tenant, int64, error) {
+	return s.tenantRepo.ListByMember(userID, params)
 }
 
-// GetAllTenants returns all tenants in the system (for platform admins)
-func (s *tenantService) GetAllTenants(pagination *models.PaginationParams) ([]*models.Tenant, int64, error) {
-	return s.tenantRepo.List(pagination)
+// GetAllTenants returns all tenants in the system that match params (for
+// platform admins)
+func (s *tenantService) GetAllTenants(params *models.TenantListParams) ([]*models.Tenant, int64, error) {
+	return s.tenantRepo.List(params)
 }
 
 func (s *tenantService) UpdateTenant(id uuid.UUID, input *models.UpdateTenantInput) (*models.Tenant, error) {
@@ -211,10 +269,15 @@ func (s *tenantService) UpdateTenant(id uuid.UUID, input *models.UpdateTenantInp
 	if input.Name != nil {
 		tenant.Name = *input.Name
 	}
-	if input.Status != nil {
-		tenant.Status = *input.Status
+	if input.Status != nil && *input.Status != tenant.Status {
+		// Status changes must go through the lifecycle endpoints so they are
+		// validated, recorded and trigger the transition hooks
+		return nil, errors.New("tenant status cannot be changed directly, use the suspend, reactivate or archive endpoints")
 	}
 	if input.Metadata != nil {
+		if err := s.schemaService.ValidateMetadata(tenant.TenantType, input.Metadata); err != nil {
+			return nil, err
+		}
 		tenant.Metadata = input.Metadata
 	}
 
@@ -225,31 +288,6 @@ func (s *tenantService) UpdateTenant(id uuid.UUID, input *models.UpdateTenantInp
 	return tenant, nil
 }
 
-func (s *tenantService) DeleteTenant(id uuid.UUID) error {
-	tenant, err := s.tenantRepo.GetByID(id)
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("tenant not found")
-		}
-		return err
-	}
-
-	// Soft delete
-	tenant.Status = models.TenantStatusDeleted
-	return s.tenantRepo.Update(tenant)
-}
-
-func (s *tenantService) GetTenantStatus(id uuid.UUID) (models.TenantStatus, error) {
-	tenant, err := s.tenantRepo.GetByID(id)
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return "", errors.New("tenant not found")
-		}
-		return "", err
-	}
-	return tenant.Status, nil
-}
-
 func normalizeSlug(slug string) string {
 	slug = strings.ToLower(slug)
 	slug = strings.ReplaceAll(slug, " ", "-")
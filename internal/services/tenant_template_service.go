@@ -0,0 +1,161 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/ysaakpr/rex/internal/models"
+	"github.com/ysaakpr/rex/internal/repository"
+	"gorm.io/gorm"
+)
+
+type TenantTemplateService interface {
+	CreateTemplate(input *models.CreateTenantTemplateInput, actorID string) (*models.TenantTemplate, error)
+	GetTemplate(id uuid.UUID) (*models.TenantTemplate, error)
+	ListTemplates() ([]*models.TenantTemplate, error)
+	UpdateTemplate(id uuid.UUID, input *models.UpdateTenantTemplateInput) (*models.TenantTemplate, error)
+	DeleteTemplate(id uuid.UUID) error
+}
+
+type tenantTemplateService struct {
+	templateRepo  repository.TenantTemplateRepository
+	serviceRepo   repository.DownstreamServiceRepository
+	schemaService MetadataSchemaService
+}
+
+func NewTenantTemplateService(
+	templateRepo repository.TenantTemplateRepository,
+	serviceRepo repository.DownstreamServiceRepository,
+	schemaService MetadataSchemaService,
+) TenantTemplateService {
+	return &tenantTemplateService{
+		templateRepo:  templateRepo,
+		serviceRepo:   serviceRepo,
+		schemaService: schemaService,
+	}
+}
+
+func (s *tenantTemplateService) CreateTemplate(input *models.CreateTenantTemplateInput, actorID string) (*models.TenantTemplate, error) {
+	name := strings.TrimSpace(input.Name)
+	if _, err := s.templateRepo.GetByName(name); err == nil {
+		return nil, errors.New("a template with this name already exists")
+	}
+
+	template := &models.TenantTemplate{
+		ID:        uuid.New(),
+		Name:      name,
+		CreatedBy: actorID,
+	}
+	applyTemplateDefinition(template, &input.TenantTemplateInput)
+
+	if err := s.validate(template); err != nil {
+		return nil, err
+	}
+
+	if err := s.templateRepo.Create(template); err != nil {
+		return nil, fmt.Errorf("failed to create template: %w", err)
+	}
+
+	return template, nil
+}
+
+func (s *tenantTemplateService) GetTemplate(id uuid.UUID) (*models.TenantTemplate, error) {
+	template, err := s.templateRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("template not found")
+		}
+		return nil, err
+	}
+	return template, nil
+}
+
+func (s *tenantTemplateService) ListTemplates() ([]*models.TenantTemplate, error) {
+	return s.templateRepo.List()
+}
+
+// UpdateTemplate replaces the template's definition. Tenants already created
+// from it keep the configuration they were created with.
+func (s *tenantTemplateService) UpdateTemplate(id uuid.UUID, input *models.UpdateTenantTemplateInput) (*models.TenantTemplate, error) {
+	template, err := s.GetTemplate(id)
+	if err != nil {
+		return nil, err
+	}
+
+	applyTemplateDefinition(template, &input.TenantTemplateInput)
+
+	if err := s.validate(template); err != nil {
+		return nil, err
+	}
+
+	if err := s.templateRepo.Update(template); err != nil {
+		return nil, fmt.Errorf("failed to update template: %w", err)
+	}
+
+	return template, nil
+}
+
+func (s *tenantTemplateService) DeleteTemplate(id uuid.UUID) error {
+	if _, err := s.GetTemplate(id); err != nil {
+		return err
+	}
+	return s.templateRepo.Delete(id)
+}
+
+// validate checks the template's structure, that everything it refers to
+// exists and that its default metadata satisfies the tenant type's schema
+func (s *tenantTemplateService) validate(template *models.TenantTemplate) error {
+	if err := template.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.templateRepo.CheckReferences(template); err != nil {
+		return err
+	}
+
+	if len(template.Services) > 0 {
+		services, err := s.serviceRepo.GetByNames(template.Services)
+		if err != nil {
+			return err
+		}
+		if len(services) != len(template.Services) {
+			found := make(map[string]bool, len(services))
+			for _, service := range services {
+				found[service.Name] = true
+			}
+			for _, name := range template.Services {
+				if !found[name] {
+					return fmt.Errorf("service %s is not registered", name)
+				}
+			}
+		}
+	}
+
+	if len(template.DefaultMetadata) > 0 {
+		if err := s.schemaService.ValidateMetadata(template.TenantType, template.DefaultMetadata); err != nil {
+			return fmt.Errorf("default metadata: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func applyTemplateDefinition(template *models.TenantTemplate, input *models.TenantTemplateInput) {
+	template.Description = input.Description
+	template.TenantType = input.TenantType
+	template.DefaultMetadata = input.DefaultMetadata
+	template.Policies = input.Policies
+	template.Roles = input.Roles
+	template.DefaultRole = input.DefaultRole
+	template.Services = input.Services
+
+	if template.DefaultMetadata == nil {
+		template.DefaultMetadata = models.JSONMap{}
+	}
+	if len(template.Services) == 0 {
+		// Provision every enabled service
+		template.Services = nil
+	}
+}
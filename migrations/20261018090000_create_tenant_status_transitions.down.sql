@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_tenant_status_transitions_created_at;
+DROP INDEX IF EXISTS idx_tenant_status_transitions_tenant_id;
+DROP TABLE IF EXISTS tenant_status_transitions;
+
+-- Note: PostgreSQL cannot drop a value from an enum type, so 'archived'
+-- remains part of tenant_status after rollback.
@@ -0,0 +1,19 @@
+-- Add archived to the tenant lifecycle
+ALTER TYPE tenant_status ADD VALUE IF NOT EXISTS 'archived';
+
+-- Record every tenant status change made through the lifecycle state machine
+CREATE TABLE IF NOT EXISTS tenant_status_transitions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    reason TEXT,
+    actor_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_tenant_status_transitions_tenant_id ON tenant_status_transitions(tenant_id);
+CREATE INDEX idx_tenant_status_transitions_created_at ON tenant_status_transitions(created_at);
+
+COMMENT ON TABLE tenant_status_transitions IS 'History of tenant lifecycle transitions (suspend, reactivate, archive, ...)';
+COMMENT ON COLUMN tenant_status_transitions.actor_id IS 'SuperTokens user ID of the actor, or "system" for automated transitions';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_tenant_purge_reports_purged_at;
+DROP INDEX IF EXISTS idx_tenant_purge_reports_tenant_id;
+DROP TABLE IF EXISTS tenant_purge_reports;
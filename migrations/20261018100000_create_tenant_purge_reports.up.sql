@@ -0,0 +1,24 @@
+-- Tenants deleted before the lifecycle state machine only had their status set.
+-- Backfill deleted_at so they fall under the restore/purge retention window.
+UPDATE tenants SET deleted_at = updated_at WHERE status = 'deleted' AND deleted_at IS NULL;
+
+-- Record what was removed each time a soft-deleted tenant is permanently purged.
+-- tenant_id is intentionally not a foreign key: the tenant row no longer exists.
+CREATE TABLE IF NOT EXISTS tenant_purge_reports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL,
+    tenant_name VARCHAR(255) NOT NULL,
+    tenant_slug VARCHAR(255) NOT NULL,
+    deleted_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    members_purged INTEGER NOT NULL DEFAULT 0,
+    invitations_purged INTEGER NOT NULL DEFAULT 0,
+    roles_purged INTEGER NOT NULL DEFAULT 0,
+    policies_purged INTEGER NOT NULL DEFAULT 0,
+    transitions_purged INTEGER NOT NULL DEFAULT 0,
+    purged_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_tenant_purge_reports_tenant_id ON tenant_purge_reports(tenant_id);
+CREATE INDEX idx_tenant_purge_reports_purged_at ON tenant_purge_reports(purged_at);
+
+COMMENT ON TABLE tenant_purge_reports IS 'Audit trail of tenants permanently removed after the retention window';
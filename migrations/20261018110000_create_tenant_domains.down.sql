@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_tenant_domains_tenant_id;
+DROP TABLE IF EXISTS tenant_domains;
@@ -0,0 +1,18 @@
+-- Custom domains mapped to tenants. A domain only resolves to its tenant once
+-- ownership has been proven through a DNS TXT record.
+CREATE TABLE IF NOT EXISTS tenant_domains (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    domain VARCHAR(255) NOT NULL UNIQUE,
+    verification_token VARCHAR(255) NOT NULL,
+    verified_at TIMESTAMP WITH TIME ZONE,
+    last_checked_at TIMESTAMP WITH TIME ZONE,
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_tenant_domains_tenant_id ON tenant_domains(tenant_id);
+
+COMMENT ON TABLE tenant_domains IS 'Custom domains that resolve to a tenant once verified';
+COMMENT ON COLUMN tenant_domains.verification_token IS 'Expected in the _rex-verification.<domain> TXT record';
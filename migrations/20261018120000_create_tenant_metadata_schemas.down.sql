@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS metadata_validation_failures;
+DROP TABLE IF EXISTS metadata_validation_runs;
+DROP TABLE IF EXISTS tenant_metadata_schemas;
+DROP INDEX IF EXISTS idx_tenants_tenant_type;
+ALTER TABLE tenants DROP COLUMN IF EXISTS tenant_type;
@@ -0,0 +1,50 @@
+-- Tenant types select which metadata schema applies to a tenant
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS tenant_type VARCHAR(100);
+CREATE INDEX IF NOT EXISTS idx_tenants_tenant_type ON tenants(tenant_type);
+
+-- Versioned JSON Schemas for tenant metadata. A NULL tenant_type is the default
+-- schema used for tenants whose type has no schema of its own.
+CREATE TABLE IF NOT EXISTS tenant_metadata_schemas (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_type VARCHAR(100),
+    version INTEGER NOT NULL,
+    schema JSONB NOT NULL,
+    description TEXT,
+    is_active BOOLEAN NOT NULL DEFAULT false,
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_tenant_metadata_schemas_type_version
+    ON tenant_metadata_schemas(COALESCE(tenant_type, ''), version);
+-- At most one active schema per tenant type
+CREATE UNIQUE INDEX idx_tenant_metadata_schemas_active
+    ON tenant_metadata_schemas(COALESCE(tenant_type, '')) WHERE is_active;
+
+-- Background re-validation of existing tenants against the active schemas
+CREATE TABLE IF NOT EXISTS metadata_validation_runs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    tenants_checked INTEGER NOT NULL DEFAULT 0,
+    tenants_non_conforming INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    triggered_by VARCHAR(255) NOT NULL,
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_metadata_validation_runs_created_at ON metadata_validation_runs(created_at);
+
+-- One row per tenant whose metadata did not conform during a run
+CREATE TABLE IF NOT EXISTS metadata_validation_failures (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    run_id UUID NOT NULL REFERENCES metadata_validation_runs(id) ON DELETE CASCADE,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    schema_id UUID NOT NULL REFERENCES tenant_metadata_schemas(id) ON DELETE CASCADE,
+    errors JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_metadata_validation_failures_run_id ON metadata_validation_failures(run_id);
+CREATE INDEX idx_metadata_validation_failures_tenant_id ON metadata_validation_failures(tenant_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_tenant_provisioning_steps_status;
+DROP INDEX IF EXISTS idx_tenant_provisioning_steps_tenant_id;
+DROP TABLE IF EXISTS tenant_provisioning_steps;
@@ -0,0 +1,21 @@
+-- One row per tenant per downstream service, tracking provisioning progress so
+-- retries only re-run the services that have not succeeded
+CREATE TABLE IF NOT EXISTS tenant_provisioning_steps (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    service VARCHAR(500) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    idempotency_key VARCHAR(255) NOT NULL UNIQUE,
+    last_attempt_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(tenant_id, service)
+);
+
+CREATE INDEX idx_tenant_provisioning_steps_tenant_id ON tenant_provisioning_steps(tenant_id);
+CREATE INDEX idx_tenant_provisioning_steps_status ON tenant_provisioning_steps(status);
+
+COMMENT ON COLUMN tenant_provisioning_steps.idempotency_key IS 'Sent as Idempotency-Key; stable across retries of the same tenant and service';
@@ -0,0 +1,12 @@
+DELETE FROM tenant_provisioning_steps WHERE operation = 'deprovision';
+
+ALTER TABLE tenant_provisioning_steps
+    DROP CONSTRAINT IF EXISTS tenant_provisioning_steps_tenant_id_service_operation_key;
+
+ALTER TABLE tenant_provisioning_steps
+    ADD CONSTRAINT tenant_provisioning_steps_tenant_id_service_key UNIQUE (tenant_id, service);
+
+ALTER TABLE tenant_provisioning_steps DROP COLUMN IF EXISTS operation;
+
+-- Postgres cannot drop enum values; move any tenants mid-teardown back to deleted
+UPDATE tenants SET status = 'deleted' WHERE status = 'deleting';
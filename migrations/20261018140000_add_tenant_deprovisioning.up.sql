@@ -0,0 +1,15 @@
+-- Tenants past retention wait in "deleting" until every downstream service has
+-- confirmed teardown, then they are purged
+ALTER TYPE tenant_status ADD VALUE IF NOT EXISTS 'deleting';
+
+-- Provisioning steps now track teardown as well as initialization
+ALTER TABLE tenant_provisioning_steps
+    ADD COLUMN operation VARCHAR(20) NOT NULL DEFAULT 'provision';
+
+ALTER TABLE tenant_provisioning_steps
+    DROP CONSTRAINT IF EXISTS tenant_provisioning_steps_tenant_id_service_key;
+
+ALTER TABLE tenant_provisioning_steps
+    ADD CONSTRAINT tenant_provisioning_steps_tenant_id_service_operation_key UNIQUE (tenant_id, service, operation);
+
+COMMENT ON COLUMN tenant_provisioning_steps.operation IS 'provision (initialize) or deprovision (teardown)';
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_tenant_provisioning_steps_callback_deadline;
+
+UPDATE tenant_provisioning_steps SET status = 'pending' WHERE status = 'accepted';
+
+ALTER TABLE tenant_provisioning_steps DROP COLUMN IF EXISTS callback_deadline;
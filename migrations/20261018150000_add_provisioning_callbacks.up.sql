@@ -0,0 +1,10 @@
+-- Services may acknowledge a provisioning request with 202 and report the
+-- outcome later through the callback endpoint
+ALTER TABLE tenant_provisioning_steps
+    ADD COLUMN callback_deadline TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_tenant_provisioning_steps_callback_deadline
+    ON tenant_provisioning_steps(callback_deadline)
+    WHERE status = 'accepted';
+
+COMMENT ON COLUMN tenant_provisioning_steps.callback_deadline IS 'When an accepted step that has not called back is marked failed';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_downstream_service_dependencies_depends_on_id;
+DROP TABLE IF EXISTS downstream_service_dependencies;
+DROP INDEX IF EXISTS idx_downstream_services_enabled;
+DROP TABLE IF EXISTS downstream_services;
@@ -0,0 +1,33 @@
+-- Registry of downstream services that tenants are provisioned in
+CREATE TABLE IF NOT EXISTS downstream_services (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL UNIQUE,
+    base_url VARCHAR(500) NOT NULL,
+    provision_path VARCHAR(255) NOT NULL DEFAULT '/api/v1/tenants/initialize',
+    deprovision_path VARCHAR(255) NOT NULL DEFAULT '/api/v1/tenants/deprovision',
+    health_path VARCHAR(255) NOT NULL DEFAULT '/health',
+    signing_secret VARCHAR(255),
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    position INTEGER NOT NULL DEFAULT 0,
+    health_status VARCHAR(20) NOT NULL DEFAULT 'unknown',
+    health_error TEXT,
+    last_health_check_at TIMESTAMP WITH TIME ZONE,
+    created_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_downstream_services_enabled ON downstream_services(enabled);
+
+-- A service is provisioned after the services it depends on, and deprovisioned before them
+CREATE TABLE IF NOT EXISTS downstream_service_dependencies (
+    service_id UUID NOT NULL REFERENCES downstream_services(id) ON DELETE CASCADE,
+    depends_on_id UUID NOT NULL REFERENCES downstream_services(id) ON DELETE CASCADE,
+    PRIMARY KEY (service_id, depends_on_id),
+    CHECK (service_id <> depends_on_id)
+);
+
+CREATE INDEX idx_downstream_service_dependencies_depends_on_id ON downstream_service_dependencies(depends_on_id);
+
+COMMENT ON COLUMN downstream_services.signing_secret IS 'HMAC secret for this service; falls back to TENANT_PROVISIONING_SECRET when empty';
+COMMENT ON COLUMN downstream_services.position IS 'Provisioning order among services without dependencies between them';
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS tenant_plan_overrides;
+DROP INDEX IF EXISTS idx_tenants_plan_id;
+ALTER TABLE tenants DROP COLUMN IF EXISTS plan_id;
+DROP INDEX IF EXISTS idx_plans_default;
+DROP TABLE IF EXISTS plans;
@@ -0,0 +1,41 @@
+-- Plans define the limits and feature entitlements of the tenants on them.
+-- NULL limits are unlimited.
+CREATE TABLE IF NOT EXISTS plans (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL UNIQUE,
+    description TEXT,
+    max_members INTEGER CHECK (max_members >= 0),
+    max_pending_invitations INTEGER CHECK (max_pending_invitations >= 0),
+    max_custom_roles INTEGER CHECK (max_custom_roles >= 0),
+    max_system_users INTEGER CHECK (max_system_users >= 0),
+    features JSONB NOT NULL DEFAULT '{}',
+    is_default BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Tenants without a plan use the default plan
+CREATE UNIQUE INDEX idx_plans_default ON plans(is_default) WHERE is_default;
+
+ALTER TABLE tenants ADD COLUMN plan_id UUID REFERENCES plans(id) ON DELETE SET NULL;
+CREATE INDEX idx_tenants_plan_id ON tenants(plan_id);
+
+-- Per-tenant overrides set by platform admins. NULL limits inherit the plan;
+-- features listed here replace the plan's value for that feature.
+CREATE TABLE IF NOT EXISTS tenant_plan_overrides (
+    tenant_id UUID PRIMARY KEY REFERENCES tenants(id) ON DELETE CASCADE,
+    max_members INTEGER CHECK (max_members >= 0),
+    max_pending_invitations INTEGER CHECK (max_pending_invitations >= 0),
+    max_custom_roles INTEGER CHECK (max_custom_roles >= 0),
+    max_system_users INTEGER CHECK (max_system_users >= 0),
+    features JSONB NOT NULL DEFAULT '{}',
+    reason TEXT,
+    updated_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Existing tenants keep unlimited access
+INSERT INTO plans (name, description, is_default)
+VALUES ('default', 'Unlimited plan applied to tenants without a plan', true)
+ON CONFLICT (name) DO NOTHING;
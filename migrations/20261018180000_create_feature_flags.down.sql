@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS feature_flag_audit_logs;
+DROP TABLE IF EXISTS feature_flags;
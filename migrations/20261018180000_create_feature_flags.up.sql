@@ -0,0 +1,33 @@
+-- Feature flags rolled out per tenant and per user. Variations, targeting
+-- rules and rollouts are stored as JSONB and evaluated in the API.
+CREATE TABLE IF NOT EXISTS feature_flags (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    key VARCHAR(100) NOT NULL UNIQUE,
+    description TEXT,
+    type VARCHAR(20) NOT NULL DEFAULT 'boolean',
+    variations JSONB NOT NULL DEFAULT '[]',
+    rules JSONB NOT NULL DEFAULT '[]',
+    default_variation VARCHAR(100) NOT NULL,
+    off_variation VARCHAR(100) NOT NULL,
+    killed BOOLEAN NOT NULL DEFAULT false,
+    killed_at TIMESTAMP WITH TIME ZONE,
+    created_by VARCHAR(255) NOT NULL,
+    updated_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Every change to a flag, kept after the flag is deleted
+CREATE TABLE IF NOT EXISTS feature_flag_audit_logs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    flag_id UUID NOT NULL,
+    flag_key VARCHAR(100) NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    reason TEXT,
+    before JSONB,
+    after JSONB,
+    actor_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_feature_flag_audit_logs_flag_id ON feature_flag_audit_logs(flag_id, created_at DESC);
@@ -0,0 +1,5 @@
+DELETE FROM policy_permissions
+WHERE permission_id IN (SELECT id FROM permissions WHERE service = 'tenant-api' AND entity = 'export');
+DELETE FROM permissions WHERE service = 'tenant-api' AND entity = 'export';
+
+DROP TABLE IF EXISTS tenant_exports;
@@ -0,0 +1,39 @@
+-- Job-backed exports of a tenant's identity data
+CREATE TABLE IF NOT EXISTS tenant_exports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    progress INTEGER NOT NULL DEFAULT 0 CHECK (progress BETWEEN 0 AND 100),
+    stage VARCHAR(50),
+    file_path VARCHAR(500),
+    file_size BIGINT NOT NULL DEFAULT 0,
+    error TEXT,
+    requested_by VARCHAR(255) NOT NULL,
+    -- Only the latest download link is valid; the token itself is not stored
+    download_token_hash VARCHAR(64),
+    download_expires_at TIMESTAMP WITH TIME ZONE,
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_tenant_exports_tenant_id ON tenant_exports(tenant_id, created_at DESC);
+CREATE UNIQUE INDEX idx_tenant_exports_download_token ON tenant_exports(download_token_hash) WHERE download_token_hash IS NOT NULL;
+CREATE INDEX idx_tenant_exports_expires_at ON tenant_exports(expires_at) WHERE file_path IS NOT NULL;
+
+-- Exports contain every member's email, so only tenant admins get them
+INSERT INTO permissions (service, entity, action, description) VALUES
+    ('tenant-api', 'export', 'create', 'Export tenant data'),
+    ('tenant-api', 'export', 'read', 'View and download tenant exports')
+ON CONFLICT (service, entity, action) DO NOTHING;
+
+INSERT INTO policy_permissions (policy_id, permission_id)
+SELECT pol.id, perm.id
+FROM policies pol
+CROSS JOIN permissions perm
+WHERE pol.name = 'Tenant Admin Policy'
+  AND perm.service = 'tenant-api'
+  AND perm.entity = 'export'
+ON CONFLICT DO NOTHING;
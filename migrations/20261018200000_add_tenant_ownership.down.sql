@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS tenant_ownership_transfers;
+
+DROP INDEX IF EXISTS idx_tenants_owner_id;
+ALTER TABLE tenants DROP COLUMN IF EXISTS owner_id;
@@ -0,0 +1,56 @@
+-- Track the current owner separately from the creator so ownership can be
+-- handed over
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS owner_id VARCHAR(255);
+
+-- Existing tenants are owned by their creator while the creator is still a
+-- member, otherwise by their longest-standing admin
+UPDATE tenants t
+SET owner_id = t.created_by
+WHERE t.owner_id IS NULL
+  AND EXISTS (
+      SELECT 1 FROM tenant_members m
+      WHERE m.tenant_id = t.id AND m.user_id = t.created_by
+  );
+
+UPDATE tenants t
+SET owner_id = (
+    SELECT m.user_id
+    FROM tenant_members m
+    JOIN roles r ON r.id = m.role_id
+    WHERE m.tenant_id = t.id AND r.name = 'Admin' AND r.tenant_id IS NULL
+    ORDER BY m.joined_at ASC
+    LIMIT 1
+)
+WHERE t.owner_id IS NULL;
+
+CREATE INDEX idx_tenants_owner_id ON tenants(owner_id);
+
+COMMENT ON COLUMN tenants.owner_id IS 'SuperTokens user ID of the current owner; changes only through an accepted ownership transfer';
+
+-- Ownership transfers double as the audit trail of ownership changes
+CREATE TABLE IF NOT EXISTS tenant_ownership_transfers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    from_user_id VARCHAR(255),
+    to_user_id VARCHAR(255) NOT NULL,
+    previous_owner_role_id UUID REFERENCES roles(id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    message TEXT,
+    initiated_by VARCHAR(255) NOT NULL,
+    resolved_by VARCHAR(255),
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_tenant_ownership_transfers_tenant_id ON tenant_ownership_transfers(tenant_id);
+CREATE INDEX idx_tenant_ownership_transfers_to_user_id ON tenant_ownership_transfers(to_user_id);
+
+-- A tenant has at most one open transfer
+CREATE UNIQUE INDEX idx_tenant_ownership_transfers_pending
+    ON tenant_ownership_transfers(tenant_id)
+    WHERE status = 'pending';
+
+COMMENT ON TABLE tenant_ownership_transfers IS 'Nominations of a new tenant owner and how each was resolved';
+COMMENT ON COLUMN tenant_ownership_transfers.previous_owner_role_id IS 'Role the previous owner is moved to on acceptance; NULL keeps their current role';
@@ -0,0 +1,6 @@
+ALTER TABLE tenants
+    DROP COLUMN IF EXISTS provision_services,
+    DROP COLUMN IF EXISTS default_role_id,
+    DROP COLUMN IF EXISTS template_id;
+
+DROP TABLE IF EXISTS tenant_templates;
@@ -0,0 +1,30 @@
+-- Platform-defined bundles of tenant-scoped roles and policies, default
+-- metadata, a default member role and the services to provision
+CREATE TABLE IF NOT EXISTS tenant_templates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL UNIQUE,
+    description TEXT,
+    tenant_type VARCHAR(100),
+    default_metadata JSONB NOT NULL DEFAULT '{}',
+    policies JSONB NOT NULL DEFAULT '[]',
+    roles JSONB NOT NULL DEFAULT '[]',
+    default_role VARCHAR(100),
+    services JSONB,
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON COLUMN tenant_templates.policies IS 'Tenant-scoped policies to create: [{name, description, permissions: ["service:entity:action"]}]';
+COMMENT ON COLUMN tenant_templates.roles IS 'Tenant-scoped roles to create: [{name, description, policies: [policy names]}]';
+COMMENT ON COLUMN tenant_templates.default_role IS 'Name of a template or system role given to members added without a role';
+COMMENT ON COLUMN tenant_templates.services IS 'Downstream service names to provision; empty provisions every enabled service';
+
+ALTER TABLE tenants
+    ADD COLUMN IF NOT EXISTS template_id UUID REFERENCES tenant_templates(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS default_role_id UUID REFERENCES roles(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS provision_services JSONB;
+
+COMMENT ON COLUMN tenants.template_id IS 'Template the tenant was created from';
+COMMENT ON COLUMN tenants.default_role_id IS 'Role given to members and invitations that do not name one';
+COMMENT ON COLUMN tenants.provision_services IS 'Services the tenant is provisioned in, copied from its template; NULL means every enabled service';
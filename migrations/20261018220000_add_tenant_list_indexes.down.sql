@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_tenant_members_tenant_status;
+DROP INDEX IF EXISTS idx_tenants_name;
+DROP INDEX IF EXISTS idx_tenants_created_at;
+DROP INDEX IF EXISTS idx_tenants_metadata;
+DROP INDEX IF EXISTS idx_tenants_slug_trgm;
+DROP INDEX IF EXISTS idx_tenants_name_trgm;
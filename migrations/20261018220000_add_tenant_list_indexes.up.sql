@@ -0,0 +1,16 @@
+-- Indexes backing the filters and sorts of the tenant listings
+
+-- Trigram indexes make the name/slug substring search (ILIKE '%q%') indexable
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_tenants_name_trgm ON tenants USING GIN (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_tenants_slug_trgm ON tenants USING GIN (slug gin_trgm_ops);
+
+-- Metadata key/value filters use containment (metadata @> '{"key": "value"}')
+CREATE INDEX IF NOT EXISTS idx_tenants_metadata ON tenants USING GIN (metadata jsonb_path_ops);
+
+CREATE INDEX IF NOT EXISTS idx_tenants_created_at ON tenants(created_at);
+CREATE INDEX IF NOT EXISTS idx_tenants_name ON tenants(name);
+
+-- Member counts for sorting are computed per tenant over active members
+CREATE INDEX IF NOT EXISTS idx_tenant_members_tenant_status ON tenant_members(tenant_id, status);
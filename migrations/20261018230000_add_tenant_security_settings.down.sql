@@ -0,0 +1,5 @@
+DELETE FROM policy_permissions
+WHERE permission_id IN (SELECT id FROM permissions WHERE service = 'tenant-api' AND entity = 'security-settings');
+DELETE FROM permissions WHERE service = 'tenant-api' AND entity = 'security-settings';
+
+ALTER TABLE tenants DROP COLUMN IF EXISTS security_settings;
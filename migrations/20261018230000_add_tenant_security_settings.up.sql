@@ -0,0 +1,20 @@
+-- Per-tenant security policy enforced by Rex; an empty object imposes no
+-- restrictions
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS security_settings JSONB NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN tenants.security_settings IS 'Allowed email domains, maximum session lifetime, allowed login methods and whether only admins can invite';
+
+-- Only tenant admins may view or change the policy
+INSERT INTO permissions (service, entity, action, description) VALUES
+    ('tenant-api', 'security-settings', 'read', 'View tenant security settings'),
+    ('tenant-api', 'security-settings', 'update', 'Change tenant security settings')
+ON CONFLICT (service, entity, action) DO NOTHING;
+
+INSERT INTO policy_permissions (policy_id, permission_id)
+SELECT pol.id, perm.id
+FROM policies pol
+CROSS JOIN permissions perm
+WHERE pol.name = 'Tenant Admin Policy'
+  AND perm.service = 'tenant-api'
+  AND perm.entity = 'security-settings'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,5 @@
+DELETE FROM policy_permissions
+WHERE permission_id IN (SELECT id FROM permissions WHERE service = 'tenant-api' AND entity = 'member' AND action = 'bulk');
+DELETE FROM permissions WHERE service = 'tenant-api' AND entity = 'member' AND action = 'bulk';
+
+DROP TABLE IF EXISTS member_bulk_operations;
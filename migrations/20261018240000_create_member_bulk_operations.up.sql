@@ -0,0 +1,38 @@
+-- Job-backed bulk member adds, role changes and removals with a result per row
+CREATE TABLE IF NOT EXISTS member_bulk_operations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    operation VARCHAR(20) NOT NULL CHECK (operation IN ('add', 'update_role', 'remove')),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    dry_run BOOLEAN NOT NULL DEFAULT FALSE,
+    rows JSONB NOT NULL DEFAULT '[]',
+    results JSONB NOT NULL DEFAULT '[]',
+    total_rows INTEGER NOT NULL DEFAULT 0,
+    processed INTEGER NOT NULL DEFAULT 0,
+    succeeded INTEGER NOT NULL DEFAULT 0,
+    failed INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    requested_by VARCHAR(255) NOT NULL,
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_member_bulk_operations_tenant_id ON member_bulk_operations(tenant_id, created_at DESC);
+
+-- Bulk operations can add or remove many members at once, so only tenant
+-- admins get them; viewing results only needs member read
+INSERT INTO permissions (service, entity, action, description) VALUES
+    ('tenant-api', 'member', 'bulk', 'Add, update or remove members in bulk')
+ON CONFLICT (service, entity, action) DO NOTHING;
+
+INSERT INTO policy_permissions (policy_id, permission_id)
+SELECT pol.id, perm.id
+FROM policies pol
+CROSS JOIN permissions perm
+WHERE pol.name = 'Tenant Admin Policy'
+  AND perm.service = 'tenant-api'
+  AND perm.entity = 'member'
+  AND perm.action = 'bulk'
+ON CONFLICT DO NOTHING;
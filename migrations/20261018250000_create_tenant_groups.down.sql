@@ -0,0 +1,7 @@
+DELETE FROM policy_permissions
+WHERE permission_id IN (SELECT id FROM permissions WHERE service = 'tenant-api' AND entity = 'group');
+DELETE FROM permissions WHERE service = 'tenant-api' AND entity = 'group';
+
+DROP TABLE IF EXISTS tenant_group_roles;
+DROP TABLE IF EXISTS tenant_group_members;
+DROP TABLE IF EXISTS tenant_groups;
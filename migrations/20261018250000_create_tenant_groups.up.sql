@@ -0,0 +1,43 @@
+-- Teams within a tenant whose roles are inherited by their members
+CREATE TABLE IF NOT EXISTS tenant_groups (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (tenant_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS tenant_group_members (
+    group_id UUID NOT NULL REFERENCES tenant_groups(id) ON DELETE CASCADE,
+    member_id UUID NOT NULL REFERENCES tenant_members(id) ON DELETE CASCADE,
+    added_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (group_id, member_id)
+);
+
+CREATE INDEX idx_tenant_group_members_member_id ON tenant_group_members(member_id);
+
+CREATE TABLE IF NOT EXISTS tenant_group_roles (
+    group_id UUID NOT NULL REFERENCES tenant_groups(id) ON DELETE CASCADE,
+    role_id UUID NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    PRIMARY KEY (group_id, role_id)
+);
+
+INSERT INTO permissions (service, entity, action, description) VALUES
+    ('tenant-api', 'group', 'create', 'Create groups'),
+    ('tenant-api', 'group', 'read', 'View groups and their members'),
+    ('tenant-api', 'group', 'update', 'Update groups, their members and roles'),
+    ('tenant-api', 'group', 'delete', 'Delete groups')
+ON CONFLICT (service, entity, action) DO NOTHING;
+
+INSERT INTO policy_permissions (policy_id, permission_id)
+SELECT pol.id, perm.id
+FROM policies pol
+CROSS JOIN permissions perm
+WHERE pol.name = 'Tenant Admin Policy'
+  AND perm.service = 'tenant-api'
+  AND perm.entity = 'group'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS member_suspensions;
+
+-- Postgres cannot drop an enum value; suspended members become inactive and
+-- the 'suspended' value is left unused
+UPDATE tenant_members SET status = 'inactive' WHERE status = 'suspended';
@@ -0,0 +1,23 @@
+ALTER TYPE member_status ADD VALUE IF NOT EXISTS 'suspended';
+
+-- Suspension history of tenant members; the open suspension has no reactivated_at
+CREATE TABLE IF NOT EXISTS member_suspensions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    member_id UUID NOT NULL REFERENCES tenant_members(id) ON DELETE CASCADE,
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL,
+    reason TEXT NOT NULL,
+    suspended_by VARCHAR(255) NOT NULL,
+    suspended_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    reactivate_at TIMESTAMP WITH TIME ZONE,
+    sessions_revoked BOOLEAN NOT NULL DEFAULT FALSE,
+    reactivated_at TIMESTAMP WITH TIME ZONE,
+    reactivated_by VARCHAR(255),
+    reactivation_reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_member_suspensions_member_id ON member_suspensions(member_id, suspended_at DESC);
+CREATE UNIQUE INDEX idx_member_suspensions_open ON member_suspensions(member_id) WHERE reactivated_at IS NULL;
+CREATE INDEX idx_member_suspensions_reactivate_at ON member_suspensions(reactivate_at) WHERE reactivated_at IS NULL AND reactivate_at IS NOT NULL;
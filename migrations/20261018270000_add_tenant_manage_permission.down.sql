@@ -0,0 +1,3 @@
+DELETE FROM policy_permissions
+WHERE permission_id IN (SELECT id FROM permissions WHERE service = 'tenant-api' AND entity = 'tenant' AND action = 'manage');
+DELETE FROM permissions WHERE service = 'tenant-api' AND entity = 'tenant' AND action = 'manage';
@@ -0,0 +1,16 @@
+-- Members holding tenant:manage can manage the tenant. Every active tenant must
+-- keep at least one active member with it; changes that would leave none are
+-- rejected.
+INSERT INTO permissions (service, entity, action, description) VALUES
+    ('tenant-api', 'tenant', 'manage', 'Manage the tenant; every active tenant keeps at least one member with this permission')
+ON CONFLICT (service, entity, action) DO NOTHING;
+
+INSERT INTO policy_permissions (policy_id, permission_id)
+SELECT pol.id, perm.id
+FROM policies pol
+CROSS JOIN permissions perm
+WHERE pol.name = 'Tenant Admin Policy'
+  AND perm.service = 'tenant-api'
+  AND perm.entity = 'tenant'
+  AND perm.action = 'manage'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_tenant_members_last_active_at;
+ALTER TABLE tenant_members DROP COLUMN IF EXISTS last_active_at;
@@ -0,0 +1,5 @@
+-- When each member last made a tenant-scoped request, recorded at most once per
+-- throttle interval
+ALTER TABLE tenant_members ADD COLUMN IF NOT EXISTS last_active_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_tenant_members_last_active_at ON tenant_members(tenant_id, last_active_at);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_tenant_email_domains_domain;
+DROP TABLE IF EXISTS tenant_email_domains;
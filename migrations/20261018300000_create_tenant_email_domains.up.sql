@@ -0,0 +1,22 @@
+-- Email domains whose users may join a tenant without an invitation. A domain
+-- only takes effect once ownership has been proven through a DNS TXT record.
+CREATE TABLE IF NOT EXISTS tenant_email_domains (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    domain VARCHAR(255) NOT NULL,
+    verification_token VARCHAR(255) NOT NULL,
+    verified_at TIMESTAMP WITH TIME ZONE,
+    last_checked_at TIMESTAMP WITH TIME ZONE,
+    role_id UUID REFERENCES roles(id) ON DELETE SET NULL,
+    join_mode VARCHAR(20) NOT NULL DEFAULT 'approval' CHECK (join_mode IN ('auto', 'approval')),
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (tenant_id, domain)
+);
+
+CREATE INDEX idx_tenant_email_domains_domain ON tenant_email_domains(domain) WHERE verified_at IS NOT NULL;
+
+COMMENT ON TABLE tenant_email_domains IS 'Email domains whose users join the tenant on sign-in once verified';
+COMMENT ON COLUMN tenant_email_domains.role_id IS 'Role given to joining users, NULL uses the tenant default role';
+COMMENT ON COLUMN tenant_email_domains.join_mode IS 'auto adds users as active members, approval adds them as pending';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_tenant_access_requests_pending;
+DROP INDEX IF EXISTS idx_tenant_access_requests_user_id;
+DROP INDEX IF EXISTS idx_tenant_access_requests_tenant_id;
+DROP TABLE IF EXISTS tenant_access_requests;
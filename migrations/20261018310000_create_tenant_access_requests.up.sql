@@ -0,0 +1,27 @@
+-- Requests from users to join a tenant, kept with how each was resolved
+CREATE TABLE IF NOT EXISTS tenant_access_requests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    message TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    role_id UUID REFERENCES roles(id) ON DELETE SET NULL,
+    reason TEXT,
+    resolved_by VARCHAR(255),
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_tenant_access_requests_tenant_id ON tenant_access_requests(tenant_id);
+CREATE INDEX idx_tenant_access_requests_user_id ON tenant_access_requests(user_id);
+
+-- A user has at most one open request per tenant
+CREATE UNIQUE INDEX idx_tenant_access_requests_pending
+    ON tenant_access_requests(tenant_id, user_id)
+    WHERE status = 'pending';
+
+COMMENT ON TABLE tenant_access_requests IS 'Requests to join a tenant and how each was resolved';
+COMMENT ON COLUMN tenant_access_requests.role_id IS 'Role the requester was given on approval';
+COMMENT ON COLUMN tenant_access_requests.reason IS 'Why the request was denied';
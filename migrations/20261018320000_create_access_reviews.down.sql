@@ -0,0 +1,10 @@
+DELETE FROM policy_permissions
+WHERE permission_id IN (SELECT id FROM permissions WHERE service = 'tenant-api' AND entity = 'access-review');
+DELETE FROM permissions WHERE service = 'tenant-api' AND entity = 'access-review';
+
+DROP INDEX IF EXISTS idx_access_review_items_pending;
+DROP INDEX IF EXISTS idx_access_review_items_tenant_id;
+DROP TABLE IF EXISTS access_review_items;
+DROP INDEX IF EXISTS idx_access_review_campaigns_status;
+DROP INDEX IF EXISTS idx_access_review_campaigns_tenant_id;
+DROP TABLE IF EXISTS access_review_campaigns;
@@ -0,0 +1,68 @@
+-- Access review campaigns: a snapshot of who holds which role in the covered
+-- tenants, certified or revoked by reviewers and applied when the campaign closes
+CREATE TABLE IF NOT EXISTS access_review_campaigns (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    tenant_id UUID REFERENCES tenants(id) ON DELETE CASCADE,
+    scope_tenant_ids JSONB,
+    role_id UUID,
+    reviewer_ids JSONB,
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    due_at TIMESTAMP WITH TIME ZONE,
+    created_by VARCHAR(255) NOT NULL,
+    closed_by VARCHAR(255),
+    closed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_access_review_campaigns_tenant_id ON access_review_campaigns(tenant_id);
+CREATE INDEX idx_access_review_campaigns_status ON access_review_campaigns(status);
+
+-- Items keep their member, role and tenant IDs without foreign keys so the
+-- record of a review survives the revocations it led to
+CREATE TABLE IF NOT EXISTS access_review_items (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    campaign_id UUID NOT NULL REFERENCES access_review_campaigns(id) ON DELETE CASCADE,
+    tenant_id UUID NOT NULL,
+    member_id UUID NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    role_id UUID NOT NULL,
+    role_name VARCHAR(255) NOT NULL,
+    decision VARCHAR(20) NOT NULL DEFAULT 'pending',
+    comment TEXT,
+    decided_by VARCHAR(255),
+    decided_at TIMESTAMP WITH TIME ZONE,
+    outcome VARCHAR(20),
+    outcome_detail TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(campaign_id, member_id)
+);
+
+CREATE INDEX idx_access_review_items_tenant_id ON access_review_items(tenant_id);
+CREATE INDEX idx_access_review_items_pending
+    ON access_review_items(campaign_id)
+    WHERE decision = 'pending';
+
+COMMENT ON TABLE access_review_campaigns IS 'Periodic reviews of who holds which role in one or more tenants';
+COMMENT ON COLUMN access_review_campaigns.tenant_id IS 'Tenant that started the campaign; NULL for campaigns started by a platform admin';
+COMMENT ON COLUMN access_review_campaigns.scope_tenant_ids IS 'Tenants covered by the campaign; NULL covers every active tenant';
+COMMENT ON COLUMN access_review_campaigns.reviewer_ids IS 'Users who review the items; NULL leaves each item to its tenant''s managers';
+COMMENT ON TABLE access_review_items IS 'One member''s role to certify or revoke within a campaign';
+COMMENT ON COLUMN access_review_items.outcome IS 'What closing the campaign did with a revocation: applied, skipped or failed';
+
+INSERT INTO permissions (service, entity, action, description) VALUES
+    ('tenant-api', 'access-review', 'create', 'Start and close access review campaigns'),
+    ('tenant-api', 'access-review', 'read', 'View access review campaigns and export their reports')
+ON CONFLICT (service, entity, action) DO NOTHING;
+
+INSERT INTO policy_permissions (policy_id, permission_id)
+SELECT pol.id, perm.id
+FROM policies pol
+CROSS JOIN permissions perm
+WHERE pol.name = 'Tenant Admin Policy'
+  AND perm.service = 'tenant-api'
+  AND perm.entity = 'access-review'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_tenant_domains_verified_domain;
+
+ALTER TABLE tenant_domains DROP CONSTRAINT IF EXISTS tenant_domains_tenant_id_domain_key;
+
+-- Restoring global uniqueness needs unverified duplicate claims removed first
+DELETE FROM tenant_domains d
+USING tenant_domains other
+WHERE d.domain = other.domain
+  AND d.verified_at IS NULL
+  AND (other.verified_at IS NOT NULL OR other.created_at < d.created_at);
+
+ALTER TABLE tenant_domains ADD CONSTRAINT tenant_domains_domain_key UNIQUE (domain);
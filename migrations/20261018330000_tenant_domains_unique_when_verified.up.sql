@@ -0,0 +1,9 @@
+-- Only a verified domain is reserved. Any tenant may claim a domain, and the
+-- first to publish its TXT record owns it; a pending claim blocks nobody.
+ALTER TABLE tenant_domains DROP CONSTRAINT IF EXISTS tenant_domains_domain_key;
+
+ALTER TABLE tenant_domains ADD CONSTRAINT tenant_domains_tenant_id_domain_key UNIQUE (tenant_id, domain);
+
+CREATE UNIQUE INDEX idx_tenant_domains_verified_domain
+    ON tenant_domains(domain)
+    WHERE verified_at IS NOT NULL;
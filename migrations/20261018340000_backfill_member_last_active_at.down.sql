@@ -0,0 +1,3 @@
+-- The backfilled timestamps cannot be told apart from recorded activity, so
+-- they are kept
+SELECT 1;
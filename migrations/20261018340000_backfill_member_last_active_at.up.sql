@@ -0,0 +1,4 @@
+-- Activity was not tracked before last_active_at was added, so members who
+-- have not been seen since would count as dormant from when they joined.
+-- Start everyone's clock now instead.
+UPDATE tenant_members SET last_active_at = NOW() WHERE last_active_at IS NULL;
@@ -0,0 +1,11 @@
+-- The last time each user's membership of a tenant was removed, whether by an
+-- admin, an access review, a bulk operation or the user leaving. Users with a
+-- removal are not added back automatically by their email domain.
+CREATE TABLE IF NOT EXISTS tenant_member_removals (
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL,
+    removed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (tenant_id, user_id)
+);
+
+COMMENT ON TABLE tenant_member_removals IS 'Users removed from a tenant, kept so email domain joins do not add them back';
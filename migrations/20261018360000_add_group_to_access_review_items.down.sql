@@ -0,0 +1,8 @@
+DELETE FROM access_review_items WHERE group_id IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_access_review_items_group_role;
+DROP INDEX IF EXISTS idx_access_review_items_member_role;
+ALTER TABLE access_review_items ADD CONSTRAINT access_review_items_campaign_id_member_id_key UNIQUE (campaign_id, member_id);
+
+ALTER TABLE access_review_items DROP COLUMN IF EXISTS group_name;
+ALTER TABLE access_review_items DROP COLUMN IF EXISTS group_id;
@@ -0,0 +1,14 @@
+-- Access review items also cover roles members inherit from their groups.
+-- A member now has one item for their own role and one per group role.
+ALTER TABLE access_review_items ADD COLUMN IF NOT EXISTS group_id UUID;
+ALTER TABLE access_review_items ADD COLUMN IF NOT EXISTS group_name VARCHAR(255);
+
+ALTER TABLE access_review_items DROP CONSTRAINT IF EXISTS access_review_items_campaign_id_member_id_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_access_review_items_member_role
+    ON access_review_items(campaign_id, member_id)
+    WHERE group_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_access_review_items_group_role
+    ON access_review_items(campaign_id, member_id, group_id, role_id)
+    WHERE group_id IS NOT NULL;
+
+COMMENT ON COLUMN access_review_items.group_id IS 'Group the role is inherited from; NULL for the member''s own role';